@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+func newRateCmd() *cobra.Command {
+	var explain bool
+	cmd := &cobra.Command{
+		Use:               "rate [activity] [cities...]",
+		Short:             "Score cities' weather for running, cycling, picnic or beach",
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeRate,
+		Run: func(cmd *cobra.Command, args []string) {
+			rateActivity(args[0], args[1:], explain)
+		},
+	}
+	cmd.Flags().BoolVar(&explain, "explain", false, "Add an AI comment for each city")
+	return cmd
+}
+
+func rateActivity(activity string, cities []string, explain bool) {
+	cities, ok := resolveCities(cities)
+	if !ok {
+		return
+	}
+	var cityData []*advisorpb.CityData
+	for _, city := range cities {
+		cityData = append(cityData, newCityData(city))
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	client := advisorpb.NewAdvisorServiceClient(conn)
+	resp, err := client.RateActivity(ctx, &advisorpb.RateActivityRequest{
+		Activity:   activity,
+		Cities:     cityData,
+		Explain:    explain,
+		Model:      modelName,
+		UnitSystem: unitSystem,
+	})
+	if err != nil {
+		color.Red("❌ Rating failed: %v", err)
+		return
+	}
+
+	for _, cityErr := range resp.Errors {
+		color.Yellow("⚠️  Skipped %s (%s failed): %s", cityErr.Location, cityErr.Stage, cityErr.Message)
+	}
+	color.HiGreen("\n🏃 %s suitability", strings.ToUpper(resp.Activity[:1])+resp.Activity[1:])
+	color.Green(strings.Repeat("─", 40))
+	for _, r := range resp.Ratings {
+		scoreColor := color.New(color.FgGreen)
+		switch {
+		case r.Score < 40:
+			scoreColor = color.New(color.FgRed)
+		case r.Score < 70:
+			scoreColor = color.New(color.FgYellow)
+		}
+		fmt.Printf("%-15s ", r.Location)
+		scoreColor.Printf("%3d/100", r.Score)
+		fmt.Printf("  %s\n", strings.Join(r.Reasons, ", "))
+		if r.Comment != "" {
+			color.HiBlack("                %s", r.Comment)
+		}
+	}
+	if u := resp.Usage; u != nil {
+		color.HiBlack("%s: %d prompt + %d response tokens (~$%.4f)", u.Model, u.PromptTokens, u.ResponseTokens, u.EstimatedCostUsd)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pixperk/effinarounf/services/markdown"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+var (
+	alertMetricNames = map[string]advisorpb.AlertMetric{
+		"high":        advisorpb.AlertMetric_ALERT_METRIC_TEMP_MAX,
+		"low":         advisorpb.AlertMetric_ALERT_METRIC_TEMP_MIN,
+		"rain":        advisorpb.AlertMetric_ALERT_METRIC_PRECIPITATION,
+		"rain-chance": advisorpb.AlertMetric_ALERT_METRIC_PRECIPITATION_PROBABILITY,
+		"wind":        advisorpb.AlertMetric_ALERT_METRIC_WIND_SPEED,
+		"uv":          advisorpb.AlertMetric_ALERT_METRIC_UV_INDEX,
+	}
+	alertOperatorNames = map[string]advisorpb.AlertOperator{
+		"above":    advisorpb.AlertOperator_ALERT_OPERATOR_ABOVE,
+		">":        advisorpb.AlertOperator_ALERT_OPERATOR_ABOVE,
+		"at-least": advisorpb.AlertOperator_ALERT_OPERATOR_AT_LEAST,
+		">=":       advisorpb.AlertOperator_ALERT_OPERATOR_AT_LEAST,
+		"below":    advisorpb.AlertOperator_ALERT_OPERATOR_BELOW,
+		"<":        advisorpb.AlertOperator_ALERT_OPERATOR_BELOW,
+		"at-most":  advisorpb.AlertOperator_ALERT_OPERATOR_AT_MOST,
+		"<=":       advisorpb.AlertOperator_ALERT_OPERATOR_AT_MOST,
+	}
+	alertWindowNames = map[string]advisorpb.AlertWindow{
+		"today":    advisorpb.AlertWindow_ALERT_WINDOW_TODAY,
+		"tomorrow": advisorpb.AlertWindow_ALERT_WINDOW_TOMORROW,
+		"3d":       advisorpb.AlertWindow_ALERT_WINDOW_NEXT_3_DAYS,
+		"7d":       advisorpb.AlertWindow_ALERT_WINDOW_NEXT_7_DAYS,
+	}
+)
+
+func newAlertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Manage weather alerts checked by the server",
+	}
+
+	var window, recipient string
+	var hooks webhookFlags
+	add := &cobra.Command{
+		Use:   "add [city] [metric] [above|at-least|below|at-most] [threshold] [unit]",
+		Short: "Get notified when the forecast meets a condition",
+		Long: `Get notified when the forecast meets a condition, e.g.
+
+  weather-advisor alert add Chicago wind above 15 m/s
+  weather-advisor alert add London rain above 0 --window tomorrow
+
+Metrics: high, low (°C or F), rain (mm or in), rain-chance (%), wind (km/h,
+m/s, mph or kn) and uv.`,
+		Args:              cobra.RangeArgs(4, 5),
+		ValidArgsFunction: completeCity,
+		Run: func(cmd *cobra.Command, args []string) {
+			addAlert(args, window, recipient, hooks.proto())
+		},
+	}
+	add.Flags().StringVar(&window, "window", "today", "Forecast days to watch: today, tomorrow, 3d or 7d")
+	add.Flags().StringVar(&recipient, "recipient", "", "Who the alert is for")
+	hooks.register(add)
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List weather alerts",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listAlerts()
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:   "remove [id]",
+		Short: "Stop an alert",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			removeAlert(args[0])
+		},
+	}
+
+	var watchRecipient string
+	watch := &cobra.Command{
+		Use:   "watch [ids...]",
+		Short: "Print alerts as the server fires them, until interrupted",
+		Run: func(cmd *cobra.Command, args []string) {
+			watchAlerts(args, watchRecipient)
+		},
+	}
+	watch.Flags().StringVar(&watchRecipient, "recipient", "", "Only alerts for this recipient")
+
+	cmd.AddCommand(add, list, remove, watch)
+	return cmd
+}
+
+func addAlert(args []string, window, recipient string, webhooks []*advisorpb.Webhook) {
+	cities, ok := resolveCities(args[:1])
+	if !ok {
+		return
+	}
+	metric, ok := alertMetricNames[strings.ToLower(args[1])]
+	if !ok {
+		color.Red("❌ Unknown metric %q (want high, low, rain, rain-chance, wind or uv)", args[1])
+		return
+	}
+	operator, ok := alertOperatorNames[strings.ToLower(args[2])]
+	if !ok {
+		color.Red("❌ Unknown comparison %q (want above, at-least, below or at-most)", args[2])
+		return
+	}
+	threshold, err := strconv.ParseFloat(args[3], 64)
+	if err != nil {
+		color.Red("❌ Threshold %q is not a number", args[3])
+		return
+	}
+	win, ok := alertWindowNames[strings.ToLower(window)]
+	if !ok {
+		color.Red("❌ Unknown window %q (want today, tomorrow, 3d or 7d)", window)
+		return
+	}
+	cond := &advisorpb.AlertCondition{Metric: metric, Operator: operator, Threshold: threshold, Window: win}
+	if len(args) == 5 {
+		cond.Unit = args[4]
+	}
+
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		sub, err := client.CreateAlert(ctx, &advisorpb.CreateAlertRequest{Subscription: &advisorpb.AlertSubscription{
+			Recipient: recipient,
+			City:      newCityData(cities[0]),
+			Condition: cond,
+			Webhooks:  webhooks,
+		}})
+		if err != nil {
+			color.Red("❌ Alert failed: %v", err)
+			return
+		}
+		color.HiGreen("✅ Alert %s created", sub.Id)
+		printAlert(sub)
+	})
+}
+
+func listAlerts() {
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		resp, err := client.ListAlerts(ctx, &advisorpb.ListAlertsRequest{})
+		if err != nil {
+			color.Red("❌ Listing alerts failed: %v", err)
+			return
+		}
+		if len(resp.Subscriptions) == 0 {
+			color.Yellow("No alerts")
+			return
+		}
+		for _, sub := range resp.Subscriptions {
+			color.HiCyan("\n%s", sub.Id)
+			printAlert(sub)
+		}
+	})
+}
+
+func printAlert(sub *advisorpb.AlertSubscription) {
+	c := sub.Condition
+	metric := strings.ToLower(strings.TrimPrefix(c.GetMetric().String(), "ALERT_METRIC_"))
+	operator := strings.ToLower(strings.TrimPrefix(c.GetOperator().String(), "ALERT_OPERATOR_"))
+	window := strings.ToLower(strings.TrimPrefix(c.GetWindow().String(), "ALERT_WINDOW_"))
+	threshold := strconv.FormatFloat(c.GetThreshold(), 'g', -1, 64)
+	if c.GetUnit() != "" {
+		threshold += " " + c.GetUnit()
+	}
+	fmt.Printf("  %s: %s %s %s, %s", sub.City.GetLocation(), strings.ReplaceAll(metric, "_", " "),
+		strings.ReplaceAll(operator, "_", " "), threshold, strings.ReplaceAll(window, "_", " "))
+	if sub.Recipient != "" {
+		fmt.Printf(" for %s", sub.Recipient)
+	}
+	fmt.Println()
+	if sub.LastAlertedAt > 0 {
+		color.HiBlack("  last alerted: %s for %s", time.Unix(sub.LastAlertedAt, 0).Format("Mon 2 Jan 15:04 MST"), sub.LastAlertedDate)
+	}
+	printWebhooks(sub.Webhooks)
+}
+
+func watchAlerts(ids []string, recipient string) {
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	stream, err := advisorpb.NewAdvisorServiceClient(conn).SubscribeAlerts(ctx, &advisorpb.SubscribeAlertsRequest{
+		SubscriptionIds: ids,
+		Recipient:       recipient,
+	})
+	if err == nil {
+		// The server sends headers once subscribed.
+		_, err = stream.Header()
+	}
+	if err != nil {
+		color.Red("❌ Watching alerts failed: %v", err)
+		return
+	}
+	color.HiBlack("Watching for alerts, Ctrl-C to stop")
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				color.Red("❌ Alert stream ended: %v", err)
+			}
+			return
+		}
+		if ev.Keepalive {
+			continue
+		}
+		color.HiYellow("\n⚠️  %s", ev.Title)
+		fmt.Print(markdown.Convert(markdown.Plain, ev.Body))
+		printAlert(ev.Subscription)
+	}
+}
+
+func removeAlert(id string) {
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		if _, err := client.DeleteAlert(ctx, &advisorpb.DeleteAlertRequest{Id: id}); err != nil {
+			color.Red("❌ Removing alert failed: %v", err)
+			return
+		}
+		color.HiGreen("✅ Alert %s removed", id)
+	})
+}
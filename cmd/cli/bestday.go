@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+type bestDayOptions struct {
+	from, to string
+	activity string
+	count    int
+	explain  bool
+}
+
+func newBestDayCmd() *cobra.Command {
+	var opts bestDayOptions
+	cmd := &cobra.Command{
+		Use:               "bestday [city]",
+		Short:             "Find the best day in the coming forecast for outdoor plans",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCity,
+		Run: func(cmd *cobra.Command, args []string) {
+			findBestDay(args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.from, "from", "", "First date, YYYY-MM-DD (default today)")
+	cmd.Flags().StringVar(&opts.to, "to", "", "Last date, YYYY-MM-DD (default a week from --from)")
+	cmd.Flags().StringVar(&opts.activity, "activity", "", "running, cycling, picnic or beach (default general outdoor plans)")
+	cmd.Flags().IntVar(&opts.count, "count", 1, "How many of the best days to show")
+	cmd.Flags().BoolVar(&opts.explain, "explain", false, "Add an AI justification")
+	return cmd
+}
+
+func selectAndFindBestDay() {
+	var selectedCity string
+	prompt := &survey.Select{
+		Message: "Select a city:",
+		Options: cityOptions(),
+	}
+	survey.AskOne(prompt, &selectedCity)
+
+	findBestDay(selectedCity, bestDayOptions{count: 1})
+}
+
+func findBestDay(cityName string, opts bestDayOptions) {
+	cityName, exists := resolveCity(cityName)
+	if !exists {
+		color.Red("City '%s' not found! Use 'weather-advisor cities' to see available cities.", cityName)
+		return
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	resp, err := advisorpb.NewAdvisorServiceClient(conn).BestDay(ctx, &advisorpb.BestDayRequest{
+		City:       newCityData(cityName),
+		StartDate:  opts.from,
+		EndDate:    opts.to,
+		Activity:   opts.activity,
+		Count:      int32(opts.count),
+		Explain:    opts.explain,
+		Model:      modelName,
+		UnitSystem: unitSystem,
+	})
+	if err != nil {
+		color.Red("❌ Best day request failed: %v", err)
+		return
+	}
+
+	best := make(map[string]bool, len(resp.Best))
+	for _, d := range resp.Best {
+		best[d.Date] = true
+	}
+
+	color.HiGreen("\n📅 Best day in %s", resp.Location)
+	color.Green(strings.Repeat("─", 60))
+	for _, d := range resp.Days {
+		date, _ := time.Parse(time.DateOnly, d.Date)
+		line := fmt.Sprintf("%s  %3d/100  %s", date.Format("Mon Jan 2"), d.Score, strings.Join(d.Reasons, ", "))
+		if best[d.Date] {
+			color.HiGreen("⭐ %s", line)
+		} else {
+			fmt.Println("   " + line)
+		}
+	}
+	color.Green(strings.Repeat("─", 60))
+	if resp.Justification != "" {
+		fmt.Println(resp.Justification)
+	}
+	if u := resp.Usage; u != nil {
+		color.HiBlack("%s: %d prompt + %d response tokens (~$%.4f)", u.Model, u.PromptTokens, u.ResponseTokens, u.EstimatedCostUsd)
+	}
+	printSources(resp.Sources)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// The CLI keeps the last successful answer to each weather, advice and
+// city list request in the user's cache directory
+// (~/.cache/weather-advisor), so that when the server can't be reached it
+// can still show something, marked as stale. Deleting the directory is
+// always safe.
+
+// cacheEntry is one cached response as stored on disk, with the request
+// it answered.
+type cacheEntry struct {
+	SavedAt  time.Time       `json:"saved_at"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response"`
+}
+
+// cacheDir is where the cache files are, or "" when the user has no cache
+// directory.
+func cacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "weather-advisor")
+}
+
+// cacheID names the entry for a request: the same request to the same
+// server always gets the same ID.
+func cacheID(kind string, req proto.Message) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write([]byte(serverAddr + "\x00" + kind + "\x00"))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func cachePath(kind, id string) string {
+	dir := cacheDir()
+	if dir == "" || id == "" || strings.ContainsAny(id, `/\.`) {
+		return ""
+	}
+	return filepath.Join(dir, kind+"-"+id+".json")
+}
+
+// saveCached stores resp as the answer to req. The cache is a convenience,
+// so failing to write it is not reported.
+func saveCached(kind string, req, resp proto.Message) {
+	path := cachePath(kind, cacheID(kind, req))
+	if path == "" {
+		return
+	}
+	reqData, err := protojson.Marshal(req)
+	if err != nil {
+		return
+	}
+	respData, err := protojson.Marshal(resp)
+	if err != nil {
+		return
+	}
+	entry, err := json.Marshal(cacheEntry{SavedAt: time.Now(), Request: reqData, Response: respData})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	// Write and rename, so a concurrent reader never sees half a file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, entry, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// loadCached fills resp with the cached answer to req and returns when it
+// was saved. It returns false when nothing usable is cached.
+func loadCached(kind string, req, resp proto.Message) (time.Time, bool) {
+	return loadCachedID(kind, cacheID(kind, req), nil, resp)
+}
+
+// loadCachedID is loadCached for the entry with the given ID. req, when not
+// nil, is filled with the request the entry answered.
+func loadCachedID(kind, id string, req, resp proto.Message) (time.Time, bool) {
+	path := cachePath(kind, id)
+	if path == "" {
+		return time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return time.Time{}, false
+	}
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal(entry.Response, resp); err != nil {
+		return time.Time{}, false
+	}
+	if req != nil && len(entry.Request) > 0 {
+		if err := opts.Unmarshal(entry.Request, req); err != nil {
+			return time.Time{}, false
+		}
+	}
+	return entry.SavedAt, true
+}
+
+// cachedIDs returns the IDs of the entries of a kind, newest first.
+func cachedIDs(kind string) []string {
+	dir := cacheDir()
+	if dir == "" {
+		return nil
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, kind+"-*.json"))
+	type file struct {
+		id      string
+		modTime time.Time
+	}
+	files := make([]file, 0, len(paths))
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(p), kind+"-"), ".json")
+		files = append(files, file{id, fi.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b file) int { return b.modTime.Compare(a.modTime) })
+	ids := make([]string, len(files))
+	for i, f := range files {
+		ids[i] = f.id
+	}
+	return ids
+}
+
+// unreachable reports whether err means the server, or the services behind
+// it, couldn't answer at all, as opposed to refusing the request.
+func unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// staleBanner tells the user that what follows is an old answer, and why.
+func staleBanner(what string, err error, savedAt time.Time) {
+	color.Yellow("⚠️  %s failed: %s", what, status.Convert(err).Message())
+	color.Yellow("⚠️  Showing cached data, stale as of %s (%s)", savedAt.Format("Mon Jan 2 15:04"), age(time.Since(savedAt)))
+}
+
+// age says how old something is in minutes, hours or days.
+func age(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%d min ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.0f h ago", d.Hours())
+	}
+	return fmt.Sprintf("%d days ago", int(d.Hours()/24))
+}
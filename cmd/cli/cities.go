@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+func newCitiesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cities",
+		Short: "List all available cities",
+		Run: func(cmd *cobra.Command, args []string) {
+			listCities()
+		},
+	}
+
+	var state, country string
+	add := &cobra.Command{
+		Use:   "add [name]",
+		Short: "Look a city up on the server and save it to the config file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			addCity(args[0], state, country)
+		},
+	}
+	add.Flags().StringVar(&state, "state", "", "Only places in this state or region")
+	add.Flags().StringVar(&country, "country", "", "Only places in this country (name or ISO code)")
+
+	remove := &cobra.Command{
+		Use:               "remove [name]",
+		Short:             "Remove a city added with cities add",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSavedCity,
+		Run: func(cmd *cobra.Command, args []string) {
+			removeCity(args[0])
+		},
+	}
+
+	cmd.AddCommand(add, remove)
+	return cmd
+}
+
+// searchCity geocodes name on the server and returns the place meant,
+// asking which one when several match. It returns nil after printing why
+// there is none.
+func searchCity(name, state, country string) (*advisorpb.LocationCandidate, []*advisorpb.DataSource) {
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return nil, nil
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := advisorpb.NewAdvisorServiceClient(conn).SearchLocations(ctx, &advisorpb.SearchLocationsRequest{
+		Query:   name,
+		State:   state,
+		Country: country,
+	})
+	if err != nil {
+		color.Red("❌ Search failed: %v", err)
+		return nil, nil
+	}
+	if len(resp.Candidates) == 0 {
+		color.Red("❌ No places found for '%s'", name)
+		return nil, nil
+	}
+	if len(resp.Candidates) == 1 {
+		return resp.Candidates[0], resp.Sources
+	}
+
+	labels := make([]string, len(resp.Candidates))
+	for i, c := range resp.Candidates {
+		labels[i] = fmt.Sprintf("%s, %s, %s (%.4f, %.4f)", c.Name, c.State, c.Country, c.Latitude, c.Longitude)
+	}
+	var choice int
+	prompt := &survey.Select{
+		Message: fmt.Sprintf("Which '%s'?", name),
+		Options: labels,
+	}
+	if err := survey.AskOne(prompt, &choice); err != nil {
+		// Without a terminal there is no one to ask.
+		color.Red("❌ Several places match '%s'; see 'weather-advisor search %s'", name, name)
+		return nil, nil
+	}
+	return resp.Candidates[choice], resp.Sources
+}
+
+// lookupCity finds a known city by name, or else geocodes the name on the
+// server, so any place can be used.
+func lookupCity(name string) (string, cityInfo, []*advisorpb.DataSource, bool) {
+	if city, ok := resolveCity(name); ok {
+		return city, knownCities()[city], nil, true
+	}
+	c, sources := searchCity(strings.Join(strings.Fields(name), " "), "", "")
+	if c == nil {
+		return "", cityInfo{}, nil, false
+	}
+	return c.Name, cityInfo{c.Latitude, c.Longitude, c.CountryCode}, sources, true
+}
+
+// addCity geocodes name and saves the place to the config file.
+func addCity(name, state, country string) {
+	c, sources := searchCity(name, state, country)
+	if c == nil {
+		return
+	}
+	if _, exists := resolveCity(c.Name); exists {
+		color.Yellow("⚠️  '%s' is already a known city", c.Name)
+		return
+	}
+	userConfig.Cities = append(userConfig.Cities, savedCity{
+		Name:      c.Name,
+		Country:   c.CountryCode,
+		Latitude:  c.Latitude,
+		Longitude: c.Longitude,
+	})
+	path, err := saveConfig()
+	if err != nil {
+		color.Red("❌ Saving the config file failed: %v", err)
+		return
+	}
+	color.HiGreen("✅ Added %s, %s (%.4f, %.4f) to %s", c.Name, c.Country, c.Latitude, c.Longitude, path)
+	printSources(sources)
+}
+
+// removeCity deletes a city added with cities add. The built-in cities
+// can't be removed.
+func removeCity(name string) {
+	i := savedCityIndex(name)
+	if i < 0 {
+		if city, ok := resolveCity(name); ok {
+			color.Red("❌ '%s' is built in and can't be removed", city)
+		} else {
+			color.Red("❌ '%s' isn't one of your cities", name)
+		}
+		return
+	}
+	removed := userConfig.Cities[i].Name
+	userConfig.Cities = slices.Delete(userConfig.Cities, i, i+1)
+	path, err := saveConfig()
+	if err != nil {
+		color.Red("❌ Saving the config file failed: %v", err)
+		return
+	}
+	color.HiGreen("✅ Removed %s from %s", removed, path)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+func newCompareCmd() *cobra.Command {
+	var activity string
+	cmd := &cobra.Command{
+		Use:               "compare [cityA] [cityB]",
+		Short:             "Compare two cities' weather side by side and which suits an activity",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeCompare,
+		Run: func(cmd *cobra.Command, args []string) {
+			compareCities(args[0], args[1], activity)
+		},
+	}
+	cmd.Flags().StringVar(&activity, "activity", "running", "running, cycling, picnic or beach")
+	return cmd
+}
+
+// completeCompare completes the two cities.
+func completeCompare(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeCities(cmd, args, toComplete)
+}
+
+// compareCities prints the current weather of two places in two columns,
+// then their scores for the activity from RateActivity and the model's
+// comment on each. With --output json or yaml it prints both weather
+// responses and the rating, one document each.
+func compareCities(nameA, nameB, activity string) {
+	nameA, infoA, geoA, ok := lookupCity(nameA)
+	if !ok {
+		return
+	}
+	nameB, infoB, geoB, ok := lookupCity(nameB)
+	if !ok {
+		return
+	}
+	if strings.EqualFold(nameA, nameB) && infoA == infoB {
+		color.Red("❌ Name two different cities to compare")
+		return
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	weather := weatherpb.NewWeatherServiceClient(conn)
+	var current [2]*weatherpb.WeatherResponse
+	for i, info := range []cityInfo{infoA, infoB} {
+		current[i], err = weather.GetCurrentWeather(ctx, &weatherpb.WeatherRequest{
+			Latitude:    info.Lat,
+			Longitude:   info.Lon,
+			CountryCode: info.Country,
+			UnitSystem:  units.ParseSystem(unitSystem),
+		})
+		if err != nil {
+			color.Red("❌ Weather request for %s failed: %v", []string{nameA, nameB}[i], err)
+			return
+		}
+	}
+
+	// Coordinates are sent for both, so places found by search are rated
+	// where they were found.
+	cityData := func(name string, info cityInfo) *advisorpb.CityData {
+		return &advisorpb.CityData{Location: name, Country: info.Country, Latitude: proto.Float64(info.Lat), Longitude: proto.Float64(info.Lon)}
+	}
+	rating, err := advisorpb.NewAdvisorServiceClient(conn).RateActivity(ctx, &advisorpb.RateActivityRequest{
+		Activity:   activity,
+		Cities:     []*advisorpb.CityData{cityData(nameA, infoA), cityData(nameB, infoB)},
+		Explain:    true,
+		Model:      modelName,
+		UnitSystem: unitSystem,
+	})
+	if err != nil {
+		color.Red("❌ Rating failed: %v", err)
+		return
+	}
+	if machineOutput() {
+		printStreamMessage(current[0])
+		printStreamMessage(current[1])
+		printStreamMessage(rating)
+		return
+	}
+
+	a, b := current[0], current[1]
+	color.HiGreen("\n⚖️  %s vs %s", nameA, nameB)
+	color.Green(strings.Repeat("─", 60))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	row := func(label string, f func(r *weatherpb.WeatherResponse) string) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", label, f(a), f(b))
+	}
+	fmt.Fprintf(w, "\t%s\t%s\n", nameA, nameB)
+	row("Temperature", func(r *weatherpb.WeatherResponse) string {
+		return fmt.Sprintf("%.1f%s", r.Temperature, r.GetUnits().GetTemperature())
+	})
+	row("Feels like", func(r *weatherpb.WeatherResponse) string {
+		return fmt.Sprintf("%.1f%s", r.FeelsLike, r.GetUnits().GetTemperature())
+	})
+	row("Condition", func(r *weatherpb.WeatherResponse) string { return r.Description })
+	row("Humidity", func(r *weatherpb.WeatherResponse) string { return fmt.Sprintf("%d%%", r.Humidity) })
+	row("Wind", func(r *weatherpb.WeatherResponse) string {
+		return fmt.Sprintf("%.1f %s", r.WindSpeed, r.GetUnits().GetWindSpeed())
+	})
+	row("UV index", func(r *weatherpb.WeatherResponse) string {
+		if !r.IsDay {
+			return "- (night)"
+		}
+		return fmt.Sprintf("%.0f", r.UvIndex)
+	})
+	row("Pressure", func(r *weatherpb.WeatherResponse) string {
+		if r.GetUnits().GetPressure() == "inHg" {
+			return fmt.Sprintf("%.2f inHg", r.PressureValue)
+		}
+		return fmt.Sprintf("%d hPa", r.Pressure)
+	})
+	scores := map[string]*advisorpb.ActivityRating{}
+	for _, r := range rating.Ratings {
+		scores[r.Location] = r
+	}
+	score := func(name string) string {
+		if r := scores[name]; r != nil {
+			return fmt.Sprintf("%d/100", r.Score)
+		}
+		return "-"
+	}
+	fmt.Fprintf(w, "%s score\t%s\t%s\n", strings.ToUpper(rating.Activity[:1])+rating.Activity[1:], score(nameA), score(nameB))
+	w.Flush()
+	color.Green(strings.Repeat("─", 60))
+
+	for _, cityErr := range rating.Errors {
+		color.Yellow("⚠️  No %s score for %s (%s failed): %s", rating.Activity, cityErr.Location, cityErr.Stage, cityErr.Message)
+	}
+	ra, rb := scores[nameA], scores[nameB]
+	if ra != nil && rb != nil {
+		switch {
+		case ra.Score > rb.Score:
+			color.HiGreen("🏆 %s is better for %s (%d vs %d)", nameA, rating.Activity, ra.Score, rb.Score)
+		case rb.Score > ra.Score:
+			color.HiGreen("🏆 %s is better for %s (%d vs %d)", nameB, rating.Activity, rb.Score, ra.Score)
+		default:
+			color.HiGreen("🤝 Both are as good for %s (%d)", rating.Activity, ra.Score)
+		}
+	}
+	for _, r := range []*advisorpb.ActivityRating{ra, rb} {
+		if r == nil {
+			continue
+		}
+		// The model's comment when it answered, else the rules' reasons.
+		why := r.Comment
+		if why == "" {
+			why = strings.Join(r.Reasons, ", ")
+		}
+		fmt.Printf("  %s: %s\n", r.Location, why)
+	}
+	if u := rating.Usage; u != nil {
+		color.HiBlack("%s: %d prompt + %d response tokens (~$%.4f)", u.Model, u.PromptTokens, u.ResponseTokens, u.EstimatedCostUsd)
+	}
+	if p := a.GetProvider(); p != nil {
+		printSource(p.Name, p.Url, p.License, p.Attribution, p.Model, p.ModelRunTime)
+	}
+	printSources(append(geoA, geoB...))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Shell completion for city arguments. Cobra's completion command writes the
+// bash, zsh, fish and powershell scripts; they call back into the CLI for
+// the candidates, which come from the config file and the server catalog.
+
+// prepareCompletion loads the config with the flags typed so far on the
+// command line, such as --server, and keeps messages off stdout, which holds
+// the candidates. A catalog that can't be fetched then only costs its
+// cities.
+func prepareCompletion(cmd *cobra.Command) bool {
+	color.Output = os.Stderr
+	return applyConfig(cmd) == nil
+}
+
+// matchCities returns the names starting with toComplete, ignoring case,
+// that aren't in exclude.
+func matchCities(names []string, toComplete string, exclude []string) []string {
+	var out []string
+	for _, name := range names {
+		if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+			continue
+		}
+		if slices.ContainsFunc(exclude, func(e string) bool { return strings.EqualFold(e, name) }) {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+// completeCities completes commands whose arguments are all cities, leaving
+// out the ones already given.
+func completeCities(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !prepareCompletion(cmd) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return matchCities(cityOptions(), toComplete, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCity completes commands that take one city first.
+func completeCity(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeCities(cmd, args, toComplete)
+}
+
+// completeHook completes the city and then the script, from files.
+func completeHook(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	return completeCities(cmd, args, toComplete)
+}
+
+// completeRate completes the cities after the activity.
+func completeRate(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeCities(cmd, args[1:], toComplete)
+}
+
+// completeSavedCity completes cities remove from the cities in the config
+// file, without asking the server.
+func completeSavedCity(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || !prepareCompletion(cmd) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(userConfig.Cities))
+	for _, c := range userConfig.Cities {
+		names = append(names, c.Name)
+	}
+	return matchCities(names, toComplete, nil), cobra.ShellCompDirectiveNoFileComp
+}
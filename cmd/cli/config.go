@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// cliConfig is the user's config file. Every setting is optional, and the
+// matching flag wins when both are given.
+type cliConfig struct {
+	// Server is the advisor's gRPC address, host:port.
+	Server string `yaml:"server,omitempty"`
+
+	// TLS, CA, Cert and Key are the --tls, --ca-cert, --cert and --key
+	// flags.
+	TLS  bool   `yaml:"tls,omitempty"`
+	CA   string `yaml:"ca,omitempty"`
+	Cert string `yaml:"cert,omitempty"`
+	Key  string `yaml:"key,omitempty"`
+
+	// Token and APIKey are the --token and --api-key credentials. Keeping
+	// them here rather than on the command line keeps them out of the
+	// shell history; the file is written readable only by its owner.
+	Token  string `yaml:"token,omitempty"`
+	APIKey string `yaml:"api_key,omitempty"`
+
+	// Units is the unit system asked of the server: metric, imperial, uk
+	// or si. Empty lets the server pick by country.
+	Units string `yaml:"units,omitempty"`
+
+	// Language is the --lang default: the language advice is written in,
+	// such as de or pt-BR.
+	Language string `yaml:"language,omitempty"`
+
+	// Favorites are the cities advice and stream use when given none, and
+	// that the interactive menus list first.
+	Favorites []string `yaml:"favorites,omitempty"`
+
+	// Cities are the user's own cities, added with cities add.
+	Cities []savedCity `yaml:"cities,omitempty"`
+}
+
+// savedCity is a city the server geocoded for cities add. Its coordinates
+// are sent with every request, so the server doesn't geocode it again.
+type savedCity struct {
+	Name      string  `yaml:"name"`
+	Country   string  `yaml:"country"`
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+}
+
+const (
+	// defaultServerAddr is where the server listens for gRPC by default.
+	defaultServerAddr = "localhost:8082"
+
+	// serverEnv names the environment variable --server falls back to.
+	serverEnv = "WEATHER_ADVISOR_SERVER"
+)
+
+var (
+	// configFile is the path given with --config; empty means the default.
+	configFile string
+
+	// userConfig is the loaded config file, or the zero config when there
+	// is none.
+	userConfig cliConfig
+
+	// unitSystem is sent with weather and advice requests: --units, or
+	// units from the config file.
+	unitSystem string
+)
+
+// defaultConfigPath is $XDG_CONFIG_HOME/weather-advisor/config.yaml,
+// falling back to ~/.config.
+func defaultConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "weather-advisor", "config.yaml"), nil
+}
+
+// configPath is the --config file, or the default one.
+func configPath() (string, error) {
+	if configFile != "" {
+		return configFile, nil
+	}
+	return defaultConfigPath()
+}
+
+// loadConfig reads the config file. A missing default file is not an
+// error; a missing --config file is.
+func loadConfig() error {
+	userConfig = cliConfig{}
+	path, err := configPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && configFile == "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config file: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &userConfig); err != nil {
+		return fmt.Errorf("config file %s: %v", path, err)
+	}
+	if !validUnits(userConfig.Units) {
+		return fmt.Errorf("config file %s: unknown units %q (want metric, imperial, uk or si)", path, userConfig.Units)
+	}
+	for _, c := range userConfig.Cities {
+		if strings.TrimSpace(c.Name) == "" || c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+			return fmt.Errorf("config file %s: city %q needs a name, a latitude and a longitude", path, c.Name)
+		}
+	}
+	return nil
+}
+
+// saveConfig writes userConfig to the config file, creating it if need
+// be. Comments in the file are not kept.
+func saveConfig() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(&userConfig)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0o600)
+}
+
+// applyConfig loads the config file and fills in every setting whose flag
+// wasn't given.
+func applyConfig(cmd *cobra.Command) error {
+	if err := loadConfig(); err != nil {
+		// A broken config file isn't a usage mistake.
+		cmd.SilenceUsage = true
+		return err
+	}
+	flags := cmd.Flags()
+	if !flags.Changed("server") {
+		if env := os.Getenv(serverEnv); env != "" {
+			serverAddr = env
+		} else if userConfig.Server != "" {
+			serverAddr = userConfig.Server
+		} else {
+			serverAddr = defaultServerAddr
+		}
+	}
+	if !flags.Changed("tls") {
+		useTLS = userConfig.TLS
+	}
+	if !flags.Changed("ca-cert") && !flags.Changed("ca") {
+		caFile = userConfig.CA
+	}
+	if !flags.Changed("cert") {
+		certFile = userConfig.Cert
+	}
+	if !flags.Changed("key") {
+		keyFile = userConfig.Key
+	}
+	if !flags.Changed("token") {
+		authToken = userConfig.Token
+	}
+	if !flags.Changed("api-key") {
+		apiKey = userConfig.APIKey
+	}
+	if !flags.Changed("units") {
+		unitSystem = userConfig.Units
+	}
+	if !flags.Changed("lang") {
+		adviceLanguage = userConfig.Language
+	}
+	if !validUnits(unitSystem) {
+		return fmt.Errorf("unknown --units %q (want metric, imperial, uk or si)", unitSystem)
+	}
+	unitSystem = strings.ToLower(unitSystem)
+	return nil
+}
+
+// validUnits reports whether the server knows the unit system. Empty
+// means the server picks by country.
+func validUnits(system string) bool {
+	switch strings.ToLower(system) {
+	case "", "metric", "imperial", "uk", "si":
+		return true
+	}
+	return false
+}
+
+// savedCityIndex returns where name is in userConfig.Cities, or -1.
+func savedCityIndex(name string) int {
+	return slices.IndexFunc(userConfig.Cities, func(c savedCity) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+}
+
+// newCityData describes a known city for a request. The user's own cities
+// carry their coordinates; the built-in ones are geocoded by the server.
+func newCityData(city string) *advisorpb.CityData {
+	i := savedCityIndex(city)
+	if i < 0 {
+		return &advisorpb.CityData{Location: city}
+	}
+	c := userConfig.Cities[i]
+	return &advisorpb.CityData{
+		Location:  c.Name,
+		Country:   c.Country,
+		Latitude:  proto.Float64(c.Latitude),
+		Longitude: proto.Float64(c.Longitude),
+	}
+}
+
+// favoriteCities returns the configured favorites that are known cities.
+func favoriteCities() []string {
+	var cities []string
+	for _, name := range userConfig.Favorites {
+		if city, ok := resolveCity(name); ok {
+			cities = append(cities, city)
+		}
+	}
+	return cities
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/requestid"
+	"github.com/pixperk/effinarounf/services/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// useTLS connects over TLS, verifying the server against the system
+	// roots unless caFile is set.
+	useTLS bool
+
+	// caFile is a PEM bundle of CAs to trust for the server certificate,
+	// from --ca-cert. Setting it implies --tls.
+	caFile string
+
+	// certFile and keyFile are the client certificate presented to servers
+	// that require mutual TLS. Setting them implies --tls.
+	certFile, keyFile string
+
+	// authToken is sent as a bearer token, for servers that check JWTs.
+	authToken string
+
+	// apiKey is sent in x-api-key, naming the caller's tenant on shared
+	// servers.
+	apiKey string
+
+	// keepaliveTime is how long the connection may sit idle before the
+	// CLI pings the server, so a quiet advice stream isn't dropped by a
+	// load balancer in between. Zero disables pings.
+	keepaliveTime time.Duration
+
+	// compress gzips requests and asks the server to gzip its responses,
+	// which mostly helps streamed advice over slow links.
+	compress bool
+)
+
+// dial connects to serverAddr, over TLS when --tls, --ca-cert or --cert is
+// given. Every call carries a fresh x-request-id, which the server logs and
+// quotes in its error messages, and the --token and --api-key credentials.
+func dial() (*grpc.ClientConn, error) {
+	creds, err := transportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withCallMetadata(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withCallMetadata(ctx), desc, cc, method, opts...)
+		}),
+	}
+	if compress {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+	if keepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    keepaliveTime,
+			Timeout: 20 * time.Second,
+		}))
+	}
+	return grpc.Dial(serverAddr, opts...)
+}
+
+func withCallMetadata(ctx context.Context) context.Context {
+	kv := []string{requestid.Header, requestid.New()}
+	if authToken != "" {
+		kv = append(kv, "authorization", "Bearer "+authToken)
+	}
+	if apiKey != "" {
+		kv = append(kv, tenant.APIKeyHeader, apiKey)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !useTLS && caFile == "" && certFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("--cert and --key must be given together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	return credentials.NewTLS(cfg), nil
+}
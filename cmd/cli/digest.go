@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+func newDigestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Manage daily advice digests generated by the server",
+	}
+
+	var at, timeZone, recipient string
+	var hooks webhookFlags
+	add := &cobra.Command{
+		Use:               "add [cities...]",
+		Short:             "Get a daily digest for cities at a local time",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeCities,
+		Run: func(cmd *cobra.Command, args []string) {
+			addDigest(args, at, timeZone, recipient, hooks.proto())
+		},
+	}
+	add.Flags().StringVar(&at, "at", "07:00", "Local delivery time, HH:MM")
+	add.Flags().StringVar(&timeZone, "tz", time.Local.String(), "IANA time zone for --at")
+	add.Flags().StringVar(&recipient, "recipient", "", "Who the digest is for")
+	hooks.register(add)
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List digest subscriptions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listDigests()
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:   "remove [id]",
+		Short: "Stop a digest",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			removeDigest(args[0])
+		},
+	}
+
+	cmd.AddCommand(add, list, remove)
+	return cmd
+}
+
+// withAdvisor dials the server and calls fn with a client and a 10 second
+// context.
+func withAdvisor(fn func(ctx context.Context, client advisorpb.AdvisorServiceClient)) {
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fn(ctx, advisorpb.NewAdvisorServiceClient(conn))
+}
+
+// webhookFlags are the --slack, --discord and --webhook flags of
+// subscriptions.
+type webhookFlags struct {
+	slack, discord, generic []string
+}
+
+func (w *webhookFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&w.slack, "slack", nil, "Also post to this Slack incoming webhook URL (repeatable)")
+	cmd.Flags().StringArrayVar(&w.discord, "discord", nil, "Also post to this Discord webhook URL (repeatable)")
+	cmd.Flags().StringArrayVar(&w.generic, "webhook", nil, "Also post signed JSON events to this URL (repeatable)")
+}
+
+func (w *webhookFlags) proto() []*advisorpb.Webhook {
+	var out []*advisorpb.Webhook
+	for _, url := range w.slack {
+		out = append(out, &advisorpb.Webhook{Kind: advisorpb.WebhookKind_WEBHOOK_KIND_SLACK, Url: url})
+	}
+	for _, url := range w.discord {
+		out = append(out, &advisorpb.Webhook{Kind: advisorpb.WebhookKind_WEBHOOK_KIND_DISCORD, Url: url})
+	}
+	for _, url := range w.generic {
+		out = append(out, &advisorpb.Webhook{Kind: advisorpb.WebhookKind_WEBHOOK_KIND_GENERIC, Url: url})
+	}
+	return out
+}
+
+func printWebhooks(webhooks []*advisorpb.Webhook) {
+	for _, w := range webhooks {
+		kind := strings.ToLower(strings.TrimPrefix(w.Kind.String(), "WEBHOOK_KIND_"))
+		color.HiBlack("  %s: %s", kind, w.Url)
+		// Only the response creating the subscription has the secret.
+		if w.Secret != "" {
+			color.Yellow("    signing secret (shown once): %s", w.Secret)
+		}
+	}
+}
+
+func addDigest(cities []string, at, timeZone, recipient string, webhooks []*advisorpb.Webhook) {
+	cities, ok := resolveCities(cities)
+	if !ok {
+		return
+	}
+	var cityData []*advisorpb.CityData
+	for _, city := range cities {
+		cityData = append(cityData, newCityData(city))
+	}
+	// "Local" isn't an IANA name the server can load.
+	if timeZone == "Local" {
+		timeZone = ""
+	}
+
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		sub, err := client.CreateDigest(ctx, &advisorpb.CreateDigestRequest{Subscription: &advisorpb.DigestSubscription{
+			Recipient:    recipient,
+			Request:      &advisorpb.AdvisorRequest{Cities: cityData, BestEffort: true, Model: modelName, UnitSystem: unitSystem},
+			DeliveryTime: at,
+			TimeZone:     timeZone,
+			Webhooks:     webhooks,
+		}})
+		if err != nil {
+			color.Red("❌ Digest failed: %v", err)
+			return
+		}
+		color.HiGreen("✅ Digest %s created", sub.Id)
+		printDigest(sub)
+	})
+}
+
+func listDigests() {
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		resp, err := client.ListDigests(ctx, &advisorpb.ListDigestsRequest{})
+		if err != nil {
+			color.Red("❌ Listing digests failed: %v", err)
+			return
+		}
+		if len(resp.Subscriptions) == 0 {
+			color.Yellow("No digests")
+			return
+		}
+		for _, sub := range resp.Subscriptions {
+			color.HiCyan("\n%s", sub.Id)
+			printDigest(sub)
+		}
+	})
+}
+
+func printDigest(sub *advisorpb.DigestSubscription) {
+	var cities []string
+	for _, c := range sub.Request.GetCities() {
+		cities = append(cities, c.Location)
+	}
+	zone := sub.TimeZone
+	if zone == "" {
+		zone = "UTC"
+	}
+	fmt.Printf("  %v daily at %s %s", cities, sub.DeliveryTime, zone)
+	if sub.Recipient != "" {
+		fmt.Printf(" for %s", sub.Recipient)
+	}
+	fmt.Println()
+	if sub.NextDelivery > 0 {
+		color.HiBlack("  next: %s", time.Unix(sub.NextDelivery, 0).Format("Mon 2 Jan 15:04 MST"))
+	}
+	printWebhooks(sub.Webhooks)
+}
+
+func removeDigest(id string) {
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		if _, err := client.DeleteDigest(ctx, &advisorpb.DeleteDigestRequest{Id: id}); err != nil {
+			color.Red("❌ Removing digest failed: %v", err)
+			return
+		}
+		color.HiGreen("✅ Digest %s removed", id)
+	})
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+// maxForecastDays is how far ahead Open-Meteo forecasts.
+const maxForecastDays = 16
+
+func newForecastCmd() *cobra.Command {
+	var days int
+	var chart bool
+	cmd := &cobra.Command{
+		Use:               "forecast [city]",
+		Short:             "Show the daily forecast for a city as a table",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCity,
+		Run: func(cmd *cobra.Command, args []string) {
+			showForecast(args[0], days, chart)
+		},
+	}
+	cmd.Flags().IntVar(&days, "days", 7, fmt.Sprintf("Number of days, at most %d", maxForecastDays))
+	cmd.Flags().BoolVar(&chart, "chart", false, "Also chart each day's hourly temperature and chance of rain")
+	return cmd
+}
+
+func showForecast(cityName string, days int, chart bool) {
+	if days < 1 || days > maxForecastDays {
+		color.Red("❌ --days must be between 1 and %d", maxForecastDays)
+		return
+	}
+	cityName, info, geocoding, ok := lookupCity(cityName)
+	if !ok {
+		return
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The forecast starts on the location's today, which may be a day
+	// either side of ours; ask for one day extra and keep the first days.
+	resp, err := weatherpb.NewWeatherServiceClient(conn).GetDailyForecast(ctx, &weatherpb.DailyForecastRequest{
+		Latitude:  info.Lat,
+		Longitude: info.Lon,
+		EndDate:   time.Now().AddDate(0, 0, days).Format(time.DateOnly),
+	})
+	if err != nil {
+		color.Red("❌ Forecast request failed: %v", err)
+		return
+	}
+	if len(resp.Days) > days {
+		resp.Days = resp.Days[:days]
+	}
+	if machineOutput() {
+		printResult(resp)
+		return
+	}
+
+	conv := units.Resolve(info.Country, units.ParseSystem(unitSystem))
+	precip := func(mm float64) string { return fmt.Sprintf("%.1f mm", mm) }
+	if conv.System == weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL {
+		precip = func(mm float64) string { return fmt.Sprintf("%.2f in", mm/25.4) }
+	}
+
+	color.HiGreen("\n📅 %d-day forecast for %s", len(resp.Days), cityName)
+	color.Green(strings.Repeat("─", 72))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Date\tHigh\tLow\tRain\tPrecip\tWind\tConditions\n")
+	for _, d := range resp.Days {
+		date, _ := time.Parse(time.DateOnly, d.Date)
+		fmt.Fprintf(w, "%s\t%.0f%s\t%.0f%s\t%d%%\t%s\t%.0f %s\t%s\n",
+			date.Format("Mon Jan 2"),
+			conv.Temp(d.TempMax), conv.Temperature,
+			conv.Temp(d.TempMin), conv.Temperature,
+			d.PrecipitationProbability,
+			precip(d.PrecipitationSum),
+			conv.Wind(d.WindSpeedMax), conv.WindSpeed,
+			d.Description)
+	}
+	w.Flush()
+	color.Green(strings.Repeat("─", 72))
+	if chart {
+		showHourlyChart(conn, info, days, conv)
+	}
+	if p := resp.GetProvider(); p != nil {
+		printSource(p.Name, p.Url, p.License, p.Attribution, p.Model, p.ModelRunTime)
+	}
+	printSources(geocoding)
+}
+
+// sparks are the bar heights of a sparkline, lowest first.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// spark picks the bar for v on a scale from lo to hi.
+func spark(v, lo, hi float64) rune {
+	if hi <= lo {
+		return sparks[0]
+	}
+	i := int((v-lo)/(hi-lo)*float64(len(sparks)-1) + 0.5)
+	return sparks[max(0, min(i, len(sparks)-1))]
+}
+
+// showHourlyChart draws one row per local day: a sparkline of the hourly
+// temperature, on one scale for the whole forecast so the days compare, and
+// bars for the chance of rain. Each column is one hour from midnight; hours
+// already past today are blank.
+func showHourlyChart(conn *grpc.ClientConn, info cityInfo, days int, conv units.Conventions) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := weatherpb.NewWeatherServiceClient(conn).GetHourlyForecast(ctx, &weatherpb.HourlyForecastRequest{
+		Latitude:  info.Lat,
+		Longitude: info.Lon,
+		// Today is partly over, so the last day needs hours from one more.
+		Hours: int32(min((days+1)*24, maxForecastDays*24)),
+	})
+	if err != nil {
+		color.Red("❌ Hourly forecast request failed: %v", err)
+		return
+	}
+
+	loc := time.FixedZone("local", int(resp.UtcOffsetSeconds))
+	lo, hi := conv.Temp(resp.Hours[0].Temperature), conv.Temp(resp.Hours[0].Temperature)
+	for _, h := range resp.Hours {
+		lo, hi = min(lo, conv.Temp(h.Temperature)), max(hi, conv.Temp(h.Temperature))
+	}
+
+	type dayRow struct {
+		date         string
+		temp, rain   [24]rune
+		dayLo, dayHi float64
+	}
+	var rows []*dayRow
+	for _, h := range resp.Hours {
+		t := time.Unix(h.Time, 0).In(loc)
+		date := t.Format("Mon Jan 2")
+		if len(rows) == 0 || rows[len(rows)-1].date != date {
+			if len(rows) == days {
+				break
+			}
+			row := &dayRow{date: date, dayLo: conv.Temp(h.Temperature), dayHi: conv.Temp(h.Temperature)}
+			for i := range 24 {
+				row.temp[i], row.rain[i] = ' ', ' '
+			}
+			rows = append(rows, row)
+		}
+		row := rows[len(rows)-1]
+		temp := conv.Temp(h.Temperature)
+		row.dayLo, row.dayHi = min(row.dayLo, temp), max(row.dayHi, temp)
+		row.temp[t.Hour()] = spark(temp, lo, hi)
+		if h.PrecipitationProbability > 0 {
+			row.rain[t.Hour()] = spark(float64(h.PrecipitationProbability), 0, 100)
+		}
+	}
+
+	color.HiGreen("\n📈 Hourly, midnight to midnight: temperature %.0f–%.0f%s and chance of rain", lo, hi, conv.Temperature)
+	color.Green(strings.Repeat("─", 72))
+	for _, row := range rows {
+		fmt.Printf("%-10s  %s  %3.0f–%-3.0f  %s\n", row.date, string(row.temp[:]), row.dayLo, row.dayHi, color.CyanString(string(row.rain[:])))
+	}
+	color.Green(strings.Repeat("─", 72))
+}
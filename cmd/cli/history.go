@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func newHistoryCmd() *cobra.Command {
+	var limit int
+	var local bool
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List advice generated earlier, from the server or this machine's cache",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			listHistory(limit, local)
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "Show at most this many, newest first")
+	cmd.Flags().BoolVar(&local, "local", false, "Only list advice cached on this machine")
+
+	show := &cobra.Command{
+		Use:   "show [id]",
+		Short: "Show one piece of advice from the history",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			showHistory(args[0])
+		},
+	}
+
+	cmd.AddCommand(show)
+	return cmd
+}
+
+// listHistory lists the server's advice history. When the server can't be
+// reached or keeps no history, it lists the advice in the local cache
+// instead; those IDs are shorter and only work on this machine.
+func listHistory(limit int, local bool) {
+	if limit < 1 {
+		color.Red("❌ --limit must be at least 1")
+		return
+	}
+	if local {
+		printHistory(localHistory(limit), "No advice cached on this machine")
+		return
+	}
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		resp, err := client.ListAdviceHistory(ctx, &advisorpb.ListAdviceHistoryRequest{
+			Page: &advisorpb.PageRequest{PageSize: int32(limit), NewestFirst: true},
+		})
+		if err != nil {
+			if !unreachable(err) {
+				color.Red("❌ Listing history failed: %v", err)
+				return
+			}
+			color.Yellow("⚠️  Server history unavailable: %s", status.Convert(err).Message())
+			color.Yellow("⚠️  Listing advice cached on this machine instead")
+			resp = localHistory(limit)
+		}
+		printHistory(resp, "No advice yet")
+	})
+}
+
+func printHistory(resp *advisorpb.ListAdviceHistoryResponse, empty string) {
+	if machineOutput() {
+		printResult(resp)
+		return
+	}
+	if len(resp.Records) == 0 {
+		color.Yellow("%s", empty)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tCreated\tCities\tModel\n")
+	for _, rec := range resp.Records {
+		model := rec.Model
+		if model == "" {
+			model = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rec.Id, time.Unix(rec.CreatedAt, 0).Format("Mon Jan 2 15:04"), strings.Join(rec.Cities, ", "), model)
+	}
+	w.Flush()
+	if total := resp.GetPage().GetTotalSize(); int(total) > len(resp.Records) {
+		color.HiBlack("%d of %d; see --limit", len(resp.Records), total)
+	}
+}
+
+// localHistory returns the newest advice in the local cache as records.
+func localHistory(limit int) *advisorpb.ListAdviceHistoryResponse {
+	ids := cachedIDs("advice")
+	resp := &advisorpb.ListAdviceHistoryResponse{Page: &advisorpb.PageResponse{TotalSize: int32(len(ids))}}
+	for _, id := range ids[:min(limit, len(ids))] {
+		if rec, ok := localAdviceRecord(id); ok {
+			resp.Records = append(resp.Records, rec)
+		}
+	}
+	return resp
+}
+
+// localAdviceRecord reads cached advice as a history record.
+func localAdviceRecord(id string) (*advisorpb.AdviceRecord, bool) {
+	req := &advisorpb.AdvisorRequest{}
+	resp := &advisorpb.AdvisorResponse{}
+	savedAt, ok := loadCachedID("advice", id, req, resp)
+	if !ok {
+		return nil, false
+	}
+	rec := &advisorpb.AdviceRecord{
+		Id:        id,
+		SessionId: resp.SessionId,
+		Advice:    resp.Advice,
+		Model:     resp.GetUsage().GetModel(),
+		CreatedAt: savedAt.Unix(),
+	}
+	for _, c := range req.Cities {
+		rec.Cities = append(rec.Cities, c.Location)
+	}
+	return rec, true
+}
+
+// showHistory prints one record, from the local cache when the ID is one
+// of its own and from the server otherwise.
+func showHistory(id string) {
+	if rec, ok := localAdviceRecord(id); ok {
+		printRecord(rec)
+		return
+	}
+	withAdvisor(func(ctx context.Context, client advisorpb.AdvisorServiceClient) {
+		rec, err := client.GetAdviceRecord(ctx, &advisorpb.GetAdviceRecordRequest{Id: id})
+		if err != nil {
+			color.Red("❌ Getting advice %s failed: %v", id, err)
+			return
+		}
+		printRecord(rec)
+	})
+}
+
+func printRecord(rec *advisorpb.AdviceRecord) {
+	if machineOutput() {
+		printResult(rec)
+		return
+	}
+	color.HiCyan("\n%s", rec.Id)
+	fmt.Printf("  %s, %s\n", strings.Join(rec.Cities, ", "), time.Unix(rec.CreatedAt, 0).Format("Mon 2 Jan 2006 15:04 MST"))
+	if rec.Model != "" {
+		color.HiBlack("  model: %s", rec.Model)
+	}
+	for _, line := range rec.Weather {
+		color.HiBlack("  %s", line)
+	}
+	color.Green(strings.Repeat("═", 60))
+	fmt.Println(rec.Advice)
+	color.Green(strings.Repeat("═", 60))
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/spf13/cobra"
+)
+
+// hookOptions controls when the hook script runs.
+type hookOptions struct {
+	// every is the polling interval; zero runs the script once and exits.
+	every time.Duration
+	// onChange skips runs where the conditions haven't changed.
+	onChange bool
+	// tempDelta is how far the temperature must move to count as a change.
+	tempDelta float64
+}
+
+func newHookCmd() *cobra.Command {
+	var opts hookOptions
+	cmd := &cobra.Command{
+		Use:   "hook [city] [script] [args...]",
+		Short: "Run a script with the current weather as WEATHER_* environment variables",
+		Long: `Run a script with the current conditions exported as environment
+variables (WEATHER_CITY, WEATHER_TEMPERATURE, WEATHER_CODE, ...), once or
+every --every interval. With --on-change the script only runs when the
+weather code changes or the temperature moves by --temp-delta.`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeHook,
+		Run: func(cmd *cobra.Command, args []string) {
+			runHook(args[0], args[1], args[2:], opts)
+		},
+	}
+	cmd.Flags().DurationVar(&opts.every, "every", 0, "Poll interval, e.g. 15m (run once if unset)")
+	cmd.Flags().BoolVar(&opts.onChange, "on-change", false, "Only run the script when conditions change")
+	cmd.Flags().Float64Var(&opts.tempDelta, "temp-delta", 2, "Temperature change that counts as a condition change")
+	return cmd
+}
+
+func runHook(cityName, script string, scriptArgs []string, opts hookOptions) {
+	if opts.every != 0 && opts.every < time.Minute {
+		color.Red("❌ --every must be at least 1m")
+		return
+	}
+	cityName, info, _, ok := lookupCity(cityName)
+	if !ok {
+		return
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+	client := weatherpb.NewWeatherServiceClient(conn)
+	req := &weatherpb.WeatherRequest{
+		Latitude:    info.Lat,
+		Longitude:   info.Lon,
+		CountryCode: info.Country,
+		UnitSystem:  units.ParseSystem(unitSystem),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var last *weatherpb.WeatherResponse
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		resp, err := client.GetCurrentWeather(fetchCtx, req)
+		cancel()
+		switch {
+		case err != nil:
+			color.Red("Weather request failed: %v", err)
+		case last == nil:
+			runHookScript(ctx, script, scriptArgs, cityName, resp, "initial")
+			last = resp
+		case conditionsChanged(last, resp, opts.tempDelta):
+			runHookScript(ctx, script, scriptArgs, cityName, resp, "change")
+			last = resp
+		case !opts.onChange:
+			runHookScript(ctx, script, scriptArgs, cityName, resp, "schedule")
+			last = resp
+		}
+
+		if opts.every == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(opts.every):
+		}
+	}
+}
+
+// conditionsChanged reports whether the sky changed or the temperature moved
+// by at least delta since the last run.
+func conditionsChanged(prev, cur *weatherpb.WeatherResponse, delta float64) bool {
+	return prev.WeatherCode != cur.WeatherCode ||
+		math.Abs(cur.Temperature-prev.Temperature) >= delta
+}
+
+func runHookScript(ctx context.Context, script string, args []string, city string, w *weatherpb.WeatherResponse, event string) {
+	cmd := exec.CommandContext(ctx, script, args...)
+	cmd.Env = append(os.Environ(), hookEnv(city, w, event)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	color.HiBlack("▶ %s (%s: %s, %.1f%s)", script, event, w.Description, w.Temperature, w.GetUnits().GetTemperature())
+	if err := cmd.Run(); err != nil {
+		color.Red("❌ Hook script failed: %v", err)
+	}
+}
+
+// hookEnv lists the variables passed to hook scripts. Values are in the
+// city's local units, named by the *_UNIT variables.
+func hookEnv(city string, w *weatherpb.WeatherResponse, event string) []string {
+	u := w.GetUnits()
+	return []string{
+		"WEATHER_EVENT=" + event,
+		"WEATHER_CITY=" + city,
+		"WEATHER_DESCRIPTION=" + w.Description,
+		"WEATHER_CODE=" + strconv.Itoa(int(w.WeatherCode)),
+		fmt.Sprintf("WEATHER_TEMPERATURE=%.1f", w.Temperature),
+		fmt.Sprintf("WEATHER_FEELS_LIKE=%.1f", w.FeelsLike),
+		"WEATHER_TEMPERATURE_UNIT=" + u.GetTemperature(),
+		"WEATHER_HUMIDITY=" + strconv.Itoa(int(w.Humidity)),
+		fmt.Sprintf("WEATHER_WIND_SPEED=%.1f", w.WindSpeed),
+		"WEATHER_WIND_UNIT=" + u.GetWindSpeed(),
+		"WEATHER_WIND_DEG=" + strconv.Itoa(int(w.WindDeg)),
+		"WEATHER_PRESSURE_HPA=" + strconv.Itoa(int(w.Pressure)),
+		"WEATHER_TIMESTAMP=" + strconv.FormatInt(w.Timestamp, 10),
+	}
+}
@@ -1,44 +1,120 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/fatih/color"
+	"github.com/pixperk/effinarounf/services/units"
 	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
 	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
-	serverAddr = "localhost:8082"
-
-	// Available cities with their coordinates
-	availableCities = map[string][2]float64{
-		"New York":      {40.7128, -74.0060},
-		"London":        {51.5074, -0.1278},
-		"Tokyo":         {35.6762, 139.6503},
-		"Paris":         {48.8566, 2.3522},
-		"Los Angeles":   {34.0522, -118.2437},
-		"Chicago":       {41.8781, -87.6298},
-		"Sydney":        {-33.8688, 151.2093},
-		"Berlin":        {52.5200, 13.4050},
-		"Toronto":       {43.6532, -79.3832},
-		"Mumbai":        {19.0760, 72.8777},
-		"Dubai":         {25.2048, 55.2708},
-		"Singapore":     {1.3521, 103.8198},
-		"San Francisco": {37.7749, -122.4194},
-		"Miami":         {25.7617, -80.1918},
-		"Barcelona":     {41.3851, 2.1734},
-	}
+	// serverAddr is the advisor's gRPC address: --server, else
+	// WEATHER_ADVISOR_SERVER, else the config file, else the server's
+	// default port on this machine.
+	serverAddr = defaultServerAddr
+
+	// modelName overrides the server's default Gemini model when set.
+	modelName string
+
+	// verbose adds provider details to the attribution lines.
+	verbose bool
+
+	// primaryCity is the city the advice should lead with.
+	primaryCity string
+
+	// outputFormat is the advice format asked of the server: markdown,
+	// plain or html.
+	outputFormat string
+
+	// adviceCategory is the topic advice should focus on: all, travel,
+	// clothing, sports or health.
+	adviceCategory string
+
+	// adviceLanguage is the language advice is written in, a tag such as
+	// de or pt-BR: --lang, or language from the config file.
+	adviceLanguage string
+
+	// sitesFile is a GeoJSON or KML file of sites to use instead of city
+	// arguments.
+	sitesFile string
+
+	// profile describes the user so the advice can be personal. It is only
+	// sent when a profile flag is set.
+	profile = &advisorpb.UserProfile{}
+)
+
+type cityInfo struct {
+	Lat     float64
+	Lon     float64
+	Country string
+}
+
+var (
+	// availableCities is the server's city catalog plus the user's own
+	// cities, by name. It is filled on first use by knownCities.
+	availableCities = map[string]cityInfo{}
+	catalogOnce     sync.Once
 )
 
+// knownCities returns the cities the CLI can name: the server's catalog,
+// fetched once with ListCities, and the cities saved with cities add. When
+// the catalog can't be fetched only the saved cities are known.
+func knownCities() map[string]cityInfo {
+	catalogOnce.Do(func() {
+		for _, c := range fetchCatalog() {
+			availableCities[c.Name] = cityInfo{c.Latitude, c.Longitude, c.CountryCode}
+		}
+		for _, c := range userConfig.Cities {
+			availableCities[c.Name] = cityInfo{c.Latitude, c.Longitude, c.Country}
+		}
+	})
+	return availableCities
+}
+
+func fetchCatalog() []*advisorpb.LocationCandidate {
+	conn, err := dial()
+	if err != nil {
+		color.Yellow("⚠️  Could not fetch the city list: %v", err)
+		return nil
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req := &advisorpb.ListCitiesRequest{}
+	resp, err := advisorpb.NewAdvisorServiceClient(conn).ListCities(ctx, req)
+	if err != nil {
+		// The catalog rarely changes, so an old copy names cities as well;
+		// whatever is then asked for them warns that it is stale.
+		var cached advisorpb.ListCitiesResponse
+		if _, ok := loadCached("cities", req, &cached); ok && unreachable(err) {
+			return cached.Cities
+		}
+		color.Yellow("⚠️  Could not fetch the city list: %v", err)
+		return nil
+	}
+	saveCached("cities", req, resp)
+	return resp.Cities
+}
+
 func main() {
 	var rootCmd = &cobra.Command{
 		Use:   "weather-advisor",
@@ -50,47 +126,100 @@ func main() {
 │       Get AI-powered weather advice for your cities        │
 └─────────────────────────────────────────────────────────────┘
 		`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(); err != nil {
+				return err
+			}
+			return applyConfig(cmd)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			runInteractiveCLI()
 		},
 	}
 
-	var listCmd = &cobra.Command{
-		Use:   "cities",
-		Short: "List all available cities",
+	var watch time.Duration
+	var weatherCmd = &cobra.Command{
+		Use:               "weather [city]",
+		Short:             "Get weather for a specific city",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeCity,
 		Run: func(cmd *cobra.Command, args []string) {
-			listCities()
+			getWeather(args[0], watch)
 		},
 	}
+	weatherCmd.Flags().DurationVar(&watch, "watch", 0, "Keep the report on screen, refreshed this often (at least 15s)")
 
-	var weatherCmd = &cobra.Command{
-		Use:   "weather [city]",
-		Short: "Get weather for a specific city",
-		Args:  cobra.ExactArgs(1),
+	var adviceCmd = &cobra.Command{
+		Use:               "advice [cities...]",
+		Short:             "Get AI advice for cities",
+		Args:              citiesOrFile,
+		ValidArgsFunction: completeCities,
 		Run: func(cmd *cobra.Command, args []string) {
-			getWeather(args[0])
+			if sitesFile != "" {
+				getSiteAdvice(sitesFile, false)
+				return
+			}
+			getAdvice(args, false)
 		},
 	}
 
-	var adviceCmd = &cobra.Command{
-		Use:   "advice [cities...]",
-		Short: "Get AI advice for cities",
-		Args:  cobra.MinimumNArgs(1),
+	var chatCmd = &cobra.Command{
+		Use:               "chat [cities...]",
+		Short:             "Chat with the advisor about cities' weather",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeCities,
 		Run: func(cmd *cobra.Command, args []string) {
-			getAdvice(args, false)
+			runChat(args)
 		},
 	}
 
 	var streamCmd = &cobra.Command{
-		Use:   "stream [cities...]",
-		Short: "Get streaming AI advice for cities",
-		Args:  cobra.MinimumNArgs(1),
+		Use:               "stream [cities...]",
+		Short:             "Get streaming AI advice for cities",
+		Args:              citiesOrFile,
+		ValidArgsFunction: completeCities,
 		Run: func(cmd *cobra.Command, args []string) {
+			if sitesFile != "" {
+				getSiteAdvice(sitesFile, true)
+				return
+			}
 			getAdvice(args, true)
 		},
 	}
+	for _, cmd := range []*cobra.Command{adviceCmd, streamCmd} {
+		cmd.Flags().StringVar(&sitesFile, "from-file", "", "Read sites from a GeoJSON or KML file of points instead of arguments")
+		cmd.Flags().StringVar(&outputFormat, "format", "markdown", "Advice format: markdown, plain or html")
+		cmd.Flags().StringVar(&adviceCategory, "category", "all", "Focus the advice on travel, clothing, sports or health")
+	}
+	for _, cmd := range []*cobra.Command{adviceCmd, streamCmd, chatCmd} {
+		cmd.Flags().BoolVar(&profile.RunsCold, "runs-cold", false, "You feel the cold more than most")
+		cmd.Flags().BoolVar(&profile.RunsHot, "runs-hot", false, "You feel the heat more than most")
+		cmd.Flags().BoolVar(&profile.HasKids, "kids", false, "You have young children with you")
+		cmd.Flags().BoolVar(&profile.CyclesToWork, "cycles", false, "You cycle to work")
+		cmd.Flags().StringSliceVar(&profile.Allergies, "allergy", nil, "Allergies to take into account, e.g. pollen (repeatable)")
+		cmd.MarkFlagsMutuallyExclusive("runs-cold", "runs-hot")
+		cmd.Flags().StringVar(&adviceLanguage, "lang", "", "Language for the advice, e.g. de or pt-BR (default English)")
+	}
 
-	rootCmd.AddCommand(listCmd, weatherCmd, adviceCmd, streamCmd)
+	rootCmd.PersistentFlags().StringVar(&serverAddr, "server", defaultServerAddr, "Advisor gRPC address, host:port (env "+serverEnv+")")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default ~/.config/weather-advisor/config.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "How weather and advice results are printed: table, json or yaml")
+	rootCmd.PersistentFlags().StringVar(&unitSystem, "units", "", "Units for temperatures and wind: metric, imperial, uk or si (default by country)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show data provider details")
+	rootCmd.PersistentFlags().StringVar(&primaryCity, "primary", "", "City the advice should lead with")
+	rootCmd.PersistentFlags().StringVar(&modelName, "model", "", "Gemini model to use for advice (server default if empty)")
+	rootCmd.PersistentFlags().BoolVar(&useTLS, "tls", false, "Connect to the server over TLS")
+	rootCmd.PersistentFlags().StringVar(&caFile, "ca-cert", "", "PEM file of CAs to trust for the server certificate (implies --tls)")
+	rootCmd.PersistentFlags().StringVar(&caFile, "ca", "", "Same as --ca-cert")
+	rootCmd.PersistentFlags().MarkHidden("ca")
+	rootCmd.PersistentFlags().StringVar(&certFile, "cert", "", "PEM client certificate for servers that require mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&keyFile, "key", "", "PEM private key for --cert")
+	rootCmd.PersistentFlags().StringVar(&authToken, "token", "", "Bearer token for servers that require one")
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key naming your tenant on a shared server")
+	rootCmd.PersistentFlags().DurationVar(&keepaliveTime, "keepalive", 30*time.Second, "Ping the server after this long without traffic, at least 10s (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&compress, "compress", true, "Gzip requests and responses")
+
+	rootCmd.AddCommand(newCitiesCmd(), weatherCmd, newForecastCmd(), adviceCmd, streamCmd, chatCmd, newHookCmd(), newRateCmd(), newBestDayCmd(), newSearchCmd(), newPackCmd(), newDigestCmd(), newAlertCmd(), newHistoryCmd(), newCompareCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -98,67 +227,125 @@ func main() {
 	}
 }
 
+type menuOption struct {
+	label string
+	run   func()
+}
+
+// fetchServerInfo asks the server what it supports. Older servers that don't
+// implement GetServerInfo get the full menu, matching the previous behaviour.
+func fetchServerInfo() *advisorpb.ServerInfoResponse {
+	fallback := &advisorpb.ServerInfoResponse{StreamingEnabled: true}
+
+	conn, err := dial()
+	if err != nil {
+		return fallback
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := advisorpb.NewAdvisorServiceClient(conn).GetServerInfo(ctx, &advisorpb.ServerInfoRequest{})
+	if err != nil {
+		color.Yellow("⚠️  Could not fetch server capabilities: %v", err)
+		return fallback
+	}
+	return info
+}
+
+func buildMenu(info *advisorpb.ServerInfoResponse) []menuOption {
+	options := []menuOption{
+		{"Get Weather for a City", selectAndGetWeather},
+	}
+
+	adviceLabel := "Get AI Weather Advice"
+	if info.TemplateOnly {
+		adviceLabel = "Get Weather Advice"
+	}
+	options = append(options, menuOption{adviceLabel, func() { selectAndGetAdvice(false) }})
+
+	if info.StreamingEnabled && !info.TemplateOnly {
+		options = append(options, menuOption{"Stream AI Advice (Real-time)", func() { selectAndGetAdvice(true) }})
+	}
+
+	if info.ForecastSupported {
+		options = append(options, menuOption{"Find the Best Day This Week", selectAndFindBestDay})
+	}
+
+	options = append(options, menuOption{"List Available Cities", listCities})
+	return options
+}
+
 func runInteractiveCLI() {
 	color.HiCyan("Welcome to Weather Advisor")
 	fmt.Println()
 
+	info := fetchServerInfo()
+	if info.TemplateOnly {
+		color.Yellow("ℹ️  The advisor is running in template-only mode; AI streaming is unavailable.")
+		fmt.Println()
+	}
+	menu := buildMenu(info)
+
+	labels := make([]string, 0, len(menu)+1)
+	actions := make(map[string]func(), len(menu))
+	for _, option := range menu {
+		labels = append(labels, option.label)
+		actions[option.label] = option.run
+	}
+	labels = append(labels, "Exit")
+
 	for {
 		var action string
 		prompt := &survey.Select{
 			Message: "What would you like to do?",
-			Options: []string{
-				"Get Weather for a City",
-				"Get AI Weather Advice",
-				"Stream AI Advice (Real-time)",
-				"List Available Cities",
-				"Exit",
-			},
+			Options: labels,
 		}
 		survey.AskOne(prompt, &action)
 
-		switch action {
-		case "Get Weather for a City":
-			selectAndGetWeather()
-		case "Get AI Weather Advice":
-			selectAndGetAdvice(false)
-		case "Stream AI Advice (Real-time)":
-			selectAndGetAdvice(true)
-		case "List Available Cities":
-			listCities()
-		case "Exit":
+		if action == "Exit" || action == "" {
 			color.HiGreen("Goodbye!")
 			return
 		}
+		if run, ok := actions[action]; ok {
+			run()
+		}
 		fmt.Println()
 	}
 }
 
-func selectAndGetWeather() {
-	cities := make([]string, 0, len(availableCities))
-	for city := range availableCities {
-		cities = append(cities, city)
+// cityOptions lists the known cities for the menus, favorites first and
+// the rest by name.
+func cityOptions() []string {
+	cities := favoriteCities()
+	rest := make([]string, 0, len(knownCities()))
+	for city := range knownCities() {
+		if !slices.Contains(cities, city) {
+			rest = append(rest, city)
+		}
 	}
+	slices.Sort(rest)
+	return append(cities, rest...)
+}
 
+func selectAndGetWeather() {
 	var selectedCity string
 	prompt := &survey.Select{
 		Message: "Select a city:",
-		Options: cities,
+		Options: cityOptions(),
 	}
 	survey.AskOne(prompt, &selectedCity)
 
-	getWeather(selectedCity)
+	getWeather(selectedCity, 0)
 }
 
 func selectAndGetAdvice(stream bool) {
-	cities := make([]string, 0, len(availableCities))
-	for city := range availableCities {
-		cities = append(cities, city)
-	}
-
 	var selectedCities []string
 	prompt := &survey.MultiSelect{
 		Message: "Select cities (use space to select, enter to confirm):",
-		Options: cities,
+		Options: cityOptions(),
+		Default: favoriteCities(),
 	}
 	survey.AskOne(prompt, &selectedCities)
 
@@ -171,33 +358,77 @@ func selectAndGetAdvice(stream bool) {
 }
 
 func listCities() {
+	cities := slices.Sorted(maps.Keys(knownCities()))
 	color.HiCyan("\nAvailable Cities:")
 	color.Cyan(strings.Repeat("─", 50))
 
-	cities := make([]string, 0, len(availableCities))
-	for city := range availableCities {
-		cities = append(cities, city)
-	}
-
 	for i, city := range cities {
-		coords := availableCities[city]
-		fmt.Printf("%-3d. %-15s (%.4f, %.6f)\n", i+1, city, coords[0], coords[1])
+		info := knownCities()[city]
+		fmt.Printf("%-3d. %-15s (%.4f, %.6f)", i+1, city, info.Lat, info.Lon)
+		if savedCityIndex(city) >= 0 {
+			color.HiBlack("  yours")
+		} else {
+			fmt.Println()
+		}
 	}
 
 	color.Cyan(strings.Repeat("─", 50))
 	color.HiBlue(fmt.Sprintf("Total: %d cities available", len(cities)))
 }
 
-func getWeather(cityName string) {
-	coords, exists := availableCities[cityName]
-	if !exists {
-		color.Red("City '%s' not found! Use 'weather-advisor cities' to see available cities.", cityName)
+// resolveCity matches a typed name to a known city, ignoring case and extra
+// whitespace, so "  new   york" finds "New York".
+func resolveCity(name string) (string, bool) {
+	name = strings.Join(strings.Fields(name), " ")
+	if _, ok := knownCities()[name]; ok {
+		return name, true
+	}
+	for city := range knownCities() {
+		if strings.EqualFold(city, name) {
+			return city, true
+		}
+	}
+	return name, false
+}
+
+// resolveCities checks every argument, reporting the position of the first
+// empty or unknown one.
+func resolveCities(names []string) ([]string, bool) {
+	resolved := make([]string, 0, len(names))
+	for i, name := range names {
+		if strings.TrimSpace(name) == "" {
+			color.Red("❌ City %d is empty!", i+1)
+			return nil, false
+		}
+		city, ok := resolveCity(name)
+		if !ok {
+			color.Red("❌ City '%s' not found!", city)
+			return nil, false
+		}
+		resolved = append(resolved, city)
+	}
+	return resolved, true
+}
+
+// getWeather shows the current weather for a city, once or, with a watch
+// interval, redrawn until Ctrl-C.
+func getWeather(cityName string, watch time.Duration) {
+	if strings.TrimSpace(cityName) == "" {
+		color.Red("City name is empty! Use 'weather-advisor cities' to see available cities.")
+		return
+	}
+	if watch != 0 && (watch < minWatchInterval || watch%time.Second != 0) {
+		color.Red("❌ --watch must be whole seconds, at least %s", minWatchInterval)
+		return
+	}
+	cityName, info, geocoding, ok := lookupCity(cityName)
+	if !ok {
 		return
 	}
 
 	color.HiYellow("Getting weather for %s...", cityName)
 
-	conn, err := grpc.Dial(serverAddr, grpc.WithInsecure())
+	conn, err := dial()
 	if err != nil {
 		color.Red("Connection failed: %v", err)
 		return
@@ -206,8 +437,14 @@ func getWeather(cityName string) {
 
 	client := weatherpb.NewWeatherServiceClient(conn)
 	req := &weatherpb.WeatherRequest{
-		Latitude:  coords[0],
-		Longitude: coords[1],
+		Latitude:    info.Lat,
+		Longitude:   info.Lon,
+		CountryCode: info.Country,
+		UnitSystem:  units.ParseSystem(unitSystem),
+	}
+	if watch > 0 {
+		watchWeather(client, cityName, req, watch, geocoding)
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -215,41 +452,119 @@ func getWeather(cityName string) {
 
 	resp, err := client.GetCurrentWeather(ctx, req)
 	if err != nil {
-		color.Red("Weather request failed: %v", err)
+		cached := &weatherpb.WeatherResponse{}
+		savedAt, ok := loadCached("weather", req, cached)
+		if !ok || !unreachable(err) {
+			color.Red("Weather request failed: %v", err)
+			return
+		}
+		staleBanner("Weather request", err, savedAt)
+		resp = cached
+	} else {
+		saveCached("weather", req, resp)
+	}
+	if machineOutput() {
+		printResult(resp)
 		return
 	}
+	printWeather(cityName, resp, geocoding)
+}
 
-	// Display weather info
+func printWeather(cityName string, resp *weatherpb.WeatherResponse, geocoding []*advisorpb.DataSource) {
 	color.HiGreen("\nWeather Report for %s", cityName)
 	color.Green(strings.Repeat("─", 40))
-	fmt.Printf("Temperature: %.1f°C (feels like %.1f°C)\n", resp.Temperature, resp.FeelsLike)
+	u := resp.GetUnits()
+	fmt.Printf("Temperature: %.1f%s (feels like %.1f%s)\n", resp.Temperature, u.GetTemperature(), resp.FeelsLike, u.GetTemperature())
 	fmt.Printf("Condition: %s\n", resp.Description)
 	fmt.Printf("Humidity: %d%%\n", resp.Humidity)
-	fmt.Printf("Wind: %.1f m/s at %d°\n", resp.WindSpeed, resp.WindDeg)
-	fmt.Printf("Pressure: %d hPa\n", resp.Pressure)
+	fmt.Printf("Wind: %.1f %s at %d°\n", resp.WindSpeed, u.GetWindSpeed(), resp.WindDeg)
+	if resp.IsDay {
+		fmt.Printf("UV index: %.0f\n", resp.UvIndex)
+	}
+	if u.GetPressure() == "inHg" {
+		fmt.Printf("Pressure: %.2f inHg\n", resp.PressureValue)
+	} else {
+		fmt.Printf("Pressure: %d hPa\n", resp.Pressure)
+	}
 	color.Green(strings.Repeat("─", 40))
+	if p := resp.GetProvider(); p != nil {
+		printSource(p.Name, p.Url, p.License, p.Attribution, p.Model, p.ModelRunTime)
+	}
+	printSources(geocoding)
+}
+
+// printSource shows the attribution a provider requires. The provider name,
+// model, run time and license are added with --verbose.
+func printSource(name, url, license, attribution, model string, runTime int64) {
+	if attribution != "" {
+		color.HiBlack("%s", attribution)
+	}
+	if !verbose {
+		return
+	}
+	details := name
+	if model != "" {
+		details += " (model " + model
+		if runTime > 0 {
+			details += ", run " + time.Unix(runTime, 0).UTC().Format("2006-01-02 15:04 UTC")
+		}
+		details += ")"
+	}
+	color.HiBlack("  Source: %s, %s, %s", details, license, url)
+}
+
+func printSources(sources []*advisorpb.DataSource) {
+	for _, src := range sources {
+		printSource(src.Name, src.Url, src.License, src.Attribution, src.Model, src.ModelRunTime)
+	}
 }
 
 func getAdvice(cities []string, stream bool) {
+	if len(cities) == 0 {
+		cities = favoriteCities()
+		if len(cities) == 0 {
+			color.Red("❌ Name at least one city, or set favorites in the config file")
+			return
+		}
+	}
 	// Validate all cities
+	cities, ok := resolveCities(cities)
+	if !ok {
+		return
+	}
+	primary, _ := resolveCity(primaryCity)
 	var cityData []*advisorpb.CityData
 	for _, city := range cities {
-		if _, exists := availableCities[city]; !exists {
-			color.Red("❌ City '%s' not found!", city)
-			return
-		}
-		cityData = append(cityData, &advisorpb.CityData{Location: city})
+		data := newCityData(city)
+		data.Primary = city == primary
+		cityData = append(cityData, data)
 	}
 
-	conn, err := grpc.Dial(serverAddr, grpc.WithInsecure())
+	sendAdvice(cityData, cities, stream)
+}
+
+// sendAdvice requests advice for already-validated cities.
+func sendAdvice(cityData []*advisorpb.CityData, cities []string, stream bool) {
+	conn, err := dial()
 	if err != nil {
 		color.Red("❌ Connection failed: %v", err)
 		return
 	}
 	defer conn.Close()
 
+	format, ok := advisorpb.AdviceFormat_value["ADVICE_FORMAT_"+strings.ToUpper(outputFormat)]
+	if !ok {
+		color.Red("❌ Unknown format '%s' (want markdown, plain or html)", outputFormat)
+		return
+	}
+	category, ok := advisorpb.AdviceCategory_value["ADVICE_CATEGORY_"+strings.ToUpper(adviceCategory)]
+	if !ok {
+		color.Red("❌ Unknown category '%s' (want all, travel, clothing, sports or health)", adviceCategory)
+		return
+	}
+
 	client := advisorpb.NewAdvisorServiceClient(conn)
-	req := &advisorpb.AdvisorRequest{Cities: cityData}
+	req := &advisorpb.AdvisorRequest{Cities: cityData, BestEffort: true, Model: modelName, Format: advisorpb.AdviceFormat(format), Category: advisorpb.AdviceCategory(category), Profile: userProfile(), UnitSystem: unitSystem, Language: adviceLanguage}
 
 	if stream {
 		getStreamingAdvice(client, req, cities)
@@ -258,6 +573,14 @@ func getAdvice(cities []string, stream bool) {
 	}
 }
 
+// userProfile returns the profile from the flags, or nil when none is set.
+func userProfile() *advisorpb.UserProfile {
+	if !profile.RunsCold && !profile.RunsHot && !profile.HasKids && !profile.CyclesToWork && len(profile.Allergies) == 0 {
+		return nil
+	}
+	return profile
+}
+
 func getNormalAdvice(client advisorpb.AdvisorServiceClient, req *advisorpb.AdvisorRequest, cities []string) {
 	color.HiYellow("🤖 Getting AI advice for: %s", strings.Join(cities, ", "))
 
@@ -266,14 +589,84 @@ func getNormalAdvice(client advisorpb.AdvisorServiceClient, req *advisorpb.Advis
 
 	resp, err := client.GetAdvice(ctx, req)
 	if err != nil {
-		color.Red("❌ Advice request failed: %v", err)
+		if !showCachedAdvice(req, err) {
+			color.Red("❌ Advice request failed: %v", err)
+		}
+		return
+	}
+	saveCached("advice", req, resp)
+	if machineOutput() {
+		printResult(resp)
 		return
 	}
+	printAdvice(resp)
+}
+
+// showCachedAdvice prints the last advice for the same request when the
+// server couldn't be reached, and reports whether there was any.
+func showCachedAdvice(req *advisorpb.AdvisorRequest, err error) bool {
+	if !unreachable(err) {
+		return false
+	}
+	resp := &advisorpb.AdvisorResponse{}
+	savedAt, ok := loadCached("advice", req, resp)
+	if !ok {
+		return false
+	}
+	staleBanner("Advice request", err, savedAt)
+	if machineOutput() {
+		printResult(resp)
+	} else {
+		printAdvice(resp)
+	}
+	return true
+}
+
+func printAdvice(resp *advisorpb.AdvisorResponse) {
+	for _, cityErr := range resp.Errors {
+		color.Yellow("⚠️  Skipped %s (%s failed): %s", cityErr.Location, cityErr.Stage, cityErr.Message)
+	}
 
 	color.HiGreen("\n🎯 AI Weather Advice")
 	color.Green(strings.Repeat("═", 60))
 	fmt.Println(resp.Advice)
 	color.Green(strings.Repeat("═", 60))
+	if u := resp.Usage; u != nil {
+		color.HiBlack("%s: %d prompt + %d response tokens (~$%.4f)", u.Model, u.PromptTokens, u.ResponseTokens, u.EstimatedCostUsd)
+	}
+	if resp.Fallback {
+		color.Yellow("ℹ️  Template advice (AI unavailable)")
+	}
+	if resp.Cached {
+		color.HiBlack("ℹ️  Reused advice from a recent request with similar weather")
+	}
+	printExposure(resp.Exposure)
+	printSources(resp.Sources)
+	if resp.AdviceId != "" {
+		color.HiBlack("Saved as %s; see 'weather-advisor history show %s'", resp.AdviceId, resp.AdviceId)
+	}
+}
+
+// printExposure lists each city's cleanest-air hours in its local time.
+func printExposure(exposure []*advisorpb.CityExposure) {
+	for _, exp := range exposure {
+		loc := time.FixedZone("local", int(exp.UtcOffsetSeconds))
+		color.HiCyan("🌬️  %s air quality", exp.Location)
+		fmt.Printf("  Exercise / cycling: %s\n", formatWindows(exp.Exercise, loc))
+		fmt.Printf("  Open windows:       %s\n", formatWindows(exp.Ventilation, loc))
+	}
+}
+
+func formatWindows(windows []*advisorpb.TimeWindow, loc *time.Location) string {
+	if len(windows) == 0 {
+		return "no clean-air hours in the next 24h"
+	}
+	parts := make([]string, 0, len(windows))
+	for _, w := range windows {
+		parts = append(parts, fmt.Sprintf("%s-%s (AQI ≤ %d)",
+			time.Unix(w.Start, 0).In(loc).Format("15:04"), time.Unix(w.End, 0).In(loc).Format("15:04"), w.MaxAqi))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func getStreamingAdvice(client advisorpb.AdvisorServiceClient, req *advisorpb.AdvisorRequest, cities []string) {
@@ -284,37 +677,295 @@ func getStreamingAdvice(client advisorpb.AdvisorServiceClient, req *advisorpb.Ad
 
 	stream, err := client.StreamAdvice(ctx, req)
 	if err != nil {
-		color.Red("❌ Streaming failed: %v", err)
+		if !showCachedAdvice(req, err) {
+			color.Red("❌ Streaming failed: %v", err)
+		}
 		return
 	}
 
 	color.HiGreen("\n🎯 AI Weather Advice (Streaming)")
 	color.Green(strings.Repeat("═", 60))
 
+	verifier := newStreamVerifier()
+	var final *advisorpb.StreamAdviceResponse
+	started := false
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
+			// Ends the advice line; stdout stays clean under --output.
+			fmt.Fprintln(color.Output)
+			// Connecting happens on the first Recv; until advice has
+			// arrived, an old answer is better than none.
+			if verifier.last > 0 || !showCachedAdvice(req, err) {
+				color.Red("❌ %s", streamErrorMessage(err))
+			}
+			return
+		}
 
-			if strings.Contains(err.Error(), "context canceled") ||
-				strings.Contains(err.Error(), "connection is closing") ||
-				strings.Contains(err.Error(), "streaming failed") {
+		verifier.add(resp)
+		if machineOutput() {
+			printStreamMessage(resp)
+			if resp.IsComplete {
+				final = resp
 				break
 			}
-			// Only log unexpected errors
-			color.Red("❌ Stream error: %v", err)
-			return
+			continue
+		}
+		if p := resp.Progress; p != nil {
+			showProgress(p)
+			continue
 		}
+		if resp.Chunk != "" && !started {
+			// Clear the progress line before the advice text starts.
+			fmt.Print("\r\033[K")
+			started = true
+		}
+		fmt.Print(resp.Chunk)
 
 		if resp.IsComplete {
+			final = resp
 			break
 		}
-
-		fmt.Print(resp.Chunk)
 	}
 
 	color.Green("\n" + strings.Repeat("═", 60))
+	if final == nil {
+		color.Yellow("⚠️  Stream closed before the advice was complete")
+		return
+	}
+	if final.ChunkCount > 0 && verifyStream(client, verifier, final) {
+		// Cached like GetAdvice, which asks with the same request.
+		saveCached("advice", req, &advisorpb.AdvisorResponse{
+			Advice:   verifier.text(final.ChunkCount),
+			Usage:    final.Usage,
+			Fallback: final.Fallback,
+			Cached:   final.Cached,
+			Sources:  final.Sources,
+		})
+	}
+	printSources(final.Sources)
+	if final.FinishReason == "MAX_TOKENS" {
+		color.Yellow("⚠️  Advice was cut short by the output token limit")
+	}
+	if final.Fallback {
+		color.Yellow("ℹ️  Template advice (AI unavailable)")
+	}
+	if final.Cached {
+		color.HiBlack("ℹ️  Reused advice from a recent request with similar weather")
+	}
 	color.HiGreen("✅ Advice complete!")
 }
+
+// runChat opens a ChatStream, prints the initial advice and then sends each
+// line typed by the user as a follow-up until an empty line or EOF.
+func runChat(cities []string) {
+	cities, ok := resolveCities(cities)
+	if !ok {
+		return
+	}
+	var cityData []*advisorpb.CityData
+	for _, city := range cities {
+		cityData = append(cityData, newCityData(city))
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := advisorpb.NewAdvisorServiceClient(conn).ChatStream(ctx)
+	if err != nil {
+		color.Red("❌ Chat failed: %v", err)
+		return
+	}
+	start := &advisorpb.AdvisorRequest{Cities: cityData, Model: modelName, Profile: userProfile(), UnitSystem: unitSystem, Language: adviceLanguage}
+	if err := stream.Send(&advisorpb.ChatRequest{Payload: &advisorpb.ChatRequest_Start{Start: start}}); err != nil {
+		color.Red("❌ Chat failed: %v", err)
+		return
+	}
+
+	color.HiGreen("\n🎯 AI Weather Chat for %s (empty line to quit)", strings.Join(cities, ", "))
+	color.Green(strings.Repeat("═", 60))
+
+	input := bufio.NewScanner(os.Stdin)
+	for {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					fmt.Println()
+					color.Red("❌ %s", streamErrorMessage(err))
+				}
+				return
+			}
+			fmt.Print(resp.Chunk)
+			if resp.TurnComplete {
+				break
+			}
+		}
+
+		color.New(color.FgCyan).Print("\n\nyou> ")
+		if !input.Scan() || strings.TrimSpace(input.Text()) == "" {
+			stream.CloseSend()
+			color.HiGreen("Goodbye!")
+			return
+		}
+		if err := stream.Send(&advisorpb.ChatRequest{Payload: &advisorpb.ChatRequest_Prompt{Prompt: input.Text()}}); err != nil {
+			color.Red("❌ Chat failed: %v", err)
+			return
+		}
+		fmt.Println()
+	}
+}
+
+// showProgress redraws a single status line while cities are fetched.
+func showProgress(p *advisorpb.ProgressEvent) {
+	var line string
+	switch p.Stage {
+	case advisorpb.ProgressEvent_GEOCODED:
+		line = fmt.Sprintf("📍 Located %s", p.Location)
+	case advisorpb.ProgressEvent_WEATHER_FETCHED:
+		line = fmt.Sprintf("🌤️  Weather fetched for %s", p.Location)
+	case advisorpb.ProgressEvent_CITY_FAILED:
+		line = fmt.Sprintf("⚠️  %s: %s", p.Location, p.Message)
+	case advisorpb.ProgressEvent_GENERATION_STARTED:
+		line = "🤖 Generating advice..."
+	default:
+		return
+	}
+	fmt.Printf("\r\033[K[%d/%d] %s", p.Completed, p.Total, line)
+	if p.Stage == advisorpb.ProgressEvent_CITY_FAILED {
+		// Keep failures on screen.
+		fmt.Println()
+	}
+}
+
+// streamErrorMessage explains a stream failure from its status code, as
+// documented on StreamAdvice.
+func streamErrorMessage(err error) string {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Canceled:
+		return "Stream cancelled"
+	case codes.DeadlineExceeded:
+		return "Timed out waiting for advice"
+	case codes.ResourceExhausted:
+		return "Server is busy: " + st.Message()
+	case codes.Unavailable:
+		return "Advice is temporarily unavailable, try again later: " + st.Message()
+	case codes.FailedPrecondition:
+		return "Advice was blocked: " + st.Message()
+	}
+	return fmt.Sprintf("Stream error: %s", st.Message())
+}
+
+// streamVerifier collects chunks by sequence number so a stream can be
+// checked against the count and digest sent in its final message.
+type streamVerifier struct {
+	chunks    map[uint64]string
+	last      uint64
+	reordered bool
+}
+
+func newStreamVerifier() *streamVerifier {
+	return &streamVerifier{chunks: make(map[uint64]string)}
+}
+
+func (v *streamVerifier) add(resp *advisorpb.StreamAdviceResponse) {
+	if resp.Sequence == 0 {
+		return
+	}
+	if resp.Sequence != v.last+1 {
+		v.reordered = true
+	}
+	if resp.Sequence > v.last {
+		v.last = resp.Sequence
+	}
+	v.chunks[resp.Sequence] = resp.Chunk
+}
+
+// missing returns the inclusive sequence ranges not yet received.
+func (v *streamVerifier) missing(count uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	for seq := uint64(1); seq <= count; seq++ {
+		if _, ok := v.chunks[seq]; ok {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1][1] == seq-1 {
+			ranges[n-1][1] = seq
+		} else {
+			ranges = append(ranges, [2]uint64{seq, seq})
+		}
+	}
+	return ranges
+}
+
+func (v *streamVerifier) text(count uint64) string {
+	var b strings.Builder
+	for seq := uint64(1); seq <= count; seq++ {
+		b.WriteString(v.chunks[seq])
+	}
+	return b.String()
+}
+
+// verifyStream re-requests missing chunks and checks the advice against the
+// final message's digest, reporting whether it is intact. Recovered advice
+// is reprinted with the other human-facing messages, so it stays off stdout
+// under --output json or yaml.
+func verifyStream(client advisorpb.AdvisorServiceClient, v *streamVerifier, final *advisorpb.StreamAdviceResponse) bool {
+	gaps := v.missing(final.ChunkCount)
+	if len(gaps) > 0 {
+		color.Yellow("⚠️  %d chunk range(s) missing, requesting re-send...", len(gaps))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, gap := range gaps {
+			resend, err := client.ResendChunks(ctx, &advisorpb.ResendChunksRequest{
+				StreamId:     final.StreamId,
+				FromSequence: gap[0],
+				ToSequence:   gap[1],
+			})
+			if err != nil {
+				color.Red("❌ Re-send failed: %v", err)
+				return false
+			}
+			for {
+				resp, err := resend.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					color.Red("❌ Re-send failed: %v", err)
+					return false
+				}
+				v.add(resp)
+				// Scripts get the re-sent chunks like the others and
+				// order them by sequence.
+				if machineOutput() {
+					printStreamMessage(resp)
+				}
+			}
+		}
+	}
+
+	text := v.text(final.ChunkCount)
+	sum := sha256.Sum256([]byte(text))
+	if hex.EncodeToString(sum[:]) != final.Sha256 {
+		color.Red("❌ Stream integrity check failed: advice may be incomplete")
+		return false
+	}
+	if len(gaps) > 0 || v.reordered {
+		color.HiGreen("\n🎯 Recovered advice")
+		color.Green(strings.Repeat("═", 60))
+		fmt.Fprintln(color.Output, text)
+		color.Green(strings.Repeat("═", 60))
+	}
+	return true
+}
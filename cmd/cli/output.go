@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"go.yaml.in/yaml/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// output is how weather and advice results are printed: table, the
+// colored report, or json or yaml for scripts.
+var output string
+
+// checkOutput validates --output. For json and yaml, everything but the
+// results goes to stderr, so stdout can be piped straight into jq.
+func checkOutput() error {
+	switch output {
+	case "table":
+	case "json", "yaml":
+		color.Output = os.Stderr
+	default:
+		return fmt.Errorf("unknown --output %q (want json, yaml or table)", output)
+	}
+	return nil
+}
+
+func machineOutput() bool {
+	return output == "json" || output == "yaml"
+}
+
+// printResult writes a response as one indented JSON or YAML document, in
+// the field names of the REST gateway.
+func printResult(m proto.Message) {
+	printMessage(m, true)
+}
+
+// printStreamMessage writes a streamed message as one line of JSON, or as
+// its own YAML document.
+func printStreamMessage(m proto.Message) {
+	printMessage(m, false)
+}
+
+func printMessage(m proto.Message, indent bool) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		color.Red("❌ Encoding the result failed: %v", err)
+		return
+	}
+	if output == "json" {
+		// protojson varies its spacing on purpose; settle it.
+		var buf bytes.Buffer
+		if indent {
+			err = json.Indent(&buf, data, "", "  ")
+		} else {
+			err = json.Compact(&buf, data)
+		}
+		if err != nil {
+			color.Red("❌ Encoding the result failed: %v", err)
+			return
+		}
+		fmt.Println(buf.String())
+		return
+	}
+	// JSON is YAML; a MapSlice keeps the fields in order.
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		color.Red("❌ Encoding the result failed: %v", err)
+		return
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		color.Red("❌ Encoding the result failed: %v", err)
+		return
+	}
+	fmt.Print("---\n" + string(out))
+}
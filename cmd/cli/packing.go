@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+func newPackCmd() *cobra.Command {
+	var from, to, tripType string
+	cmd := &cobra.Command{
+		Use:               "pack [cities...]",
+		Short:             "Build a packing list from the forecast at your destinations",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeCities,
+		Run: func(cmd *cobra.Command, args []string) {
+			generatePackingList(args, from, to, tripType)
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "First day of the trip, YYYY-MM-DD (default today)")
+	cmd.Flags().StringVar(&to, "to", "", "Last day of the trip, YYYY-MM-DD (default a week from --from)")
+	cmd.Flags().StringVar(&tripType, "type", "leisure", "Trip type: leisure, business, beach or hiking")
+	return cmd
+}
+
+func generatePackingList(cities []string, from, to, tripType string) {
+	cities, ok := resolveCities(cities)
+	if !ok {
+		return
+	}
+	var destinations []*advisorpb.CityData
+	for _, city := range cities {
+		destinations = append(destinations, newCityData(city))
+	}
+
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := advisorpb.NewAdvisorServiceClient(conn).GeneratePackingList(ctx, &advisorpb.PackingListRequest{
+		Destinations: destinations,
+		StartDate:    from,
+		EndDate:      to,
+		TripType:     tripType,
+		UnitSystem:   unitSystem,
+	})
+	if err != nil {
+		color.Red("❌ Packing list failed: %v", err)
+		return
+	}
+
+	for _, cityErr := range resp.Errors {
+		color.Yellow("⚠️  Skipped %s (%s failed): %s", cityErr.Location, cityErr.Stage, cityErr.Message)
+	}
+	color.HiGreen("\n🧳 Packing list (%d days)", resp.TripDays)
+	category := ""
+	for _, item := range resp.Items {
+		if item.Category != category {
+			category = item.Category
+			color.HiCyan("\n%s", category)
+		}
+		line := fmt.Sprintf("[ ] %s", item.Name)
+		if item.Quantity > 1 {
+			line += fmt.Sprintf(" ×%d", item.Quantity)
+		}
+		fmt.Print(line)
+		if item.Reason != "" {
+			color.HiBlack("  (%s)", item.Reason)
+		} else {
+			fmt.Println()
+		}
+	}
+	fmt.Println()
+	printSources(resp.Sources)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	var state, country string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "search [name]",
+		Short: "List the places a name could mean, with their coordinates",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			searchLocations(args[0], state, country, limit)
+		},
+	}
+	cmd.Flags().StringVar(&state, "state", "", "Only places in this state or region")
+	cmd.Flags().StringVar(&country, "country", "", "Only places in this country (name or ISO code)")
+	cmd.Flags().IntVar(&limit, "limit", 5, "Maximum number of candidates")
+	return cmd
+}
+
+func searchLocations(query, state, country string, limit int) {
+	conn, err := dial()
+	if err != nil {
+		color.Red("❌ Connection failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := advisorpb.NewAdvisorServiceClient(conn).SearchLocations(ctx, &advisorpb.SearchLocationsRequest{
+		Query:   query,
+		State:   state,
+		Country: country,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		color.Red("❌ Search failed: %v", err)
+		return
+	}
+	if len(resp.Candidates) == 0 {
+		color.Yellow("No places found for '%s'", query)
+		return
+	}
+
+	color.HiGreen("\n📍 Places matching '%s'", query)
+	for i, c := range resp.Candidates {
+		fmt.Printf("%2d. %s, %s, %s  (%.4f, %.4f)", i+1, c.Name, c.State, c.Country, c.Latitude, c.Longitude)
+		if c.Population > 0 {
+			color.HiBlack("  pop. %d", c.Population)
+		} else {
+			fmt.Println()
+		}
+	}
+	printSources(resp.Sources)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+// site is one point read from a GeoJSON or KML file.
+type site struct {
+	Name    string
+	State   string
+	Country string
+	Lat     float64
+	Lon     float64
+}
+
+// loadSites reads the points in a .geojson/.json or .kml file. Features
+// that aren't points are skipped with a note.
+func loadSites(path string) ([]site, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".geojson", ".json":
+		return parseGeoJSON(data)
+	case ".kml":
+		return parseKML(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported file type %q (want .geojson or .kml)", filepath.Ext(path))
+	}
+}
+
+type geoJSONFeature struct {
+	Geometry *struct {
+		Type string `json:"type"`
+		// Only decoded for points; other geometries nest deeper.
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+func parseGeoJSON(data []byte) ([]site, []string, error) {
+	var doc struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	if doc.Type == "Feature" {
+		var f geoJSONFeature
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, nil, fmt.Errorf("invalid GeoJSON: %v", err)
+		}
+		doc.Features = []geoJSONFeature{f}
+	}
+
+	var sites []site
+	var skipped []string
+	for i, f := range doc.Features {
+		if f.Geometry == nil || f.Geometry.Type != "Point" {
+			skipped = append(skipped, fmt.Sprintf("feature %d is not a point", i+1))
+			continue
+		}
+		var coords []float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err != nil || len(coords) < 2 {
+			skipped = append(skipped, fmt.Sprintf("feature %d has invalid coordinates", i+1))
+			continue
+		}
+		// GeoJSON coordinates are [longitude, latitude].
+		sites = append(sites, site{
+			Name:    firstProperty(f.Properties, "name", "title", "city"),
+			State:   firstProperty(f.Properties, "state", "admin1", "region"),
+			Country: firstProperty(f.Properties, "country", "country_code"),
+			Lat:     coords[1],
+			Lon:     coords[0],
+		})
+	}
+	return sites, skipped, nil
+}
+
+func firstProperty(props map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := props[key].(string); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+type kmlPlacemark struct {
+	Name  string `xml:"name"`
+	Point *struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+	Data []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	} `xml:"ExtendedData>Data"`
+}
+
+func parseKML(data []byte) ([]site, []string, error) {
+	// Placemarks can sit at any depth under Document and Folder elements,
+	// so walk the tokens rather than mapping the tree.
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var sites []site
+	var skipped []string
+	for n := 0; ; {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return sites, skipped, nil
+			}
+			return nil, nil, fmt.Errorf("invalid KML: %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Placemark" {
+			continue
+		}
+		n++
+		var p kmlPlacemark
+		if err := dec.DecodeElement(&p, &start); err != nil {
+			return nil, nil, fmt.Errorf("invalid KML placemark %d: %v", n, err)
+		}
+		if p.Point == nil {
+			skipped = append(skipped, fmt.Sprintf("placemark %d is not a point", n))
+			continue
+		}
+		// KML coordinates are "longitude,latitude[,altitude]".
+		parts := strings.Split(strings.TrimSpace(p.Point.Coordinates), ",")
+		if len(parts) < 2 {
+			skipped = append(skipped, fmt.Sprintf("placemark %d has no coordinates", n))
+			continue
+		}
+		lon, errLon := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if errLon != nil || errLat != nil {
+			skipped = append(skipped, fmt.Sprintf("placemark %d has invalid coordinates", n))
+			continue
+		}
+		s := site{Name: strings.TrimSpace(p.Name), Lat: lat, Lon: lon}
+		for _, d := range p.Data {
+			switch strings.ToLower(d.Name) {
+			case "state", "admin1", "region":
+				s.State = strings.TrimSpace(d.Value)
+			case "country", "country_code":
+				s.Country = strings.TrimSpace(d.Value)
+			}
+		}
+		sites = append(sites, s)
+	}
+}
+
+// citiesOrFile rejects city arguments together with --from-file. Without
+// either, getAdvice falls back to the favorites in the config file.
+func citiesOrFile(cmd *cobra.Command, args []string) error {
+	if sitesFile != "" && len(args) > 0 {
+		return fmt.Errorf("pass either cities or --from-file, not both")
+	}
+	return nil
+}
+
+// getSiteAdvice asks for advice for every site in a file in one request.
+// Sites are sent with their coordinates, so the server never geocodes them
+// and the name is only used as a label.
+func getSiteAdvice(path string, stream bool) {
+	sites, skipped, err := loadSites(path)
+	if err != nil {
+		color.Red("❌ Could not read %s: %v", path, err)
+		return
+	}
+
+	var cityData []*advisorpb.CityData
+	var names []string
+	for _, s := range sites {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("%.4f,%.4f", s.Lat, s.Lon)
+		}
+		cityData = append(cityData, &advisorpb.CityData{
+			Location:  name,
+			State:     s.State,
+			Country:   s.Country,
+			Latitude:  proto.Float64(s.Lat),
+			Longitude: proto.Float64(s.Lon),
+		})
+		names = append(names, name)
+	}
+	for _, note := range skipped {
+		color.Yellow("⚠️  Skipped %s", note)
+	}
+	if len(cityData) == 0 {
+		color.Red("❌ No usable sites in %s", path)
+		return
+	}
+
+	color.HiBlue("Loaded %d sites from %s", len(cityData), path)
+	sendAdvice(cityData, names, stream)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/fatih/color"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// minWatchInterval is the shortest refresh StreamDashboard allows.
+const minWatchInterval = 15 * time.Second
+
+// watchWeather subscribes to StreamDashboard for one location and redraws
+// the report in place on every snapshot until Ctrl-C. With --output json or
+// yaml each snapshot is printed instead.
+func watchWeather(client weatherpb.WeatherServiceClient, cityName string, req *weatherpb.WeatherRequest, every time.Duration, geocoding []*advisorpb.DataSource) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	stream, err := client.StreamDashboard(ctx, &weatherpb.DashboardRequest{
+		Locations: []*weatherpb.DashboardLocation{{
+			Id:          cityName,
+			Latitude:    req.Latitude,
+			Longitude:   req.Longitude,
+			CountryCode: req.CountryCode,
+		}},
+		IntervalSeconds: int32(every / time.Second),
+		UnitSystem:      req.UnitSystem,
+	})
+	if err != nil {
+		color.Red("Weather request failed: %v", err)
+		return
+	}
+
+	for {
+		snapshot, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				color.Red("Watch stopped: %v", err)
+			}
+			return
+		}
+		for _, loc := range snapshot.Locations {
+			if machineOutput() {
+				printStreamMessage(loc)
+				continue
+			}
+			// Home the cursor and clear the screen.
+			fmt.Print("\033[H\033[2J")
+			if loc.Weather != nil {
+				printWeather(cityName, loc.Weather, geocoding)
+			}
+			if loc.Error != "" {
+				color.Yellow("⚠️  Latest refresh failed: %s", loc.Error)
+			}
+			updated := "never"
+			if loc.UpdatedAt > 0 {
+				updated = time.Unix(loc.UpdatedAt, 0).Format("15:04:05")
+			}
+			color.HiBlack("Updated %s, refreshing every %s. Ctrl-C to stop.", updated, every)
+		}
+	}
+}
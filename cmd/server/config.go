@@ -0,0 +1,637 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/advisor"
+	"github.com/pixperk/effinarounf/services/deadline"
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/pixperk/effinarounf/services/jobs"
+	"github.com/pixperk/effinarounf/services/logging"
+	"github.com/pixperk/effinarounf/services/mqtt"
+	"github.com/pixperk/effinarounf/services/rpcmetrics"
+	"github.com/pixperk/effinarounf/services/weather"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+)
+
+// config is the part of the server setup that used to be hard-coded. Each
+// value comes from the defaults, then the YAML config file, then its
+// environment variable, then its flag; later sources win. Only secrets and
+// the optional GEMINI_* generation parameters are read from the
+// environment alone, by the new* helpers in main.go.
+type config struct {
+	GRPCAddr             string        `yaml:"grpc_addr"`
+	MetricsAddr          string        `yaml:"metrics_addr"`
+	HTTPAddr             string        `yaml:"http_addr"`
+	Dashboard            bool          `yaml:"dashboard"`
+	Services             string        `yaml:"services"`
+	WeatherAddr          string        `yaml:"weather_addr"`
+	WeatherTLS           bool          `yaml:"weather_tls"`
+	WeatherCAFile        string        `yaml:"weather_ca_file"`
+	MaxConcurrentStreams int64         `yaml:"max_concurrent_streams"`
+	MaxRecvMsgSize       int64         `yaml:"max_recv_msg_size"`
+	MaxSendMsgSize       int64         `yaml:"max_send_msg_size"`
+	ConnectionTimeout    time.Duration `yaml:"connection_timeout"`
+	KeepaliveTime        time.Duration `yaml:"keepalive_time"`
+	KeepaliveTimeout     time.Duration `yaml:"keepalive_timeout"`
+	KeepaliveMinTime     time.Duration `yaml:"keepalive_min_time"`
+	KeepaliveNoStream    bool          `yaml:"keepalive_permit_without_stream"`
+	WeatherKeepalive     time.Duration `yaml:"weather_keepalive_time"`
+	WeatherCompression   bool          `yaml:"weather_compression"`
+	RPCTimeouts          string        `yaml:"rpc_timeouts"`
+	RPCBuckets           string        `yaml:"rpc_buckets"`
+	RPCStreamBuckets     string        `yaml:"rpc_stream_buckets"`
+	GeminiModel          string        `yaml:"gemini_model"`
+	GeocodingURL         string        `yaml:"geocoding_url"`
+	ForecastURL          string        `yaml:"forecast_url"`
+	AirQualityURL        string        `yaml:"air_quality_url"`
+	UpstreamTimeout      time.Duration `yaml:"upstream_timeout"`
+	UpstreamMaxBodyBytes int64         `yaml:"upstream_max_body_bytes"`
+	ShutdownGrace        time.Duration `yaml:"shutdown_grace"`
+	TLSCertFile          string        `yaml:"tls_cert_file"`
+	TLSKeyFile           string        `yaml:"tls_key_file"`
+	TLSClientCAFile      string        `yaml:"tls_client_ca_file"`
+	JWTIssuer            string        `yaml:"jwt_issuer"`
+	JWTAudience          string        `yaml:"jwt_audience"`
+	JWTJWKSURL           string        `yaml:"jwt_jwks_url"`
+	JWTTenantClaim       string        `yaml:"jwt_tenant_claim"`
+	Reflection           bool          `yaml:"reflection"`
+	LogFormat            string        `yaml:"log_format"`
+	LogLevel             string        `yaml:"log_level"`
+	OTLPEndpoint         string        `yaml:"otlp_endpoint"`
+	ServiceName          string        `yaml:"service_name"`
+	TraceSampleRatio     float64       `yaml:"trace_sample_ratio"`
+
+	StorageDSN             string        `yaml:"storage_dsn"`
+	StorageMigrate         string        `yaml:"storage_migrate"`
+	SessionStore           string        `yaml:"session_store"`
+	SessionTTL             time.Duration `yaml:"session_ttl"`
+	RedisAddr              string        `yaml:"redis_addr"`
+	RedisDB                int64         `yaml:"redis_db"`
+	AdviceHistory          string        `yaml:"advice_history"`
+	AdviceHistoryPath      string        `yaml:"advice_history_path"`
+	DigestStore            string        `yaml:"digest_store"`
+	AlertStore             string        `yaml:"alert_store"`
+	GeocodeCacheTTL        time.Duration `yaml:"geocode_cache_ttl"`
+	WeatherCacheTTL        time.Duration `yaml:"weather_cache_ttl"`
+	JobSchedules           string        `yaml:"job_schedules"`
+	TenantsFile            string        `yaml:"tenants_file"`
+	AuditLog               string        `yaml:"audit_log"`
+	AuditLogPath           string        `yaml:"audit_log_path"`
+	AuditLogMaxBytes       int64         `yaml:"audit_log_max_bytes"`
+	AuditLogKeep           int64         `yaml:"audit_log_keep"`
+	AuditExporters         string        `yaml:"audit_exporters"`
+	StreamSinkWebhooks     string        `yaml:"stream_sink_webhooks"`
+	NotifyRecipientsFile   string        `yaml:"notify_recipients_file"`
+	WebhookDeadLetterFile  string        `yaml:"webhook_dead_letter_file"`
+	WebhookAllowPrivate    bool          `yaml:"webhook_allow_private"`
+	SMTPAddr               string        `yaml:"smtp_addr"`
+	SMTPUsername           string        `yaml:"smtp_username"`
+	SMTPFrom               string        `yaml:"smtp_from"`
+	EmailTemplateDir       string        `yaml:"email_template_dir"`
+	MQTTBroker             string        `yaml:"mqtt_broker"`
+	MQTTLocations          string        `yaml:"mqtt_locations"`
+	MQTTTopicPrefix        string        `yaml:"mqtt_topic_prefix"`
+	MQTTClientID           string        `yaml:"mqtt_client_id"`
+	MQTTUsername           string        `yaml:"mqtt_username"`
+	MQTTQoS                int64         `yaml:"mqtt_qos"`
+	EventBus               string        `yaml:"event_bus"`
+	EventBusURL            string        `yaml:"event_bus_url"`
+	EventBusTopic          string        `yaml:"event_bus_topic"`
+	SemanticCacheThreshold float64       `yaml:"semantic_cache_threshold"`
+	SemanticCacheTTL       time.Duration `yaml:"semantic_cache_ttl"`
+	LLMQPS                 float64       `yaml:"llm_qps"`
+	LLMTokensPerMinute     int64         `yaml:"llm_tokens_per_minute"`
+	LLMBudgetDailyUSD      float64       `yaml:"llm_budget_daily_usd"`
+	LLMBudgetMonthlyUSD    float64       `yaml:"llm_budget_monthly_usd"`
+	LLMBudgetDailyTokens   int64         `yaml:"llm_budget_daily_tokens"`
+	LLMBudgetMonthlyTokens int64         `yaml:"llm_budget_monthly_tokens"`
+	LLMBudgetMode          string        `yaml:"llm_budget_mode"`
+}
+
+// defaultRPCTimeouts bounds calls from clients that set no deadline. Streams
+// meant to stay open get no limit.
+const defaultRPCTimeouts = "weather.WeatherService=15s,weather.WeatherService/StreamDashboard=0," +
+	"advisor.AdvisorService=60s,advisor.AdvisorService/StreamAdvice=90s," +
+	"advisor.AdvisorService/ChatStream=0,advisor.AdvisorService/ExportAuditLog=0," +
+	"advisor.AdvisorService/SubscribeAlerts=0"
+
+func defaultConfig() config {
+	return config{
+		GRPCAddr:             ":8082",
+		MetricsAddr:          ":2113",
+		Dashboard:            true,
+		Services:             "weather,advisor",
+		MaxRecvMsgSize:       4 << 20,
+		ConnectionTimeout:    120 * time.Second,
+		KeepaliveTime:        time.Minute,
+		KeepaliveTimeout:     20 * time.Second,
+		KeepaliveMinTime:     10 * time.Second,
+		KeepaliveNoStream:    true,
+		WeatherKeepalive:     30 * time.Second,
+		WeatherCompression:   true,
+		RPCTimeouts:          defaultRPCTimeouts,
+		RPCBuckets:           rpcmetrics.DefaultUnaryBuckets,
+		RPCStreamBuckets:     rpcmetrics.DefaultStreamBuckets,
+		GeminiModel:          advisor.DefaultModel,
+		GeocodingURL:         advisor.GeocodingURL,
+		ForecastURL:          weather.ForecastURL,
+		AirQualityURL:        weather.AirQualityURL,
+		UpstreamTimeout:      10 * time.Second,
+		UpstreamMaxBodyBytes: httpclient.MaxBodyBytes,
+		ShutdownGrace:        30 * time.Second,
+		LogFormat:            "json",
+		LogLevel:             "info",
+		ServiceName:          "weather-advisor",
+		TraceSampleRatio:     1,
+		StorageMigrate:       "auto",
+		SessionTTL:           30 * time.Minute,
+		RedisAddr:            "localhost:6379",
+		AdviceHistoryPath:    "advice_history.jsonl",
+		GeocodeCacheTTL:      30 * 24 * time.Hour,
+		WeatherCacheTTL:      10 * time.Minute,
+		AuditLog:             "off",
+		AuditLogPath:         "audit.jsonl",
+		AuditLogMaxBytes:     100 << 20,
+		MQTTTopicPrefix:      "weather",
+		MQTTQoS:              1,
+		EventBusTopic:        "weather",
+		SemanticCacheTTL:     15 * time.Minute,
+		LLMBudgetMode:        "fallback",
+	}
+}
+
+// setting ties a config field to its environment variable and flag. value
+// is a *string, *bool, *time.Duration, *int64 or *float64 into the config.
+type setting struct {
+	env, flag, usage string
+	value            any
+}
+
+func (c *config) settings() []setting {
+	return []setting{
+		{"GRPC_ADDR", "grpc-addr", "gRPC listen address, host:port or systemd:<name> for a socket-activated one", &c.GRPCAddr},
+		{"METRICS_ADDR", "metrics-addr", "metrics and admin HTTP listen address, host:port or systemd:<name>", &c.MetricsAddr},
+		{"HTTP_ADDR", "http-addr", "REST gateway listen address, host:port or systemd:<name> (empty disables the gateway)", &c.HTTPAddr},
+		{"DASHBOARD", "dashboard", "serve the web dashboard at /ui/ on the REST gateway", &c.Dashboard},
+		{"SERVICES", "services", "comma-separated services to run: weather, advisor or both", &c.Services},
+		{"WEATHER_ADDR", "weather-addr", "weather replicas the advisor calls when weather is not run here: host:port list or a gRPC target such as dns:///weather:8082", &c.WeatherAddr},
+		{"WEATHER_TLS", "weather-tls", "connect to weather_addr over TLS", &c.WeatherTLS},
+		{"WEATHER_CA_FILE", "weather-ca", "PEM CAs to verify the weather service with (implies weather_tls)", &c.WeatherCAFile},
+		{"GRPC_MAX_CONCURRENT_STREAMS", "max-concurrent-streams", "concurrent RPCs allowed per client connection (0 is unlimited)", &c.MaxConcurrentStreams},
+		{"GRPC_MAX_RECV_MSG_SIZE", "max-recv-msg-size", "largest request message in bytes", &c.MaxRecvMsgSize},
+		{"GRPC_MAX_SEND_MSG_SIZE", "max-send-msg-size", "largest response message in bytes (0 is unlimited)", &c.MaxSendMsgSize},
+		{"GRPC_CONNECTION_TIMEOUT", "connection-timeout", "time a new connection has to finish the TLS and HTTP/2 handshakes", &c.ConnectionTimeout},
+		{"GRPC_KEEPALIVE_TIME", "keepalive-time", "idle time after which the server pings a client connection", &c.KeepaliveTime},
+		{"GRPC_KEEPALIVE_TIMEOUT", "keepalive-timeout", "how long a keepalive ping may go unanswered before the connection is closed", &c.KeepaliveTimeout},
+		{"GRPC_KEEPALIVE_MIN_TIME", "keepalive-min-time", "shortest client ping interval allowed; faster clients are disconnected", &c.KeepaliveMinTime},
+		{"GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", "keepalive-permit-without-stream", "allow client pings on connections with no open RPC", &c.KeepaliveNoStream},
+		{"WEATHER_KEEPALIVE_TIME", "weather-keepalive-time", "idle time after which the advisor pings the weather service (0 disables)", &c.WeatherKeepalive},
+		{"WEATHER_COMPRESSION", "weather-compression", "gzip the advisor's calls to the weather service", &c.WeatherCompression},
+		{"RPC_TIMEOUTS", "rpc-timeouts", "server-side time limits as service[/method]=duration pairs; 0 means none", &c.RPCTimeouts},
+		{"RPC_BUCKETS", "rpc-buckets", "latency histogram buckets for unary RPCs, comma-separated seconds", &c.RPCBuckets},
+		{"RPC_STREAM_BUCKETS", "rpc-stream-buckets", "duration histogram buckets for streaming RPCs, comma-separated seconds", &c.RPCStreamBuckets},
+		{"GEMINI_MODEL", "model", "default Gemini model", &c.GeminiModel},
+		{"GEOCODING_URL", "geocoding-url", "geocoding search endpoint", &c.GeocodingURL},
+		{"FORECAST_URL", "forecast-url", "weather forecast endpoint", &c.ForecastURL},
+		{"AIR_QUALITY_URL", "air-quality-url", "air quality forecast endpoint", &c.AirQualityURL},
+		{"UPSTREAM_TIMEOUT", "upstream-timeout", "timeout for each upstream HTTP request", &c.UpstreamTimeout},
+		{"UPSTREAM_MAX_BODY_BYTES", "upstream-max-body-bytes", "largest upstream response read", &c.UpstreamMaxBodyBytes},
+		{"SHUTDOWN_GRACE", "shutdown-grace", "how long in-flight RPCs may run after SIGINT/SIGTERM", &c.ShutdownGrace},
+		{"TLS_CERT_FILE", "tls-cert", "PEM certificate chain for gRPC TLS", &c.TLSCertFile},
+		{"TLS_KEY_FILE", "tls-key", "PEM private key for gRPC TLS", &c.TLSKeyFile},
+		{"TLS_CLIENT_CA_FILE", "tls-client-ca", "PEM CAs that client certificates must chain to (enables mTLS)", &c.TLSClientCAFile},
+		{"JWT_ISSUER", "jwt-issuer", "required token issuer (enables JWT authentication)", &c.JWTIssuer},
+		{"JWT_AUDIENCE", "jwt-audience", "required token audience", &c.JWTAudience},
+		{"JWT_JWKS_URL", "jwt-jwks-url", "signing keys URL (default from the issuer's OIDC discovery)", &c.JWTJWKSURL},
+		{"JWT_TENANT_CLAIM", "jwt-tenant-claim", "token claim naming the caller's tenant, e.g. org_id", &c.JWTTenantClaim},
+		{"GRPC_REFLECTION", "reflection", "register the gRPC reflection service for grpcurl and evans", &c.Reflection},
+		{"LOG_FORMAT", "log-format", "log output, json or text", &c.LogFormat},
+		{"LOG_LEVEL", "log-level", "minimum log level: debug, info, warn or error", &c.LogLevel},
+		{"OTEL_EXPORTER_OTLP_ENDPOINT", "otlp-endpoint", "OTLP/HTTP collector to export traces to, e.g. http://localhost:4318 (enables tracing)", &c.OTLPEndpoint},
+		{"OTEL_SERVICE_NAME", "service-name", "service.name reported on exported traces", &c.ServiceName},
+		{"TRACE_SAMPLE_RATIO", "trace-sample-ratio", "share of new traces recorded, 0 to 1", &c.TraceSampleRatio},
+		{"STORAGE_DSN", "storage-dsn", "database for history, sessions, subscriptions, API keys and geocodes: a SQLite path or a postgres:// URL", &c.StorageDSN},
+		{"STORAGE_MIGRATE", "storage-migrate", "auto applies pending migrations at startup, off refuses to start while any are pending", &c.StorageMigrate},
+		{"SESSION_STORE", "session-store", "conversation store: memory, redis or sql (default sql with storage_dsn, else memory)", &c.SessionStore},
+		{"SESSION_TTL", "session-ttl", "how long an idle conversation is kept", &c.SessionTTL},
+		{"REDIS_ADDR", "redis-addr", "Redis address when session_store is redis", &c.RedisAddr},
+		{"REDIS_DB", "redis-db", "Redis database number when session_store is redis", &c.RedisDB},
+		{"ADVICE_HISTORY", "advice-history", "where generated advice is kept: memory, file, sql or off (default sql with storage_dsn, else memory)", &c.AdviceHistory},
+		{"ADVICE_HISTORY_PATH", "advice-history-path", "JSON Lines file when advice_history is file", &c.AdviceHistoryPath},
+		{"DIGEST_STORE", "digest-store", "where digest subscriptions are kept: memory or sql (default sql with storage_dsn, else memory)", &c.DigestStore},
+		{"ALERT_STORE", "alert-store", "where alert subscriptions are kept: memory or sql (default sql with storage_dsn, else memory)", &c.AlertStore},
+		{"GEOCODE_CACHE_TTL", "geocode-cache-ttl", "how long geocoding answers are reused from the database (0 disables)", &c.GeocodeCacheTTL},
+		{"WEATHER_CACHE_TTL", "weather-cache-ttl", "how long current conditions are cached (0 disables)", &c.WeatherCacheTTL},
+		{"JOB_SCHEDULES", "job-schedules", "background job schedule overrides as name=schedule pairs separated by semicolons", &c.JobSchedules},
+		{"TENANTS_FILE", "tenants-file", "YAML file of tenants and their quotas (enables tenants)", &c.TenantsFile},
+		{"AUDIT_LOG", "audit-log", "audit trail of advisor usage: off, memory or file", &c.AuditLog},
+		{"AUDIT_LOG_PATH", "audit-log-path", "JSON Lines file when audit_log is file", &c.AuditLogPath},
+		{"AUDIT_LOG_MAX_BYTES", "audit-log-max-bytes", "size past which the audit file is rotated", &c.AuditLogMaxBytes},
+		{"AUDIT_LOG_KEEP", "audit-log-keep", "rotated audit files to keep (0 keeps all)", &c.AuditLogKeep},
+		{"AUDIT_EXPORTERS", "audit-exporters", "comma-separated caller identities allowed to call ExportAuditLog", &c.AuditExporters},
+		{"STREAM_SINK_WEBHOOKS", "stream-sink-webhooks", "comma-separated URLs that receive a copy of every streamed advice", &c.StreamSinkWebhooks},
+		{"NOTIFY_RECIPIENTS_FILE", "notify-recipients-file", "YAML file of each recipient's delivery preferences", &c.NotifyRecipientsFile},
+		{"WEBHOOK_DEAD_LETTER_FILE", "webhook-dead-letter-file", "file failed generic webhook deliveries are appended to (default the log)", &c.WebhookDeadLetterFile},
+		{"WEBHOOK_ALLOW_PRIVATE", "webhook-allow-private", "let generic webhooks post to loopback and private addresses", &c.WebhookAllowPrivate},
+		{"SMTP_ADDR", "smtp-addr", "mail server host:port digests and alerts are emailed through (enables email)", &c.SMTPAddr},
+		{"SMTP_USERNAME", "smtp-username", "SMTP user name; the password is read from SMTP_PASSWORD", &c.SMTPUsername},
+		{"SMTP_FROM", "smtp-from", "sender address of notification emails", &c.SMTPFrom},
+		{"EMAIL_TEMPLATE_DIR", "email-template-dir", "directory with templates replacing the built-in email templates", &c.EmailTemplateDir},
+		{"MQTT_BROKER", "mqtt-broker", "MQTT broker URL to publish conditions and alerts to (enables MQTT)", &c.MQTTBroker},
+		{"MQTT_LOCATIONS", "mqtt-locations", "semicolon-separated name=lat,lon locations whose conditions are published", &c.MQTTLocations},
+		{"MQTT_TOPIC_PREFIX", "mqtt-topic-prefix", "first MQTT topic level", &c.MQTTTopicPrefix},
+		{"MQTT_CLIENT_ID", "mqtt-client-id", "MQTT client ID (default weather-advisor-<hostname>)", &c.MQTTClientID},
+		{"MQTT_USERNAME", "mqtt-username", "MQTT user name; the password is read from MQTT_PASSWORD", &c.MQTTUsername},
+		{"MQTT_QOS", "mqtt-qos", "QoS of published MQTT messages, 0 to 2", &c.MQTTQoS},
+		{"EVENT_BUS", "event-bus", "nats or kafka to publish events to (empty disables)", &c.EventBus},
+		{"EVENT_BUS_URL", "event-bus-url", "NATS server URL or comma-separated Kafka brokers", &c.EventBusURL},
+		{"EVENT_BUS_TOPIC", "event-bus-topic", "NATS subject prefix or Kafka topic", &c.EventBusTopic},
+		{"SEMANTIC_CACHE_THRESHOLD", "semantic-cache-threshold", "cosine similarity, 0 to 1, at which recent advice is reused (0 disables)", &c.SemanticCacheThreshold},
+		{"SEMANTIC_CACHE_TTL", "semantic-cache-ttl", "how long cached advice is reused", &c.SemanticCacheTTL},
+		{"LLM_QPS", "llm-qps", "Gemini calls allowed per second (0 is unlimited)", &c.LLMQPS},
+		{"LLM_TOKENS_PER_MINUTE", "llm-tokens-per-minute", "Gemini tokens allowed per minute (0 is unlimited)", &c.LLMTokensPerMinute},
+		{"LLM_BUDGET_DAILY_USD", "llm-budget-daily-usd", "estimated Gemini spend allowed per UTC day (0 is unlimited)", &c.LLMBudgetDailyUSD},
+		{"LLM_BUDGET_MONTHLY_USD", "llm-budget-monthly-usd", "estimated Gemini spend allowed per month (0 is unlimited)", &c.LLMBudgetMonthlyUSD},
+		{"LLM_BUDGET_DAILY_TOKENS", "llm-budget-daily-tokens", "Gemini tokens allowed per UTC day (0 is unlimited)", &c.LLMBudgetDailyTokens},
+		{"LLM_BUDGET_MONTHLY_TOKENS", "llm-budget-monthly-tokens", "Gemini tokens allowed per month (0 is unlimited)", &c.LLMBudgetMonthlyTokens},
+		{"LLM_BUDGET_MODE", "llm-budget-mode", "once a budget is used up: fallback to template advice or reject", &c.LLMBudgetMode},
+	}
+}
+
+// registerFlags adds --config and a flag per setting. Flags are parsed into
+// their own config so they can be applied last, after the file and env.
+func registerFlags(cmd *cobra.Command, flags *config) {
+	*flags = defaultConfig()
+	cmd.Flags().String("config", "", "YAML config file (default $SERVER_CONFIG)")
+	for _, s := range flags.settings() {
+		switch v := s.value.(type) {
+		case *string:
+			cmd.Flags().StringVar(v, s.flag, *v, s.usage+" (env "+s.env+")")
+		case *bool:
+			cmd.Flags().BoolVar(v, s.flag, *v, s.usage+" (env "+s.env+")")
+		case *time.Duration:
+			cmd.Flags().DurationVar(v, s.flag, *v, s.usage+" (env "+s.env+")")
+		case *int64:
+			cmd.Flags().Int64Var(v, s.flag, *v, s.usage+" (env "+s.env+")")
+		case *float64:
+			cmd.Flags().Float64Var(v, s.flag, *v, s.usage+" (env "+s.env+")")
+		}
+	}
+}
+
+// loadConfig merges the defaults, config file, environment and the flags
+// that were set on cmd, and validates the result.
+func loadConfig(cmd *cobra.Command, flags *config) (config, error) {
+	cfg := defaultConfig()
+
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = os.Getenv("SERVER_CONFIG")
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("config file: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("config file %s: %v", path, err)
+		}
+	}
+
+	settings := cfg.settings()
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			if err := parseSetting(s.value, v); err != nil {
+				return cfg, fmt.Errorf("invalid %s %q: %v", s.env, v, err)
+			}
+		}
+	}
+	for i, f := range flags.settings() {
+		if !cmd.Flags().Changed(f.flag) {
+			continue
+		}
+		switch v := settings[i].value.(type) {
+		case *string:
+			*v = *f.value.(*string)
+		case *bool:
+			*v = *f.value.(*bool)
+		case *time.Duration:
+			*v = *f.value.(*time.Duration)
+		case *int64:
+			*v = *f.value.(*int64)
+		case *float64:
+			*v = *f.value.(*float64)
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+func parseSetting(target any, raw string) error {
+	switch v := target.(type) {
+	case *string:
+		*v = raw
+	case *bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		*v = b
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		*v = d
+	case *int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		*v = n
+	case *float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		*v = f
+	}
+	return nil
+}
+
+func (c *config) validate() error {
+	for _, a := range []struct{ name, addr string }{{"grpc_addr", c.GRPCAddr}, {"metrics_addr", c.MetricsAddr}} {
+		if err := validListenAddr(a.addr); err != nil {
+			return fmt.Errorf("invalid %s %q: %v", a.name, a.addr, err)
+		}
+	}
+	if c.GRPCAddr == c.MetricsAddr {
+		return fmt.Errorf("grpc_addr and metrics_addr are both %q", c.GRPCAddr)
+	}
+	if c.HTTPAddr != "" {
+		if err := validListenAddr(c.HTTPAddr); err != nil {
+			return fmt.Errorf("invalid http_addr %q: %v", c.HTTPAddr, err)
+		}
+		if c.HTTPAddr == c.GRPCAddr || c.HTTPAddr == c.MetricsAddr {
+			return fmt.Errorf("http_addr %q is already used by grpc_addr or metrics_addr", c.HTTPAddr)
+		}
+	}
+	if err := c.validateServices(); err != nil {
+		return err
+	}
+	if c.MaxConcurrentStreams < 0 || c.MaxConcurrentStreams > math.MaxUint32 {
+		return fmt.Errorf("max_concurrent_streams must be between 0 and %d, got %d", uint32(math.MaxUint32), c.MaxConcurrentStreams)
+	}
+	if c.MaxRecvMsgSize <= 0 || c.MaxRecvMsgSize > math.MaxInt32 {
+		return fmt.Errorf("max_recv_msg_size must be between 1 and %d, got %d", math.MaxInt32, c.MaxRecvMsgSize)
+	}
+	if c.MaxSendMsgSize < 0 || c.MaxSendMsgSize > math.MaxInt32 {
+		return fmt.Errorf("max_send_msg_size must be between 0 and %d, got %d", math.MaxInt32, c.MaxSendMsgSize)
+	}
+	if c.ConnectionTimeout <= 0 {
+		return fmt.Errorf("connection_timeout must be positive, got %s", c.ConnectionTimeout)
+	}
+	for _, k := range []struct {
+		name string
+		d    time.Duration
+	}{{"keepalive_time", c.KeepaliveTime}, {"keepalive_timeout", c.KeepaliveTimeout}, {"keepalive_min_time", c.KeepaliveMinTime}} {
+		if k.d <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", k.name, k.d)
+		}
+	}
+	if c.WeatherKeepalive != 0 && c.WeatherKeepalive < 10*time.Second {
+		// gRPC raises shorter client intervals to 10s anyway.
+		return fmt.Errorf("weather_keepalive_time must be 0 or at least 10s, got %s", c.WeatherKeepalive)
+	}
+	if _, err := deadline.Parse(c.RPCTimeouts); err != nil {
+		return fmt.Errorf("rpc_timeouts: %v", err)
+	}
+	if _, err := rpcmetrics.ParseBuckets(c.RPCBuckets); err != nil {
+		return fmt.Errorf("rpc_buckets: %v", err)
+	}
+	if _, err := rpcmetrics.ParseBuckets(c.RPCStreamBuckets); err != nil {
+		return fmt.Errorf("rpc_stream_buckets: %v", err)
+	}
+	if c.GeminiModel == "" {
+		return fmt.Errorf("gemini_model must not be empty")
+	}
+	for _, e := range []struct{ name, raw string }{{"geocoding_url", c.GeocodingURL}, {"forecast_url", c.ForecastURL}, {"air_quality_url", c.AirQualityURL}} {
+		u, err := url.Parse(e.raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" {
+			return fmt.Errorf("invalid %s %q (want an http(s) URL without a query)", e.name, e.raw)
+		}
+	}
+	if c.UpstreamTimeout <= 0 {
+		return fmt.Errorf("upstream_timeout must be positive, got %s", c.UpstreamTimeout)
+	}
+	if c.UpstreamMaxBodyBytes <= 0 {
+		return fmt.Errorf("upstream_max_body_bytes must be positive, got %d", c.UpstreamMaxBodyBytes)
+	}
+	if c.ShutdownGrace < 0 {
+		return fmt.Errorf("shutdown_grace must not be negative, got %s", c.ShutdownGrace)
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("tls_cert_file and tls_key_file must be set together")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		return fmt.Errorf("tls_client_ca_file needs tls_cert_file and tls_key_file")
+	}
+	if c.TLSClientCAFile != "" && c.HTTPAddr != "" {
+		// The gateway has no client certificate of its own to present.
+		return fmt.Errorf("http_addr cannot be used with tls_client_ca_file")
+	}
+	if c.OTLPEndpoint != "" {
+		if u, err := url.Parse(c.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid otlp_endpoint %q (want an http(s) URL)", c.OTLPEndpoint)
+		}
+		if c.ServiceName == "" {
+			return fmt.Errorf("service_name must not be empty when tracing")
+		}
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		return fmt.Errorf("trace_sample_ratio must be between 0 and 1, got %v", c.TraceSampleRatio)
+	}
+	if _, err := logging.New(c.LogFormat, c.LogLevel, io.Discard); err != nil {
+		return err
+	}
+	if c.JWTIssuer != "" || c.JWTAudience != "" || c.JWTJWKSURL != "" || c.JWTTenantClaim != "" {
+		if c.JWTIssuer == "" || c.JWTAudience == "" {
+			return fmt.Errorf("jwt_issuer and jwt_audience must be set together")
+		}
+		urls := []string{c.JWTIssuer}
+		if c.JWTJWKSURL != "" {
+			urls = append(urls, c.JWTJWKSURL)
+		}
+		for _, raw := range urls {
+			if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("invalid JWT URL %q (want http(s))", raw)
+			}
+		}
+	}
+	if err := c.validateStorage(); err != nil {
+		return err
+	}
+	return c.validateIntegrations()
+}
+
+// validateStorage checks the database and the stores kept in it.
+func (c *config) validateStorage() error {
+	if c.StorageMigrate != "auto" && c.StorageMigrate != "off" {
+		return fmt.Errorf("storage_migrate must be auto or off, got %q", c.StorageMigrate)
+	}
+	for _, st := range []struct {
+		name, kind string
+		kinds      []string
+	}{
+		{"session_store", c.SessionStore, []string{"memory", "redis", "sql"}},
+		{"advice_history", c.AdviceHistory, []string{"memory", "file", "sql", "off"}},
+		{"digest_store", c.DigestStore, []string{"memory", "sql"}},
+		{"alert_store", c.AlertStore, []string{"memory", "sql"}},
+	} {
+		if st.kind != "" && !slices.Contains(st.kinds, st.kind) {
+			return fmt.Errorf("unknown %s %q (want %s)", st.name, st.kind, strings.Join(st.kinds, ", "))
+		}
+		if st.kind == "sql" && c.StorageDSN == "" {
+			return fmt.Errorf("%s sql needs storage_dsn", st.name)
+		}
+	}
+	if c.SessionTTL <= 0 {
+		return fmt.Errorf("session_ttl must be positive, got %s", c.SessionTTL)
+	}
+	if c.RedisDB < 0 {
+		return fmt.Errorf("redis_db must not be negative, got %d", c.RedisDB)
+	}
+	if c.GeocodeCacheTTL < 0 {
+		return fmt.Errorf("geocode_cache_ttl must not be negative, got %s", c.GeocodeCacheTTL)
+	}
+	if c.WeatherCacheTTL < 0 {
+		return fmt.Errorf("weather_cache_ttl must not be negative, got %s", c.WeatherCacheTTL)
+	}
+	switch c.AuditLog {
+	case "off", "memory", "file":
+	default:
+		return fmt.Errorf("unknown audit_log %q (want off, memory or file)", c.AuditLog)
+	}
+	if c.AuditLogMaxBytes <= 0 {
+		return fmt.Errorf("audit_log_max_bytes must be positive, got %d", c.AuditLogMaxBytes)
+	}
+	if c.AuditLogKeep < 0 {
+		return fmt.Errorf("audit_log_keep must not be negative, got %d", c.AuditLogKeep)
+	}
+	if _, err := jobs.ParseOverrides(c.JobSchedules); err != nil {
+		return fmt.Errorf("job_schedules: %v", err)
+	}
+	return nil
+}
+
+// validateIntegrations checks the brokers, notification channels and LLM
+// limits.
+func (c *config) validateIntegrations() error {
+	if c.MQTTBroker != "" {
+		if _, err := mqtt.ParseLocations(c.MQTTLocations); err != nil {
+			return fmt.Errorf("mqtt_locations: %v", err)
+		}
+	}
+	if c.MQTTQoS < 0 || c.MQTTQoS > 2 {
+		return fmt.Errorf("mqtt_qos must be between 0 and 2, got %d", c.MQTTQoS)
+	}
+	switch c.EventBus {
+	case "":
+	case "nats", "kafka":
+		if c.EventBusURL == "" {
+			return fmt.Errorf("event_bus_url is required with event_bus %s", c.EventBus)
+		}
+	default:
+		return fmt.Errorf("unknown event_bus %q (want nats or kafka)", c.EventBus)
+	}
+	if c.SMTPAddr != "" {
+		if _, _, err := net.SplitHostPort(c.SMTPAddr); err != nil {
+			return fmt.Errorf("invalid smtp_addr %q: %v", c.SMTPAddr, err)
+		}
+		if c.SMTPFrom == "" {
+			return fmt.Errorf("smtp_from is required with smtp_addr")
+		}
+	}
+	if c.SemanticCacheThreshold < 0 || c.SemanticCacheThreshold > 1 {
+		return fmt.Errorf("semantic_cache_threshold must be between 0 and 1, got %v", c.SemanticCacheThreshold)
+	}
+	if c.SemanticCacheTTL <= 0 {
+		return fmt.Errorf("semantic_cache_ttl must be positive, got %s", c.SemanticCacheTTL)
+	}
+	for _, l := range []struct {
+		name  string
+		value float64
+	}{
+		{"llm_qps", c.LLMQPS},
+		{"llm_tokens_per_minute", float64(c.LLMTokensPerMinute)},
+		{"llm_budget_daily_usd", c.LLMBudgetDailyUSD},
+		{"llm_budget_monthly_usd", c.LLMBudgetMonthlyUSD},
+		{"llm_budget_daily_tokens", float64(c.LLMBudgetDailyTokens)},
+		{"llm_budget_monthly_tokens", float64(c.LLMBudgetMonthlyTokens)},
+	} {
+		if l.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %v", l.name, l.value)
+		}
+	}
+	if c.LLMTokensPerMinute > math.MaxInt32 {
+		return fmt.Errorf("llm_tokens_per_minute must be at most %d, got %d", math.MaxInt32, c.LLMTokensPerMinute)
+	}
+	if c.LLMBudgetMode != "fallback" && c.LLMBudgetMode != "reject" {
+		return fmt.Errorf("llm_budget_mode must be fallback or reject, got %q", c.LLMBudgetMode)
+	}
+	return nil
+}
+
+// knownServices are the gRPC services one process can run.
+var knownServices = map[string]bool{"weather": true, "advisor": true}
+
+// serves reports whether the named service runs in this process.
+func (c *config) serves(name string) bool {
+	for _, s := range strings.Split(c.Services, ",") {
+		if strings.TrimSpace(s) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *config) validateServices() error {
+	if strings.TrimSpace(c.Services) == "" {
+		return fmt.Errorf("services must name at least one of weather and advisor")
+	}
+	for _, s := range strings.Split(c.Services, ",") {
+		if !knownServices[strings.TrimSpace(s)] {
+			return fmt.Errorf("unknown service %q in services (want weather or advisor)", strings.TrimSpace(s))
+		}
+	}
+	if c.serves("weather") {
+		if c.WeatherAddr != "" {
+			return fmt.Errorf("weather_addr is set but the weather service runs in this process")
+		}
+		return nil
+	}
+	if strings.TrimSpace(c.WeatherAddr) == "" {
+		return fmt.Errorf("weather_addr is required when services does not include weather")
+	}
+	endpoints := weatherEndpoints(c.WeatherAddr)
+	if len(endpoints) == 1 && strings.Contains(endpoints[0], "://") {
+		// A resolver target such as dns:///weather:8082.
+		return nil
+	}
+	for _, e := range endpoints {
+		if _, _, err := net.SplitHostPort(e); err != nil {
+			return fmt.Errorf("invalid weather_addr entry %q: %v", e, err)
+		}
+	}
+	return nil
+}
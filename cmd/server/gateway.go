@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/dashboard"
+	"github.com/pixperk/effinarounf/services/gateway"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newGateway returns the REST gateway's HTTP server, which forwards to the
+// gRPC server listening on grpcAddr, and the connection it forwards over.
+// traced adds the otel handlers so gateway requests and their RPCs share
+// a trace.
+func newGateway(cfg config, grpcAddr net.Addr, traced bool) (*http.Server, *grpc.ClientConn, error) {
+	creds, err := gatewayCredentials(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if traced {
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+	conn, err := grpc.NewClient(loopbackTarget(grpcAddr), opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to gRPC server: %v", err)
+	}
+
+	handler, err := gateway.NewHandler(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	if cfg.Dashboard {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		dashboard.Register(mux)
+		handler = mux
+	}
+	if traced {
+		handler = otelhttp.NewHandler(handler, "gateway", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}))
+	}
+	srv := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv, conn, nil
+}
+
+// loopbackTarget turns the gRPC listen address into one to dial, replacing
+// a wildcard host with localhost. A socket-activated Unix socket is dialed
+// by its path.
+func loopbackTarget(addr net.Addr) string {
+	if addr.Network() == "unix" {
+		return "unix://" + addr.String()
+	}
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// gatewayCredentials connects to the server the way it serves. With TLS
+// the gateway only ever talks to this process, so instead of verifying a
+// hostname the certificate may not carry, it checks that the server
+// presents the certificate loaded from tls_cert_file.
+func gatewayCredentials(cfg config) (credentials.TransportCredentials, error) {
+	if cfg.TLSCertFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS key pair: %v", err)
+	}
+	leaf := cert.Certificate[0]
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], leaf) {
+				return fmt.Errorf("gRPC server did not present %s", cfg.TLSCertFile)
+			}
+			return nil
+		},
+	}), nil
+}
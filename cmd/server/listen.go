@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// systemdPrefix marks a listen address as a socket passed in by systemd
+// socket activation: systemd:<name>, where name is the FileDescriptorName=
+// of the socket in the .socket unit.
+const systemdPrefix = "systemd:"
+
+// listenFDsStart is the first file descriptor systemd passes.
+const listenFDsStart = 3
+
+// activatedSockets returns the sockets systemd passed to this process, by
+// name, or none when it wasn't socket-activated. The LISTEN_* variables are
+// then unset so processes started by the server don't take them as theirs.
+var activatedSockets = sync.OnceValues(func() (map[string]*os.File, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", os.Getenv("LISTEN_FDS"))
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	files := make(map[string]*os.File, n)
+	for i := range n {
+		// systemd names sockets after their unit unless told otherwise.
+		name := "unknown"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("systemd passed two sockets named %q; set FileDescriptorName= on each", name)
+		}
+		files[name] = os.NewFile(uintptr(listenFDsStart+i), name)
+	}
+	return files, nil
+})
+
+// listen opens the listener for addr: a TCP host:port, or a socket passed
+// by systemd as systemd:<name>.
+func listen(addr string) (net.Listener, error) {
+	name, ok := strings.CutPrefix(addr, systemdPrefix)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	files, err := activatedSockets()
+	if err != nil {
+		return nil, err
+	}
+	f := files[name]
+	if f == nil {
+		return nil, fmt.Errorf("systemd passed no socket named %q (LISTEN_FDNAMES)", name)
+	}
+	delete(files, name)
+	// FileListener works on a duplicate, so the original can be closed.
+	defer f.Close()
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("socket %q from systemd: %v", name, err)
+	}
+	return l, nil
+}
+
+// validListenAddr checks a listen address without opening it.
+func validListenAddr(addr string) error {
+	if name, ok := strings.CutPrefix(addr, systemdPrefix); ok {
+		if name == "" {
+			return fmt.Errorf("missing the socket's FileDescriptorName after %s", systemdPrefix)
+		}
+		return nil
+	}
+	_, _, err := net.SplitHostPort(addr)
+	return err
+}
@@ -1,54 +1,941 @@
 package main
 
 import (
-	"log"
-	"net"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/pixperk/effinarounf/services/advisor"
+	"github.com/pixperk/effinarounf/services/alert"
+	"github.com/pixperk/effinarounf/services/audit"
+	"github.com/pixperk/effinarounf/services/auth"
+	"github.com/pixperk/effinarounf/services/deadline"
+	"github.com/pixperk/effinarounf/services/digest"
+	"github.com/pixperk/effinarounf/services/events"
+	"github.com/pixperk/effinarounf/services/gateway"
+	"github.com/pixperk/effinarounf/services/history"
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/pixperk/effinarounf/services/identity"
+	"github.com/pixperk/effinarounf/services/jobs"
+	"github.com/pixperk/effinarounf/services/logging"
+	"github.com/pixperk/effinarounf/services/mqtt"
+	"github.com/pixperk/effinarounf/services/notify"
+	"github.com/pixperk/effinarounf/services/readiness"
+	"github.com/pixperk/effinarounf/services/rpcmetrics"
+	"github.com/pixperk/effinarounf/services/session"
+	"github.com/pixperk/effinarounf/services/sink"
+	"github.com/pixperk/effinarounf/services/storage"
+	"github.com/pixperk/effinarounf/services/tenant"
+	"github.com/pixperk/effinarounf/services/tracing"
+	"github.com/pixperk/effinarounf/services/validate"
 	"github.com/pixperk/effinarounf/services/weather"
 	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
 	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
+	// Registering gzip lets clients send gzipped requests and advertises it
+	// in grpc-accept-encoding; replies to a gzipped call are gzipped too.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+	// Load .env file before the config is read so it can set overrides.
+	envErr := godotenv.Load()
+
+	var flags config
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Run the weather and advisor gRPC server",
+		Args:  cobra.NoArgs,
+		// Configuration errors are reported by run, not as usage errors.
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd, &flags)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %v", err)
+			}
+			logger, err := logging.New(cfg.LogFormat, cfg.LogLevel, os.Stderr)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %v", err)
+			}
+			slog.SetDefault(logger)
+			if envErr != nil {
+				slog.Info("no .env file found, using system environment variables")
+			}
+			run(cfg, logger)
+			return nil
+		},
 	}
+	registerFlags(cmd, &flags)
+	cmd.AddCommand(&cobra.Command{
+		Use:   "openapi",
+		Short: "Print the REST gateway's OpenAPI document",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := cmd.OutOrStdout().Write(gateway.OpenAPI())
+			return err
+		},
+	})
+
+	cmd.AddCommand(migrateCommand())
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(cfg config, logger *slog.Logger) {
 	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY not set")
+	if geminiAPIKey == "" && cfg.serves("advisor") {
+		slog.Warn("GEMINI_API_KEY not set, serving rule-based template advice only")
+	}
+	advisor.GeocodingURL = cfg.GeocodingURL
+	weather.ForecastURL = cfg.ForecastURL
+	weather.AirQualityURL = cfg.AirQualityURL
+	httpclient.MaxBodyBytes = cfg.UpstreamMaxBodyBytes
+
+	// The first SIGINT/SIGTERM starts a graceful shutdown; a second one
+	// exits at once.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db := newStorage(ctx, cfg)
+	if db != nil {
+		defer db.Close()
+	}
+	// Validated with the rest of the config.
+	overrides, _ := jobs.ParseOverrides(cfg.JobSchedules)
+	scheduler := jobs.NewScheduler(overrides)
+	budget := newBudget(cfg)
+	// With ADMIN_TOKEN set, every /admin endpoint requires it and the
+	// advisor's cache, stream and mode endpoints are served too.
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", readiness.Live)
+	if budget != nil && adminToken != "" {
+		mux.Handle("GET /admin/budget", requireToken(adminToken, budget))
+	}
+	metricsSrv := &http.Server{Addr: cfg.MetricsAddr, Handler: mux}
+	metricsLis, err := listen(cfg.MetricsAddr)
+	if err != nil {
+		fatal("listen failed", "addr", cfg.MetricsAddr, "error", err)
+	}
+	go func() {
+		slog.Info("metrics server listening", "addr", metricsLis.Addr().String())
+		if err := metricsSrv.Serve(metricsLis); err != http.ErrServerClosed {
+			fatal("metrics server failed", "error", err)
+		}
+	}()
+
+	lis, err := listen(cfg.GRPCAddr)
+	if err != nil {
+		fatal("listen failed", "addr", cfg.GRPCAddr, "error", err)
+	}
+
+	// One pooled client for all upstream HTTP so connections are reused.
+	httpClient := httpclient.New(cfg.UpstreamTimeout)
+	tracer := newTracerProvider(cfg)
+	if tracer != nil {
+		httpClient.Transport = otelhttp.NewTransport(httpClient.Transport,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + r.URL.Host
+			}))
+	}
+
+	creds, err := serverCredentials(cfg)
+	if err != nil {
+		fatal("TLS setup failed", "error", err)
+	}
+	// Metrics and logging go first so calls rejected by auth are counted
+	// and logged too. The buckets are validated with the rest of the config.
+	unaryBuckets, _ := rpcmetrics.ParseBuckets(cfg.RPCBuckets)
+	streamBuckets, _ := rpcmetrics.ParseBuckets(cfg.RPCStreamBuckets)
+	rpcMetrics := rpcmetrics.New(unaryBuckets, streamBuckets)
+	unary := []grpc.UnaryServerInterceptor{rpcMetrics.UnaryServerInterceptor(), logging.UnaryServerInterceptor(logger)}
+	streams := []grpc.StreamServerInterceptor{rpcMetrics.StreamServerInterceptor(), logging.StreamServerInterceptor(logger)}
+	// Validated with the rest of the config.
+	timeouts, _ := deadline.Parse(cfg.RPCTimeouts)
+	unary = append(unary, timeouts.UnaryServerInterceptor())
+	streams = append(streams, timeouts.StreamServerInterceptor())
+	// API keys are checked before bearer tokens, which they stand in for.
+	tenants := newTenants(cfg)
+	if tenants != nil && db != nil {
+		tenants.SetKeyStore(tenant.NewSQLKeyStore(db))
+		if adminToken != "" {
+			mux.Handle("/admin/api-keys", requireToken(adminToken, tenants.AdminHandler()))
+			mux.Handle("/admin/api-keys/", requireToken(adminToken, tenants.AdminHandler()))
+		}
+	}
+	if tenants != nil {
+		unary = append(unary, tenants.UnaryServerInterceptor())
+		streams = append(streams, tenants.StreamServerInterceptor())
+	}
+	if verifier := newTokenVerifier(cfg, httpClient); verifier != nil {
+		unary = append(unary, verifier.UnaryServerInterceptor())
+		streams = append(streams, verifier.StreamServerInterceptor())
+	}
+	unary = append(unary, identity.UnaryServerInterceptor())
+	streams = append(streams, identity.StreamServerInterceptor())
+	// Invalid requests are rejected before they count against a quota.
+	validator := newValidator(cfg)
+	unary = append(unary, validator.UnaryServerInterceptor())
+	streams = append(streams, validator.StreamServerInterceptor())
+	if tenants != nil {
+		unary = append(unary, tenants.UnaryQuotaInterceptor(advisor.LLMMethods))
+		streams = append(streams, tenants.StreamQuotaInterceptor(advisor.LLMMethods))
+	}
+	var auditLog *audit.Recorder
+	if cfg.serves("advisor") {
+		auditLog = newAuditLog(cfg, scheduler)
+	}
+	if auditLog != nil {
+		unary = append(unary, auditLog.UnaryServerInterceptor())
+		streams = append(streams, auditLog.StreamServerInterceptor())
+	}
+	opts := []grpc.ServerOption{
+		grpc.Creds(creds),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(streams...),
+	}
+	opts = append(opts, limitOptions(cfg)...)
+	if tracer != nil {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+	s := grpc.NewServer(opts...)
+
+	// Readiness follows what this process depends on: Open-Meteo for the
+	// services it runs, the remote weather service and the LLM.
+	var probes []readiness.Probe
+	bus := newEventBus(cfg)
+	defer bus.Close(5 * time.Second)
+	var weatherSvc weatherpb.WeatherServiceServer
+	// Only a local weather service's cache can be flushed from here.
+	var weatherCache advisor.WeatherCache
+	if cfg.serves("weather") {
+		var observe func(*weather.Observation)
+		if bus != nil {
+			observe = func(o *weather.Observation) {
+				bus.Publish(events.TypeObservation, fmt.Sprintf("%.2f,%.2f", o.Latitude, o.Longitude), o)
+			}
+		}
+		local := weather.NewWeatherService(httpClient, cfg.WeatherCacheTTL, observe)
+		weatherSvc = local
+		weatherCache = local
+		weatherpb.RegisterWeatherServiceServer(s, weatherSvc)
+		// Refreshing more often than the cache TTL keeps popular cities
+		// answered from the cache.
+		addJob(scheduler, "weather_refresh", "*/5 * * * *", 2*time.Minute, func(ctx context.Context) error {
+			return local.RefreshPopular(ctx, popularLocations)
+		})
+		probes = append(probes, readiness.HTTPProbe("open_meteo_forecast", httpClient, cfg.ForecastURL+"?latitude=0&longitude=0&current=temperature_2m"))
+	} else {
+		conn, err := dialWeather(cfg, tracer != nil)
+		if err != nil {
+			fatal("weather service connection failed", "error", err)
+		}
+		defer conn.Close()
+		weatherSvc = weather.NewRemoteService(conn)
+		slog.Info("using remote weather service", "addr", cfg.WeatherAddr)
+		probes = append(probes, readiness.Probe{Name: "weather_service", Check: func(ctx context.Context) error {
+			resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: weatherpb.WeatherService_ServiceDesc.ServiceName})
+			if err != nil {
+				return err
+			}
+			if resp.Status != healthpb.HealthCheckResponse_SERVING {
+				return fmt.Errorf("weather service is %s", resp.Status)
+			}
+			return nil
+		}})
+	}
+
+	mqttPublisher := newMQTTPublisher(cfg, weatherSvc)
+	if mqttPublisher != nil {
+		defer mqttPublisher.Close()
+		addJob(scheduler, "mqtt_publish", "*/5 * * * *", time.Minute, mqttPublisher.PublishCurrent)
+	}
+
+	if cfg.serves("advisor") {
+		sessions := newSessionStore(cfg, db)
+		sinks := newStreamSinks(cfg, httpClient)
+		defer sinks.Close()
+		digests := newDigestStore(cfg, db)
+		alerts := newAlertStore(cfg, db)
+		alertFeed := alert.NewFeed()
+		geocodes := newGeocodeCache(cfg, db)
+		advisorSvc, err := advisor.NewAdvisorService(weatherSvc, httpClient, advisor.AdvisorOptions{
+			GeminiAPIKey: geminiAPIKey,
+			Model:        cfg.GeminiModel,
+			Generation:   newGenerationSettings(),
+			Sessions:     sessions,
+			History:      newAdviceHistory(cfg, db),
+			Limiter:      newRateLimiter(cfg),
+			Sinks:        sinks,
+			Digests:      digests,
+			Alerts:       alerts,
+			AlertFeed:    alertFeed,
+			Cache:        newSemanticCache(cfg),
+			Budget:       budget,
+			Audit:        auditLog,
+			Geocodes:     geocodes,
+			WeatherCache: weatherCache,
+			Events:       bus,
+		})
+		if err != nil {
+			fatal("advisor service failed", "error", err)
+		}
+		defer advisorSvc.Close()
+
+		// Digests are checked every minute, the resolution of delivery times.
+		notifier := newNotifier(cfg, httpClient)
+		digestScheduler := digest.NewScheduler(digests, auditedDigests(auditLog, advisorSvc.GenerateDigest), notifier)
+		addJob(scheduler, "digests", "* * * * *", 0, digestScheduler.Tick)
+		// Open-Meteo updates its forecast hourly.
+		alertChecker := alert.NewChecker(alerts, dailyForecaster(weatherSvc), notifier, alertFeed)
+		addJob(scheduler, "alerts", "*/30 * * * *", 5*time.Minute, alertChecker.Check)
+		if mqttPublisher != nil {
+			go mqttPublisher.PublishAlerts(ctx, alertFeed)
+		}
+		if bus != nil {
+			go bus.PublishAlerts(ctx, alertFeed)
+		}
+		// Memory and Redis sessions expire by themselves; database rows
+		// are deleted here.
+		if p, ok := sessions.(pruner); ok {
+			addJob(scheduler, "session_prune", "@hourly", time.Minute, pruneJob("sessions", p))
+		}
+		if p, ok := geocodes.(pruner); ok {
+			addJob(scheduler, "geocode_prune", "@daily", time.Minute, pruneJob("geocodes", p))
+		}
+		advisorpb.RegisterAdvisorServiceServer(s, advisorSvc)
+		probes = append(probes, readiness.HTTPProbe("open_meteo_geocoding", httpClient, cfg.GeocodingURL+"?name=London&count=1"))
+		if geminiAPIKey != "" {
+			probes = append(probes, readiness.Probe{Name: "llm", Check: advisorSvc.CheckLLM})
+		}
+		if adminToken != "" {
+			mux.Handle("/admin/", requireToken(adminToken, advisorSvc.AdminHandler()))
+		} else {
+			slog.Info("ADMIN_TOKEN not set, admin API off")
+		}
+	}
+	// Probe results are reused for 30s, so Kubernetes can probe often
+	// without hammering the upstreams.
+	ready := readiness.New(30*time.Second, 5*time.Second, probes...)
+	mux.Handle("/readyz", ready)
+	// The health service lets weather clients balance across replicas and
+	// skip the ones shutting down.
+	healthSrv := health.NewServer()
+	for name := range s.GetServiceInfo() {
+		healthSrv.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s, healthSrv)
+	if cfg.Reflection {
+		reflection.Register(s)
+		slog.Info("gRPC reflection enabled")
+	}
+	rpcMetrics.Init(s)
+
+	if unknown := scheduler.Unknown(); len(unknown) > 0 {
+		fatal("job_schedules names unknown jobs", "jobs", unknown)
+	}
+	go scheduler.Run(ctx)
+
+	slog.Info("gRPC server listening", "addr", lis.Addr().String(), "services", cfg.Services)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Serve(lis) }()
+
+	var gatewaySrv *http.Server
+	if cfg.HTTPAddr != "" {
+		var conn *grpc.ClientConn
+		gatewaySrv, conn, err = newGateway(cfg, lis.Addr(), tracer != nil)
+		if err != nil {
+			fatal("REST gateway failed", "error", err)
+		}
+		defer conn.Close()
+		httpLis, err := listen(cfg.HTTPAddr)
+		if err != nil {
+			fatal("listen failed", "addr", cfg.HTTPAddr, "error", err)
+		}
+		go func() {
+			slog.Info("REST gateway listening", "addr", httpLis.Addr().String())
+			if err := gatewaySrv.Serve(httpLis); err != http.ErrServerClosed {
+				serveErr <- fmt.Errorf("REST gateway: %v", err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		fatal("server failed", "error", err)
+	case <-ctx.Done():
 	}
+	stop()
 
+	ready.Drain()
+	shutdown(s, healthSrv, gatewaySrv, metricsSrv, cfg.ShutdownGrace)
+	if tracer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracer.Shutdown(ctx); err != nil {
+			slog.Error("flushing traces failed", "error", err)
+		}
+	}
+}
+
+// shutdown stops accepting RPCs and waits up to grace for in-flight ones,
+// including advice streams, to finish before cancelling the rest. Health
+// checks report NOT_SERVING first so balancing clients move away. The REST
+// gateway, if any, drains next since its requests are RPCs to s. The
+// metrics server goes last so the drain can still be scraped.
+func shutdown(s *grpc.Server, healthSrv *health.Server, gatewaySrv, metricsSrv *http.Server, grace time.Duration) {
+	slog.Info("shutting down, draining in-flight RPCs", "grace", grace.String())
+	healthSrv.Shutdown()
+	graceCtx, cancelGrace := context.WithTimeout(context.Background(), grace)
+	defer cancelGrace()
+	if gatewaySrv != nil {
+		if err := gatewaySrv.Shutdown(graceCtx); err != nil {
+			slog.Warn("REST gateway did not drain in time", "error", err)
+		}
+	}
+	drained := make(chan struct{})
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Println("Metrics server on :2113")
-		log.Fatal(http.ListenAndServe(":2113", nil))
+		s.GracefulStop()
+		close(drained)
 	}()
+	select {
+	case <-drained:
+		slog.Info("all RPCs finished")
+	case <-graceCtx.Done():
+		slog.Warn("grace period over, cancelling remaining RPCs")
+		s.Stop()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := metricsSrv.Shutdown(ctx); err != nil {
+		slog.Error("metrics server shutdown failed", "error", err)
+	}
+}
+
+// fatal logs msg with its attributes at error level and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// newTokenVerifier returns a JWT verifier when jwt_issuer is configured,
+// or nil to leave bearer tokens unchecked.
+func newTokenVerifier(cfg config, httpClient *http.Client) *auth.Verifier {
+	if cfg.JWTIssuer == "" {
+		return nil
+	}
+	verifier, err := auth.NewVerifier(auth.Config{
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		JWKSURL:     cfg.JWTJWKSURL,
+		Leeway:      time.Minute,
+		TenantClaim: cfg.JWTTenantClaim,
+	}, httpClient)
+	if err != nil {
+		fatal("JWT setup failed", "error", err)
+	}
+	slog.Info("requiring bearer tokens", "issuer", cfg.JWTIssuer, "audience", cfg.JWTAudience)
+	return verifier
+}
+
+// limitOptions turns the connection and message limits into server
+// options. Zero limits keep gRPC's defaults. Keepalive pings stop load
+// balancers from dropping a StreamAdvice connection as idle while the
+// weather is gathered.
+func limitOptions(cfg config) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(int(cfg.MaxRecvMsgSize)),
+		grpc.ConnectionTimeout(cfg.ConnectionTimeout),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.KeepaliveTime,
+			Timeout: cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime,
+			PermitWithoutStream: cfg.KeepaliveNoStream,
+		}),
+	}
+	if cfg.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(uint32(cfg.MaxConcurrentStreams)))
+	}
+	if cfg.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(int(cfg.MaxSendMsgSize)))
+	}
+	return opts
+}
+
+// newTracerProvider installs an OTLP exporting tracer provider and the W3C
+// trace context propagator when otlp_endpoint is set. Returns nil, leaving
+// the otel no-op defaults in place, otherwise.
+func newTracerProvider(cfg config) *tracing.Provider {
+	if cfg.OTLPEndpoint == "" {
+		return nil
+	}
+	provider := tracing.NewProvider(cfg.OTLPEndpoint, cfg.ServiceName, cfg.TraceSampleRatio)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	slog.Info("exporting traces", "endpoint", cfg.OTLPEndpoint, "service", cfg.ServiceName, "sample_ratio", cfg.TraceSampleRatio)
+	return provider
+}
+
+// newStorage opens the database at storage_dsn: a SQLite file path
+// (optionally prefixed "sqlite:") or a postgres:// URL. Returns nil when it
+// is unset, leaving every store in memory or in files. Pending migrations
+// are applied unless storage_migrate is "off", in which case the server
+// refuses to start until "server migrate" has been run.
+func newStorage(ctx context.Context, cfg config) *storage.DB {
+	if cfg.StorageDSN == "" {
+		return nil
+	}
+	db, err := storage.Open(ctx, cfg.StorageDSN)
+	if err != nil {
+		fatal("storage setup failed", "error", err)
+	}
+	if cfg.StorageMigrate == "off" {
+		pending, err := db.Pending(ctx)
+		if err != nil {
+			fatal("checking migrations failed", "error", err)
+		}
+		if len(pending) > 0 {
+			fatal("storage has pending migrations; run server migrate", "pending", len(pending))
+		}
+	} else {
+		applied, err := db.Migrate(ctx)
+		for _, m := range applied {
+			slog.Info("applied migration", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			fatal("migrating storage failed", "error", err)
+		}
+	}
+	slog.Info("using database storage", "dialect", db.Dialect)
+	return db
+}
+
+// storeKind returns the configured store, defaulting to "sql" when a
+// database is configured and to def otherwise. The config makes sure "sql"
+// comes with a database.
+func storeKind(kind, def string, db *storage.DB) string {
+	if kind != "" {
+		return kind
+	}
+	if db != nil {
+		return "sql"
+	}
+	return def
+}
+
+// newSessionStore picks the conversation store from session_store. Use
+// "redis" or "sql" with a shared database when running more than one
+// replica so sessions follow the user.
+func newSessionStore(cfg config, db *storage.DB) session.SessionStore {
+	ttl := cfg.SessionTTL
+	switch storeKind(cfg.SessionStore, "memory", db) {
+	case "redis":
+		slog.Info("using Redis session store", "addr", cfg.RedisAddr, "ttl", ttl.String())
+		return session.NewRedisStore(cfg.RedisAddr, os.Getenv("REDIS_PASSWORD"), int(cfg.RedisDB), ttl)
+	case "sql":
+		slog.Info("using database session store", "ttl", ttl.String())
+		return session.NewSQLStore(db, ttl)
+	default:
+		slog.Info("using in-memory session store", "ttl", ttl.String())
+		return session.NewMemoryStore(ttl)
+	}
+}
+
+// newAdviceHistory picks where generated advice is kept from advice_history:
+// "memory" (default), "file" (JSON Lines at advice_history_path), "sql"
+// (default with storage_dsn) or "off".
+func newAdviceHistory(cfg config, db *storage.DB) history.AdviceStore {
+	const maxRecords = 1000
+
+	switch storeKind(cfg.AdviceHistory, "memory", db) {
+	case "file":
+		fileStore, err := history.NewFileStore(cfg.AdviceHistoryPath, maxRecords)
+		if err != nil {
+			fatal("advice history failed", "error", err)
+		}
+		slog.Info("saving advice history", "path", cfg.AdviceHistoryPath)
+		return fileStore
+	case "sql":
+		slog.Info("saving advice history in the database")
+		return history.NewSQLStore(db)
+	case "off":
+		return nil
+	default:
+		return history.NewMemoryStore(maxRecords)
+	}
+}
+
+// newDigestStore picks where digest subscriptions are kept from
+// digest_store: "memory" (default) or "sql" (default with storage_dsn).
+func newDigestStore(cfg config, db *storage.DB) digest.Store {
+	if storeKind(cfg.DigestStore, "memory", db) == "sql" {
+		return digest.NewSQLStore(db)
+	}
+	return digest.NewMemoryStore()
+}
+
+// newAlertStore picks where weather alert subscriptions are kept from
+// alert_store: "memory" (default) or "sql" (default with storage_dsn).
+func newAlertStore(cfg config, db *storage.DB) alert.Store {
+	if storeKind(cfg.AlertStore, "memory", db) == "sql" {
+		return alert.NewSQLStore(db)
+	}
+	return alert.NewMemoryStore()
+}
+
+// dailyForecaster reads alert forecasts from the weather service, local or
+// remote.
+func dailyForecaster(weatherSvc weatherpb.WeatherServiceServer) alert.Forecaster {
+	return func(ctx context.Context, lat, lon float64) (*weatherpb.DailyForecast, error) {
+		return weatherSvc.GetDailyForecast(ctx, &weatherpb.DailyForecastRequest{Latitude: lat, Longitude: lon})
+	}
+}
+
+// newGeocodeCache caches geocoding answers in the database for
+// geocode_cache_ttl (0 disables it). Returns nil without a database.
+func newGeocodeCache(cfg config, db *storage.DB) advisor.GeocodeCache {
+	if db == nil || cfg.GeocodeCacheTTL == 0 {
+		return nil
+	}
+	return storage.NewGeocodeCache(db, cfg.GeocodeCacheTTL)
+}
+
+// newMQTTPublisher connects to mqtt_broker, if set, to publish the current
+// conditions of mqtt_locations and the alerts that fire.
+func newMQTTPublisher(cfg config, weatherSvc weatherpb.WeatherServiceServer) *mqtt.Publisher {
+	if cfg.MQTTBroker == "" {
+		return nil
+	}
+	// Validated with the rest of the config.
+	locations, _ := mqtt.ParseLocations(cfg.MQTTLocations)
+	mc := mqtt.Config{
+		Broker:      cfg.MQTTBroker,
+		ClientID:    cfg.MQTTClientID,
+		Username:    cfg.MQTTUsername,
+		Password:    os.Getenv("MQTT_PASSWORD"),
+		TopicPrefix: strings.Trim(cfg.MQTTTopicPrefix, "/"),
+		QoS:         byte(cfg.MQTTQoS),
+		Locations:   locations,
+	}
+	if mc.ClientID == "" {
+		host, _ := os.Hostname()
+		mc.ClientID = "weather-advisor-" + host
+	}
+	if mc.TopicPrefix == "" {
+		mc.TopicPrefix = "weather"
+	}
+	slog.Info("publishing to MQTT", "broker", mc.Broker, "prefix", mc.TopicPrefix, "locations", len(locations))
+	return mqtt.New(mc, weatherSvc)
+}
+
+// newEventBus connects to the broker event_bus names, nats or kafka, at
+// event_bus_url. Events go to the event_bus_topic subject or topic. It
+// returns nil when event_bus is unset.
+func newEventBus(cfg config) *events.Bus {
+	if cfg.EventBus == "" {
+		return nil
+	}
+	topic := cfg.EventBusTopic
+	if topic == "" {
+		topic = "weather"
+	}
+	var transport events.Transport
+	if cfg.EventBus == "kafka" {
+		transport = events.NewKafka(strings.Split(cfg.EventBusURL, ","), topic)
+	} else {
+		host, _ := os.Hostname()
+		n, err := events.NewNATS(cfg.EventBusURL, topic, "weather-advisor-"+host)
+		if err != nil {
+			fatal("event bus failed", "error", err)
+		}
+		transport = n
+	}
+	slog.Info("publishing events", "bus", cfg.EventBus, "topic", topic)
+	return events.New(transport)
+}
+
+// popularLocations is how many of the most requested locations the
+// weather_refresh job keeps fresh.
+const popularLocations = 50
+
+// addJob schedules a background job, by default on spec.
+func addJob(scheduler *jobs.Scheduler, name, spec string, timeout time.Duration, run jobs.Func) {
+	if err := scheduler.Add(name, spec, timeout, run); err != nil {
+		fatal("background job setup failed", "error", err)
+	}
+}
+
+// pruner is a store that deletes its expired rows on demand.
+type pruner interface {
+	Prune(ctx context.Context) (int64, error)
+}
+
+func pruneJob(what string, p pruner) jobs.Func {
+	return func(ctx context.Context) error {
+		n, err := p.Prune(ctx)
+		if n > 0 {
+			slog.Info("pruned expired rows", "store", what, "count", n)
+		}
+		return err
+	}
+}
+
+// newTenants loads the tenants and their quotas from tenants_file. Returns
+// nil, leaving tenants off, when it is unset.
+func newTenants(cfg config) *tenant.Registry {
+	if cfg.TenantsFile == "" {
+		return nil
+	}
+	tenants, err := tenant.Load(cfg.TenantsFile)
+	if err != nil {
+		fatal("tenants setup failed", "error", err)
+	}
+	slog.Info("enforcing tenant quotas", "tenants", tenants.Len())
+	return tenants
+}
 
-	lis, err := net.Listen("tcp", ":8082")
+// newValidator enforces the field constraints of the services this server
+// runs.
+func newValidator(cfg config) *validate.Validator {
+	var rules []validate.Rule
+	if cfg.serves("weather") {
+		rules = append(rules, weather.ValidationRules...)
+	}
+	if cfg.serves("advisor") {
+		rules = append(rules, advisor.ValidationRules...)
+	}
+	v, err := validate.New(rules...)
 	if err != nil {
-		log.Fatalf("Listen failed: %v", err)
+		fatal("validation setup failed", "error", err)
+	}
+	return v
+}
+
+// newAuditLog picks where advisor usage is audited from audit_log: "off"
+// (default), "memory" (last 10000 entries) or "file" (append-only JSON
+// Lines at audit_log_path, rotated past audit_log_max_bytes).
+// audit_exporters lists the caller identities allowed to call
+// ExportAuditLog.
+func newAuditLog(cfg config, scheduler *jobs.Scheduler) *audit.Recorder {
+	var store audit.Store
+	switch cfg.AuditLog {
+	case "off":
+		return nil
+	case "memory":
+		store = audit.NewMemoryStore(10000)
+	case "file":
+		fileStore, err := audit.NewFileStore(cfg.AuditLogPath)
+		if err != nil {
+			fatal("audit log failed", "error", err)
+		}
+		store = fileStore
+		addJob(scheduler, "audit_rotate", "@hourly", time.Minute, func(context.Context) error {
+			rotated, err := fileStore.Rotate(cfg.AuditLogMaxBytes, int(cfg.AuditLogKeep))
+			if rotated {
+				slog.Info("rotated audit log", "path", cfg.AuditLogPath)
+			}
+			return err
+		})
+	}
+	var exporters []string
+	for _, e := range strings.Split(cfg.AuditExporters, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			exporters = append(exporters, e)
+		}
+	}
+	slog.Info("auditing advisor usage", "store", cfg.AuditLog, "exporters", exporters)
+	return audit.NewRecorder(store, advisor.LLMMethods, exporters)
+}
+
+// auditedDigests audits each scheduled digest on behalf of its recipient.
+func auditedDigests(rec *audit.Recorder, generate digest.Generator) digest.Generator {
+	if rec == nil {
+		return generate
+	}
+	return func(ctx context.Context, sub *digest.Subscription) (string, error) {
+		var text string
+		err := rec.Track(ctx, "digest:"+sub.Recipient, "digest", audit.Cities(sub.Request), func(ctx context.Context) error {
+			var err error
+			text, err = generate(ctx, sub)
+			return err
+		})
+		return text, err
 	}
+}
 
-	log.Println("gRPC server starting on :8082")
-	s := grpc.NewServer()
+// newStreamSinks builds the sinks advice streams are teed to from
+// stream_sink_webhooks, a comma-separated list of URLs. Returns nil when
+// none are configured.
+func newStreamSinks(cfg config, httpClient *http.Client) *sink.Fanout {
+	var sinks []sink.Sink
+	for _, url := range strings.Split(cfg.StreamSinkWebhooks, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			sinks = append(sinks, sink.NewWebhook(url, httpClient))
+		}
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	slog.Info("teeing advice streams", "sinks", len(sinks))
+	return sink.NewFanout(sinks, 1024)
+}
 
-	weatherSvc := weather.NewWeatherService()
-	weatherpb.RegisterWeatherServiceServer(s, weatherSvc)
+// newNotifier builds the notification channels digests and alerts are
+// delivered to: the server log, the Slack, Discord and generic webhooks of
+// each subscription, and email through smtp_addr when it is set.
+// notify_recipients_file holds each recipient's delivery preferences.
+// Generic webhooks that fail for good go to webhook_dead_letter_file, or
+// the log; webhook_allow_private lets them reach private addresses, for
+// receivers on the server's own network.
+func newNotifier(cfg config, httpClient *http.Client) notify.Notifier {
+	var recipients notify.Recipients
+	if cfg.NotifyRecipientsFile != "" {
+		var err error
+		recipients, err = notify.LoadRecipients(cfg.NotifyRecipientsFile)
+		if err != nil {
+			fatal("notification recipients failed", "error", err)
+		}
+	}
 
-	advisorSvc, err := advisor.NewAdvisorService(weatherSvc, geminiAPIKey)
+	deadLetter, err := notify.NewDeadLetter(cfg.WebhookDeadLetterFile)
 	if err != nil {
-		log.Fatalf("Advisor service failed: %v", err)
+		fatal("webhook dead letters failed", "error", err)
+	}
+	webhookClient := httpclient.NewPublic(10 * time.Second)
+	if cfg.WebhookAllowPrivate {
+		webhookClient = httpclient.New(10 * time.Second)
+	}
+
+	notifiers := notify.Multi{
+		notify.Log{},
+		notify.NewSlack(httpClient),
+		notify.NewDiscord(httpClient),
+		notify.NewSigned(webhookClient, deadLetter),
+	}
+	if cfg.SMTPAddr != "" {
+		email, err := notify.NewEmail(notify.SMTPConfig{
+			Addr:     cfg.SMTPAddr,
+			Username: cfg.SMTPUsername,
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     cfg.SMTPFrom,
+		}, recipients, cfg.EmailTemplateDir)
+		if err != nil {
+			fatal("email notifications failed", "error", err)
+		}
+		slog.Info("sending notifications by email", "smtp", cfg.SMTPAddr)
+		notifiers = append(notifiers, email)
+	}
+	return notifiers
+}
+
+// newSemanticCache reuses recent advice whose weather context embeds at
+// least semantic_cache_threshold close, for semantic_cache_ttl. The cache
+// is off unless a threshold is set.
+func newSemanticCache(cfg config) *advisor.SemanticCache {
+	if cfg.SemanticCacheThreshold == 0 {
+		return nil
+	}
+	slog.Info("semantic advice cache on", "threshold", cfg.SemanticCacheThreshold, "ttl", cfg.SemanticCacheTTL.String())
+	return advisor.NewSemanticCache(cfg.SemanticCacheThreshold, cfg.SemanticCacheTTL, 500)
+}
+
+// newBudget limits Gemini spend by the llm_budget_* settings. Returns nil
+// when no limit is set.
+func newBudget(cfg config) *advisor.Budget {
+	limits := advisor.BudgetLimits{
+		DailyUSD:      cfg.LLMBudgetDailyUSD,
+		MonthlyUSD:    cfg.LLMBudgetMonthlyUSD,
+		DailyTokens:   cfg.LLMBudgetDailyTokens,
+		MonthlyTokens: cfg.LLMBudgetMonthlyTokens,
+		Reject:        cfg.LLMBudgetMode == "reject",
+	}
+	if limits.DailyUSD == 0 && limits.MonthlyUSD == 0 && limits.DailyTokens == 0 && limits.MonthlyTokens == 0 {
+		return nil
+	}
+	slog.Info("LLM budget (0 = no limit)", "daily_usd", limits.DailyUSD, "monthly_usd", limits.MonthlyUSD,
+		"daily_tokens", limits.DailyTokens, "monthly_tokens", limits.MonthlyTokens)
+	return advisor.NewBudget(limits)
+}
+
+// requireToken rejects requests to h that don't carry the bearer token.
+func requireToken(token string, h http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// newRateLimiter applies llm_qps and llm_tokens_per_minute. Zero leaves
+// that limit off.
+func newRateLimiter(cfg config) *advisor.RateLimiter {
+	if cfg.LLMQPS > 0 || cfg.LLMTokensPerMinute > 0 {
+		slog.Info("LLM rate limit", "calls_per_second", cfg.LLMQPS, "tokens_per_minute", cfg.LLMTokensPerMinute)
 	}
-	defer advisorSvc.Close()
-	advisorpb.RegisterAdvisorServiceServer(s, advisorSvc)
+	return advisor.NewRateLimiter(cfg.LLMQPS, int(cfg.LLMTokensPerMinute))
+}
 
-	log.Println("gRPC server on :8080")
-	log.Fatal(s.Serve(lis))
+// newGenerationSettings reads the GEMINI_* generation parameters. Unset
+// values keep the model defaults.
+func newGenerationSettings() advisor.GenerationSettings {
+	var gen advisor.GenerationSettings
+	if v := os.Getenv("GEMINI_TEMPERATURE"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			fatal("invalid GEMINI_TEMPERATURE", "value", v, "error", err)
+		}
+		t := float32(parsed)
+		gen.Temperature = &t
+	}
+	if v := os.Getenv("GEMINI_TOP_P"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			fatal("invalid GEMINI_TOP_P", "value", v, "error", err)
+		}
+		p := float32(parsed)
+		gen.TopP = &p
+	}
+	if v := os.Getenv("GEMINI_MAX_OUTPUT_TOKENS"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			fatal("invalid GEMINI_MAX_OUTPUT_TOKENS", "value", v, "error", err)
+		}
+		n := int32(parsed)
+		gen.MaxOutputTokens = &n
+	}
+	threshold, err := advisor.ParseSafetyThreshold(os.Getenv("GEMINI_SAFETY_THRESHOLD"))
+	if err != nil {
+		fatal("invalid GEMINI_SAFETY_THRESHOLD", "error", err)
+	}
+	gen.SafetyThreshold = threshold
+	return gen
 }
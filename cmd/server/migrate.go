@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/pixperk/effinarounf/services/storage"
+	"github.com/spf13/cobra"
+)
+
+// migrateCommand applies the database migrations built into the binary to
+// storage_dsn, for deployments that run with storage_migrate off and
+// migrate as a separate release step. The database is resolved like the
+// server's: config file, then STORAGE_DSN, then --storage-dsn.
+func migrateCommand() *cobra.Command {
+	var dryRun bool
+	flags := defaultConfig()
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations to storage_dsn",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd, &flags)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %v", err)
+			}
+			if cfg.StorageDSN == "" {
+				return fmt.Errorf("storage_dsn is not set")
+			}
+			db, err := storage.Open(cmd.Context(), cfg.StorageDSN)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				pending, err := db.Pending(cmd.Context())
+				if err != nil {
+					return err
+				}
+				for _, m := range pending {
+					fmt.Fprintf(out, "pending %04d %s\n", m.Version, m.Name)
+				}
+				if len(pending) == 0 {
+					fmt.Fprintln(out, "up to date")
+				}
+				return nil
+			}
+			applied, err := db.Migrate(cmd.Context())
+			for _, m := range applied {
+				fmt.Fprintf(out, "applied %04d %s\n", m.Version, m.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Fprintln(out, "up to date")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "list the pending migrations without applying them")
+	// loadConfig only applies the flags registered here.
+	cmd.Flags().String("config", "", "YAML config file (default $SERVER_CONFIG)")
+	cmd.Flags().StringVar(&flags.StorageDSN, "storage-dsn", "", "database to migrate (env STORAGE_DSN)")
+	return cmd
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// serverCredentials returns TLS credentials when a certificate is
+// configured and plaintext otherwise. With a client CA every client must
+// present a certificate that chains to it (mTLS). Files are loaded here so a
+// bad certificate stops the server at startup rather than on the first
+// handshake.
+func serverCredentials(cfg config) (credentials.TransportCredentials, error) {
+	if cfg.TLSCertFile == "" {
+		slog.Warn("TLS not configured, serving plaintext gRPC")
+		return insecure.NewCredentials(), nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS key pair: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		slog.Info("serving gRPC over mutual TLS", "cert", cfg.TLSCertFile, "client_ca", cfg.TLSClientCAFile)
+	} else {
+		slog.Info("serving gRPC over TLS", "cert", cfg.TLSCertFile)
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
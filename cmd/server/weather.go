@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// weatherServiceConfig spreads calls round-robin over every resolved
+// weather replica, leaving out those whose health check isn't SERVING.
+var weatherServiceConfig = fmt.Sprintf(`{
+	"loadBalancingConfig": [{"round_robin": {}}],
+	"healthCheckConfig": {"serviceName": %q}
+}`, weatherpb.WeatherService_ServiceDesc.ServiceName)
+
+// dialWeather connects to the remote weather service at weather_addr, over
+// TLS when weather_tls or weather_ca_file is set. weather_addr is either a
+// comma-separated list of replicas or one gRPC target such as
+// dns:///weather:8082, whose every address is used. Idle connections are
+// pinged every weather_keepalive_time, and calls are gzipped unless
+// weather_compression is off. traced adds the otel handler so
+// weather calls join the advisor RPC's trace.
+func dialWeather(cfg config, traced bool) (*grpc.ClientConn, error) {
+	creds, err := weatherCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(weatherServiceConfig),
+	}
+	if traced {
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+	if cfg.WeatherCompression {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+	if cfg.WeatherKeepalive > 0 {
+		// The weather service must allow this interval in its
+		// keepalive_min_time, or it closes the connection.
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.WeatherKeepalive,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	target := cfg.WeatherAddr
+	if endpoints := weatherEndpoints(cfg.WeatherAddr); len(endpoints) > 1 {
+		r := manual.NewBuilderWithScheme("weather")
+		var addrs []resolver.Address
+		for _, e := range endpoints {
+			host, _, _ := net.SplitHostPort(e)
+			// Each replica's certificate is checked against its own host.
+			addrs = append(addrs, resolver.Address{Addr: e, ServerName: host})
+		}
+		r.InitialState(resolver.State{Addresses: addrs})
+		opts = append(opts, grpc.WithResolvers(r))
+		target = r.Scheme() + ":///weather"
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %v", cfg.WeatherAddr, err)
+	}
+	return conn, nil
+}
+
+// weatherEndpoints splits weather_addr into its replicas.
+func weatherEndpoints(addr string) []string {
+	var endpoints []string
+	for _, e := range strings.Split(addr, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+func weatherCredentials(cfg config) (credentials.TransportCredentials, error) {
+	if !cfg.WeatherTLS && cfg.WeatherCAFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.WeatherCAFile != "" {
+		pem, err := os.ReadFile(cfg.WeatherCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading weather_ca_file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.WeatherCAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
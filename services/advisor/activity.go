@@ -0,0 +1,98 @@
+package advisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RateActivity scores each city with the rules engine. A city whose lookup
+// fails is reported in errors instead of failing the whole request.
+func (s *advisorService) RateActivity(ctx context.Context, req *advisorpb.RateActivityRequest) (*advisorpb.RateActivityResponse, error) {
+	activity := strings.ToLower(strings.TrimSpace(req.Activity))
+	if !slices.Contains(rules.Activities(), activity) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown activity %q (want one of %s)", req.Activity, strings.Join(rules.Activities(), ", "))
+	}
+	cities, err := normalizeCities(req.Cities)
+	if err != nil {
+		return nil, err
+	}
+	req.Cities = cities
+	modelName := s.model
+	if req.Explain {
+		var err error
+		if modelName, err = s.resolveModel(req.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &advisorpb.RateActivityResponse{Activity: activity}
+	var weatherData []string
+	for i, city := range req.Cities {
+		loc, err := s.geocodeCity(ctx, city)
+		if err != nil {
+			resp.Errors = append(resp.Errors, cityError(i, city, "geocoding", err))
+			continue
+		}
+		w, err := s.weatherSvc.GetCurrentWeather(ctx, metricWeatherRequest(loc))
+		if err != nil {
+			resp.Errors = append(resp.Errors, cityError(i, city, "weather", err))
+			continue
+		}
+		conv := units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+		score, reasons, err := rules.RateActivity(activity, w, conv)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		resp.Ratings = append(resp.Ratings, &advisorpb.ActivityRating{
+			Location: city.Location,
+			Score:    int32(score),
+			Reasons:  reasons,
+		})
+		weatherData = append(weatherData, fmt.Sprintf("%s|score %d|%s", formatWeather(city.Location, w, conv), score, strings.Join(reasons, "; ")))
+	}
+	if len(resp.Ratings) == 0 {
+		return nil, status.Error(codes.Unavailable, "no weather data available for any city")
+	}
+
+	if req.Explain {
+		s.explainRatings(ctx, modelName, activity, weatherData, resp)
+	}
+	return resp, nil
+}
+
+// explainRatings adds the model's comment to each rating. The scores stand
+// on their own, so a failed call only leaves the comments empty.
+func (s *advisorService) explainRatings(ctx context.Context, modelName, activity string, weatherData []string, resp *advisorpb.RateActivityResponse) {
+	prompt := fmt.Sprintf(`Weather advisor. Each row is city|temp|condition|humidity|wind|score|reasons, where the score (0-100) rates the weather for %s. %s
+
+%s
+
+For each city write one line "City: comment" with one short, practical sentence for someone planning %s there now. Do not change the scores.`, activity, dataNotice, dataBlock(weatherData), activity)
+
+	text, usage, err := s.generateText(ctx, modelName, nil, prompt)
+	if err != nil {
+		slog.WarnContext(ctx, "activity explanation failed", "error", err)
+		return
+	}
+	resp.Usage = usage
+
+	comments := make(map[string]string)
+	for _, line := range strings.Split(text, "\n") {
+		city, comment, ok := strings.Cut(strings.Trim(line, " -*"), ":")
+		if ok {
+			comments[strings.ToLower(strings.Trim(city, " *"))] = strings.TrimSpace(comment)
+		}
+	}
+	for _, r := range resp.Ratings {
+		r.Comment = comments[strings.ToLower(r.Location)]
+	}
+}
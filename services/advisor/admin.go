@@ -0,0 +1,164 @@
+package advisor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// activeStreams tracks the StreamAdvice calls in progress for the admin API.
+type activeStreams struct {
+	mu      sync.Mutex
+	streams map[string]StreamInfo
+}
+
+// StreamInfo describes one StreamAdvice call in progress.
+type StreamInfo struct {
+	ID      string    `json:"id"`
+	Client  string    `json:"client"`
+	Cities  []string  `json:"cities"`
+	Started time.Time `json:"started"`
+}
+
+func newActiveStreams() *activeStreams {
+	return &activeStreams{streams: make(map[string]StreamInfo)}
+}
+
+// add registers a stream and returns the function that removes it.
+func (a *activeStreams) add(info StreamInfo) (remove func()) {
+	a.mu.Lock()
+	a.streams[info.ID] = info
+	a.mu.Unlock()
+	return func() {
+		a.mu.Lock()
+		delete(a.streams, info.ID)
+		a.mu.Unlock()
+	}
+}
+
+// list returns the streams in progress, oldest first.
+func (a *activeStreams) list() []StreamInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]StreamInfo, 0, len(a.streams))
+	for _, info := range a.streams {
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Started.Before(out[j].Started) })
+	return out
+}
+
+// AdminHandler serves the operator endpoints under /admin/:
+//
+//	GET  /admin/cache        semantic cache and replay buffer sizes
+//	POST /admin/cache/flush  empties both, and the weather and geocode caches
+//	GET  /admin/streams      StreamAdvice calls in progress
+//	GET  /admin/mode         the runtime modes
+//	POST /admin/mode         changes them from a JSON body of the same shape
+//
+// It does no authentication of its own; mount it behind a check.
+func (s *advisorService) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/cache", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.cacheStats())
+	})
+	mux.HandleFunc("POST /admin/cache/flush", func(w http.ResponseWriter, r *http.Request) {
+		flushed := flushStats{cacheStats: s.cacheStats()}
+		s.cache.flush()
+		s.replay.flush()
+		if s.weatherCache != nil {
+			flushed.WeatherReadings = s.weatherCache.FlushCache()
+		}
+		if s.geocodes != nil {
+			n, err := s.geocodes.Flush(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			flushed.Geocodes = n
+		}
+		writeJSON(w, map[string]any{"flushed": flushed})
+	})
+	mux.HandleFunc("GET /admin/streams", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"streams": s.streams.list()})
+	})
+	mux.HandleFunc("GET /admin/mode", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.modes())
+	})
+	mux.HandleFunc("POST /admin/mode", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TemplateOnly *bool   `json:"template_only"`
+			BudgetMode   *string `json:"budget_mode"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.BudgetMode != nil {
+			if s.budget == nil {
+				http.Error(w, "no LLM budget is configured", http.StatusConflict)
+				return
+			}
+			switch *req.BudgetMode {
+			case "fallback":
+				s.budget.SetReject(false)
+			case "reject":
+				s.budget.SetReject(true)
+			default:
+				http.Error(w, "budget_mode must be fallback or reject", http.StatusBadRequest)
+				return
+			}
+		}
+		if req.TemplateOnly != nil {
+			s.templateOnly.Store(*req.TemplateOnly)
+		}
+		writeJSON(w, s.modes())
+	})
+	return mux
+}
+
+type cacheStats struct {
+	SemanticEntries int `json:"semantic_entries"`
+	ReplayStreams   int `json:"replay_streams"`
+}
+
+// flushStats counts what POST /admin/cache/flush dropped.
+type flushStats struct {
+	cacheStats
+	WeatherReadings int   `json:"weather_readings"`
+	Geocodes        int64 `json:"geocodes"`
+}
+
+// WeatherCache is a weather service cache the admin API can flush.
+type WeatherCache interface {
+	// FlushCache drops the cached readings and returns how many there
+	// were.
+	FlushCache() int
+}
+
+func (s *advisorService) cacheStats() cacheStats {
+	return cacheStats{SemanticEntries: s.cache.len(), ReplayStreams: s.replay.len()}
+}
+
+type modes struct {
+	// TemplateOnly answers every request with template advice, as if no
+	// LLM were configured.
+	TemplateOnly bool `json:"template_only"`
+	// BudgetMode is empty when no LLM budget is configured.
+	BudgetMode string `json:"budget_mode,omitempty"`
+}
+
+func (s *advisorService) modes() modes {
+	m := modes{TemplateOnly: s.templateOnly.Load()}
+	if s.budget != nil {
+		m.BudgetMode = s.budget.mode()
+	}
+	return m
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,228 @@
+package advisor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/alert"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var alertStreams = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "advisor_alert_streams",
+		Help: "Open SubscribeAlerts streams",
+	},
+)
+
+const (
+	// alertKeepaliveInterval is how long a SubscribeAlerts stream may go
+	// without a message before a keepalive is sent. Alerts are checked
+	// every half hour at most, so streams are mostly quiet.
+	alertKeepaliveInterval = 30 * time.Second
+	// alertStreamBuffer is how many alerts a slow stream may fall behind
+	// before it misses some.
+	alertStreamBuffer = 64
+	// maxAlertStreamIDs caps the subscriptions one stream may filter on.
+	maxAlertStreamIDs = 100
+)
+
+var (
+	alertMetrics = map[advisorpb.AlertMetric]alert.Metric{
+		advisorpb.AlertMetric_ALERT_METRIC_TEMP_MAX:                  alert.TempMax,
+		advisorpb.AlertMetric_ALERT_METRIC_TEMP_MIN:                  alert.TempMin,
+		advisorpb.AlertMetric_ALERT_METRIC_PRECIPITATION:             alert.Precipitation,
+		advisorpb.AlertMetric_ALERT_METRIC_PRECIPITATION_PROBABILITY: alert.PrecipitationProbability,
+		advisorpb.AlertMetric_ALERT_METRIC_WIND_SPEED:                alert.WindSpeed,
+		advisorpb.AlertMetric_ALERT_METRIC_UV_INDEX:                  alert.UVIndex,
+	}
+	alertOperators = map[advisorpb.AlertOperator]alert.Operator{
+		advisorpb.AlertOperator_ALERT_OPERATOR_ABOVE:    alert.Above,
+		advisorpb.AlertOperator_ALERT_OPERATOR_AT_LEAST: alert.AtLeast,
+		advisorpb.AlertOperator_ALERT_OPERATOR_BELOW:    alert.Below,
+		advisorpb.AlertOperator_ALERT_OPERATOR_AT_MOST:  alert.AtMost,
+	}
+	alertWindows = map[advisorpb.AlertWindow]alert.Window{
+		advisorpb.AlertWindow_ALERT_WINDOW_TODAY:       alert.Today,
+		advisorpb.AlertWindow_ALERT_WINDOW_TOMORROW:    alert.Tomorrow,
+		advisorpb.AlertWindow_ALERT_WINDOW_NEXT_3_DAYS: alert.Next3Days,
+		advisorpb.AlertWindow_ALERT_WINDOW_NEXT_7_DAYS: alert.Next7Days,
+	}
+)
+
+func (s *advisorService) CreateAlert(ctx context.Context, req *advisorpb.CreateAlertRequest) (*advisorpb.AlertSubscription, error) {
+	if s.alerts == nil {
+		return nil, status.Error(codes.Unavailable, "alerts are not enabled")
+	}
+	in := req.Subscription
+	if in == nil || in.City == nil || in.Condition == nil {
+		return nil, status.Error(codes.InvalidArgument, "subscription.city and subscription.condition are required")
+	}
+	cities, err := normalizeCities([]*advisorpb.CityData{in.City})
+	if err != nil {
+		return nil, err
+	}
+	city := cities[0]
+	cond := alert.Condition{
+		Metric:    alertMetrics[in.Condition.Metric],
+		Operator:  alertOperators[in.Condition.Operator],
+		Threshold: in.Condition.Threshold,
+		Unit:      in.Condition.Unit,
+		Window:    alertWindows[in.Condition.Window],
+	}
+	if err := cond.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	webhooks, err := subscriptionWebhooks(in.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	// Geocoding once here keeps the checker from geocoding every city on
+	// every run, and the alert from moving if the geocoder changes its mind.
+	loc, err := s.geocodeCity(ctx, city)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+
+	sub := &alert.Subscription{
+		ID:        uuid.NewString(),
+		Recipient: in.Recipient,
+		Location:  city.Location,
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		Condition: cond,
+		CreatedAt: time.Now(),
+		Webhooks:  webhooks,
+	}
+	if err := s.alerts.Save(ctx, sub); err != nil {
+		return nil, status.Errorf(codes.Internal, "save alert failed: %v", err)
+	}
+	out := alertSubscription(sub)
+	showSecrets(out.Webhooks, sub.Webhooks)
+	return out, nil
+}
+
+func (s *advisorService) ListAlerts(ctx context.Context, req *advisorpb.ListAlertsRequest) (*advisorpb.ListAlertsResponse, error) {
+	if s.alerts == nil {
+		return nil, status.Error(codes.Unavailable, "alerts are not enabled")
+	}
+	subs, err := s.alerts.List(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list alerts failed: %v", err)
+	}
+	resp := &advisorpb.ListAlertsResponse{}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, alertSubscription(sub))
+	}
+	return resp, nil
+}
+
+func (s *advisorService) DeleteAlert(ctx context.Context, req *advisorpb.DeleteAlertRequest) (*advisorpb.DeleteAlertResponse, error) {
+	if s.alerts == nil {
+		return nil, status.Error(codes.Unavailable, "alerts are not enabled")
+	}
+	err := s.alerts.Delete(ctx, req.Id)
+	if errors.Is(err, alert.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "alert %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete alert failed: %v", err)
+	}
+	return &advisorpb.DeleteAlertResponse{}, nil
+}
+
+func (s *advisorService) SubscribeAlerts(req *advisorpb.SubscribeAlertsRequest, stream advisorpb.AdvisorService_SubscribeAlertsServer) error {
+	if s.alerts == nil || s.alertFeed == nil {
+		return status.Error(codes.Unavailable, "alerts are not enabled")
+	}
+	ids := make(map[string]bool, len(req.SubscriptionIds))
+	for _, id := range req.SubscriptionIds {
+		ids[id] = true
+	}
+	events, cancel := s.alertFeed.Subscribe(alertStreamBuffer)
+	defer cancel()
+
+	alertStreams.Inc()
+	defer alertStreams.Dec()
+	// Sending the headers now tells the client it is subscribed, rather
+	// than leaving it waiting for the first alert.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	keepalive := time.NewTicker(alertKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepalive.C:
+			if err := stream.Send(&advisorpb.AlertEvent{Keepalive: true}); err != nil {
+				return err
+			}
+		case ev := <-events:
+			sub := ev.Subscription
+			if len(ids) > 0 && !ids[sub.ID] || req.Recipient != "" && sub.Recipient != req.Recipient {
+				continue
+			}
+			if err := stream.Send(alertEvent(ev)); err != nil {
+				return err
+			}
+			keepalive.Reset(alertKeepaliveInterval)
+		}
+	}
+}
+
+func alertEvent(ev alert.Event) *advisorpb.AlertEvent {
+	return &advisorpb.AlertEvent{
+		Subscription: alertSubscription(ev.Subscription),
+		Date:         ev.Date,
+		Value:        ev.Value,
+		Description:  ev.Description,
+		Title:        ev.Title,
+		Body:         ev.Body,
+		Time:         ev.Time.Unix(),
+	}
+}
+
+func alertSubscription(sub *alert.Subscription) *advisorpb.AlertSubscription {
+	lat, lon := sub.Latitude, sub.Longitude
+	out := &advisorpb.AlertSubscription{
+		Id:        sub.ID,
+		Recipient: sub.Recipient,
+		City:      &advisorpb.CityData{Location: sub.Location, Latitude: &lat, Longitude: &lon},
+		Condition: &advisorpb.AlertCondition{
+			Metric:    protoKey(alertMetrics, sub.Condition.Metric),
+			Operator:  protoKey(alertOperators, sub.Condition.Operator),
+			Threshold: sub.Condition.Threshold,
+			Unit:      sub.Condition.Unit,
+			Window:    protoKey(alertWindows, sub.Condition.Window),
+		},
+		CreatedAt:       sub.CreatedAt.Unix(),
+		LastAlertedDate: sub.LastAlertedDate,
+		Webhooks:        webhooksProto(sub.Webhooks),
+	}
+	if !sub.LastAlertedAt.IsZero() {
+		out.LastAlertedAt = sub.LastAlertedAt.Unix()
+	}
+	return out
+}
+
+// protoKey finds the enum value mapped to v.
+func protoKey[K comparable, V comparable](m map[K]V, v V) K {
+	for k, mv := range m {
+		if mv == v {
+			return k
+		}
+	}
+	var zero K
+	return zero
+}
@@ -0,0 +1,70 @@
+package advisor
+
+import (
+	"time"
+
+	"github.com/pixperk/effinarounf/services/audit"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LLMMethods are the RPCs that may call the LLM. They are written to the
+// audit log and count against tenant token quotas.
+var LLMMethods = []string{
+	advisorpb.AdvisorService_GetAdvice_FullMethodName,
+	advisorpb.AdvisorService_StreamAdvice_FullMethodName,
+	advisorpb.AdvisorService_CompareModels_FullMethodName,
+	advisorpb.AdvisorService_ChatStream_FullMethodName,
+	advisorpb.AdvisorService_RateActivity_FullMethodName,
+	advisorpb.AdvisorService_BestDay_FullMethodName,
+}
+
+func (s *advisorService) ExportAuditLog(req *advisorpb.ExportAuditLogRequest, stream advisorpb.AdvisorService_ExportAuditLogServer) error {
+	if s.audit == nil {
+		return status.Error(codes.Unavailable, "audit log is not enabled")
+	}
+	if !s.audit.CanExport(stream.Context()) {
+		return status.Error(codes.PermissionDenied, "not allowed to export the audit log")
+	}
+	if req.Until != 0 && req.Until < req.Since {
+		return status.Errorf(codes.InvalidArgument, "until %d is before since %d", req.Until, req.Since)
+	}
+	var filter audit.Filter
+	if req.Since != 0 {
+		filter.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until != 0 {
+		filter.Until = time.Unix(req.Until, 0)
+	}
+	filter.Client = req.Client
+
+	err := s.audit.Export(stream.Context(), filter, func(e *audit.Entry) error {
+		return stream.Send(auditEntry(e))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "audit export failed: %v", err)
+	}
+	return nil
+}
+
+func auditEntry(e *audit.Entry) *advisorpb.AuditEntry {
+	return &advisorpb.AuditEntry{
+		Id:               e.ID,
+		Time:             e.Time.Unix(),
+		RequestId:        e.RequestID,
+		Client:           e.Client,
+		Method:           e.Method,
+		Cities:           e.Cities,
+		Models:           e.Models,
+		PromptTokens:     e.PromptTokens,
+		ResponseTokens:   e.ResponseTokens,
+		TotalTokens:      e.TotalTokens,
+		EstimatedCostUsd: e.CostUSD,
+		Outcome:          e.Outcome,
+		DurationMs:       e.DurationMs,
+	}
+}
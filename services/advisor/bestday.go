@@ -0,0 +1,119 @@
+package advisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBestDays bounds BestDayRequest.count.
+const maxBestDays = 7
+
+func (s *advisorService) BestDay(ctx context.Context, req *advisorpb.BestDayRequest) (*advisorpb.BestDayResponse, error) {
+	if req.City == nil {
+		return nil, status.Error(codes.InvalidArgument, "city is required")
+	}
+	if _, err := normalizeCities([]*advisorpb.CityData{req.City}); err != nil {
+		return nil, err
+	}
+	activity := strings.ToLower(strings.TrimSpace(req.Activity))
+	if activity != "" && !slices.Contains(rules.Activities(), activity) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown activity %q (want one of %s)", req.Activity, strings.Join(rules.Activities(), ", "))
+	}
+	count := int(req.Count)
+	switch {
+	case count < 0 || count > maxBestDays:
+		return nil, status.Errorf(codes.InvalidArgument, "count must be between 1 and %d", maxBestDays)
+	case count == 0:
+		count = 1
+	}
+	modelName := s.model
+	if req.Explain {
+		var err error
+		if modelName, err = s.resolveModel(req.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	loc, err := s.geocodeCity(ctx, req.City)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "city: %v", err)
+	}
+	forecast, err := s.weatherSvc.GetDailyForecast(ctx, &weatherpb.DailyForecastRequest{
+		Latitude:  loc.Latitude,
+		Longitude: loc.Longitude,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "forecast request failed for %s: %v", req.City.Location, err)
+	}
+
+	conv := units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+	sources := newSourceSet()
+	if loc.Geocoded {
+		sources.add(geocodingSource())
+	}
+	sources.addProvider(forecast.Provider)
+	resp := &advisorpb.BestDayResponse{Location: req.City.Location}
+	for _, day := range forecast.Days {
+		score, reasons, err := rules.RateDay(activity, day, conv)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		resp.Days = append(resp.Days, &advisorpb.DayRating{Date: day.Date, Score: int32(score), Reasons: reasons})
+	}
+
+	best := append([]*advisorpb.DayRating(nil), resp.Days...)
+	sort.SliceStable(best, func(i, j int) bool { return best[i].Score > best[j].Score })
+	resp.Best = best[:min(count, len(best))]
+	resp.Sources = sources.list
+
+	if req.Explain {
+		s.explainBestDay(ctx, modelName, activity, resp)
+	}
+	return resp, nil
+}
+
+// explainBestDay asks the model to justify the pick. The ranking comes from
+// the rules, so a failed call only leaves the justification empty.
+func (s *advisorService) explainBestDay(ctx context.Context, modelName, activity string, resp *advisorpb.BestDayResponse) {
+	plan := "outdoor plans"
+	if activity != "" {
+		plan = activity
+	}
+	var rows []string
+	for _, d := range resp.Days {
+		rows = append(rows, fmt.Sprintf("%s|score %d|%s", d.Date, d.Score, strings.Join(d.Reasons, "; ")))
+	}
+	var picks []string
+	for _, d := range resp.Best {
+		picks = append(picks, d.Date)
+	}
+	prompt := fmt.Sprintf(`Weather advisor. Daily forecast for the place named in the first row, then one row per day as date|score|weather (score 0-100 for %s). %s
+
+%s
+
+The best day(s) are %s. In two or three sentences explain why, mentioning what makes the other days worse. Do not change the pick.`, plan, dataNotice, dataBlock(append([]string{resp.Location}, rows...)), strings.Join(picks, ", "))
+
+	text, usage, err := s.generateText(ctx, modelName, nil, prompt)
+	if err != nil {
+		slog.WarnContext(ctx, "best day explanation failed", "error", err)
+		return
+	}
+	resp.Justification = strings.TrimSpace(text)
+	resp.Usage = usage
+}
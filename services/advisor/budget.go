@@ -0,0 +1,210 @@
+package advisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	budgetSpentUSD = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "advisor_llm_budget_spent_usd",
+			Help: "Estimated LLM spend in the current budget period",
+		},
+		[]string{"period"},
+	)
+	budgetSpentTokens = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "advisor_llm_budget_spent_tokens",
+			Help: "LLM tokens used in the current budget period",
+		},
+		[]string{"period"},
+	)
+	budgetExceeded = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_llm_budget_exceeded_total",
+			Help: "LLM calls refused because a budget was used up, by period",
+		},
+		[]string{"period"},
+	)
+)
+
+// BudgetLimits caps LLM use per UTC day and calendar month. Zero leaves a
+// limit off.
+type BudgetLimits struct {
+	DailyUSD      float64
+	MonthlyUSD    float64
+	DailyTokens   int64
+	MonthlyTokens int64
+	// Reject makes an exhausted budget fail requests with RESOURCE_EXHAUSTED
+	// instead of answering them with template advice.
+	Reject bool
+}
+
+// Budget tracks LLM spend against BudgetLimits. Spend is kept in memory, so
+// a restart starts the periods afresh. A nil Budget allows everything.
+type Budget struct {
+	mu     sync.Mutex
+	limits BudgetLimits
+	day    budgetPeriod
+	month  budgetPeriod
+}
+
+type budgetPeriod struct {
+	key    string
+	usd    float64
+	tokens int64
+}
+
+func NewBudget(limits BudgetLimits) *Budget {
+	return &Budget{limits: limits}
+}
+
+// budgetError is returned when a budget is used up. It carries a
+// ResourceExhausted status; shouldFallback answers it with template advice
+// unless the budget rejects.
+type budgetError struct {
+	period string
+	resets time.Time
+	reject bool
+}
+
+func (e *budgetError) Error() string {
+	return fmt.Sprintf("%s LLM budget exhausted until %s", e.period, e.resets.Format(time.RFC3339))
+}
+
+func (e *budgetError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// roll starts new periods when the day or month has changed. Callers must
+// hold b.mu.
+func (b *Budget) roll(now time.Time) {
+	now = now.UTC()
+	if key := now.Format("2006-01-02"); b.day.key != key {
+		b.day = budgetPeriod{key: key}
+	}
+	if key := now.Format("2006-01"); b.month.key != key {
+		b.month = budgetPeriod{key: key}
+	}
+}
+
+// exceeded returns the first used-up period, "daily" or "monthly", and
+// when it resets, or "" when both have room. Callers must hold b.mu.
+func (b *Budget) exceeded(now time.Time) (string, time.Time) {
+	b.roll(now)
+	utc := now.UTC()
+	l := b.limits
+	switch {
+	case l.DailyUSD > 0 && b.day.usd >= l.DailyUSD, l.DailyTokens > 0 && b.day.tokens >= l.DailyTokens:
+		return "daily", time.Date(utc.Year(), utc.Month(), utc.Day()+1, 0, 0, 0, 0, time.UTC)
+	case l.MonthlyUSD > 0 && b.month.usd >= l.MonthlyUSD, l.MonthlyTokens > 0 && b.month.tokens >= l.MonthlyTokens:
+		return "monthly", time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return "", time.Time{}
+}
+
+// check returns a *budgetError when either period's budget is used up.
+func (b *Budget) check(now time.Time) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	period, resets := b.exceeded(now)
+	if period == "" {
+		return nil
+	}
+	budgetExceeded.WithLabelValues(period).Inc()
+	return &budgetError{period: period, resets: resets, reject: b.limits.Reject}
+}
+
+// charge adds a call's usage to both periods. The call that crosses a
+// limit is allowed to finish; the next one is refused.
+func (b *Budget) charge(usage *advisorpb.TokenUsage, now time.Time) {
+	if b == nil || usage == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(now)
+
+	for _, p := range []*budgetPeriod{&b.day, &b.month} {
+		p.usd += usage.EstimatedCostUsd
+		p.tokens += int64(usage.TotalTokens)
+	}
+	budgetSpentUSD.WithLabelValues("daily").Set(b.day.usd)
+	budgetSpentUSD.WithLabelValues("monthly").Set(b.month.usd)
+	budgetSpentTokens.WithLabelValues("daily").Set(float64(b.day.tokens))
+	budgetSpentTokens.WithLabelValues("monthly").Set(float64(b.month.tokens))
+}
+
+// SetReject switches between rejecting requests and answering them with
+// template advice once a budget is used up.
+func (b *Budget) SetReject(reject bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limits.Reject = reject
+}
+
+// mode returns "reject" or "fallback".
+func (b *Budget) mode() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limits.Reject {
+		return "reject"
+	}
+	return "fallback"
+}
+
+type budgetUsage struct {
+	Period      string  `json:"period"`
+	SpentUSD    float64 `json:"spent_usd"`
+	LimitUSD    float64 `json:"limit_usd,omitempty"`
+	SpentTokens int64   `json:"spent_tokens"`
+	LimitTokens int64   `json:"limit_tokens,omitempty"`
+}
+
+// ServeHTTP reports the spend in both periods as JSON, for operators. Only
+// GET is allowed.
+func (b *Budget) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	b.mu.Lock()
+	period, resets := b.exceeded(time.Now())
+	report := struct {
+		Mode      string        `json:"mode"`
+		Usage     []budgetUsage `json:"usage"`
+		Exhausted string        `json:"exhausted,omitempty"`
+		ResetsAt  *time.Time    `json:"resets_at,omitempty"`
+	}{
+		Mode: "fallback",
+		Usage: []budgetUsage{
+			{Period: b.day.key, SpentUSD: b.day.usd, LimitUSD: b.limits.DailyUSD, SpentTokens: b.day.tokens, LimitTokens: b.limits.DailyTokens},
+			{Period: b.month.key, SpentUSD: b.month.usd, LimitUSD: b.limits.MonthlyUSD, SpentTokens: b.month.tokens, LimitTokens: b.limits.MonthlyTokens},
+		},
+		Exhausted: period,
+	}
+	if b.limits.Reject {
+		report.Mode = "reject"
+	}
+	if period != "" {
+		report.ResetsAt = &resets
+	}
+	b.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,207 @@
+package advisor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/pixperk/effinarounf/services/session"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// embeddingModel embeds prompts for the semantic cache.
+const embeddingModel = "text-embedding-004"
+
+var semanticCacheLookups = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_semantic_cache_total",
+		Help: "Semantic advice cache lookups, by result",
+	},
+	[]string{"result"},
+)
+
+// SemanticCache reuses recent advice when a new prompt's weather context
+// embeds close to a cached one. Entries only match requests for the same
+// model, set of cities, units and user profile, so similar weather
+// elsewhere or advice written for someone else is never served. A nil SemanticCache disables caching.
+type SemanticCache struct {
+	mu         sync.Mutex
+	threshold  float64
+	ttl        time.Duration
+	maxEntries int
+	entries    []cacheEntry
+}
+
+type cacheEntry struct {
+	key     string
+	vector  []float32
+	advice  string
+	created time.Time
+}
+
+// NewSemanticCache caches up to maxEntries pieces of advice for ttl. A
+// cached advice is reused when the cosine similarity of the prompts is at
+// least threshold.
+func NewSemanticCache(threshold float64, ttl time.Duration, maxEntries int) *SemanticCache {
+	return &SemanticCache{threshold: threshold, ttl: ttl, maxEntries: maxEntries}
+}
+
+// lookup returns the most similar live entry for key at or above the
+// threshold.
+func (c *SemanticCache) lookup(key string, vector []float32, now time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(now)
+	best, bestScore := -1, c.threshold
+	for i, e := range c.entries {
+		if e.key != key {
+			continue
+		}
+		if score := cosine(vector, e.vector); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return c.entries[best].advice, true
+}
+
+func (c *SemanticCache) store(key string, vector []float32, advice string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expire(now)
+	c.entries = append(c.entries, cacheEntry{key: key, vector: vector, advice: advice, created: now})
+	if len(c.entries) > c.maxEntries {
+		c.entries = c.entries[len(c.entries)-c.maxEntries:]
+	}
+}
+
+// len returns the number of live entries.
+func (c *SemanticCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(time.Now())
+	return len(c.entries)
+}
+
+// flush drops every entry.
+func (c *SemanticCache) flush() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// expire drops entries older than the TTL. Entries are kept oldest first.
+// Callers must hold c.mu.
+func (c *SemanticCache) expire(now time.Time) {
+	i := sort.Search(len(c.entries), func(i int) bool { return now.Sub(c.entries[i].created) < c.ttl })
+	c.entries = c.entries[i:]
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// cacheProbe is a prompt that was looked up in the cache and can be stored
+// under the same key once fresh advice is generated.
+type cacheProbe struct {
+	key    string
+	vector []float32
+}
+
+// cacheKey is the exact-match part of a cache lookup: the model, advice
+// category, language, unit system, a digest of the user profile and the
+// cities. Similar weather therefore never returns advice about something
+// else, in other units or written for someone else. It also returns the
+// language, which the embedded text is prefixed with.
+func cacheKey(modelName string, req *advisorpb.AdvisorRequest, summaries []*advisorpb.CitySummary) (key, lang string) {
+	names := make([]string, 0, len(summaries))
+	for _, c := range summaries {
+		names = append(names, strings.ToLower(c.Location))
+	}
+	sort.Strings(names)
+	// req.Language is already canonical; no language means English.
+	lang = req.Language
+	if lang == "" {
+		lang = "en"
+	}
+	// The profile is hashed so the key holds nothing personal. Profiles
+	// that describe the user the same way share a digest.
+	var profile string
+	if line := profileInstruction(req.Profile); line != "" {
+		sum := sha256.Sum256([]byte(line))
+		profile = hex.EncodeToString(sum[:8])
+	}
+	parts := append([]string{modelName, req.Category.String(), lang, units.ParseSystem(req.UnitSystem).String(), profile}, names...)
+	return strings.Join(parts, "|"), lang
+}
+
+// cachedAdvice looks the prompt up in the semantic cache. Requests that
+// continue a session or override the generation settings are never cached,
+// since their answer depends on more than the weather. The probe is nil
+// when caching doesn't apply or the embedding failed.
+func (s *advisorService) cachedAdvice(ctx context.Context, modelName string, req *advisorpb.AdvisorRequest, summaries []*advisorpb.CitySummary, promptData []string, history []session.Message) (string, *cacheProbe) {
+	if s.cache == nil || !s.llmEnabled() {
+		return "", nil
+	}
+	if req.Generation != nil || len(history) > 0 {
+		semanticCacheLookups.WithLabelValues("bypass").Inc()
+		return "", nil
+	}
+	key, lang := cacheKey(modelName, req, summaries)
+
+	embedCtx, call := startLLMCall(ctx, "embed", embeddingModel)
+	res, err := s.genaiClient.EmbeddingModel(embeddingModel).EmbedContent(embedCtx, genai.Text("language: "+lang+"\n"+strings.Join(promptData, "\n")))
+	call.end(nil, err)
+	if err != nil || res.Embedding == nil {
+		slog.WarnContext(ctx, "semantic cache embedding failed", "error", err)
+		semanticCacheLookups.WithLabelValues("error").Inc()
+		return "", nil
+	}
+	probe := &cacheProbe{key: key, vector: res.Embedding.Values}
+	if advice, ok := s.cache.lookup(key, probe.vector, time.Now()); ok {
+		semanticCacheLookups.WithLabelValues("hit").Inc()
+		return advice, probe
+	}
+	semanticCacheLookups.WithLabelValues("miss").Inc()
+	return "", probe
+}
+
+// cacheAdvice stores freshly generated advice for a probe from cachedAdvice.
+func (s *advisorService) cacheAdvice(probe *cacheProbe, advice string) {
+	if probe == nil || strings.TrimSpace(advice) == "" {
+		return
+	}
+	s.cache.store(probe.key, probe.vector, advice, time.Now())
+}
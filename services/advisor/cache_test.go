@@ -0,0 +1,53 @@
+package advisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+func TestCacheKeySeparatesProfilesAndUnits(t *testing.T) {
+	summaries := []*advisorpb.CitySummary{{Location: "Berlin"}, {Location: "Paris"}}
+	vector := []float32{0.3, 0.5, 0.8}
+	now := time.Now()
+
+	base := &advisorpb.AdvisorRequest{UnitSystem: "metric"}
+	cold := &advisorpb.AdvisorRequest{UnitSystem: "metric", Profile: &advisorpb.UserProfile{RunsCold: true}}
+	allergic := &advisorpb.AdvisorRequest{UnitSystem: "metric", Profile: &advisorpb.UserProfile{Allergies: []string{"pollen"}}}
+	imperial := &advisorpb.AdvisorRequest{UnitSystem: "imperial"}
+
+	cache := NewSemanticCache(0.9, time.Minute, 10)
+	coldKey, _ := cacheKey("gemini", cold, summaries)
+	cache.store(coldKey, vector, "advice for someone who runs cold", now)
+
+	// Identical weather, and so an identical embedding, must not share an
+	// entry across profiles or unit systems.
+	for name, req := range map[string]*advisorpb.AdvisorRequest{"no profile": base, "allergies": allergic, "imperial": imperial} {
+		key, _ := cacheKey("gemini", req, summaries)
+		if key == coldKey {
+			t.Errorf("%s: shares key %q with the runs-cold profile", name, key)
+		}
+		if advice, ok := cache.lookup(key, vector, now); ok {
+			t.Errorf("%s: served %q", name, advice)
+		}
+	}
+	if _, ok := cache.lookup(coldKey, vector, now); !ok {
+		t.Error("same profile missed the cache")
+	}
+
+	// The key holds a digest, not the profile itself.
+	allergicKey, _ := cacheKey("gemini", allergic, summaries)
+	if strings.Contains(allergicKey, "pollen") {
+		t.Errorf("key %q contains the profile", allergicKey)
+	}
+
+	// Profiles that describe the user the same way share an entry.
+	empty := &advisorpb.AdvisorRequest{UnitSystem: "metric", Profile: &advisorpb.UserProfile{}}
+	emptyKey, _ := cacheKey("gemini", empty, summaries)
+	baseKey, _ := cacheKey("gemini", base, summaries)
+	if emptyKey != baseKey {
+		t.Errorf("empty profile key %q, want %q", emptyKey, baseKey)
+	}
+}
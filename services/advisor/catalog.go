@@ -0,0 +1,48 @@
+package advisor
+
+import (
+	"context"
+	"strings"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// cityCatalog is the cities clients offer before the user searches for a
+// place. Each name geocodes to the city given here.
+var cityCatalog = []*advisorpb.LocationCandidate{
+	{Name: "New York", State: "New York", Country: "United States", CountryCode: "US", Latitude: 40.7128, Longitude: -74.0060, Timezone: "America/New_York"},
+	{Name: "London", State: "England", Country: "United Kingdom", CountryCode: "GB", Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"},
+	{Name: "Tokyo", State: "Tokyo", Country: "Japan", CountryCode: "JP", Latitude: 35.6762, Longitude: 139.6503, Timezone: "Asia/Tokyo"},
+	{Name: "Paris", State: "Île-de-France", Country: "France", CountryCode: "FR", Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"},
+	{Name: "Los Angeles", State: "California", Country: "United States", CountryCode: "US", Latitude: 34.0522, Longitude: -118.2437, Timezone: "America/Los_Angeles"},
+	{Name: "Chicago", State: "Illinois", Country: "United States", CountryCode: "US", Latitude: 41.8781, Longitude: -87.6298, Timezone: "America/Chicago"},
+	{Name: "Sydney", State: "New South Wales", Country: "Australia", CountryCode: "AU", Latitude: -33.8688, Longitude: 151.2093, Timezone: "Australia/Sydney"},
+	{Name: "Berlin", State: "Berlin", Country: "Germany", CountryCode: "DE", Latitude: 52.5200, Longitude: 13.4050, Timezone: "Europe/Berlin"},
+	{Name: "Toronto", State: "Ontario", Country: "Canada", CountryCode: "CA", Latitude: 43.6532, Longitude: -79.3832, Timezone: "America/Toronto"},
+	{Name: "Mumbai", State: "Maharashtra", Country: "India", CountryCode: "IN", Latitude: 19.0760, Longitude: 72.8777, Timezone: "Asia/Kolkata"},
+	{Name: "Dubai", State: "Dubai", Country: "United Arab Emirates", CountryCode: "AE", Latitude: 25.2048, Longitude: 55.2708, Timezone: "Asia/Dubai"},
+	{Name: "Singapore", Country: "Singapore", CountryCode: "SG", Latitude: 1.3521, Longitude: 103.8198, Timezone: "Asia/Singapore"},
+	{Name: "San Francisco", State: "California", Country: "United States", CountryCode: "US", Latitude: 37.7749, Longitude: -122.4194, Timezone: "America/Los_Angeles"},
+	{Name: "Miami", State: "Florida", Country: "United States", CountryCode: "US", Latitude: 25.7617, Longitude: -80.1918, Timezone: "America/New_York"},
+	{Name: "Barcelona", State: "Catalonia", Country: "Spain", CountryCode: "ES", Latitude: 41.3851, Longitude: 2.1734, Timezone: "Europe/Madrid"},
+}
+
+// catalogHint names a few catalog cities for "location not found" errors.
+func catalogHint() string {
+	names := make([]string, 0, 7)
+	for _, c := range cityCatalog[:min(7, len(cityCatalog))] {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ListCities returns the city catalog. The entries are copies, so callers
+// can't change the catalog.
+func (s *advisorService) ListCities(ctx context.Context, req *advisorpb.ListCitiesRequest) (*advisorpb.ListCitiesResponse, error) {
+	resp := &advisorpb.ListCitiesResponse{Cities: make([]*advisorpb.LocationCandidate, len(cityCatalog))}
+	for i, c := range cityCatalog {
+		resp.Cities[i] = proto.Clone(c).(*advisorpb.LocationCandidate)
+	}
+	return resp, nil
+}
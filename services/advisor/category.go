@@ -0,0 +1,23 @@
+package advisor
+
+import advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+
+// categoryFocus is what the advice covers for each category, in place of
+// the general list in the prompt.
+var categoryFocus = map[advisorpb.AdviceCategory]string{
+	advisorpb.AdviceCategory_ADVICE_CATEGORY_TRAVEL:   "travel: getting around, likely delays, which sights suit the weather and what to pack",
+	advisorpb.AdviceCategory_ADVICE_CATEGORY_CLOTHING: "clothing: what to wear layer by layer, footwear and accessories such as an umbrella or sunglasses",
+	advisorpb.AdviceCategory_ADVICE_CATEGORY_SPORTS:   "sports: which outdoor exercise suits the weather, the best hours for it and what to bring",
+	advisorpb.AdviceCategory_ADVICE_CATEGORY_HEALTH:   "health: heat or cold stress, hydration, UV, air quality and pollen, and who should take care",
+}
+
+// categoryInstruction narrows the advice to the requested category, or
+// returns "" for general advice. It comes before the prompt's list of
+// what to include, so it says that it wins.
+func categoryInstruction(c advisorpb.AdviceCategory) string {
+	focus, ok := categoryFocus[c]
+	if !ok {
+		return ""
+	}
+	return "Only cover " + focus + ". This replaces the Include list below; still mention warnings."
+}
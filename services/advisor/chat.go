@@ -0,0 +1,171 @@
+package advisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *advisorService) ChatStream(stream advisorpb.AdvisorService_ChatStreamServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := first.GetStart()
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "the first message must be a start request")
+	}
+	cities, err := normalizeCities(start.Cities)
+	if err != nil {
+		return err
+	}
+	start.Cities = cities
+	if err := normalizeProfile(start.Profile); err != nil {
+		return err
+	}
+	if err := normalizeLanguage(&start.Language); err != nil {
+		return err
+	}
+
+	modelName, err := s.resolveModel(start.Model)
+	if err != nil {
+		return err
+	}
+	model, err := s.newModel(modelName, start.Generation)
+	if err != nil {
+		return err
+	}
+	sess, err := s.loadSession(ctx, start.SessionId)
+	if err != nil {
+		return err
+	}
+
+	var weatherData []string
+	var warnings []rules.Warning
+	for _, city := range start.Cities {
+		loc, err := s.geocodeCity(ctx, city)
+		if err != nil {
+			weatherData = append(weatherData, fmt.Sprintf("%s|no data (geocoding failed)", city.Location))
+			continue
+		}
+		weatherResp, err := s.weatherSvc.GetCurrentWeather(ctx, metricWeatherRequest(loc))
+		if err != nil {
+			weatherData = append(weatherData, fmt.Sprintf("%s|no data (weather failed)", city.Location))
+			continue
+		}
+		conv := units.Resolve(loc.CountryCode, units.ParseSystem(start.UnitSystem))
+		weatherData = append(weatherData, formatWeather(city.Location, weatherResp, conv))
+		weatherData = append(weatherData, rules.ComfortLine(city.Location, weatherResp))
+		warnings = append(warnings, rules.SafetyWarnings(city.Location, weatherResp, conv)...)
+	}
+
+	if block := rules.SafetyBlock(warnings); block != "" {
+		if err := stream.Send(&advisorpb.ChatResponse{Chunk: block}); err != nil {
+			return err
+		}
+	}
+
+	chat := model.StartChat()
+	var instructions []string
+	if line := profileInstruction(start.Profile); line != "" {
+		instructions = append(instructions, line)
+	}
+	if line := languageInstruction(start.Language); line != "" {
+		instructions = append(instructions, line)
+	}
+	prompt := fmt.Sprintf(`Weather advisor. Based on the data below provide practical advice (weather rows are city|temp|condition|humidity|wind). Facts rows are computed from the data; base clothing, umbrella and sunscreen advice on them. %s
+
+%s%s
+
+%sInclude: summary, clothing advice, activity suggestions, warnings. Keep it concise. Answer follow-up questions using this data.`, dataNotice, formatHistory(sessionHistory(sess)), dataBlock(weatherData), formatInstructions(instructions))
+
+	// The session keeps the weather rather than the full first prompt, which
+	// already embeds the earlier history.
+	saved := weatherData
+	for turn := int32(0); ; turn++ {
+		answer, err := s.chatTurn(ctx, stream, chat, modelName, turn, prompt)
+		if err != nil {
+			return err
+		}
+		s.saveExchange(ctx, sess, saved, answer)
+
+		next, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		prompt = next.GetPrompt()
+		if prompt == "" {
+			return status.Error(codes.InvalidArgument, "follow-up messages must carry a prompt")
+		}
+		saved = []string{prompt}
+	}
+}
+
+// chatTurn streams one answer and returns its text. A failed turn is removed
+// from the chat history so the conversation stays consistent.
+func (s *advisorService) chatTurn(ctx context.Context, stream advisorpb.AdvisorService_ChatStreamServer, chat *genai.ChatSession, modelName string, turn int32, prompt string) (_ string, err error) {
+	if err := s.budget.check(time.Now()); err != nil {
+		return "", err
+	}
+	estimate := estimateTokens(prompt)
+	if err := s.limiter.acquire(estimate); err != nil {
+		return "", err
+	}
+
+	var meta *genai.UsageMetadata
+	ctx, call := startLLMCall(ctx, "chat", modelName)
+	defer func() { call.end(meta, err) }()
+
+	historyLen := len(chat.History)
+	iter := chat.SendMessageStream(ctx, genai.Text(prompt))
+
+	var answer strings.Builder
+	for {
+		resp, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			chat.History = chat.History[:historyLen]
+			llmFailures.WithLabelValues("chat").Inc()
+			return "", generationStatus(ctx, err)
+		}
+		if resp.UsageMetadata != nil {
+			meta = resp.UsageMetadata
+		}
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range resp.Candidates[0].Content.Parts {
+			text, ok := part.(genai.Text)
+			if !ok {
+				continue
+			}
+			call.firstChunk()
+			answer.WriteString(string(text))
+			if err := stream.Send(&advisorpb.ChatResponse{Chunk: string(text), Turn: turn}); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	usage := recordUsage(modelName, meta)
+	s.limiter.settle(estimate, usage)
+	s.budget.charge(usage, time.Now())
+	return answer.String(), stream.Send(&advisorpb.ChatResponse{Turn: turn, TurnComplete: true, Usage: usage})
+}
@@ -0,0 +1,93 @@
+package advisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *advisorService) CompareModels(ctx context.Context, req *advisorpb.CompareModelsRequest) (*advisorpb.CompareModelsResponse, error) {
+	cities, err := normalizeCities(req.Cities)
+	if err != nil {
+		return nil, err
+	}
+	req.Cities = cities
+	models := append([]string(nil), req.Models...)
+	if len(models) == 0 {
+		models = []string{s.model, counterpartModel(s.model)}
+	}
+	if len(models) != 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "need exactly two models to compare, got %d", len(models))
+	}
+	for i, name := range models {
+		resolved, err := s.resolveModel(name)
+		if err != nil {
+			return nil, err
+		}
+		models[i] = resolved
+	}
+
+	var weatherData []string
+	for i, city := range req.Cities {
+		loc, err := s.geocodeCity(ctx, city)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: %v", i, err)
+		}
+		w, err := s.weatherSvc.GetCurrentWeather(ctx, metricWeatherRequest(loc))
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "weather request failed for %s: %v", city.Location, err)
+		}
+		conv := units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+		weatherData = append(weatherData, formatWeather(city.Location, w, conv))
+		weatherData = append(weatherData, rules.ComfortLine(city.Location, w))
+	}
+
+	// Both models run at once so neither benefits from a warmer connection
+	// or a quieter moment on the API.
+	results := make([]*advisorpb.ModelResult, len(models))
+	var wg sync.WaitGroup
+	for i, name := range models {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			started := time.Now()
+			advice, usage, err := s.generateAdvice(ctx, name, req.Generation, weatherData, nil, nil)
+			result := &advisorpb.ModelResult{
+				Model:     name,
+				Advice:    advice,
+				LatencyMs: time.Since(started).Milliseconds(),
+				Usage:     usage,
+			}
+			if err != nil {
+				result.Error = fmt.Sprintf("%v", err)
+			}
+			results[i] = result
+		}(i, name)
+	}
+	wg.Wait()
+
+	return &advisorpb.CompareModelsResponse{Weather: weatherData, Results: results}, nil
+}
+
+// counterpartModel pairs the default model with its sibling tier, which is
+// the comparison operators usually want (is flash good enough?).
+func counterpartModel(model string) string {
+	switch model {
+	case "gemini-2.5-pro":
+		return "gemini-2.5-flash"
+	case "gemini-2.5-flash":
+		return "gemini-2.5-pro"
+	case "gemini-1.5-pro":
+		return "gemini-1.5-flash"
+	case "gemini-1.5-flash":
+		return "gemini-1.5-pro"
+	}
+	return "gemini-2.5-flash"
+}
@@ -0,0 +1,128 @@
+package advisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/digest"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *advisorService) CreateDigest(ctx context.Context, req *advisorpb.CreateDigestRequest) (*advisorpb.DigestSubscription, error) {
+	if s.digests == nil {
+		return nil, status.Error(codes.Unavailable, "digests are not enabled")
+	}
+	in := req.Subscription
+	if in == nil || in.Request == nil {
+		return nil, status.Error(codes.InvalidArgument, "subscription.request is required")
+	}
+	cities, err := normalizeCities(in.Request.Cities)
+	if err != nil {
+		return nil, err
+	}
+	in.Request.Cities = cities
+	if err := normalizeProfile(in.Request.Profile); err != nil {
+		return nil, err
+	}
+	if err := normalizeLanguage(&in.Request.Language); err != nil {
+		return nil, err
+	}
+	if _, err := s.resolveModel(in.Request.Model); err != nil {
+		return nil, err
+	}
+	hour, minute, err := digest.ParseDeliveryTime(in.DeliveryTime)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if _, err := time.LoadLocation(in.TimeZone); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown time zone %q", in.TimeZone)
+	}
+	webhooks, err := subscriptionWebhooks(in.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	in.Request.SessionId = ""
+	sub := &digest.Subscription{
+		ID:        uuid.NewString(),
+		Recipient: in.Recipient,
+		Request:   in.Request,
+		Hour:      hour,
+		Minute:    minute,
+		TimeZone:  in.TimeZone,
+		CreatedAt: time.Now(),
+		Webhooks:  webhooks,
+	}
+	if err := s.digests.Save(ctx, sub); err != nil {
+		return nil, status.Errorf(codes.Internal, "save digest failed: %v", err)
+	}
+	out := digestSubscription(sub)
+	showSecrets(out.Webhooks, sub.Webhooks)
+	return out, nil
+}
+
+func (s *advisorService) ListDigests(ctx context.Context, req *advisorpb.ListDigestsRequest) (*advisorpb.ListDigestsResponse, error) {
+	if s.digests == nil {
+		return nil, status.Error(codes.Unavailable, "digests are not enabled")
+	}
+	subs, err := s.digests.List(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list digests failed: %v", err)
+	}
+	resp := &advisorpb.ListDigestsResponse{}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, digestSubscription(sub))
+	}
+	return resp, nil
+}
+
+func (s *advisorService) DeleteDigest(ctx context.Context, req *advisorpb.DeleteDigestRequest) (*advisorpb.DeleteDigestResponse, error) {
+	if s.digests == nil {
+		return nil, status.Error(codes.Unavailable, "digests are not enabled")
+	}
+	err := s.digests.Delete(ctx, req.Id)
+	if errors.Is(err, digest.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "digest %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete digest failed: %v", err)
+	}
+	return &advisorpb.DeleteDigestResponse{}, nil
+}
+
+// GenerateDigest runs a subscription's advice request. It is the
+// digest.Generator the scheduler calls.
+func (s *advisorService) GenerateDigest(ctx context.Context, sub *digest.Subscription) (string, error) {
+	resp, err := s.GetAdvice(ctx, sub.Request)
+	if err != nil {
+		return "", fmt.Errorf("digest advice failed: %v", err)
+	}
+	return resp.Advice, nil
+}
+
+func digestSubscription(sub *digest.Subscription) *advisorpb.DigestSubscription {
+	out := &advisorpb.DigestSubscription{
+		Id:           sub.ID,
+		Recipient:    sub.Recipient,
+		Request:      sub.Request,
+		DeliveryTime: fmt.Sprintf("%02d:%02d", sub.Hour, sub.Minute),
+		TimeZone:     sub.TimeZone,
+		CreatedAt:    sub.CreatedAt.Unix(),
+		Webhooks:     webhooksProto(sub.Webhooks),
+	}
+	since := sub.LastDelivered
+	if since.IsZero() {
+		since = sub.CreatedAt
+	} else {
+		out.LastDelivered = sub.LastDelivered.Unix()
+	}
+	if next, err := sub.NextDelivery(since); err == nil {
+		out.NextDelivery = next.Unix()
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package advisor
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+// cityExposure fetches the air quality forecast for a city and works out its
+// low-exposure windows, returning the structured result and a line for the
+// prompt. Air quality is optional context, so failures are logged and give
+// nil rather than failing the request.
+func (s *advisorService) cityExposure(ctx context.Context, city string, loc geoLocation, sources *sourceSet) (*advisorpb.CityExposure, string) {
+	forecast, err := s.weatherSvc.GetAirQualityForecast(ctx, metricWeatherRequest(loc))
+	if err != nil {
+		slog.WarnContext(ctx, "air quality unavailable", "city", city, "error", err)
+		return nil, ""
+	}
+	for _, p := range forecast.Providers {
+		sources.addProvider(p)
+	}
+
+	plan := rules.ExposureWindows(forecast.Hours)
+	return &advisorpb.CityExposure{
+		Location:         city,
+		Exercise:         timeWindows(plan.Exercise),
+		Ventilation:      timeWindows(plan.Ventilation),
+		UtcOffsetSeconds: forecast.UtcOffsetSeconds,
+	}, rules.FormatExposure(city, plan, forecast.UtcOffsetSeconds)
+}
+
+func timeWindows(ws []rules.Window) []*advisorpb.TimeWindow {
+	out := make([]*advisorpb.TimeWindow, 0, len(ws))
+	for _, w := range ws {
+		out = append(out, &advisorpb.TimeWindow{
+			Start:  w.Start.Unix(),
+			End:    w.End.Unix(),
+			MaxAqi: w.MaxAQI,
+		})
+	}
+	return out
+}
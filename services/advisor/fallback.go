@@ -0,0 +1,59 @@
+package advisor
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var adviceFallbacks = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_fallback_total",
+		Help: "Requests answered with template advice because the LLM failed",
+	},
+	[]string{"reason"},
+)
+
+// errNoLLM is returned by generation when the server runs without a Gemini
+// API key.
+var errNoLLM = status.Error(codes.Unavailable, "LLM is not configured")
+
+// errTemplateOnly is returned by generation while an operator has switched
+// the service to template advice.
+var errTemplateOnly = status.Error(codes.Unavailable, "LLM is disabled by an operator")
+
+// shouldFallback reports whether a generation error should be answered with
+// template advice. Bad requests, callers that went away and budgets set to
+// reject are not.
+func shouldFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var budgetErr *budgetError
+	if errors.As(err, &budgetErr) && budgetErr.reject {
+		return false
+	}
+	return status.Code(err) != codes.InvalidArgument
+}
+
+func fallbackReason(err error) string {
+	if err == errNoLLM {
+		return "not_configured"
+	}
+	if err == errTemplateOnly {
+		return "template_only"
+	}
+	var budgetErr *budgetError
+	if errors.As(err, &budgetErr) {
+		return "budget"
+	}
+	if st, ok := status.FromError(err); ok {
+		return strings.ToLower(st.Code().String())
+	}
+	return "error"
+}
@@ -0,0 +1,24 @@
+package advisor
+
+import (
+	"github.com/pixperk/effinarounf/services/markdown"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+// newAdviceFormatter converts the model's Markdown to the requested format.
+// Markdown itself needs no converter, and the nil one passes it through.
+func newAdviceFormatter(format advisorpb.AdviceFormat) *markdown.Converter {
+	switch format {
+	case advisorpb.AdviceFormat_ADVICE_FORMAT_PLAIN:
+		return markdown.NewConverter(markdown.Plain)
+	case advisorpb.AdviceFormat_ADVICE_FORMAT_HTML:
+		return markdown.NewConverter(markdown.HTML)
+	}
+	return nil
+}
+
+// formatAdvice converts a whole text.
+func formatAdvice(format advisorpb.AdviceFormat, text string) string {
+	f := newAdviceFormatter(format)
+	return f.Write(text) + f.Flush()
+}
@@ -0,0 +1,105 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GenerationSettings are the deployment-wide model parameters. Nil fields
+// leave the model's own default in place.
+type GenerationSettings struct {
+	Temperature     *float32
+	TopP            *float32
+	MaxOutputTokens *int32
+	// SafetyThreshold applies to every harm category. HarmBlockUnspecified
+	// keeps Gemini's defaults.
+	SafetyThreshold genai.HarmBlockThreshold
+}
+
+// maxOutputTokensLimit bounds per-request max_output_tokens overrides.
+const maxOutputTokensLimit = 8192
+
+var harmCategories = []genai.HarmCategory{
+	genai.HarmCategoryHarassment,
+	genai.HarmCategoryHateSpeech,
+	genai.HarmCategorySexuallyExplicit,
+	genai.HarmCategoryDangerousContent,
+}
+
+// ParseSafetyThreshold maps a config value such as "block_medium_and_above"
+// to a threshold. Empty means unspecified.
+func ParseSafetyThreshold(name string) (genai.HarmBlockThreshold, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return genai.HarmBlockUnspecified, nil
+	case "block_low_and_above":
+		return genai.HarmBlockLowAndAbove, nil
+	case "block_medium_and_above":
+		return genai.HarmBlockMediumAndAbove, nil
+	case "block_only_high":
+		return genai.HarmBlockOnlyHigh, nil
+	case "block_none":
+		return genai.HarmBlockNone, nil
+	}
+	return 0, fmt.Errorf("unknown safety threshold %q", name)
+}
+
+// newModel builds a model with the deployment settings and then applies the
+// request's overrides, which are validated so clients can't ask for
+// unbounded output.
+func (s *advisorService) newModel(name string, override *advisorpb.GenerationConfig) (*genai.GenerativeModel, error) {
+	if s.genaiClient == nil {
+		return nil, errNoLLM
+	}
+	if s.templateOnly.Load() {
+		return nil, errTemplateOnly
+	}
+	model := s.genaiClient.GenerativeModel(name)
+
+	gen := s.generation
+	if gen.Temperature != nil {
+		model.SetTemperature(*gen.Temperature)
+	}
+	if gen.TopP != nil {
+		model.SetTopP(*gen.TopP)
+	}
+	if gen.MaxOutputTokens != nil {
+		model.SetMaxOutputTokens(*gen.MaxOutputTokens)
+	}
+	if gen.SafetyThreshold != genai.HarmBlockUnspecified {
+		for _, category := range harmCategories {
+			model.SafetySettings = append(model.SafetySettings, &genai.SafetySetting{
+				Category:  category,
+				Threshold: gen.SafetyThreshold,
+			})
+		}
+	}
+
+	if override == nil {
+		return model, nil
+	}
+	if override.Temperature != nil {
+		if t := override.GetTemperature(); t < 0 || t > 2 {
+			return nil, status.Errorf(codes.InvalidArgument, "temperature %.2f out of range [0, 2]", t)
+		}
+		model.SetTemperature(override.GetTemperature())
+	}
+	if override.TopP != nil {
+		if p := override.GetTopP(); p < 0 || p > 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "top_p %.2f out of range [0, 1]", p)
+		}
+		model.SetTopP(override.GetTopP())
+	}
+	if override.MaxOutputTokens != nil {
+		if n := override.GetMaxOutputTokens(); n <= 0 || n > maxOutputTokensLimit {
+			return nil, status.Errorf(codes.InvalidArgument, "max_output_tokens %d out of range (0, %d]", n, maxOutputTokensLimit)
+		}
+		model.SetMaxOutputTokens(override.GetMaxOutputTokens())
+	}
+	return model, nil
+}
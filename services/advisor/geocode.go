@@ -0,0 +1,182 @@
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/httpclient"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// geocodeCandidates is how many matches geocodeCity considers when
+	// filtering by state and country.
+	geocodeCandidates = 10
+	// defaultSearchLimit and maxSearchLimit bound SearchLocations.
+	defaultSearchLimit = 5
+	maxSearchLimit     = 20
+)
+
+// GeocodingURL is the Open-Meteo geocoding search endpoint.
+var GeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+// GeocodeCache remembers geocoding responses by request, since places
+// don't move.
+type GeocodeCache interface {
+	Get(ctx context.Context, query string) ([]byte, bool, error)
+	Put(ctx context.Context, query string, results []byte) error
+	// Flush forgets every response and returns how many there were.
+	Flush(ctx context.Context) (int64, error)
+}
+
+type GeocodeResponse struct {
+	Results []geocodeResult `json:"results"`
+}
+
+type geocodeResult struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code"`
+	Admin1      string  `json:"admin1"`
+	Population  int64   `json:"population"`
+	Timezone    string  `json:"timezone"`
+}
+
+// searchLocations asks the geocoding API for up to count matches. An ISO
+// country code is passed on so the API filters; names are matched later.
+// Answers are served from the geocode cache when there is one; a failing
+// cache only costs the API call it would have saved.
+func (s *advisorService) searchLocations(ctx context.Context, name, country string, count int) ([]geocodeResult, error) {
+	apiURL := fmt.Sprintf("%s?name=%s&count=%d&language=en&format=json", GeocodingURL, url.QueryEscape(name), count)
+	if code := countryCode(country); code != "" {
+		apiURL += "&countryCode=" + code
+	}
+	if s.geocodes != nil {
+		cached, ok, err := s.geocodes.Get(ctx, apiURL)
+		if err != nil {
+			slog.WarnContext(ctx, "geocode cache read failed", "error", err)
+		}
+		var results []geocodeResult
+		if ok && json.Unmarshal(cached, &results) == nil {
+			return results, nil
+		}
+	}
+	results, err := s.fetchLocations(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+	if s.geocodes != nil {
+		data, _ := json.Marshal(results)
+		if err := s.geocodes.Put(ctx, apiURL, data); err != nil {
+			slog.WarnContext(ctx, "geocode cache write failed", "error", err)
+		}
+	}
+	return results, nil
+}
+
+func (s *advisorService) fetchLocations(ctx context.Context, apiURL string) ([]geocodeResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding failed: %v", err)
+	}
+	resp, err := httpclient.Do(s.httpClient, "geocoding", req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding API returned status %d", resp.StatusCode)
+	}
+
+	var geocodeResp GeocodeResponse
+	if err := httpclient.DecodeJSON(resp, &geocodeResp); err != nil {
+		return nil, fmt.Errorf("decode failed: %v", err)
+	}
+	return geocodeResp.Results, nil
+}
+
+// rankCandidates drops results that contradict the given state or country
+// and keeps the API's relevance order for the rest. Exact state matches
+// come before prefix matches ("New" for "New South Wales").
+func rankCandidates(results []geocodeResult, state, country string) []geocodeResult {
+	state = strings.TrimSpace(state)
+	country = strings.TrimSpace(country)
+
+	var exact, partial []geocodeResult
+	for _, r := range results {
+		if country != "" && !strings.EqualFold(r.Country, country) && !strings.EqualFold(r.CountryCode, country) {
+			continue
+		}
+		switch {
+		case state == "" || strings.EqualFold(r.Admin1, state):
+			exact = append(exact, r)
+		case strings.HasPrefix(strings.ToLower(r.Admin1), strings.ToLower(state)):
+			partial = append(partial, r)
+		}
+	}
+	return append(exact, partial...)
+}
+
+func placeName(state, country string) string {
+	var parts []string
+	for _, p := range []string{strings.TrimSpace(state), strings.TrimSpace(country)} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SearchLocations returns ranked candidates for a name so clients can ask
+// the user which one they meant. A candidate's coordinates can be sent back
+// in CityData to skip geocoding.
+func (s *advisorService) SearchLocations(ctx context.Context, req *advisorpb.SearchLocationsRequest) (*advisorpb.SearchLocationsResponse, error) {
+	query := normalizeLocation(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	limit := int(req.Limit)
+	switch {
+	case limit < 0:
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	case limit == 0:
+		limit = defaultSearchLimit
+	case limit > maxSearchLimit:
+		limit = maxSearchLimit
+	}
+
+	// Fetch more than asked for so filtering by state still fills the page.
+	results, err := s.searchLocations(ctx, query, req.Country, maxSearchLimit)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	ranked := rankCandidates(results, req.State, req.Country)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	resp := &advisorpb.SearchLocationsResponse{Sources: []*advisorpb.DataSource{geocodingSource()}}
+	for _, r := range ranked {
+		resp.Candidates = append(resp.Candidates, &advisorpb.LocationCandidate{
+			Name:        r.Name,
+			State:       r.Admin1,
+			Country:     r.Country,
+			CountryCode: r.CountryCode,
+			Latitude:    r.Latitude,
+			Longitude:   r.Longitude,
+			Population:  r.Population,
+			Timezone:    r.Timezone,
+		})
+	}
+	return resp, nil
+}
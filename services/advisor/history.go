@@ -0,0 +1,145 @@
+package advisor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/events"
+	"github.com/pixperk/effinarounf/services/history"
+	"github.com/pixperk/effinarounf/services/pagination"
+	"github.com/pixperk/effinarounf/services/session"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *advisorService) ListHistory(ctx context.Context, req *advisorpb.ListHistoryRequest) (*advisorpb.ListHistoryResponse, error) {
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	if s.sessions == nil {
+		return nil, status.Error(codes.Unavailable, "sessions are not enabled")
+	}
+
+	sess, err := s.sessions.Get(ctx, req.SessionId)
+	if errors.Is(err, session.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "session %s not found", req.SessionId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "session lookup failed: %v", err)
+	}
+
+	messages, page, err := pagination.Paginate(sess.Messages,
+		func(m session.Message) time.Time { return m.At },
+		req.SessionId, req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*advisorpb.HistoryEntry, 0, len(messages))
+	for _, msg := range messages {
+		entries = append(entries, &advisorpb.HistoryEntry{
+			Role:      msg.Role,
+			Content:   msg.Content,
+			CreatedAt: msg.At.Unix(),
+		})
+	}
+	return &advisorpb.ListHistoryResponse{Entries: entries, Page: page}, nil
+}
+
+// saveAdvice stores generated advice and returns its ID, or "" when history
+// is disabled or the save failed. A failed save doesn't fail the request.
+func (s *advisorService) saveAdvice(ctx context.Context, sessionID, model string, summaries []*advisorpb.CitySummary, weatherData []string, advice string) string {
+	if s.history == nil {
+		return ""
+	}
+	cities := make([]string, 0, len(summaries))
+	for _, c := range summaries {
+		cities = append(cities, c.Location)
+	}
+	rec := &history.Record{
+		ID:        uuid.NewString(),
+		SessionID: sessionID,
+		Cities:    cities,
+		Weather:   weatherData,
+		Advice:    advice,
+		Model:     model,
+		CreatedAt: time.Now(),
+	}
+	if err := s.history.Save(ctx, rec); err != nil {
+		slog.ErrorContext(ctx, "saving advice history failed", "error", err)
+		return ""
+	}
+	return rec.ID
+}
+
+// publishAdvice sends generated advice to the event bus.
+func (s *advisorService) publishAdvice(id, sessionID, model string, summaries []*advisorpb.CitySummary, advice string, streamed bool) {
+	if s.events == nil {
+		return
+	}
+	cities := make([]string, 0, len(summaries))
+	for _, c := range summaries {
+		cities = append(cities, c.Location)
+	}
+	s.events.Publish(events.TypeAdvice, sessionID, events.Advice{
+		ID:        id,
+		SessionID: sessionID,
+		Model:     model,
+		Cities:    cities,
+		Advice:    advice,
+		Streamed:  streamed,
+	})
+}
+
+func (s *advisorService) ListAdviceHistory(ctx context.Context, req *advisorpb.ListAdviceHistoryRequest) (*advisorpb.ListAdviceHistoryResponse, error) {
+	if s.history == nil {
+		return nil, status.Error(codes.Unavailable, "advice history is not enabled")
+	}
+	records, err := s.history.List(ctx, req.SessionId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "history lookup failed: %v", err)
+	}
+
+	page, pageResp, err := pagination.Paginate(records,
+		func(r *history.Record) time.Time { return r.CreatedAt },
+		"advice|"+req.SessionId, req.Page)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*advisorpb.AdviceRecord, 0, len(page))
+	for _, rec := range page {
+		out = append(out, adviceRecord(rec))
+	}
+	return &advisorpb.ListAdviceHistoryResponse{Records: out, Page: pageResp}, nil
+}
+
+func (s *advisorService) GetAdviceRecord(ctx context.Context, req *advisorpb.GetAdviceRecordRequest) (*advisorpb.AdviceRecord, error) {
+	if s.history == nil {
+		return nil, status.Error(codes.Unavailable, "advice history is not enabled")
+	}
+	rec, err := s.history.Get(ctx, req.Id)
+	if errors.Is(err, history.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "advice %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "history lookup failed: %v", err)
+	}
+	return adviceRecord(rec), nil
+}
+
+func adviceRecord(rec *history.Record) *advisorpb.AdviceRecord {
+	return &advisorpb.AdviceRecord{
+		Id:        rec.ID,
+		SessionId: rec.SessionID,
+		Cities:    rec.Cities,
+		Weather:   rec.Weather,
+		Advice:    rec.Advice,
+		Model:     rec.Model,
+		CreatedAt: rec.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,42 @@
+package advisor
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// normalizeLanguage replaces a requested language tag with its canonical
+// form ("PT_br" becomes "pt-BR"), rejecting anything that isn't a BCP 47
+// tag. Only the canonical tag and its English name reach the prompt.
+func normalizeLanguage(tag *string) error {
+	if *tag == "" {
+		return nil
+	}
+	t, err := language.Parse(*tag)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "language %q is not a BCP 47 tag such as \"de\" or \"pt-BR\"", *tag)
+	}
+	*tag = t.String()
+	return nil
+}
+
+// languageInstruction asks for the advice in a language other than
+// English, or returns "" for English.
+func languageInstruction(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	t := language.Make(tag)
+	if base, _ := t.Base(); base.String() == "en" {
+		return ""
+	}
+	name := display.English.Tags().Name(t)
+	if name == "" {
+		name = tag
+	}
+	return fmt.Sprintf("Write the advice in %s (%s); keep city names as given.", name, tag)
+}
@@ -0,0 +1,87 @@
+package advisor
+
+import (
+	"math"
+	"time"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var llmRateLimited = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_llm_rate_limited_total",
+		Help: "LLM calls rejected by the local rate limiter, by limit",
+	},
+	[]string{"limit"},
+)
+
+// RateLimiter caps LLM calls per second and tokens per minute so a traffic
+// spike is turned away locally instead of exhausting the API quota. A nil
+// RateLimiter, or a zero limit, allows everything.
+type RateLimiter struct {
+	requests *rate.Limiter
+	tokens   *rate.Limiter
+}
+
+func NewRateLimiter(qps float64, tokensPerMinute int) *RateLimiter {
+	l := &RateLimiter{}
+	if qps > 0 {
+		l.requests = rate.NewLimiter(rate.Limit(qps), int(math.Max(1, math.Ceil(qps))))
+	}
+	if tokensPerMinute > 0 {
+		l.tokens = rate.NewLimiter(rate.Limit(float64(tokensPerMinute)/60), tokensPerMinute)
+	}
+	return l
+}
+
+// estimateTokens is a rough prompt size, about four characters per token,
+// used to charge the token bucket before the real count is known.
+func estimateTokens(prompt string) int {
+	return len(prompt)/4 + 1
+}
+
+// acquire reserves one call and the estimated prompt tokens. It returns a
+// ResourceExhausted status telling the caller when to retry.
+func (l *RateLimiter) acquire(estimate int) error {
+	if l == nil {
+		return nil
+	}
+	now := time.Now()
+	if l.requests != nil && !l.requests.AllowN(now, 1) {
+		llmRateLimited.WithLabelValues("requests").Inc()
+		return tryLater(l.requests, 1)
+	}
+	if l.tokens != nil {
+		if estimate > l.tokens.Burst() {
+			estimate = l.tokens.Burst()
+		}
+		if !l.tokens.AllowN(now, estimate) {
+			llmRateLimited.WithLabelValues("tokens").Inc()
+			return tryLater(l.tokens, estimate)
+		}
+	}
+	return nil
+}
+
+// settle charges the difference between the estimate and the tokens the
+// model actually used, so long responses count against the next minute.
+func (l *RateLimiter) settle(estimate int, usage *advisorpb.TokenUsage) {
+	if l == nil || l.tokens == nil || usage == nil {
+		return
+	}
+	if extra := int(usage.TotalTokens) - estimate; extra > 0 {
+		l.tokens.ReserveN(time.Now(), min(extra, l.tokens.Burst()))
+	}
+}
+
+func tryLater(limiter *rate.Limiter, n int) error {
+	r := limiter.ReserveN(time.Now(), n)
+	wait := r.Delay()
+	r.Cancel()
+	return status.Errorf(codes.ResourceExhausted, "advice service is busy, try again in %s", wait.Truncate(time.Second)+time.Second)
+}
@@ -0,0 +1,103 @@
+package advisor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/pixperk/effinarounf/services/audit"
+	"github.com/pixperk/effinarounf/services/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer follows the global provider, so Gemini spans are only recorded
+// once main installs one.
+var tracer = otel.Tracer("github.com/pixperk/effinarounf/services/advisor")
+
+var (
+	llmDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "advisor_llm_call_duration_seconds",
+			Help:    "Gemini call latency including retries, by model, operation and result",
+			Buckets: []float64{.1, .25, .5, 1, 2, 4, 8, 16, 32, 64},
+		},
+		[]string{"model", "operation", "result"},
+	)
+	llmFirstChunk = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "advisor_llm_first_chunk_seconds",
+			Help:    "Time until a streamed Gemini call returned its first text, by model and operation",
+			Buckets: []float64{.1, .25, .5, 1, 2, 4, 8, 16},
+		},
+		[]string{"model", "operation"},
+	)
+)
+
+// llmCall measures one Gemini operation (generate, stream, chat or embed)
+// as both a client span and latency metrics. Retries are events on the
+// same span and count towards the same duration.
+type llmCall struct {
+	ctx       context.Context
+	span      trace.Span
+	model     string
+	operation string
+	start     time.Time
+	firstOnce sync.Once
+}
+
+func startLLMCall(ctx context.Context, operation, model string) (context.Context, *llmCall) {
+	ctx, span := tracer.Start(ctx, "gemini."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("gen_ai.system", "gemini"),
+			attribute.String("gen_ai.operation.name", operation),
+			attribute.String("gen_ai.request.model", model),
+		))
+	return ctx, &llmCall{ctx: ctx, span: span, model: model, operation: operation, start: time.Now()}
+}
+
+// firstChunk records the time to the first streamed text; later calls do
+// nothing.
+func (c *llmCall) firstChunk() {
+	c.firstOnce.Do(func() {
+		llmFirstChunk.WithLabelValues(c.model, c.operation).Observe(time.Since(c.start).Seconds())
+		c.span.AddEvent("first chunk")
+	})
+}
+
+// retry notes that a failed attempt is being retried.
+func (c *llmCall) retry(attempt int, err error) {
+	c.span.AddEvent("retry", trace.WithAttributes(
+		attribute.Int("attempt", attempt),
+		attribute.String("error", err.Error()),
+	))
+}
+
+// end records the token counts, if any, and the outcome, then ends the
+// span. The tokens are also charged to the audited call and the caller's
+// tenant, if any.
+func (c *llmCall) end(meta *genai.UsageMetadata, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	llmDuration.WithLabelValues(c.model, c.operation, result).Observe(time.Since(c.start).Seconds())
+	if meta != nil {
+		c.span.SetAttributes(
+			attribute.Int("gen_ai.usage.input_tokens", int(meta.PromptTokenCount)),
+			attribute.Int("gen_ai.usage.output_tokens", int(meta.CandidatesTokenCount)),
+		)
+		audit.AddUsage(c.ctx, c.model, meta.PromptTokenCount, meta.CandidatesTokenCount, meta.TotalTokenCount,
+			estimateCost(c.model, meta.PromptTokenCount, meta.CandidatesTokenCount))
+		tenant.AddTokens(c.ctx, meta.TotalTokenCount)
+	}
+	c.span.End()
+}
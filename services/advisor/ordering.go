@@ -0,0 +1,59 @@
+package advisor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+// orderedCity keeps a city's position in the request so errors still report
+// the index the caller used.
+type orderedCity struct {
+	index int
+	city  *advisorpb.CityData
+}
+
+// orderCities puts the primary city first and the rest in the requested
+// order. Only the first city marked primary counts.
+func orderCities(req *advisorpb.AdvisorRequest) []orderedCity {
+	cities := make([]orderedCity, 0, len(req.Cities))
+	primary := -1
+	for i, city := range req.Cities {
+		if city.Primary && primary < 0 {
+			primary = i
+		}
+		cities = append(cities, orderedCity{index: i, city: city})
+	}
+
+	if req.Order == advisorpb.CityOrder_CITY_ORDER_ALPHABETICAL {
+		sort.SliceStable(cities, func(i, j int) bool {
+			return strings.ToLower(cities[i].city.Location) < strings.ToLower(cities[j].city.Location)
+		})
+	}
+	if primary >= 0 {
+		sort.SliceStable(cities, func(i, j int) bool {
+			return cities[i].index == primary && cities[j].index != primary
+		})
+	}
+	return cities
+}
+
+// orderInstruction tells the model which order to cover the cities in, so the
+// text matches the structured response instead of the model's own choice.
+func orderInstruction(cities []*advisorpb.CitySummary) string {
+	if len(cities) < 2 {
+		return ""
+	}
+	// Names are quoted so they read as labels; they can't contain quotes.
+	names := make([]string, 0, len(cities))
+	for _, c := range cities {
+		names = append(names, `"`+c.Location+`"`)
+	}
+	text := fmt.Sprintf("Cover the cities in exactly this order: %s.", strings.Join(names, ", "))
+	if cities[0].Primary {
+		text += fmt.Sprintf(" %s is the user's main city: lead with it and give it the most detail.", names[0])
+	}
+	return text
+}
@@ -0,0 +1,89 @@
+package advisor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GeneratePackingList is computed entirely by the rules engine, so the list
+// is the same for the same forecast and never depends on the model.
+func (s *advisorService) GeneratePackingList(ctx context.Context, req *advisorpb.PackingListRequest) (*advisorpb.PackingListResponse, error) {
+	tripType := strings.ToLower(strings.TrimSpace(req.TripType))
+	if tripType != "" && !slices.Contains(rules.TripTypes, tripType) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown trip type %q (want one of %s)", req.TripType, strings.Join(rules.TripTypes, ", "))
+	}
+	cities, err := normalizeCities(req.Destinations)
+	if err != nil {
+		return nil, err
+	}
+	req.Destinations = cities
+
+	resp := &advisorpb.PackingListResponse{}
+	sources := newSourceSet()
+	var days []rules.TripDay
+	var conv units.Conventions
+	for i, city := range req.Destinations {
+		loc, err := s.geocodeCity(ctx, city)
+		if err != nil {
+			resp.Errors = append(resp.Errors, cityError(i, city, "geocoding", err))
+			continue
+		}
+		if loc.Geocoded {
+			sources.add(geocodingSource())
+		}
+		forecast, err := s.weatherSvc.GetDailyForecast(ctx, &weatherpb.DailyForecastRequest{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			StartDate: req.StartDate,
+			EndDate:   req.EndDate,
+		})
+		if err != nil {
+			// Bad dates fail every destination the same way.
+			if code := status.Code(err); code == codes.InvalidArgument || code == codes.OutOfRange {
+				return nil, err
+			}
+			resp.Errors = append(resp.Errors, cityError(i, city, "forecast", err))
+			continue
+		}
+		sources.addProvider(forecast.Provider)
+		// The list is written in the first destination's units.
+		if len(days) == 0 {
+			conv = units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+		}
+		for _, day := range forecast.Days {
+			days = append(days, rules.TripDay{City: city.Location, Day: day})
+		}
+	}
+	if len(days) == 0 {
+		return nil, status.Error(codes.Unavailable, "no forecast available for any destination")
+	}
+
+	items, err := rules.PackingList(tripType, days, conv)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	dates := make(map[string]bool)
+	for _, d := range days {
+		dates[d.Day.Date] = true
+	}
+	resp.TripDays = int32(len(dates))
+	for _, item := range items {
+		resp.Items = append(resp.Items, &advisorpb.PackingItem{
+			Category:  item.Category,
+			Name:      item.Name,
+			Quantity:  int32(item.Quantity),
+			Reason:    item.Reason,
+			Essential: item.Essential,
+		})
+	}
+	resp.Sources = sources.list
+	return resp, nil
+}
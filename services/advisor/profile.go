@@ -0,0 +1,73 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxAllergies     = 10
+	maxAllergyLength = 40
+)
+
+// normalizeProfile trims the allergies in place and rejects profiles that
+// contradict themselves. Allergies end up in the prompt, so they are kept
+// short and to plain text.
+func normalizeProfile(p *advisorpb.UserProfile) error {
+	if p == nil {
+		return nil
+	}
+	if p.RunsCold && p.RunsHot {
+		return status.Error(codes.InvalidArgument, "profile: runs_cold and runs_hot are mutually exclusive")
+	}
+	if len(p.Allergies) > maxAllergies {
+		return status.Errorf(codes.InvalidArgument, "profile: at most %d allergies", maxAllergies)
+	}
+	allergies := p.Allergies[:0]
+	for i, a := range p.Allergies {
+		a = strings.Join(strings.Fields(a), " ")
+		if len(a) > maxAllergyLength {
+			return status.Errorf(codes.InvalidArgument, "profile: allergies[%d] is longer than %d characters", i, maxAllergyLength)
+		}
+		if err := checkPromptText(a); err != nil {
+			return status.Errorf(codes.InvalidArgument, "profile: allergies[%d] %v", i, err)
+		}
+		if a != "" {
+			allergies = append(allergies, a)
+		}
+	}
+	p.Allergies = allergies
+	return nil
+}
+
+// profileInstruction describes the user to the model, or returns "" when
+// there is nothing to say.
+func profileInstruction(p *advisorpb.UserProfile) string {
+	if p == nil {
+		return ""
+	}
+	var notes []string
+	if p.RunsCold {
+		notes = append(notes, "feels the cold more than most, so suggest one layer warmer")
+	}
+	if p.RunsHot {
+		notes = append(notes, "feels the heat more than most, so suggest one layer lighter")
+	}
+	if p.HasKids {
+		notes = append(notes, "has young children, so cover what they should wear and kid-friendly plans")
+	}
+	if p.CyclesToWork {
+		notes = append(notes, "cycles to work, so cover the commute: rain, wind, ice and visibility")
+	}
+	if len(p.Allergies) > 0 {
+		notes = append(notes, fmt.Sprintf("has allergies (%s), so flag pollen, dust or air quality that could affect them", strings.Join(p.Allergies, ", ")))
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return "About the user: " + strings.Join(notes, "; ") + ". Tailor the advice to them."
+}
@@ -0,0 +1,85 @@
+package advisor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	llmRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_llm_retries_total",
+			Help: "LLM calls retried after a transient error",
+		},
+		[]string{"mode"},
+	)
+	llmFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_llm_failed_generations_total",
+			Help: "LLM generations that failed after all retries",
+		},
+		[]string{"mode"},
+	)
+)
+
+// Backoff settings for transient Gemini errors (429 and 5xx).
+const (
+	maxLLMAttempts = 4
+	baseBackoff    = 500 * time.Millisecond
+	maxBackoff     = 8 * time.Second
+)
+
+// isTransient reports whether a Gemini error is worth retrying. The SDK
+// surfaces HTTP errors as googleapi.Error and gRPC errors as statuses.
+func isTransient(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusTooManyRequests || gerr.Code >= 500
+	}
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.ResourceExhausted, codes.Unavailable, codes.Internal:
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before the next attempt, or false if
+// the error is permanent, attempts are used up, or the wait would run past
+// the caller's deadline. attempt counts from zero.
+func retryDelay(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt+1 >= maxLLMAttempts || !isTransient(err) {
+		return 0, false
+	}
+	ceiling := baseBackoff << attempt
+	if ceiling > maxBackoff {
+		ceiling = maxBackoff
+	}
+	// Full jitter keeps simultaneous retries from hitting the API together.
+	delay := time.Duration(rand.Int63n(int64(ceiling))) + time.Millisecond
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return 0, false
+	}
+	return delay, true
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
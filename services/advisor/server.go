@@ -2,63 +2,163 @@ package advisor
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"net/url"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/pixperk/effinarounf/services/alert"
+	"github.com/pixperk/effinarounf/services/audit"
+	"github.com/pixperk/effinarounf/services/digest"
+	"github.com/pixperk/effinarounf/services/events"
+	"github.com/pixperk/effinarounf/services/history"
+	"github.com/pixperk/effinarounf/services/identity"
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/session"
+	"github.com/pixperk/effinarounf/services/sink"
+	"github.com/pixperk/effinarounf/services/units"
 	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
 	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var (
-	advisorRequests = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "advisor_requests_total",
-			Help: "Total advisor requests",
-		},
-		[]string{"status"},
-	)
-	advisorDuration = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name: "advisor_request_duration_seconds",
-			Help: "Advisor request duration",
-		},
-	)
+var advisorRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_requests_total",
+		Help: "Total advisor requests",
+	},
+	[]string{"status"},
 )
 
 type advisorService struct {
 	advisorpb.UnimplementedAdvisorServiceServer
 	weatherSvc  weatherpb.WeatherServiceServer
+	httpClient  *http.Client
 	genaiClient *genai.Client
+	model       string
+	generation  GenerationSettings
+	sessions    session.SessionStore
+	history     history.AdviceStore
+	limiter     *RateLimiter
+	replay      *replayBuffer
+	// sinks receives a copy of every advice stream; nil disables it.
+	sinks *sink.Fanout
+	// digests holds the scheduled digest subscriptions; nil disables them.
+	digests digest.Store
+	// alerts holds the weather alert subscriptions; nil disables them.
+	alerts alert.Store
+	// alertFeed carries fired alerts to SubscribeAlerts streams.
+	alertFeed *alert.Feed
+	cache     *SemanticCache
+	budget    *Budget
+	// audit records LLM use and serves exports; nil disables both.
+	audit *audit.Recorder
+	// streams lists the StreamAdvice calls in progress for the admin API.
+	streams *activeStreams
+	// geocodes caches geocoding answers; nil disables it.
+	geocodes GeocodeCache
+	// weatherCache is nil when the weather service is remote.
+	weatherCache WeatherCache
+	// events receives the advice generated; nil disables it.
+	events *events.Bus
+	// templateOnly is set by an operator to stop all LLM calls.
+	templateOnly atomic.Bool
 }
 
-type GeocodeResponse struct {
-	Results []struct {
-		Name      string  `json:"name"`
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
-		Country   string  `json:"country"`
-		Admin1    string  `json:"admin1"`
-	} `json:"results"`
+// DefaultModel is used when neither GEMINI_MODEL nor the request names one.
+const DefaultModel = "gemini-2.5-pro"
+
+// maxSessionMessages bounds how much conversation history is replayed into
+// the prompt.
+const maxSessionMessages = 10
+
+type geoLocation struct {
+	Latitude    float64
+	Longitude   float64
+	CountryCode string
+	// Geocoded is false when the caller supplied the coordinates.
+	Geocoded bool
 }
 
-func NewAdvisorService(weatherSvc weatherpb.WeatherServiceServer, geminiAPIKey string) (*advisorService, error) {
-	ctx := context.Background()
-	genaiClient, err := genai.NewClient(ctx, option.WithAPIKey(geminiAPIKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+// AdvisorOptions holds the advisor's settings and optional dependencies.
+// Nil stores, sinks and feeds turn the features that use them off.
+type AdvisorOptions struct {
+	// GeminiAPIKey enables LLM advice; without it the service runs
+	// template-only.
+	GeminiAPIKey string
+	// Model is the default model, DefaultModel when empty.
+	Model      string
+	Generation GenerationSettings
+	Sessions   session.SessionStore
+	History    history.AdviceStore
+	Limiter    *RateLimiter
+	// Sinks receives a copy of every advice stream.
+	Sinks *sink.Fanout
+	// Digests holds the scheduled digest subscriptions.
+	Digests digest.Store
+	// Alerts holds the weather alert subscriptions, and AlertFeed carries
+	// the fired ones to SubscribeAlerts streams.
+	Alerts    alert.Store
+	AlertFeed *alert.Feed
+	Cache     *SemanticCache
+	Budget    *Budget
+	// Audit records LLM use and serves exports.
+	Audit *audit.Recorder
+	// Geocodes caches geocoding answers.
+	Geocodes GeocodeCache
+	// WeatherCache is the local weather service's current-conditions
+	// cache, flushed with the others by the admin API.
+	WeatherCache WeatherCache
+	// Events receives the advice generated.
+	Events *events.Bus
+}
+
+func NewAdvisorService(weatherSvc weatherpb.WeatherServiceServer, httpClient *http.Client, opts AdvisorOptions) (*advisorService, error) {
+	model := opts.Model
+	if model == "" {
+		model = DefaultModel
+	}
+	// Without an API key the service runs template-only.
+	var genaiClient *genai.Client
+	if opts.GeminiAPIKey != "" {
+		var err error
+		genaiClient, err = genai.NewClient(context.Background(), option.WithAPIKey(opts.GeminiAPIKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+		}
 	}
 
 	return &advisorService{
-		weatherSvc:  weatherSvc,
-		genaiClient: genaiClient,
+		weatherSvc:   weatherSvc,
+		httpClient:   httpClient,
+		genaiClient:  genaiClient,
+		model:        model,
+		generation:   opts.Generation,
+		sessions:     opts.Sessions,
+		history:      opts.History,
+		limiter:      opts.Limiter,
+		replay:       newReplayBuffer(),
+		sinks:        opts.Sinks,
+		digests:      opts.Digests,
+		alerts:       opts.Alerts,
+		alertFeed:    opts.AlertFeed,
+		cache:        opts.Cache,
+		budget:       opts.Budget,
+		audit:        opts.Audit,
+		streams:      newActiveStreams(),
+		geocodes:     opts.Geocodes,
+		weatherCache: opts.WeatherCache,
+		events:       opts.Events,
 	}, nil
 }
 
@@ -68,93 +168,395 @@ func (s *advisorService) Close() {
 	}
 }
 
-func (s *advisorService) geocodeCity(_ context.Context, city *advisorpb.CityData) (float64, float64, error) {
+// llmEnabled reports whether advice may be generated by the LLM.
+func (s *advisorService) llmEnabled() bool {
+	return s.genaiClient != nil && !s.templateOnly.Load()
+}
 
-	encodedQuery := url.QueryEscape(city.Location)
-	apiURL := fmt.Sprintf("https://geocoding-api.open-meteo.com/v1/search?name=%s&count=1&language=en&format=json", encodedQuery)
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(apiURL)
+// CheckLLM looks up the default model, which fails when Gemini is
+// unreachable or the API key is rejected. It passes without an LLM.
+func (s *advisorService) CheckLLM(ctx context.Context) error {
+	if s.genaiClient == nil {
+		return nil
+	}
+	_, err := s.genaiClient.GenerativeModel(s.model).Info(ctx)
+	return err
+}
+
+// resolveModel returns the model for a request. Overrides are limited to the
+// configured default and models with known pricing, so a client can't pick
+// an arbitrary (and arbitrarily expensive) model.
+func (s *advisorService) resolveModel(requested string) (string, error) {
+	if requested == "" || requested == s.model {
+		return s.model, nil
+	}
+	if _, ok := modelPrices[requested]; ok {
+		return requested, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "model %q is not allowed", requested)
+}
+
+// loadSession returns nil when the request isn't part of a conversation. An
+// unknown or expired ID starts a fresh session under that ID.
+func (s *advisorService) loadSession(ctx context.Context, id string) (*session.Session, error) {
+	if id == "" || s.sessions == nil {
+		return nil, nil
+	}
+	sess, err := s.sessions.Get(ctx, id)
+	if errors.Is(err, session.ErrNotFound) {
+		return &session.Session{ID: id}, nil
+	}
 	if err != nil {
-		return 0, 0, fmt.Errorf("geocoding failed: %v", err)
+		return nil, status.Errorf(codes.Internal, "session lookup failed: %v", err)
+	}
+	return sess, nil
+}
+
+func (s *advisorService) saveExchange(ctx context.Context, sess *session.Session, weatherData []string, advice string) {
+	if sess == nil {
+		return
+	}
+	now := time.Now()
+	sess.Messages = append(sess.Messages,
+		session.Message{Role: "user", Content: strings.Join(weatherData, "\n"), At: now},
+		session.Message{Role: "model", Content: advice, At: now},
+	)
+	sess.Trim(maxSessionMessages)
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		slog.ErrorContext(ctx, "saving session failed", "session", sess.ID, "error", err)
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("geocoding API returned status %d", resp.StatusCode)
+func sessionHistory(sess *session.Session) []session.Message {
+	if sess == nil {
+		return nil
 	}
+	return sess.Messages
+}
 
-	var geocodeResp GeocodeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&geocodeResp); err != nil {
-		return 0, 0, fmt.Errorf("decode failed: %v", err)
+// formatHistory replays the session as data: the earlier turns hold city
+// names and model output, neither of which should steer the new answer.
+func formatHistory(history []session.Message) string {
+	if len(history) == 0 {
+		return ""
 	}
+	rows := make([]string, 0, len(history))
+	for _, msg := range history {
+		rows = append(rows, fmt.Sprintf("%s: %s", msg.Role, msg.Content))
+	}
+	return "Earlier in this conversation:\n" + dataBlock(rows) + "\n\n"
+}
+
+// formatInstructions renders the per-request instructions, each on its own
+// line, ready to precede the closing instructions of a prompt.
+func formatInstructions(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
 
-	if len(geocodeResp.Results) == 0 {
-		return 0, 0, fmt.Errorf("location not found: %s (try: New York, London, Tokyo, Paris, Los Angeles, Chicago, Sydney)", city.Location)
+func (s *advisorService) geocodeCity(ctx context.Context, city *advisorpb.CityData) (geoLocation, error) {
+	if city.Latitude != nil && city.Longitude != nil {
+		return geoLocation{Latitude: *city.Latitude, Longitude: *city.Longitude, CountryCode: countryCode(city.Country)}, nil
 	}
 
-	return geocodeResp.Results[0].Latitude, geocodeResp.Results[0].Longitude, nil
+	results, err := s.searchLocations(ctx, city.Location, city.Country, geocodeCandidates)
+	if err != nil {
+		return geoLocation{}, err
+	}
+	ranked := rankCandidates(results, city.State, city.Country)
+	if len(ranked) == 0 {
+		if len(results) > 0 {
+			return geoLocation{}, fmt.Errorf("location not found: %s in %s", city.Location, placeName(city.State, city.Country))
+		}
+		return geoLocation{}, fmt.Errorf("location not found: %s (try: %s)", city.Location, catalogHint())
+	}
+
+	result := ranked[0]
+	return geoLocation{Latitude: result.Latitude, Longitude: result.Longitude, CountryCode: result.CountryCode, Geocoded: true}, nil
+}
+
+// metricWeatherRequest asks for metric values so the rules engine always sees
+// the same units; conversion for display happens in formatWeather.
+func metricWeatherRequest(loc geoLocation) *weatherpb.WeatherRequest {
+	return &weatherpb.WeatherRequest{
+		Latitude:    loc.Latitude,
+		Longitude:   loc.Longitude,
+		CountryCode: loc.CountryCode,
+		UnitSystem:  weatherpb.UnitSystem_UNIT_SYSTEM_METRIC,
+	}
+}
+
+// formatWeather renders one compact prompt row. The column names are given
+// once in the prompt, and whole numbers are plenty for advice, which keeps
+// the prompt to a fraction of the tokens of labelled English.
+func formatWeather(city string, w *weatherpb.WeatherResponse, conv units.Conventions) string {
+	return fmt.Sprintf("%s|%.0f%s|%s|%d%%|%.0f%s",
+		city, conv.Temp(w.Temperature), conv.Temperature, w.Description, w.Humidity, conv.Wind(w.WindSpeed), conv.WindSpeed)
 }
 
 func (s *advisorService) GetAdvice(ctx context.Context, req *advisorpb.AdvisorRequest) (*advisorpb.AdvisorResponse, error) {
-	timer := prometheus.NewTimer(advisorDuration)
-	defer timer.ObserveDuration()
+	cities, err := normalizeCities(req.Cities)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	req.Cities = cities
+	if err := normalizeProfile(req.Profile); err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	if err := normalizeLanguage(&req.Language); err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	modelName, err := s.resolveModel(req.Model)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	sess, err := s.loadSession(ctx, req.SessionId)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
 
 	var weatherData []string
-	for _, city := range req.Cities {
-		lat, lon, err := s.geocodeCity(ctx, city)
+	var warnings []rules.Warning
+	var cityErrors []*advisorpb.CityError
+	var exposure []*advisorpb.CityExposure
+	var summaries []*advisorpb.CitySummary
+	var cityWeather []rules.CityWeather
+	sources := newSourceSet()
+	for _, oc := range orderCities(req) {
+		i, city := oc.index, oc.city
+		loc, err := s.geocodeCity(ctx, city)
 		if err != nil {
+			if err := canceled(ctx); err != nil {
+				advisorRequests.WithLabelValues("error").Inc()
+				return nil, err
+			}
+			if req.BestEffort {
+				cityErrors = append(cityErrors, cityError(i, city, "geocoding", err))
+				continue
+			}
 			advisorRequests.WithLabelValues("error").Inc()
 			return nil, fmt.Errorf("geocoding failed for %s: %v", city.Location, err)
 		}
+		if loc.Geocoded {
+			sources.add(geocodingSource())
+		}
 
-		weatherReq := &weatherpb.WeatherRequest{Latitude: lat, Longitude: lon}
-		weatherResp, err := s.weatherSvc.GetCurrentWeather(ctx, weatherReq)
+		weatherResp, err := s.weatherSvc.GetCurrentWeather(ctx, metricWeatherRequest(loc))
 		if err != nil {
+			if err := canceled(ctx); err != nil {
+				advisorRequests.WithLabelValues("error").Inc()
+				return nil, err
+			}
+			if req.BestEffort {
+				cityErrors = append(cityErrors, cityError(i, city, "weather", err))
+				continue
+			}
 			advisorRequests.WithLabelValues("error").Inc()
 			return nil, fmt.Errorf("weather request failed for %s: %v", city.Location, err)
 		}
+		sources.addProvider(weatherResp.Provider)
+
+		conv := units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+		weatherData = append(weatherData, formatWeather(city.Location, weatherResp, conv))
+		weatherData = append(weatherData, rules.ComfortLine(city.Location, weatherResp))
+		summaries = append(summaries, citySummary(city, weatherResp, conv))
+		cityWeather = append(cityWeather, rules.CityWeather{Name: city.Location, Weather: weatherResp, Conv: conv})
+		warnings = append(warnings, rules.SafetyWarnings(city.Location, weatherResp, conv)...)
+		if exp, line := s.cityExposure(ctx, city.Location, loc, sources); exp != nil {
+			exposure = append(exposure, exp)
+			weatherData = append(weatherData, line)
+		}
+	}
 
-		weatherInfo := fmt.Sprintf("City: %s, Temp: %.1f°C, Condition: %s, Humidity: %d%%, Wind: %.1f m/s",
-			city.Location, weatherResp.Temperature, weatherResp.Description, weatherResp.Humidity, weatherResp.WindSpeed)
-		weatherData = append(weatherData, weatherInfo)
+	if len(weatherData) == 0 {
+		advisorRequests.WithLabelValues("error").Inc()
+		return nil, fmt.Errorf("no weather data for any requested city (%d failed)", len(cityErrors))
 	}
 
-	advice, err := s.generateAdvice(ctx, weatherData)
+	// The order, profile, category and language instructions go to the
+	// model but not into the session.
+	instructions := adviceInstructions(req, summaries)
+	advice, probe := s.cachedAdvice(ctx, modelName, req, summaries, slices.Concat(weatherData, instructions), sessionHistory(sess))
+	cached := advice != ""
+	var usage *advisorpb.TokenUsage
+	if !cached {
+		advice, usage, err = s.generateAdvice(ctx, modelName, req.Generation, weatherData, instructions, sessionHistory(sess))
+		if err == nil {
+			s.cacheAdvice(probe, advice)
+		}
+	}
+	fallback := false
 	if err != nil {
-		advisorRequests.WithLabelValues("error").Inc()
-		return nil, fmt.Errorf("advice generation failed: %v", err)
+		if !shouldFallback(ctx, err) {
+			advisorRequests.WithLabelValues("error").Inc()
+			return nil, generationError(err)
+		}
+		slog.WarnContext(ctx, "advice generation failed, using template advice", "error", err)
+		adviceFallbacks.WithLabelValues(fallbackReason(err)).Inc()
+		advice, fallback = rules.TemplateAdvice(cityWeather), true
 	}
+	s.saveExchange(ctx, sess, weatherData, advice)
+	fullAdvice := rules.SafetyBlock(warnings) + advice
+	adviceID := s.saveAdvice(ctx, req.SessionId, modelName, summaries, weatherData, fullAdvice)
+	s.publishAdvice(adviceID, req.SessionId, modelName, summaries, fullAdvice, false)
 
 	advisorRequests.WithLabelValues("success").Inc()
-	return &advisorpb.AdvisorResponse{Advice: advice}, nil
+	return &advisorpb.AdvisorResponse{
+		Advice:    formatAdvice(req.Format, fullAdvice),
+		SessionId: req.SessionId,
+		Errors:    cityErrors,
+		Usage:     usage,
+		Sources:   sources.list,
+		Exposure:  exposure,
+		Cities:    summaries,
+		AdviceId:  adviceID,
+		Fallback:  fallback,
+		Warnings:  warningMessages(warnings),
+		Cached:    cached,
+	}, nil
+}
+
+// canceled returns the Canceled or DeadlineExceeded status once the caller
+// has gone, so a failed lookup isn't reported as a city failure and the
+// remaining cities aren't tried.
+func canceled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
 }
 
-func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advisorpb.AdvisorService_StreamAdviceServer) error {
-	timer := prometheus.NewTimer(advisorDuration)
-	defer timer.ObserveDuration()
+func citySummary(city *advisorpb.CityData, w *weatherpb.WeatherResponse, conv units.Conventions) *advisorpb.CitySummary {
+	return &advisorpb.CitySummary{
+		Location:        city.Location,
+		Primary:         city.Primary,
+		Temperature:     conv.Temp(w.Temperature),
+		TemperatureUnit: conv.Temperature,
+		Description:     w.Description,
+	}
+}
+
+func cityError(index int, city *advisorpb.CityData, stage string, err error) *advisorpb.CityError {
+	return &advisorpb.CityError{
+		Index:    int32(index),
+		Location: city.Location,
+		Stage:    stage,
+		Message:  err.Error(),
+	}
+}
+
+func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advisorpb.AdvisorService_StreamAdviceServer) (err error) {
+	cities, err := normalizeCities(req.Cities)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+	req.Cities = cities
+	if err := normalizeProfile(req.Profile); err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+	if err := normalizeLanguage(&req.Language); err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+
+	modelName, err := s.resolveModel(req.Model)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+
+	sess, err := s.loadSession(stream.Context(), req.SessionId)
+	if err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+
+	sender := s.newChunkSender(stream, req.SessionId)
+	sender.format = newAdviceFormatter(req.Format)
+	names := make([]string, 0, len(req.Cities))
+	for _, c := range req.Cities {
+		names = append(names, c.Location)
+	}
+	defer s.streams.add(StreamInfo{ID: sender.id, Client: identity.Subject(stream.Context()), Cities: names, Started: time.Now()})()
+	defer func() {
+		if err != nil {
+			sender.abort(err)
+		}
+	}()
+	stopKeepalive := sender.keepalive(keepaliveInterval)
+	defer stopKeepalive()
 
 	var weatherData []string
 	var failedCities []string
-
-	for _, city := range req.Cities {
-		lat, lon, err := s.geocodeCity(stream.Context(), city)
+	var warnings []rules.Warning
+
+	sources := newSourceSet()
+	var summaries []*advisorpb.CitySummary
+	var cityWeather []rules.CityWeather
+	total := int32(len(req.Cities))
+	for i, oc := range orderCities(req) {
+		city := oc.city
+		done := int32(i)
+		loc, err := s.geocodeCity(stream.Context(), city)
 		if err != nil {
+			if err := canceled(stream.Context()); err != nil {
+				advisorRequests.WithLabelValues("error").Inc()
+				return err
+			}
 			failedCities = append(failedCities, fmt.Sprintf("%s (geocoding failed)", city.Location))
+			if err := sender.progress(advisorpb.ProgressEvent_CITY_FAILED, city.Location, done+1, total, "geocoding failed"); err != nil {
+				return err
+			}
 			continue
 		}
+		if loc.Geocoded {
+			sources.add(geocodingSource())
+		}
+		if err := sender.progress(advisorpb.ProgressEvent_GEOCODED, city.Location, done, total, ""); err != nil {
+			return err
+		}
 
-		weatherReq := &weatherpb.WeatherRequest{Latitude: lat, Longitude: lon}
-		weatherResp, err := s.weatherSvc.GetCurrentWeather(stream.Context(), weatherReq)
+		weatherResp, err := s.weatherSvc.GetCurrentWeather(stream.Context(), metricWeatherRequest(loc))
 		if err != nil {
+			if err := canceled(stream.Context()); err != nil {
+				advisorRequests.WithLabelValues("error").Inc()
+				return err
+			}
 			failedCities = append(failedCities, fmt.Sprintf("%s (weather failed)", city.Location))
+			if err := sender.progress(advisorpb.ProgressEvent_CITY_FAILED, city.Location, done+1, total, "weather failed"); err != nil {
+				return err
+			}
 			continue
 		}
-
-		weatherInfo := fmt.Sprintf("City: %s, Temp: %.1f°C, Condition: %s, Humidity: %d%%, Wind: %.1f m/s",
-			city.Location, weatherResp.Temperature, weatherResp.Description, weatherResp.Humidity, weatherResp.WindSpeed)
-		weatherData = append(weatherData, weatherInfo)
+		sources.addProvider(weatherResp.Provider)
+
+		conv := units.Resolve(loc.CountryCode, units.ParseSystem(req.UnitSystem))
+		weatherData = append(weatherData, formatWeather(city.Location, weatherResp, conv))
+		weatherData = append(weatherData, rules.ComfortLine(city.Location, weatherResp))
+		summaries = append(summaries, citySummary(city, weatherResp, conv))
+		cityWeather = append(cityWeather, rules.CityWeather{Name: city.Location, Weather: weatherResp, Conv: conv})
+		warnings = append(warnings, rules.SafetyWarnings(city.Location, weatherResp, conv)...)
+		if _, line := s.cityExposure(stream.Context(), city.Location, loc, sources); line != "" {
+			weatherData = append(weatherData, line)
+		}
+		if err := sender.progress(advisorpb.ProgressEvent_WEATHER_FETCHED, city.Location, done+1, total, ""); err != nil {
+			return err
+		}
 	}
 
+	sender.sources = sources.list
+
 	if len(weatherData) == 0 {
 		message := "I couldn't get weather data for any cities. "
 		if len(failedCities) > 0 {
@@ -162,9 +564,10 @@ func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advi
 		}
 		message += "Please try again with different cities or check your connection."
 
-		err := stream.Send(&advisorpb.StreamAdviceResponse{
-			Chunk:      message,
-			IsComplete: true,
+		err := sender.send(&advisorpb.StreamAdviceResponse{
+			Chunk:        message,
+			IsComplete:   true,
+			FinishReason: "NO_DATA",
 		})
 		if err == nil {
 			advisorRequests.WithLabelValues("success").Inc()
@@ -174,83 +577,320 @@ func (s *advisorService) StreamAdvice(req *advisorpb.AdvisorRequest, stream advi
 		return err
 	}
 
+	// Safety warnings go out before any model output so they can't be
+	// dropped or reworded by the LLM.
+	if block := rules.SafetyBlock(warnings); block != "" {
+		if err := sender.send(&advisorpb.StreamAdviceResponse{Chunk: block, Warnings: warningMessages(warnings)}); err != nil {
+			advisorRequests.WithLabelValues("error").Inc()
+			return err
+		}
+	}
+
+	if err := sender.progress(advisorpb.ProgressEvent_GENERATION_STARTED, "", total, total, ""); err != nil {
+		advisorRequests.WithLabelValues("error").Inc()
+		return err
+	}
+
 	// Stream the advice generation
-	err := s.streamAdviceGeneration(stream.Context(), weatherData, stream)
+	// The order, profile, category and language instructions go to the
+	// model but not into the session.
+	instructions := adviceInstructions(req, summaries)
+	sentBefore := sender.written
+	advice, probe := s.cachedAdvice(stream.Context(), modelName, req, summaries, slices.Concat(weatherData, instructions), sessionHistory(sess))
+	if advice != "" {
+		err = sendCachedAdvice(sender, advice)
+	} else if advice, err = s.streamAdviceGeneration(stream.Context(), modelName, req.Generation, weatherData, instructions, sessionHistory(sess), sender); err == nil {
+		s.cacheAdvice(probe, advice)
+	}
 	if err != nil {
-		advisorRequests.WithLabelValues("error").Inc()
-		return fmt.Errorf("advice generation failed: %v", err)
+		// Template advice can only replace the answer if none of the
+		// model's text has gone out yet.
+		if sender.written != sentBefore || !shouldFallback(stream.Context(), err) {
+			advisorRequests.WithLabelValues("error").Inc()
+			return err
+		}
+		slog.WarnContext(stream.Context(), "advice generation failed, using template advice", "error", err)
+		adviceFallbacks.WithLabelValues(fallbackReason(err)).Inc()
+		advice = rules.TemplateAdvice(cityWeather)
+		if err := sender.send(&advisorpb.StreamAdviceResponse{Chunk: advice}); err != nil {
+			advisorRequests.WithLabelValues("error").Inc()
+			return err
+		}
+		if err := sender.send(&advisorpb.StreamAdviceResponse{
+			IsComplete:   true,
+			FinishReason: "FALLBACK",
+			Fallback:     true,
+		}); err != nil {
+			advisorRequests.WithLabelValues("error").Inc()
+			return err
+		}
 	}
+	s.saveExchange(stream.Context(), sess, weatherData, advice)
+	fullAdvice := rules.SafetyBlock(warnings) + advice
+	adviceID := s.saveAdvice(stream.Context(), req.SessionId, modelName, summaries, weatherData, fullAdvice)
+	s.publishAdvice(adviceID, req.SessionId, modelName, summaries, fullAdvice, true)
 
 	advisorRequests.WithLabelValues("success").Inc()
 	return nil
 }
 
-func (s *advisorService) generateAdvice(ctx context.Context, weatherData []string) (string, error) {
-	model := s.genaiClient.GenerativeModel("gemini-2.5-pro")
-	prompt := fmt.Sprintf(`Weather advisor. Based on this data provide practical advice:
+// sendCachedAdvice sends cached advice as one chunk and completes the stream.
+func sendCachedAdvice(sender *chunkSender, advice string) error {
+	if err := sender.send(&advisorpb.StreamAdviceResponse{Chunk: advice}); err != nil {
+		return err
+	}
+	return sender.send(&advisorpb.StreamAdviceResponse{
+		IsComplete:   true,
+		FinishReason: "CACHED",
+		Cached:       true,
+	})
+}
 
-%s
+func (s *advisorService) GetServerInfo(ctx context.Context, req *advisorpb.ServerInfoRequest) (*advisorpb.ServerInfoResponse, error) {
+	return &advisorpb.ServerInfoResponse{
+		StreamingEnabled:  s.llmEnabled(),
+		TemplateOnly:      !s.llmEnabled(),
+		ForecastSupported: true,
+	}, nil
+}
+
+// generationError keeps gRPC status errors (such as rate limiting) intact so
+// clients see the real code, and wraps everything else.
+func generationError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return fmt.Errorf("advice generation failed: %v", err)
+}
+
+// adviceInstructions returns the per-request instructions that follow the
+// weather data in the advice prompt.
+func adviceInstructions(req *advisorpb.AdvisorRequest, summaries []*advisorpb.CitySummary) []string {
+	var lines []string
+	if line := orderInstruction(summaries); line != "" {
+		lines = append(lines, line)
+	}
+	if line := profileInstruction(req.Profile); line != "" {
+		lines = append(lines, line)
+	}
+	if line := categoryInstruction(req.Category); line != "" {
+		lines = append(lines, line)
+	}
+	if line := languageInstruction(req.Language); line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
 
-Include: summary, clothing advice, activity suggestions, places to visit if good weather, warnings. Keep it concise.`, strings.Join(weatherData, "\n"))
+func (s *advisorService) generateAdvice(ctx context.Context, modelName string, gen *advisorpb.GenerationConfig, weatherData, instructions []string, history []session.Message) (string, *advisorpb.TokenUsage, error) {
+	return s.generateText(ctx, modelName, gen, advicePrompt(weatherData, instructions, history))
+}
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+// advicePrompt is the GetAdvice prompt for the weather and facts rows.
+func advicePrompt(weatherData, instructions []string, history []session.Message) string {
+	return fmt.Sprintf(`Weather advisor. Based on the data below provide practical advice (weather rows are city|temp|condition|humidity|wind). Facts rows are computed from the data; base clothing, umbrella and sunscreen advice on them. %s
+
+%s%s
+
+%sInclude: summary, clothing advice, activity suggestions, places to visit if good weather, best hours for exercise and airing the home when air quality is given, warnings. Keep it concise.`, dataNotice, formatHistory(history), dataBlock(weatherData), formatInstructions(instructions))
+}
+
+// generateText runs a unary generation under the rate limiter, retrying
+// transient failures, and records its token usage.
+func (s *advisorService) generateText(ctx context.Context, modelName string, gen *advisorpb.GenerationConfig, prompt string) (_ string, _ *advisorpb.TokenUsage, err error) {
+	model, err := s.newModel(modelName, gen)
 	if err != nil {
-		return "", fmt.Errorf("gemini API failed: %v", err)
+		return "", nil, err
 	}
 
-	if len(resp.Candidates) == 0 {
-		return "", fmt.Errorf("no response generated")
+	if err := s.budget.check(time.Now()); err != nil {
+		return "", nil, err
+	}
+	estimate := estimateTokens(prompt)
+	if err := s.limiter.acquire(estimate); err != nil {
+		return "", nil, err
 	}
 
-	var advice strings.Builder
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if text, ok := part.(genai.Text); ok {
-			advice.WriteString(string(text))
+	var advice string
+	var meta *genai.UsageMetadata
+	ctx, call := startLLMCall(ctx, "generate", modelName)
+	defer func() { call.end(meta, err) }()
+	for attempt := 0; ; attempt++ {
+		var err error
+		advice, meta, err = readAdvice(ctx, model, prompt)
+		if err == nil {
+			break
+		}
+		delay, ok := retryDelay(ctx, attempt, err)
+		if !ok {
+			llmFailures.WithLabelValues("unary").Inc()
+			return "", nil, fmt.Errorf("gemini API failed: %v", err)
+		}
+		slog.WarnContext(ctx, "gemini call failed, retrying", "attempt", attempt+1, "delay", delay, "error", err)
+		call.retry(attempt+1, err)
+		llmRetries.WithLabelValues("unary").Inc()
+		if err := sleepCtx(ctx, delay); err != nil {
+			return "", nil, err
 		}
 	}
 
-	return advice.String(), nil
+	usage := recordUsage(modelName, meta)
+	s.limiter.settle(estimate, usage)
+	s.budget.charge(usage, time.Now())
+	if advice == "" {
+		return "", usage, fmt.Errorf("no response generated")
+	}
+	return advice, usage, nil
 }
 
-func (s *advisorService) streamAdviceGeneration(ctx context.Context, weatherData []string, stream advisorpb.AdvisorService_StreamAdviceServer) error {
-	model := s.genaiClient.GenerativeModel("gemini-2.5-pro")
-	prompt := fmt.Sprintf(`Weather advisor. Based on this data provide practical advice:
+// readAdvice runs one generation and collects its text. GenerateContent
+// merges the streamed chunks but keeps the usage metadata of the first one,
+// so read the stream here to get the final token counts.
+func readAdvice(ctx context.Context, model *genai.GenerativeModel, prompt string) (string, *genai.UsageMetadata, error) {
+	iter := model.GenerateContentStream(ctx, genai.Text(prompt))
 
-%s
+	var advice strings.Builder
+	var meta *genai.UsageMetadata
+	for {
+		resp, err := iter.Next()
+		if err == iterator.Done {
+			return advice.String(), meta, nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if resp.UsageMetadata != nil {
+			meta = resp.UsageMetadata
+		}
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if text, ok := part.(genai.Text); ok {
+				advice.WriteString(string(text))
+			}
+		}
+	}
+}
 
-Include: summary, clothing advice, activity suggestions, warnings. Keep it concise.`, strings.Join(weatherData, "\n"))
+func (s *advisorService) streamAdviceGeneration(ctx context.Context, modelName string, gen *advisorpb.GenerationConfig, weatherData, instructions []string, history []session.Message, sender *chunkSender) (_ string, err error) {
+	model, err := s.newModel(modelName, gen)
+	if err != nil {
+		return "", err
+	}
+	prompt := fmt.Sprintf(`Weather advisor. Based on the data below provide practical advice (weather rows are city|temp|condition|humidity|wind). Facts rows are computed from the data; base clothing, umbrella and sunscreen advice on them. %s
+
+%s%s
+
+%sInclude: summary, clothing advice, activity suggestions, best hours for exercise and airing the home when air quality is given, warnings. Keep it concise.`, dataNotice, formatHistory(history), dataBlock(weatherData), formatInstructions(instructions))
+
+	if err := s.budget.check(time.Now()); err != nil {
+		return "", err
+	}
+	estimate := estimateTokens(prompt)
+	if err := s.limiter.acquire(estimate); err != nil {
+		return "", err
+	}
+
+	var meta *genai.UsageMetadata
+	ctx, call := startLLMCall(ctx, "stream", modelName)
+	defer func() { call.end(meta, err) }()
 
 	// Use streaming generation
 	iter := model.GenerateContentStream(ctx, genai.Text(prompt))
 
+	var advice strings.Builder
+	var finish genai.FinishReason
+	attempt := 0
 	for {
 		resp, err := iter.Next()
+		if err == iterator.Done {
+			usage := recordUsage(modelName, meta)
+			s.limiter.settle(estimate, usage)
+			s.budget.charge(usage, time.Now())
+			if err := sender.send(&advisorpb.StreamAdviceResponse{
+				IsComplete:   true,
+				Usage:        usage,
+				FinishReason: finishReasonName(finish),
+			}); err != nil {
+				return "", err
+			}
+			return advice.String(), nil
+		}
 		if err != nil {
-			// Check if it's end of stream
-			if strings.Contains(err.Error(), "EOF") || strings.Contains(err.Error(), "iterator stopped") {
-				// Send completion signal
-				return stream.Send(&advisorpb.StreamAdviceResponse{
-					Chunk:      "",
-					IsComplete: true,
-				})
+			// Once text has reached the client a retry would repeat it, so
+			// only errors before the first chunk are retried.
+			if advice.Len() == 0 {
+				if delay, ok := retryDelay(ctx, attempt, err); ok {
+					slog.WarnContext(ctx, "gemini stream failed, retrying", "attempt", attempt+1, "delay", delay, "error", err)
+					call.retry(attempt+1, err)
+					llmRetries.WithLabelValues("stream").Inc()
+					if err := sleepCtx(ctx, delay); err != nil {
+						return "", status.FromContextError(err).Err()
+					}
+					attempt++
+					iter = model.GenerateContentStream(ctx, genai.Text(prompt))
+					continue
+				}
 			}
-			return fmt.Errorf("streaming failed: %v", err)
+			llmFailures.WithLabelValues("stream").Inc()
+			return "", generationStatus(ctx, err)
+		}
+
+		if resp.UsageMetadata != nil {
+			meta = resp.UsageMetadata
 		}
 
 		// Extract text from response
 		for _, cand := range resp.Candidates {
+			if cand.FinishReason != genai.FinishReasonUnspecified {
+				finish = cand.FinishReason
+			}
+			if cand.Content == nil {
+				continue
+			}
 			for _, part := range cand.Content.Parts {
 				if text, ok := part.(genai.Text); ok {
+					call.firstChunk()
 					// Send the text chunk
-					err := stream.Send(&advisorpb.StreamAdviceResponse{
-						Chunk:      string(text),
-						IsComplete: false,
-					})
-					if err != nil {
-						return fmt.Errorf("failed to send chunk: %v", err)
+					advice.WriteString(string(text))
+					if err := sender.send(&advisorpb.StreamAdviceResponse{Chunk: string(text)}); err != nil {
+						return "", err
 					}
 				}
 			}
 		}
 	}
 }
+
+// generationStatus turns a failed generation into the status code documented
+// for StreamAdvice: Canceled or DeadlineExceeded when the caller gave up,
+// FailedPrecondition when the safety filter blocked the answer, Unavailable
+// for transient model errors and Internal otherwise.
+func generationStatus(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return status.Errorf(codes.FailedPrecondition, "advice blocked by safety filter: %v", err)
+	}
+	if isTransient(err) {
+		return status.Errorf(codes.Unavailable, "advice generation failed: %v", err)
+	}
+	return status.Errorf(codes.Internal, "advice generation failed: %v", err)
+}
+
+func finishReasonName(reason genai.FinishReason) string {
+	switch reason {
+	case genai.FinishReasonUnspecified, genai.FinishReasonStop:
+		return "STOP"
+	case genai.FinishReasonMaxTokens:
+		return "MAX_TOKENS"
+	case genai.FinishReasonSafety:
+		return "SAFETY"
+	case genai.FinishReasonRecitation:
+		return "RECITATION"
+	}
+	return "OTHER"
+}
@@ -0,0 +1,89 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pixperk/effinarounf/services/rules"
+	"github.com/pixperk/effinarounf/services/units"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// legacyFormatWeather is the labelled sentence formatWeather replaced.
+func legacyFormatWeather(city string, w *weatherpb.WeatherResponse, conv units.Conventions) string {
+	return fmt.Sprintf("City: %s, Temp: %.1f%s, Condition: %s, Humidity: %d%%, Wind: %.1f %s",
+		city, conv.Temp(w.Temperature), conv.Temperature, w.Description, w.Humidity, conv.Wind(w.WindSpeed), conv.WindSpeed)
+}
+
+func TestFormatWeatherCompact(t *testing.T) {
+	w := &weatherpb.WeatherResponse{Temperature: 18.4, Description: "light rain", Humidity: 82, WindSpeed: 14.6}
+	for _, system := range []weatherpb.UnitSystem{weatherpb.UnitSystem_UNIT_SYSTEM_METRIC, weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL} {
+		conv := units.Resolve("", system)
+		got := formatWeather("Berlin", w, conv)
+		old := legacyFormatWeather("Berlin", w, conv)
+		if pct := len(got) * 100 / len(old); pct > 45 {
+			t.Errorf("%v: compact row is %d%% of the legacy sentence (%d of %d bytes), want at most 45%%", system, pct, len(got), len(old))
+		}
+
+		// The prompt names the columns city|temp|condition|humidity|wind.
+		want := []string{
+			"Berlin",
+			fmt.Sprintf("%.0f%s", conv.Temp(w.Temperature), conv.Temperature),
+			"light rain",
+			"82%",
+			fmt.Sprintf("%.0f%s", conv.Wind(w.WindSpeed), conv.WindSpeed),
+		}
+		if cols := strings.Split(got, "|"); len(cols) != len(want) {
+			t.Errorf("%v: %q has %d columns, want %d", system, got, len(cols), len(want))
+		} else {
+			for i, col := range cols {
+				if col != want[i] {
+					t.Errorf("%v: column %d = %q, want %q", system, i, col, want[i])
+				}
+			}
+		}
+	}
+}
+
+// TestAdvicePromptCompact measures the whole GetAdvice prompt, facts rows
+// and instructions included, against the same prompt with legacy rows.
+// The fixed text weighs less the more cities are asked about.
+func TestAdvicePromptCompact(t *testing.T) {
+	cities := []struct {
+		name string
+		w    *weatherpb.WeatherResponse
+	}{
+		{"London", &weatherpb.WeatherResponse{Temperature: 11.2, Description: "overcast clouds", Humidity: 87, WindSpeed: 21.4, UvIndex: 1}},
+		{"Madrid", &weatherpb.WeatherResponse{Temperature: 31.6, Description: "clear sky", Humidity: 24, WindSpeed: 9.8, UvIndex: 9}},
+		{"Oslo", &weatherpb.WeatherResponse{Temperature: -3.4, Description: "light snow", Humidity: 91, WindSpeed: 12.1, UvIndex: 0}},
+		{"Mumbai", &weatherpb.WeatherResponse{Temperature: 29.3, Description: "moderate rain", Humidity: 94, WindSpeed: 18.7, UvIndex: 4}},
+		{"Denver", &weatherpb.WeatherResponse{Temperature: 17.9, Description: "few clouds", Humidity: 33, WindSpeed: 25.2, UvIndex: 7}},
+	}
+	instructions := []string{profileInstruction(&advisorpb.UserProfile{CyclesToWork: true})}
+	for _, tc := range []struct {
+		cities int
+		maxPct int
+	}{
+		{cities: 3, maxPct: 90},
+		{cities: 5, maxPct: 85},
+		{cities: 10, maxPct: 80},
+	} {
+		conv := units.Resolve("", weatherpb.UnitSystem_UNIT_SYSTEM_METRIC)
+		var compact, legacy []string
+		for i := 0; i < tc.cities; i++ {
+			c := cities[i%len(cities)]
+			facts := rules.ComfortLine(c.name, c.w)
+			compact = append(compact, formatWeather(c.name, c.w, conv), facts)
+			legacy = append(legacy, legacyFormatWeather(c.name, c.w, conv), facts)
+		}
+		got := advicePrompt(compact, instructions, nil)
+		old := advicePrompt(legacy, instructions, nil)
+		pct := len(got) * 100 / len(old)
+		if pct > tc.maxPct {
+			t.Errorf("%d cities: prompt is %d%% of the legacy prompt (%d of %d bytes), want at most %d%%", tc.cities, pct, len(got), len(old), tc.maxPct)
+		}
+		t.Logf("%d cities: %d -> %d bytes (%d%%)", tc.cities, len(old), len(got), pct)
+	}
+}
@@ -0,0 +1,50 @@
+package advisor
+
+import (
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// geocodingSource credits the Open-Meteo geocoding API, which is built on
+// GeoNames data.
+func geocodingSource() *advisorpb.DataSource {
+	return &advisorpb.DataSource{
+		Name:        "Open-Meteo Geocoding",
+		Url:         "https://open-meteo.com/en/docs/geocoding-api",
+		License:     "CC BY 4.0",
+		Attribution: "Location data by Open-Meteo.com, based on GeoNames",
+	}
+}
+
+// sourceSet collects each provider once, in the order first used.
+type sourceSet struct {
+	list []*advisorpb.DataSource
+	seen map[string]bool
+}
+
+func newSourceSet() *sourceSet {
+	return &sourceSet{seen: make(map[string]bool)}
+}
+
+func (s *sourceSet) add(src *advisorpb.DataSource) {
+	key := src.Name + "|" + src.Model
+	if s.seen[key] {
+		return
+	}
+	s.seen[key] = true
+	s.list = append(s.list, src)
+}
+
+func (s *sourceSet) addProvider(p *weatherpb.Provider) {
+	if p == nil {
+		return
+	}
+	s.add(&advisorpb.DataSource{
+		Name:         p.Name,
+		Url:          p.Url,
+		License:      p.License,
+		Attribution:  p.Attribution,
+		Model:        p.Model,
+		ModelRunTime: p.ModelRunTime,
+	})
+}
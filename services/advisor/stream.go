@@ -0,0 +1,303 @@
+package advisor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/markdown"
+	"github.com/pixperk/effinarounf/services/sink"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// replayTTL is how long a finished stream can still be re-sent.
+const replayTTL = 10 * time.Minute
+
+// keepaliveInterval is how long a stream may go without a message before a
+// KEEPALIVE progress event is sent. Looking up many cities, or waiting for
+// the model's first token, can otherwise leave it silent long enough for a
+// proxy to drop it.
+const keepaliveInterval = 10 * time.Second
+
+var streamChunks = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "advisor_stream_chunks",
+		Help:    "Messages sent per completed advice stream, by where the advice came from",
+		Buckets: prometheus.ExponentialBuckets(4, 2, 8),
+	},
+	[]string{"source"},
+)
+
+// chunkSender stamps every outgoing message with the stream ID and a
+// sequence number, and closes the stream with a chunk count and SHA-256 of
+// the text so clients can detect dropped or reordered chunks. Messages are
+// kept in the replay buffer for ResendChunks and teed to the output sinks.
+type chunkSender struct {
+	stream    advisorpb.AdvisorService_StreamAdviceServer
+	id        string
+	sessionID string
+	seq       uint64
+	digest    hash.Hash
+	length    uint64
+	record    *streamRecord
+	// sources is attached to the final message.
+	sources []*advisorpb.DataSource
+	sinks   *sink.Fanout
+	// format converts chunk text to the requested format; nil keeps the
+	// model's Markdown.
+	format *markdown.Converter
+	// written counts chunk bytes handed to send, including text the
+	// formatter is still holding back.
+	written uint64
+
+	// mu serializes sends between the handler and the keepalive goroutine.
+	mu        sync.Mutex
+	lastSent  time.Time
+	completed bool
+}
+
+func (s *advisorService) newChunkSender(stream advisorpb.AdvisorService_StreamAdviceServer, sessionID string) *chunkSender {
+	id := uuid.NewString()
+	return &chunkSender{
+		stream:    stream,
+		id:        id,
+		sessionID: sessionID,
+		digest:    sha256.New(),
+		record:    s.replay.start(id),
+		sinks:     s.sinks,
+	}
+}
+
+func (c *chunkSender) send(msg *advisorpb.StreamAdviceResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sendLocked(msg)
+}
+
+func (c *chunkSender) sendLocked(msg *advisorpb.StreamAdviceResponse) error {
+	if msg.Chunk != "" {
+		c.written += uint64(len(msg.Chunk))
+	}
+	if c.format != nil && msg.Progress == nil {
+		msg.Chunk = c.format.Write(msg.Chunk)
+		if msg.IsComplete {
+			msg.Chunk += c.format.Flush()
+		}
+		// Partial lines are held back until they are complete.
+		if msg.Chunk == "" && !msg.IsComplete && len(msg.Warnings) == 0 {
+			return nil
+		}
+	}
+	c.seq++
+	msg.StreamId = c.id
+	msg.Sequence = c.seq
+	c.digest.Write([]byte(msg.Chunk))
+	c.length += uint64(len(msg.Chunk))
+	if msg.IsComplete {
+		c.completed = true
+		msg.ChunkCount = c.seq
+		msg.AdviceLength = c.length
+		msg.Sha256 = hex.EncodeToString(c.digest.Sum(nil))
+		msg.Sources = c.sources
+		streamChunks.WithLabelValues(adviceSource(msg)).Observe(float64(c.seq))
+	}
+	c.record.append(msg)
+	if msg.Progress == nil {
+		c.sinks.Publish(sink.Event{
+			StreamID:     c.id,
+			SessionID:    c.sessionID,
+			Sequence:     msg.Sequence,
+			Chunk:        msg.Chunk,
+			Complete:     msg.IsComplete,
+			FinishReason: msg.FinishReason,
+			Fallback:     msg.Fallback,
+			Time:         time.Now(),
+		})
+	}
+	c.lastSent = time.Now()
+	return c.stream.Send(msg)
+}
+
+// adviceSource labels a completed stream as "model", "cached" or
+// "fallback".
+func adviceSource(msg *advisorpb.StreamAdviceResponse) string {
+	switch {
+	case msg.Cached:
+		return "cached"
+	case msg.Fallback:
+		return "fallback"
+	default:
+		return "model"
+	}
+}
+
+// keepalive sends a KEEPALIVE progress event whenever the stream has been
+// quiet for interval, until the stream completes or stop is called. stop
+// waits for the goroutine, so nothing is sent once it returns.
+func (c *chunkSender) keepalive(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-c.stream.Context().Done():
+				return
+			case <-ticker.C:
+			}
+			c.mu.Lock()
+			var err error
+			if !c.completed && time.Since(c.lastSent) >= interval {
+				err = c.sendLocked(&advisorpb.StreamAdviceResponse{
+					Progress: &advisorpb.ProgressEvent{Stage: advisorpb.ProgressEvent_KEEPALIVE},
+				})
+			}
+			c.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// abort tells the sinks that a stream ended without completing, so they can
+// drop what they buffered for it. The client has already gone or will get
+// the error status.
+func (c *chunkSender) abort(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.completed {
+		return
+	}
+	c.sinks.Publish(sink.Event{
+		StreamID:     c.id,
+		SessionID:    c.sessionID,
+		Sequence:     c.seq,
+		Aborted:      true,
+		FinishReason: status.Code(err).String(),
+		Time:         time.Now(),
+	})
+}
+
+// progress sends a progress event. It goes through send so it is numbered
+// like any other message; it carries no chunk text.
+func (c *chunkSender) progress(stage advisorpb.ProgressEvent_Stage, location string, completed, total int32, message string) error {
+	return c.send(&advisorpb.StreamAdviceResponse{
+		Progress: &advisorpb.ProgressEvent{
+			Stage:     stage,
+			Location:  location,
+			Completed: completed,
+			Total:     total,
+			Message:   message,
+		},
+	})
+}
+
+type streamRecord struct {
+	mu       sync.Mutex
+	messages []*advisorpb.StreamAdviceResponse
+	started  time.Time
+}
+
+func (r *streamRecord) append(msg *advisorpb.StreamAdviceResponse) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, proto.Clone(msg).(*advisorpb.StreamAdviceResponse))
+}
+
+func (r *streamRecord) slice(from, to uint64) []*advisorpb.StreamAdviceResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []*advisorpb.StreamAdviceResponse
+	for _, msg := range r.messages {
+		if msg.Sequence >= from && (to == 0 || msg.Sequence <= to) {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+type replayBuffer struct {
+	mu      sync.Mutex
+	streams map[string]*streamRecord
+}
+
+func newReplayBuffer() *replayBuffer {
+	return &replayBuffer{streams: make(map[string]*streamRecord)}
+}
+
+func (b *replayBuffer) start(id string) *streamRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for key, rec := range b.streams {
+		if now.Sub(rec.started) > replayTTL {
+			delete(b.streams, key)
+		}
+	}
+
+	rec := &streamRecord{started: now}
+	b.streams[id] = rec
+	return rec
+}
+
+func (b *replayBuffer) get(id string) (*streamRecord, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec, ok := b.streams[id]
+	if !ok || time.Since(rec.started) > replayTTL {
+		return nil, false
+	}
+	return rec, true
+}
+
+func (b *replayBuffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.streams)
+}
+
+// flush forgets every recorded stream. Streams in progress keep sending but
+// can no longer be replayed.
+func (b *replayBuffer) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.streams = make(map[string]*streamRecord)
+}
+
+func (s *advisorService) ResendChunks(req *advisorpb.ResendChunksRequest, stream advisorpb.AdvisorService_ResendChunksServer) error {
+	if req.ToSequence != 0 && req.ToSequence < req.FromSequence {
+		return status.Errorf(codes.InvalidArgument, "to_sequence %d is before from_sequence %d", req.ToSequence, req.FromSequence)
+	}
+	rec, ok := s.replay.get(req.StreamId)
+	if !ok {
+		return status.Errorf(codes.NotFound, "stream %s is unknown or has expired", req.StreamId)
+	}
+
+	for _, msg := range rec.slice(req.FromSequence, req.ToSequence) {
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package advisor
+
+import (
+	"github.com/google/generative-ai-go/genai"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	llmTokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_llm_tokens_total",
+			Help: "LLM tokens consumed, by model and token type",
+		},
+		[]string{"model", "type"},
+	)
+	llmCost = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "advisor_llm_cost_usd_total",
+			Help: "Estimated LLM spend in US dollars",
+		},
+		[]string{"model"},
+	)
+	llmPromptTokens = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "advisor_llm_prompt_tokens",
+			Help:    "Prompt tokens per LLM call",
+			Buckets: prometheus.ExponentialBuckets(50, 2, 8),
+		},
+		[]string{"model"},
+	)
+)
+
+// modelPrice is the list price in USD per million tokens.
+type modelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices uses the standard (<=200k context) Gemini API rates. Unknown
+// models are still counted but report a zero cost.
+var modelPrices = map[string]modelPrice{
+	"gemini-2.5-pro":   {Input: 1.25, Output: 10.00},
+	"gemini-2.5-flash": {Input: 0.30, Output: 2.50},
+	"gemini-1.5-pro":   {Input: 1.25, Output: 5.00},
+	"gemini-1.5-flash": {Input: 0.075, Output: 0.30},
+}
+
+func estimateCost(model string, promptTokens, responseTokens int32) float64 {
+	price, ok := modelPrices[model]
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.Input + float64(responseTokens)*price.Output) / 1e6
+}
+
+// recordUsage updates the token and cost metrics and returns the usage to
+// attach to the response. It returns nil when the model reported no usage.
+func recordUsage(model string, meta *genai.UsageMetadata) *advisorpb.TokenUsage {
+	if meta == nil {
+		return nil
+	}
+	cost := estimateCost(model, meta.PromptTokenCount, meta.CandidatesTokenCount)
+
+	llmTokens.WithLabelValues(model, "prompt").Add(float64(meta.PromptTokenCount))
+	llmPromptTokens.WithLabelValues(model).Observe(float64(meta.PromptTokenCount))
+	llmTokens.WithLabelValues(model, "response").Add(float64(meta.CandidatesTokenCount))
+	llmCost.WithLabelValues(model).Add(cost)
+
+	return &advisorpb.TokenUsage{
+		Model:            model,
+		PromptTokens:     meta.PromptTokenCount,
+		ResponseTokens:   meta.CandidatesTokenCount,
+		TotalTokens:      meta.TotalTokenCount,
+		EstimatedCostUsd: cost,
+	}
+}
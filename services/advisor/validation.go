@@ -0,0 +1,172 @@
+package advisor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pixperk/effinarounf/services/validate"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// normalizeLocation trims and collapses whitespace. Names typed entirely in
+// lower or upper case are title-cased ("new york" becomes "New York") so the
+// advice reads naturally; mixed-case names are left as given.
+func normalizeLocation(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if name != strings.ToLower(name) && name != strings.ToUpper(name) {
+		return name
+	}
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+// Limits on a request's cities, so one request can't fan out into unbounded
+// geocoding, weather and LLM work.
+const (
+	maxCities         = 10
+	maxLocationLength = 100
+	// maxRequestedCities caps the list before repeats are dropped.
+	maxRequestedCities = 5 * maxCities
+)
+
+// ValidationRules are the field constraints the validation interceptor
+// enforces on advisor requests. Handlers still normalize and check cities
+// themselves, since scheduled digests don't pass through the interceptor.
+var ValidationRules = []validate.Rule{
+	validate.Latitude("advisor.CityData.latitude"),
+	validate.Longitude("advisor.CityData.longitude"),
+	validate.MaxLen("advisor.CityData.location", maxLocationLength),
+	validate.MaxLen("advisor.CityData.state", maxLocationLength),
+	validate.MaxLen("advisor.CityData.country", maxLocationLength),
+	validate.Required("advisor.AdvisorRequest.cities"),
+	validate.MaxItems("advisor.AdvisorRequest.cities", maxRequestedCities),
+	validate.Required("advisor.CompareModelsRequest.cities"),
+	validate.MaxItems("advisor.CompareModelsRequest.cities", maxRequestedCities),
+	validate.Required("advisor.RateActivityRequest.cities"),
+	validate.MaxItems("advisor.RateActivityRequest.cities", maxRequestedCities),
+	validate.Required("advisor.PackingListRequest.destinations"),
+	validate.MaxItems("advisor.PackingListRequest.destinations", maxRequestedCities),
+	validate.Required("advisor.BestDayRequest.city"),
+	validate.MaxItems("advisor.UserProfile.allergies", maxAllergies),
+	validate.Required("advisor.ResendChunksRequest.stream_id"),
+	validate.Required("advisor.ListHistoryRequest.session_id"),
+	validate.Required("advisor.GetAdviceRecordRequest.id"),
+	validate.Required("advisor.SearchLocationsRequest.query"),
+	validate.MaxLen("advisor.SearchLocationsRequest.query", maxLocationLength),
+	validate.Required("advisor.CreateDigestRequest.subscription"),
+	validate.Required("advisor.DeleteDigestRequest.id"),
+	validate.Required("advisor.CreateAlertRequest.subscription"),
+	validate.Required("advisor.AlertSubscription.city"),
+	validate.Required("advisor.AlertSubscription.condition"),
+	validate.Required("advisor.DeleteAlertRequest.id"),
+	validate.MaxItems("advisor.SubscribeAlertsRequest.subscription_ids", maxAlertStreamIDs),
+	validate.MaxItems("advisor.DigestSubscription.webhooks", maxWebhooks),
+	validate.MaxItems("advisor.AlertSubscription.webhooks", maxWebhooks),
+	validate.Required("advisor.Webhook.url"),
+	validate.MaxLen("advisor.Webhook.url", maxWebhookURLLength),
+	validate.MaxLen("advisor.Webhook.secret", maxWebhookSecret),
+}
+
+// normalizeCities cleans every city name in place, drops repeated cities and
+// rejects the request with the offending index when a city is invalid,
+// before any upstream call. Indexes in later errors refer to the returned
+// slice.
+func normalizeCities(cities []*advisorpb.CityData) ([]*advisorpb.CityData, error) {
+	if len(cities) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one city is required")
+	}
+	for i, city := range cities {
+		if city == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: missing city", i)
+		}
+		if err := checkCoordinates(city); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: %v", i, err)
+		}
+		for _, field := range []struct{ name, value string }{{"location", city.Location}, {"state", city.State}, {"country", city.Country}} {
+			if n := utf8.RuneCountInString(field.value); n > maxLocationLength {
+				return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: %s is %d characters (at most %d)", i, field.name, n, maxLocationLength)
+			}
+		}
+		city.Location = normalizeLocation(city.Location)
+		if city.Location == "" && city.Latitude != nil {
+			city.Location = fmt.Sprintf("%.4f,%.4f", *city.Latitude, *city.Longitude)
+		}
+		if city.Location == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: location is empty", i)
+		}
+		if err := checkPromptText(city.Location); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: location %v", i, err)
+		}
+		if n := len(strings.Fields(city.Location)); n > maxLocationWords {
+			return nil, status.Errorf(codes.InvalidArgument, "cities[%d]: location has %d words (at most %d)", i, n, maxLocationWords)
+		}
+	}
+
+	unique := dedupeCities(cities)
+	if len(unique) > maxCities {
+		return nil, status.Errorf(codes.InvalidArgument, "too many cities: %d (at most %d)", len(unique), maxCities)
+	}
+	return unique, nil
+}
+
+// dedupeCities keeps the first of each repeated city. A repeat marked
+// primary makes the kept city primary.
+func dedupeCities(cities []*advisorpb.CityData) []*advisorpb.CityData {
+	seen := make(map[string]*advisorpb.CityData, len(cities))
+	unique := make([]*advisorpb.CityData, 0, len(cities))
+	for _, city := range cities {
+		key := strings.ToLower(strings.Join([]string{city.Location, strings.TrimSpace(city.State), strings.TrimSpace(city.Country)}, "|"))
+		if city.Latitude != nil {
+			key += fmt.Sprintf("|%.4f,%.4f", *city.Latitude, *city.Longitude)
+		}
+		if first, ok := seen[key]; ok {
+			first.Primary = first.Primary || city.Primary
+			continue
+		}
+		seen[key] = city
+		unique = append(unique, city)
+	}
+	return unique
+}
+
+// checkCoordinates requires latitude and longitude together and in range.
+func checkCoordinates(city *advisorpb.CityData) error {
+	if (city.Latitude == nil) != (city.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be set together")
+	}
+	if city.Latitude == nil {
+		return nil
+	}
+	if lat := *city.Latitude; math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v is out of range", lat)
+	}
+	if lon := *city.Longitude; math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude %v is out of range", lon)
+	}
+	return nil
+}
+
+// countryCode returns country as an ISO 3166 alpha-2 code, or "" when it is
+// a name or empty.
+func countryCode(country string) string {
+	country = strings.TrimSpace(country)
+	if len(country) != 2 {
+		return ""
+	}
+	for _, r := range country {
+		if !unicode.IsLetter(r) {
+			return ""
+		}
+	}
+	return strings.ToUpper(country)
+}
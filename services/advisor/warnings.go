@@ -0,0 +1,29 @@
+package advisor
+
+import (
+	"github.com/pixperk/effinarounf/services/rules"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+// warningMessages converts rule warnings for the API, most severe first.
+func warningMessages(warnings []rules.Warning) []*advisorpb.Warning {
+	if len(warnings) == 0 {
+		return nil
+	}
+	ranked := append([]rules.Warning(nil), warnings...)
+	rules.SortWarnings(ranked)
+
+	out := make([]*advisorpb.Warning, 0, len(ranked))
+	for _, w := range ranked {
+		out = append(out, &advisorpb.Warning{
+			Code:        w.Code,
+			Severity:    advisorpb.Warning_Severity(w.Severity),
+			Location:    w.City,
+			Headline:    w.Headline,
+			Description: w.Text,
+			Start:       w.Start,
+			End:         w.End,
+		})
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package advisor
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/pixperk/effinarounf/services/notify"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits on a subscription's chat webhooks.
+const (
+	maxWebhooks         = 5
+	maxWebhookURLLength = 500
+	maxWebhookSecret    = 200
+)
+
+var webhookKinds = map[advisorpb.WebhookKind]string{
+	advisorpb.WebhookKind_WEBHOOK_KIND_SLACK:   notify.WebhookSlack,
+	advisorpb.WebhookKind_WEBHOOK_KIND_DISCORD: notify.WebhookDiscord,
+	advisorpb.WebhookKind_WEBHOOK_KIND_GENERIC: notify.WebhookGeneric,
+}
+
+// subscriptionWebhooks checks a subscription's webhooks. Only the chat
+// services' own webhook URLs are accepted for Slack and Discord. Generic
+// webhooks without a secret get a generated one.
+func subscriptionWebhooks(in []*advisorpb.Webhook) ([]notify.Webhook, error) {
+	var out []notify.Webhook
+	for i, w := range in {
+		hook := notify.Webhook{Kind: webhookKinds[w.Kind], URL: w.Url}
+		switch {
+		case hook.Kind == "":
+			return nil, status.Errorf(codes.InvalidArgument, "webhooks[%d]: kind is required", i)
+		case hook.Kind == notify.WebhookGeneric && w.Secret == "":
+			hook.Secret = newWebhookSecret()
+		case hook.Kind == notify.WebhookGeneric:
+			hook.Secret = w.Secret
+		}
+		if err := hook.Validate(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "webhooks[%d]: %v", i, err)
+		}
+		out = append(out, hook)
+	}
+	return out, nil
+}
+
+// webhooksProto returns webhooks with their URLs redacted, since anyone
+// who has a URL can post to the channel, and without their secrets.
+func webhooksProto(webhooks []notify.Webhook) []*advisorpb.Webhook {
+	var out []*advisorpb.Webhook
+	for _, w := range webhooks {
+		out = append(out, &advisorpb.Webhook{Kind: protoKey(webhookKinds, w.Kind), Url: w.Redacted()})
+	}
+	return out
+}
+
+// showSecrets adds the webhook secrets to the response that creates a
+// subscription, the only time they are shown.
+func showSecrets(out []*advisorpb.Webhook, webhooks []notify.Webhook) {
+	for i, w := range webhooks {
+		out[i].Secret = w.Secret
+	}
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
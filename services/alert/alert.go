@@ -0,0 +1,259 @@
+// Package alert keeps weather alert subscriptions ("wind above 15 m/s in
+// Chicago", "any rain in London tomorrow") and checks them against the
+// daily forecast, handing the ones that are met to the notifiers.
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/notify"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+var ErrNotFound = errors.New("alert subscription not found")
+
+// Metric is a daily forecast value a condition compares.
+type Metric string
+
+const (
+	TempMax                  Metric = "temp_max"
+	TempMin                  Metric = "temp_min"
+	Precipitation            Metric = "precipitation"
+	PrecipitationProbability Metric = "precipitation_probability"
+	WindSpeed                Metric = "wind_speed"
+	UVIndex                  Metric = "uv_index"
+)
+
+// Operator compares the forecast value with the threshold.
+type Operator string
+
+const (
+	Above   Operator = "above"
+	AtLeast Operator = "at_least"
+	Below   Operator = "below"
+	AtMost  Operator = "at_most"
+)
+
+// Window is which forecast days a condition looks at, counted from the
+// location's today.
+type Window string
+
+const (
+	Today     Window = "today"
+	Tomorrow  Window = "tomorrow"
+	Next3Days Window = "next_3_days"
+	Next7Days Window = "next_7_days"
+)
+
+// Condition is met when the metric compares with the threshold on any
+// day of the window.
+type Condition struct {
+	Metric    Metric
+	Operator  Operator
+	Threshold float64
+	// Unit is the unit Threshold is given in, see units; empty means the
+	// forecast's own unit (°C, km/h, mm).
+	Unit   string
+	Window Window
+}
+
+// Subscription watches one location for one condition.
+type Subscription struct {
+	ID        string
+	Recipient string
+	// Location is the display name; the coordinates are resolved when the
+	// subscription is created.
+	Location            string
+	Latitude, Longitude float64
+	Condition           Condition
+	CreatedAt           time.Time
+	// LastAlertedDate is the local forecast date (YYYY-MM-DD) the alert
+	// last fired for. A condition met again for the same day doesn't fire
+	// again.
+	LastAlertedDate string
+	LastAlertedAt   time.Time
+	// Webhooks are chat channels the alert is posted to as well.
+	Webhooks []notify.Webhook
+}
+
+// Store keeps alert subscriptions.
+type Store interface {
+	// Save creates or replaces the subscription with sub.ID.
+	Save(ctx context.Context, sub *Subscription) error
+	Get(ctx context.Context, id string) (*Subscription, error)
+	Delete(ctx context.Context, id string) error
+	// List returns every subscription, oldest first.
+	List(ctx context.Context) ([]*Subscription, error)
+}
+
+// unit is a threshold unit and its conversion to the forecast's unit.
+type unit struct {
+	name   string
+	toBase func(float64) float64
+}
+
+// units lists the threshold units each metric accepts. The first is the
+// forecast's own.
+var units = map[Metric][]unit{
+	TempMax:                  tempUnits,
+	TempMin:                  tempUnits,
+	Precipitation:            {{"mm", same}, {"in", func(v float64) float64 { return v * 25.4 }}},
+	PrecipitationProbability: {{"%", same}},
+	WindSpeed: {
+		{"km/h", same},
+		{"m/s", func(v float64) float64 { return v * 3.6 }},
+		{"mph", func(v float64) float64 { return v * 1.609344 }},
+		{"kn", func(v float64) float64 { return v * 1.852 }},
+	},
+	UVIndex: {{"", same}},
+}
+
+var tempUnits = []unit{{"C", same}, {"F", func(v float64) float64 { return (v - 32) * 5 / 9 }}}
+
+func same(v float64) float64 { return v }
+
+// Validate checks that the metric, operator, unit and window are known,
+// and fills in the default unit.
+func (c *Condition) Validate() error {
+	allowed, ok := units[c.Metric]
+	if !ok {
+		return fmt.Errorf("unknown alert metric %q", c.Metric)
+	}
+	switch c.Operator {
+	case Above, AtLeast, Below, AtMost:
+	default:
+		return fmt.Errorf("unknown alert operator %q", c.Operator)
+	}
+	if _, _, err := c.Window.days(); err != nil {
+		return err
+	}
+	if c.Unit == "" {
+		c.Unit = allowed[0].name
+	}
+	var names []string
+	for _, u := range allowed {
+		if u.name == c.Unit {
+			return nil
+		}
+		names = append(names, u.name)
+	}
+	if len(names) == 1 && names[0] == "" {
+		return fmt.Errorf("%s takes no unit", c.Metric)
+	}
+	return fmt.Errorf("unit %q doesn't apply to %s (want one of %q)", c.Unit, c.Metric, names)
+}
+
+// threshold returns the threshold in the forecast's unit.
+func (c *Condition) threshold() float64 {
+	for _, u := range units[c.Metric] {
+		if u.name == c.Unit {
+			return u.toBase(c.Threshold)
+		}
+	}
+	return c.Threshold
+}
+
+// inUnit converts a forecast value to the condition's unit, for messages.
+func (c *Condition) inUnit(v float64) float64 {
+	// Every conversion is linear, so invert it from two points.
+	for _, u := range units[c.Metric] {
+		if u.name == c.Unit {
+			zero, one := u.toBase(0), u.toBase(1)
+			return (v - zero) / (one - zero)
+		}
+	}
+	return v
+}
+
+// value picks the condition's metric out of a forecast day, in the
+// forecast's unit.
+func (c *Condition) value(day *weatherpb.DailyConditions) float64 {
+	switch c.Metric {
+	case TempMax:
+		return day.TempMax
+	case TempMin:
+		return day.TempMin
+	case Precipitation:
+		return day.PrecipitationSum
+	case PrecipitationProbability:
+		return float64(day.PrecipitationProbability)
+	case WindSpeed:
+		return day.WindSpeedMax
+	default:
+		return day.UvIndexMax
+	}
+}
+
+func (c *Condition) met(day *weatherpb.DailyConditions) bool {
+	v, t := c.value(day), c.threshold()
+	switch c.Operator {
+	case Above:
+		return v > t
+	case AtLeast:
+		return v >= t
+	case Below:
+		return v < t
+	default:
+		return v <= t
+	}
+}
+
+// days returns the window as a range of forecast days, today being 0.
+func (w Window) days() (from, to int, err error) {
+	switch w {
+	case Today:
+		return 0, 0, nil
+	case Tomorrow:
+		return 1, 1, nil
+	case Next3Days:
+		return 0, 2, nil
+	case Next7Days:
+		return 0, 6, nil
+	}
+	return 0, 0, fmt.Errorf("unknown alert window %q", w)
+}
+
+// String describes the condition, e.g. "wind speed above 15 m/s tomorrow".
+func (c Condition) String() string {
+	s := fmt.Sprintf("%s %s %g", metricNames[c.Metric], operatorNames[c.Operator], c.Threshold)
+	if c.Unit != "" {
+		s += unitSuffix(c.Unit)
+	}
+	return s + " " + windowNames[c.Window]
+}
+
+func unitSuffix(unit string) string {
+	switch unit {
+	case "C", "F":
+		return "°" + unit
+	case "%":
+		return "%"
+	}
+	return " " + unit
+}
+
+var (
+	metricNames = map[Metric]string{
+		TempMax:                  "high temperature",
+		TempMin:                  "low temperature",
+		Precipitation:            "precipitation",
+		PrecipitationProbability: "chance of precipitation",
+		WindSpeed:                "wind speed",
+		UVIndex:                  "UV index",
+	}
+	operatorNames = map[Operator]string{
+		Above:   "above",
+		AtLeast: "at least",
+		Below:   "below",
+		AtMost:  "at most",
+	}
+	windowNames = map[Window]string{
+		Today:     "today",
+		Tomorrow:  "tomorrow",
+		Next3Days: "in the next 3 days",
+		Next7Days: "in the next 7 days",
+	}
+)
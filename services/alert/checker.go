@@ -0,0 +1,148 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/notify"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var alertChecks = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "alert_checks_total",
+		Help: "Alert subscriptions checked against the forecast, by result",
+	},
+	[]string{"result"},
+)
+
+// Forecaster returns the daily forecast at a location, starting with its
+// today.
+type Forecaster func(ctx context.Context, lat, lon float64) (*weatherpb.DailyForecast, error)
+
+// Checker checks every subscription against the forecast each time Check
+// is called, which the server does on the alerts job's schedule. Alerts
+// that fire go to the notifier and, once delivered, to the feed.
+type Checker struct {
+	store    Store
+	forecast Forecaster
+	notifier notify.Notifier
+	feed     *Feed
+}
+
+// NewChecker publishes fired alerts to feed, which may be nil.
+func NewChecker(store Store, forecast Forecaster, notifier notify.Notifier, feed *Feed) *Checker {
+	return &Checker{store: store, forecast: forecast, notifier: notifier, feed: feed}
+}
+
+// Check notifies the subscriptions whose condition is met. Each location
+// is forecast once however many subscriptions watch it. Failures for one
+// subscription are logged and retried on the next check; only failing to
+// read the subscriptions is returned.
+func (c *Checker) Check(ctx context.Context) error {
+	subs, err := c.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing alert subscriptions failed: %v", err)
+	}
+
+	forecasts := make(map[[2]float64]*weatherpb.DailyForecast)
+	for _, sub := range subs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := [2]float64{sub.Latitude, sub.Longitude}
+		forecast, ok := forecasts[key]
+		if !ok {
+			forecast, err = c.forecast(ctx, sub.Latitude, sub.Longitude)
+			if err != nil {
+				slog.Error("alert forecast failed", "subscription", sub.ID, "location", sub.Location, "error", err)
+				alertChecks.WithLabelValues("failed").Inc()
+				continue
+			}
+			forecasts[key] = forecast
+		}
+
+		day := sub.firstMet(forecast.Days)
+		if day == nil {
+			alertChecks.WithLabelValues("clear").Inc()
+			continue
+		}
+		ev := newEvent(sub, day)
+		if err := c.notify(ctx, ev); err != nil {
+			slog.Error("alert delivery failed", "subscription", sub.ID, "error", err)
+			alertChecks.WithLabelValues("failed").Inc()
+			continue
+		}
+		alertChecks.WithLabelValues("triggered").Inc()
+		// Published only once delivered, so a failure retried on the next
+		// check doesn't reach live subscribers twice.
+		c.feed.Publish(ev)
+		c.markAlerted(ctx, sub, day.Date)
+	}
+	return nil
+}
+
+// firstMet returns the first day in the window the condition is met on and
+// that hasn't been alerted for yet.
+func (s *Subscription) firstMet(days []*weatherpb.DailyConditions) *weatherpb.DailyConditions {
+	from, to, err := s.Condition.Window.days()
+	if err != nil {
+		return nil
+	}
+	for i := from; i <= to && i < len(days); i++ {
+		// Dates are YYYY-MM-DD, so they compare as strings.
+		if days[i].Date > s.LastAlertedDate && s.Condition.met(days[i]) {
+			return days[i]
+		}
+	}
+	return nil
+}
+
+func newEvent(sub *Subscription, day *weatherpb.DailyConditions) Event {
+	date, _ := time.Parse(time.DateOnly, day.Date)
+	cond := sub.Condition
+	value := cond.inUnit(cond.value(day))
+	shown := fmt.Sprintf("%.1f", value)
+	if cond.Unit != "" {
+		shown += unitSuffix(cond.Unit)
+	}
+	return Event{
+		Subscription: sub,
+		Date:         day.Date,
+		Value:        value,
+		Description:  day.Description,
+		Title:        fmt.Sprintf("Weather alert for %s on %s", sub.Location, date.Format("Monday, 2 January")),
+		Body:         fmt.Sprintf("Forecast %s: %s (%s).\n\nAlert: %s.", metricNames[cond.Metric], shown, day.Description, cond),
+		Time:         time.Now(),
+	}
+}
+
+func (c *Checker) notify(ctx context.Context, ev Event) error {
+	return c.notifier.Notify(ctx, &notify.Message{
+		Kind:           notify.KindAlert,
+		SubscriptionID: ev.Subscription.ID,
+		Recipient:      ev.Subscription.Recipient,
+		Title:          ev.Title,
+		Body:           ev.Body,
+		Time:           ev.Time,
+		Webhooks:       ev.Subscription.Webhooks,
+	})
+}
+
+// markAlerted re-reads the subscription so a concurrent delete isn't
+// undone.
+func (c *Checker) markAlerted(ctx context.Context, sub *Subscription, date string) {
+	current, err := c.store.Get(ctx, sub.ID)
+	if err != nil {
+		return
+	}
+	current.LastAlertedDate = date
+	current.LastAlertedAt = time.Now()
+	if err := c.store.Save(ctx, current); err != nil {
+		slog.Error("saving alert subscription failed", "subscription", sub.ID, "error", err)
+	}
+}
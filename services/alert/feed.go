@@ -0,0 +1,69 @@
+package alert
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var feedDropped = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "alert_feed_dropped_total",
+	Help: "Alert events not sent to a live subscriber because it was too slow",
+})
+
+// Event is an alert that fired, as sent to live subscribers.
+type Event struct {
+	Subscription *Subscription
+	// Date is the local forecast date the condition is met on.
+	Date string
+	// Value is the forecast value in the condition's unit.
+	Value       float64
+	Description string
+	Title, Body string
+	Time        time.Time
+}
+
+// Feed hands the alerts the checker fires to live subscribers, such as
+// SubscribeAlerts streams. It only reaches subscribers in this process.
+type Feed struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+func NewFeed() *Feed {
+	return &Feed{subs: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel of the events published from now on, with
+// room for buffer events. A subscriber that falls further behind misses
+// events. cancel must be called when done.
+func (f *Feed) Subscribe(buffer int) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, buffer)
+	f.mu.Lock()
+	f.subs[ch] = struct{}{}
+	f.mu.Unlock()
+	return ch, func() {
+		f.mu.Lock()
+		delete(f.subs, ch)
+		f.mu.Unlock()
+	}
+}
+
+// Publish sends ev to every subscriber without blocking. It is a no-op on
+// a nil Feed.
+func (f *Feed) Publish(ev Event) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- ev:
+		default:
+			feedDropped.Inc()
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package alert
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps subscriptions in memory; they are lost on restart.
+type MemoryStore struct {
+	mu   sync.Mutex
+	subs map[string]*Subscription
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{subs: make(map[string]*Subscription)}
+}
+
+func (m *MemoryStore) Save(_ context.Context, sub *Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs[sub.ID] = clone(sub)
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(sub), nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.subs, id)
+	return nil
+}
+
+func (m *MemoryStore) List(_ context.Context) ([]*Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]*Subscription, 0, len(m.subs))
+	for _, sub := range m.subs {
+		out = append(out, clone(sub))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+// clone copies sub so callers can't change the stored webhooks.
+func clone(sub *Subscription) *Subscription {
+	out := *sub
+	out.Webhooks = slices.Clone(sub.Webhooks)
+	return &out
+}
@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/storage"
+)
+
+// SQLStore keeps subscriptions in the database, so alerts keep being
+// checked after a restart.
+type SQLStore struct {
+	db *storage.DB
+}
+
+func NewSQLStore(db *storage.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+const subscriptionColumns = `id, recipient, location, latitude, longitude, metric, operator, threshold, unit,
+	alert_window, created_at, last_alerted_date, last_alerted_at, webhooks`
+
+func (s *SQLStore) Save(ctx context.Context, sub *Subscription) error {
+	c := sub.Condition
+	webhooks, err := json.Marshal(sub.Webhooks)
+	if err != nil {
+		return fmt.Errorf("encode alert %s: %v", sub.ID, err)
+	}
+	_, err = s.db.Exec(ctx, `INSERT INTO alert_subscriptions (`+subscriptionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET recipient = excluded.recipient, location = excluded.location,
+			latitude = excluded.latitude, longitude = excluded.longitude, metric = excluded.metric,
+			operator = excluded.operator, threshold = excluded.threshold, unit = excluded.unit,
+			alert_window = excluded.alert_window, created_at = excluded.created_at,
+			last_alerted_date = excluded.last_alerted_date, last_alerted_at = excluded.last_alerted_at,
+			webhooks = excluded.webhooks`,
+		sub.ID, sub.Recipient, sub.Location, sub.Latitude, sub.Longitude, string(c.Metric), string(c.Operator), c.Threshold, c.Unit,
+		string(c.Window), unixMilli(sub.CreatedAt), sub.LastAlertedDate, unixMilli(sub.LastAlertedAt), string(webhooks))
+	if err != nil {
+		return fmt.Errorf("write alert %s: %v", sub.ID, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (*Subscription, error) {
+	sub, err := scanSubscription(s.db.QueryRow(ctx, `SELECT `+subscriptionColumns+` FROM alert_subscriptions WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read alert %s: %v", id, err)
+	}
+	return sub, nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.Exec(ctx, `DELETE FROM alert_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete alert %s: %v", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]*Subscription, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+subscriptionColumns+` FROM alert_subscriptions ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %v", err)
+	}
+	defer rows.Close()
+
+	var out []*Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list alerts: %v", err)
+		}
+		out = append(out, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list alerts: %v", err)
+	}
+	return out, nil
+}
+
+func scanSubscription(row interface{ Scan(...any) error }) (*Subscription, error) {
+	var sub Subscription
+	var metric, operator, window, webhooks string
+	var created, alerted int64
+	if err := row.Scan(&sub.ID, &sub.Recipient, &sub.Location, &sub.Latitude, &sub.Longitude, &metric, &operator,
+		&sub.Condition.Threshold, &sub.Condition.Unit, &window, &created, &sub.LastAlertedDate, &alerted, &webhooks); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(webhooks), &sub.Webhooks); err != nil {
+		return nil, fmt.Errorf("decode webhooks of %s: %v", sub.ID, err)
+	}
+	sub.Condition.Metric = Metric(metric)
+	sub.Condition.Operator = Operator(operator)
+	sub.Condition.Window = Window(window)
+	sub.CreatedAt = fromUnixMilli(created)
+	sub.LastAlertedAt = fromUnixMilli(alerted)
+	return &sub, nil
+}
+
+// unixMilli and fromUnixMilli store the zero time as 0.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileStore appends entries to a JSON Lines file opened in append-only
+// mode. Exports read the file itself, and the files it was rotated into,
+// so every entry kept on disk is available and nothing is held in memory.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log failed: %v", err)
+	}
+	return &FileStore{path: path, file: file}, nil
+}
+
+func (f *FileStore) Append(_ context.Context, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode audit entry failed: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write audit log failed: %v", err)
+	}
+	return nil
+}
+
+// Rotate moves the file aside once it has grown past maxBytes, naming it
+// after the current time (audit.jsonl.20060102T150405Z), and starts a new
+// one. Only the newest keep rotated files are kept, or all of them if keep
+// is 0. It reports whether the file was rotated.
+func (f *FileStore) Rotate(maxBytes int64, keep int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := f.file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("stat audit log failed: %v", err)
+	}
+	if info.Size() < maxBytes {
+		return false, nil
+	}
+	rotated := f.path + "." + time.Now().UTC().Format("20060102T150405Z")
+	if err := os.Rename(f.path, rotated); err != nil {
+		return false, fmt.Errorf("rotate audit log failed: %v", err)
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		// Keep appending to the renamed file rather than losing entries.
+		return false, fmt.Errorf("reopen audit log failed: %v", err)
+	}
+	f.file.Close()
+	f.file = file
+
+	if keep > 0 {
+		old, err := f.rotatedFiles()
+		if err != nil {
+			return true, err
+		}
+		for len(old) > keep {
+			if err := os.Remove(old[0]); err != nil {
+				return true, fmt.Errorf("remove old audit log failed: %v", err)
+			}
+			old = old[1:]
+		}
+	}
+	return true, nil
+}
+
+// rotatedFiles returns the files Rotate moved the log into, oldest first.
+func (f *FileStore) rotatedFiles() ([]string, error) {
+	matches, err := filepath.Glob(f.path + ".*T*Z")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// List scans the rotated files, oldest first, then the current one.
+func (f *FileStore) List(ctx context.Context, filter Filter, fn func(*Entry) error) error {
+	paths, err := f.rotatedFiles()
+	if err != nil {
+		return fmt.Errorf("list audit logs failed: %v", err)
+	}
+	for _, path := range append(paths, f.path) {
+		if err := listFile(ctx, path, filter, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listFile scans one file. Lines that don't parse, such as one cut short
+// by a crash, are skipped.
+func listFile(ctx context.Context, path string, filter Filter, fn func(*Entry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open audit log failed: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || !filter.match(&e) {
+			continue
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read audit log failed: %v", err)
+	}
+	return nil
+}
+
+func (f *FileStore) Close() error {
+	return f.file.Close()
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the most recent entries in memory. When full, the
+// oldest entry is dropped, so use FileStore where the log must be complete.
+type MemoryStore struct {
+	mu      sync.Mutex
+	max     int
+	entries []*Entry
+}
+
+func NewMemoryStore(max int) *MemoryStore {
+	return &MemoryStore{max: max}
+}
+
+func (m *MemoryStore) Append(_ context.Context, e *Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *e
+	m.entries = append(m.entries, &stored)
+	if m.max > 0 && len(m.entries) > m.max {
+		m.entries = m.entries[1:]
+	}
+	return nil
+}
+
+func (m *MemoryStore) List(_ context.Context, f Filter, fn func(*Entry) error) error {
+	m.mu.Lock()
+	var matched []*Entry
+	for _, e := range m.entries {
+		if f.match(e) {
+			copied := *e
+			matched = append(matched, &copied)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, e := range matched {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/identity"
+	"github.com/pixperk/effinarounf/services/requestid"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var auditWriteFailures = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "audit_write_failures_total",
+		Help: "Audit entries that could not be written",
+	},
+)
+
+// Usage accumulates the LLM use of one audited call.
+type Usage struct {
+	mu             sync.Mutex
+	models         []string
+	promptTokens   int64
+	responseTokens int64
+	totalTokens    int64
+	costUSD        float64
+}
+
+type usageKey struct{}
+
+// AddUsage charges one LLM call to the audited call ctx belongs to. It
+// does nothing outside an audited call.
+func AddUsage(ctx context.Context, model string, promptTokens, responseTokens, totalTokens int32, costUSD float64) {
+	u, ok := ctx.Value(usageKey{}).(*Usage)
+	if !ok {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !slices.Contains(u.models, model) {
+		u.models = append(u.models, model)
+	}
+	u.promptTokens += int64(promptTokens)
+	u.responseTokens += int64(responseTokens)
+	u.totalTokens += int64(totalTokens)
+	u.costUSD += costUSD
+}
+
+// Recorder writes an entry for every audited call and serves exports to
+// the allowed callers.
+type Recorder struct {
+	store     Store
+	methods   map[string]bool
+	exporters map[string]bool
+}
+
+// NewRecorder audits the given full gRPC method names. exporters are the
+// caller identities allowed to read the log back.
+func NewRecorder(store Store, methods, exporters []string) *Recorder {
+	r := &Recorder{store: store, methods: make(map[string]bool), exporters: make(map[string]bool)}
+	for _, m := range methods {
+		r.methods[m] = true
+	}
+	for _, e := range exporters {
+		r.exporters[e] = true
+	}
+	return r
+}
+
+// CanExport reports whether the caller of ctx may read the log.
+func (r *Recorder) CanExport(ctx context.Context) bool {
+	return r.exporters[identity.Subject(ctx)]
+}
+
+// Export calls fn for each matching entry, oldest first.
+func (r *Recorder) Export(ctx context.Context, f Filter, fn func(*Entry) error) error {
+	return r.store.List(ctx, f, fn)
+}
+
+// Track runs fn as an audited call made outside gRPC, such as a scheduled
+// digest, on behalf of client.
+func (r *Recorder) Track(ctx context.Context, client, method string, cities []string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	u := &Usage{}
+	err := fn(context.WithValue(ctx, usageKey{}, u))
+	r.write(ctx, client, method, cities, u, start, err)
+	return err
+}
+
+// write records a finished call. A failed write is logged and counted but
+// never fails the call, which has already been served.
+func (r *Recorder) write(ctx context.Context, client, method string, cities []string, u *Usage, start time.Time, err error) {
+	u.mu.Lock()
+	e := &Entry{
+		ID:             uuid.NewString(),
+		Time:           time.Now().UTC(),
+		RequestID:      requestid.FromContext(ctx),
+		Client:         client,
+		Method:         method,
+		Cities:         cities,
+		Models:         u.models,
+		PromptTokens:   u.promptTokens,
+		ResponseTokens: u.responseTokens,
+		TotalTokens:    u.totalTokens,
+		CostUSD:        u.costUSD,
+		Outcome:        status.Code(err).String(),
+		DurationMs:     time.Since(start).Milliseconds(),
+	}
+	u.mu.Unlock()
+	if err := r.store.Append(context.WithoutCancel(ctx), e); err != nil {
+		auditWriteFailures.Inc()
+		slog.ErrorContext(ctx, "writing audit entry failed", "error", err)
+	}
+}
+
+// UnaryServerInterceptor audits calls to the recorder's methods. Install
+// it after the authentication and identity interceptors so the caller is
+// known.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !r.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		u := &Usage{}
+		resp, err := handler(context.WithValue(ctx, usageKey{}, u), req)
+		r.write(ctx, identity.Subject(ctx), info.FullMethod, Cities(req), u, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs.
+// The cities are taken from the first message the client sends.
+func (r *Recorder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !r.methods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		start := time.Now()
+		u := &Usage{}
+		as := &auditedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), usageKey{}, u)}
+		err := handler(srv, as)
+		r.write(ss.Context(), identity.Subject(ss.Context()), info.FullMethod, as.cities, u, start, err)
+		return err
+	}
+}
+
+type auditedStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received bool
+	cities   []string
+}
+
+func (s *auditedStream) Context() context.Context { return s.ctx }
+
+func (s *auditedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && !s.received {
+		s.received = true
+		s.cities = Cities(m)
+	}
+	return err
+}
+
+var cityDescriptor = (&advisorpb.CityData{}).ProtoReflect().Descriptor()
+
+// Cities returns the location of every CityData in msg, however deeply
+// nested, in field order.
+func Cities(msg any) []string {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	var out []string
+	collectCities(m.ProtoReflect(), &out)
+	return out
+}
+
+func collectCities(m protoreflect.Message, out *[]string) {
+	if m.Descriptor() == cityDescriptor {
+		*out = append(*out, m.Interface().(*advisorpb.CityData).Location)
+		return
+	}
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !m.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				collectCities(list.Get(j).Message(), out)
+			}
+			continue
+		}
+		collectCities(m.Get(fd).Message(), out)
+	}
+}
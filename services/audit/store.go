@@ -0,0 +1,50 @@
+// Package audit keeps an append-only record of who used the advisor, for
+// which cities, how many LLM tokens it cost and how the call ended.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is one audited call.
+type Entry struct {
+	ID             string    `json:"id"`
+	Time           time.Time `json:"time"`
+	RequestID      string    `json:"request_id,omitempty"`
+	Client         string    `json:"client"`
+	Method         string    `json:"method"`
+	Cities         []string  `json:"cities,omitempty"`
+	Models         []string  `json:"models,omitempty"`
+	PromptTokens   int64     `json:"prompt_tokens"`
+	ResponseTokens int64     `json:"response_tokens"`
+	TotalTokens    int64     `json:"total_tokens"`
+	CostUSD        float64   `json:"estimated_cost_usd"`
+	Outcome        string    `json:"outcome"`
+	DurationMs     int64     `json:"duration_ms"`
+}
+
+// Filter selects entries for an export. Zero values match everything.
+type Filter struct {
+	Since, Until time.Time
+	Client       string
+}
+
+func (f Filter) match(e *Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	return f.Client == "" || e.Client == f.Client
+}
+
+// Store appends entries and reads them back. Entries are never changed or
+// removed through it.
+type Store interface {
+	Append(ctx context.Context, e *Entry) error
+	// List calls fn for each matching entry, oldest first, and stops at
+	// the first error fn returns.
+	List(ctx context.Context, f Filter, fn func(*Entry) error) error
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/identity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var authResults = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "grpc_auth_results_total",
+		Help: "Bearer token checks by result",
+	},
+	[]string{"result"},
+)
+
+// publicPrefix marks the health service, which load balancers and
+// orchestrators call without credentials.
+const publicPrefix = "/grpc.health.v1.Health/"
+
+// authenticate checks the request's bearer token and returns a context
+// carrying the token subject as the caller identity.
+func (v *Verifier) authenticate(ctx context.Context) (context.Context, error) {
+	// A caller already identified by an API key needs no token.
+	if id, ok := identity.FromContext(ctx); ok && id.Source == identity.SourceAPIKey {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		authResults.WithLabelValues("missing").Inc()
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		authResults.WithLabelValues("malformed").Inc()
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+	}
+
+	claims, err := v.Verify(ctx, strings.TrimSpace(token))
+	if err != nil {
+		authResults.WithLabelValues("rejected").Inc()
+		// Key fetch failures are our problem, not the caller's.
+		if !errors.Is(err, ErrInvalidToken) {
+			slog.ErrorContext(ctx, "token verification failed", "error", err)
+			return nil, status.Error(codes.Unavailable, "cannot verify tokens right now")
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+	authResults.WithLabelValues("ok").Inc()
+	return identity.NewContext(ctx, identity.Identity{Subject: claims.Subject, Source: "jwt", Tenant: claims.Tenant}), nil
+}
+
+// UnaryServerInterceptor rejects calls without a valid bearer token and
+// attaches the token subject to the context. Health checks are let through.
+// Install it before the identity interceptors so the subject takes
+// precedence over a client certificate.
+func (v *Verifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, publicPrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := v.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs.
+func (v *Verifier) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, publicPrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := v.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, identity.WrapStream(ss, ctx))
+	}
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/httpclient"
+)
+
+const (
+	// keysTTL is how long fetched keys are used before being refreshed.
+	keysTTL = time.Hour
+	// minRefresh limits refetches triggered by unknown key IDs, so tokens
+	// with made-up kids can't be used to hammer the identity provider.
+	minRefresh = time.Minute
+)
+
+// keySet caches an issuer's signing keys by key ID.
+type keySet struct {
+	issuer     string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	jwksURL string
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+func newKeySet(issuer, jwksURL string, httpClient *http.Client) *keySet {
+	return &keySet{issuer: issuer, jwksURL: jwksURL, httpClient: httpClient}
+}
+
+// get returns the key for kid, refreshing the set when it is stale or the
+// kid is unknown. An empty kid matches the only key of a one-key set.
+func (k *keySet) get(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, ok := k.lookup(kid)
+	stale := time.Since(k.fetched) > keysTTL
+	if (!ok && time.Since(k.fetched) > minRefresh) || stale {
+		if err := k.refresh(ctx); err != nil {
+			// Keep serving the keys we have if the provider is down.
+			if k.keys == nil {
+				return nil, fmt.Errorf("fetching signing keys: %v", err)
+			}
+			slog.Warn("refreshing JWKS failed, keeping cached keys", "keys", len(k.keys), "error", err)
+		}
+		key, ok = k.lookup(kid)
+	}
+	if !ok {
+		return nil, invalid("unknown key ID %q", kid)
+	}
+	return key, nil
+}
+
+func (k *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *keySet) refresh(ctx context.Context) error {
+	if k.jwksURL == "" {
+		var doc struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		discovery := strings.TrimSuffix(k.issuer, "/") + "/.well-known/openid-configuration"
+		if err := k.getJSON(ctx, discovery, &doc); err != nil {
+			return fmt.Errorf("OIDC discovery: %v", err)
+		}
+		if doc.JWKSURI == "" {
+			return fmt.Errorf("OIDC discovery: no jwks_uri in %s", discovery)
+		}
+		k.jwksURL = doc.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := k.getJSON(ctx, k.jwksURL, &set); err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey)
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		key, err := j.publicKey()
+		if err != nil {
+			slog.Warn("skipping JWKS key", "kid", j.Kid, "error", err)
+			continue
+		}
+		keys[j.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no usable signing keys at %s", k.jwksURL)
+	}
+	k.keys = keys
+	k.fetched = time.Now()
+	return nil
+}
+
+func (k *keySet) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpclient.Do(k.httpClient, "jwks", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return httpclient.DecodeJSON(resp, v)
+}
+
+func (j jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("n: %v", err)
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		if n.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA key shorter than 2048 bits")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, errX := decodeBigInt(j.X)
+		y, errY := decodeBigInt(j.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid point")
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH rejects points that are not on the curve.
+		if _, err := pub.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid point: %v", err)
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
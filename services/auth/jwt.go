@@ -0,0 +1,226 @@
+// Package auth validates bearer tokens on incoming RPCs.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config says which tokens a Verifier accepts.
+type Config struct {
+	// Issuer must match the token's iss claim. It is also where the JWKS
+	// is discovered from when JWKSURL is empty.
+	Issuer string
+	// Audience must be in the token's aud claim.
+	Audience string
+	// JWKSURL is where the signing keys are fetched. Empty uses the
+	// issuer's OpenID Connect discovery document.
+	JWKSURL string
+	// Leeway allows for clock skew when checking exp and nbf.
+	Leeway time.Duration
+	// TenantClaim, if set, names a string claim that carries the caller's
+	// tenant, e.g. "org_id".
+	TenantClaim string
+}
+
+// Claims are the registered claims of a verified token.
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt float64  `json:"exp"`
+	NotBefore float64  `json:"nbf"`
+	IssuedAt  float64  `json:"iat"`
+	// Tenant is the value of Config.TenantClaim, if any.
+	Tenant string `json:"-"`
+}
+
+// audience decodes aud, which may be a single string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("aud must be a string or list of strings")
+	}
+	*a = many
+	return nil
+}
+
+// ErrInvalidToken wraps every reason a token is rejected.
+var ErrInvalidToken = errors.New("invalid token")
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+}
+
+// Verifier checks JWT signatures against an issuer's JWKS and validates the
+// registered claims. It is safe for concurrent use.
+type Verifier struct {
+	cfg  Config
+	keys *keySet
+	now  func() time.Time
+}
+
+// NewVerifier returns a Verifier for cfg. Keys are fetched with httpClient
+// on first use, so the identity provider doesn't have to be reachable at
+// startup.
+func NewVerifier(cfg Config, httpClient *http.Client) (*Verifier, error) {
+	if cfg.Issuer == "" {
+		return nil, fmt.Errorf("issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, fmt.Errorf("audience is required")
+	}
+	return &Verifier{cfg: cfg, keys: newKeySet(cfg.Issuer, cfg.JWKSURL, httpClient), now: time.Now}, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Verify parses a compact JWS token, checks its signature and claims, and
+// returns the claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, invalid("malformed token")
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, invalid("header: %v", err)
+	}
+	hash, ok := algHashes[h.Alg]
+	if !ok {
+		// This also rejects "none" and the HMAC algorithms, which would
+		// let anyone holding the public key forge tokens.
+		return nil, invalid("unsupported algorithm %q", h.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, invalid("signature: %v", err)
+	}
+
+	key, err := v.keys.get(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := hash.New()
+	digest.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(h.Alg, key, hash, digest.Sum(nil), sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, invalid("claims: %v", err)
+	}
+	if err := v.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+	if v.cfg.TenantClaim != "" {
+		var extra map[string]any
+		if err := decodeSegment(parts[1], &extra); err != nil {
+			return nil, invalid("claims: %v", err)
+		}
+		claims.Tenant, _ = extra[v.cfg.TenantClaim].(string)
+	}
+	return &claims, nil
+}
+
+func (v *Verifier) checkClaims(c *Claims) error {
+	now := v.now()
+	switch {
+	case c.Issuer != v.cfg.Issuer:
+		return invalid("issuer %q not accepted", c.Issuer)
+	case !slices.Contains(c.Audience, v.cfg.Audience):
+		return invalid("audience does not include %q", v.cfg.Audience)
+	case c.Subject == "":
+		return invalid("missing sub")
+	case c.ExpiresAt == 0:
+		return invalid("missing exp")
+	case now.After(unixTime(c.ExpiresAt).Add(v.cfg.Leeway)):
+		return invalid("expired")
+	case c.NotBefore != 0 && now.Add(v.cfg.Leeway).Before(unixTime(c.NotBefore)):
+		return invalid("not valid yet")
+	}
+	return nil
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+var algHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// curves is the curve each ECDSA algorithm must be used with.
+var curves = map[string]string{"ES256": "P-256", "ES384": "P-384", "ES512": "P-521"}
+
+func verifySignature(alg string, key crypto.PublicKey, hash crypto.Hash, digest, sig []byte) error {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		var err error
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, sig)
+		case "PS":
+			err = rsa.VerifyPSS(pub, hash, digest, sig, nil)
+		default:
+			return invalid("algorithm %s does not match RSA key", alg)
+		}
+		if err != nil {
+			return invalid("bad signature")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if curves[alg] != pub.Curve.Params().Name {
+			return invalid("algorithm %s does not match %s key", alg, pub.Curve.Params().Name)
+		}
+		// JWS ECDSA signatures are r and s as fixed-size big-endian
+		// integers, not ASN.1.
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return invalid("bad signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return invalid("bad signature")
+		}
+		return nil
+	default:
+		return invalid("unsupported key type %T", key)
+	}
+}
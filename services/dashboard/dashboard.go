@@ -0,0 +1,40 @@
+// Package dashboard serves a small browser UI on top of the REST gateway:
+// current weather for the cities a user saves, streamed advice for them and
+// the state of the weather alerts. The assets are embedded in the binary.
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Path is where the dashboard is served.
+const Path = "/ui/"
+
+// csp only lets the page load its own assets and call the gateway it came
+// from.
+const csp = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// Register serves the dashboard on mux at Path, and redirects / to it.
+func Register(mux *http.ServeMux) {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	files := http.StripPrefix(Path, http.FileServerFS(assets))
+	mux.Handle("GET "+Path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", csp)
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		// Embedded files have no modification time, so make browsers
+		// revalidate rather than keep assets from an older build.
+		h.Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	}))
+	mux.Handle("GET /{$}", http.RedirectHandler(Path, http.StatusFound))
+}
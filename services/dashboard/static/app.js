@@ -0,0 +1,271 @@
+// Weather Advisor dashboard. Everything goes through the REST gateway this
+// page is served from; saved cities and settings live in localStorage.
+"use strict";
+
+const store = {
+  get(key, fallback) {
+    try {
+      const v = localStorage.getItem("weather-advisor." + key);
+      return v === null ? fallback : JSON.parse(v);
+    } catch {
+      return fallback;
+    }
+  },
+  set(key, value) {
+    localStorage.setItem("weather-advisor." + key, JSON.stringify(value));
+  },
+};
+
+const $ = (sel) => document.querySelector(sel);
+
+// refreshInterval is how often current conditions and alerts are reloaded.
+const refreshInterval = 5 * 60 * 1000;
+
+let cities = store.get("cities", []);
+
+// query builds a query string. Arrays become repeated parameters; empty
+// values are left out. The token is added for EventSource, which can't send
+// headers.
+function query(params, withToken) {
+  const q = new URLSearchParams();
+  for (const [k, v] of Object.entries(params)) {
+    for (const item of [].concat(v)) {
+      if (item !== "" && item !== undefined && item !== null) q.append(k, item);
+    }
+  }
+  const token = store.get("token", "");
+  if (withToken && token) q.set("access_token", token);
+  return q.toString();
+}
+
+async function api(path, params = {}) {
+  const headers = {};
+  const token = store.get("token", "");
+  if (token) headers.Authorization = "Bearer " + token;
+  const resp = await fetch(path + "?" + query(params), { headers });
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) throw new Error(body.message || resp.status + " " + resp.statusText);
+  return body;
+}
+
+// enumLabel turns ALERT_METRIC_TEMP_MAX into "temp max".
+function enumLabel(value, prefix) {
+  return String(value || "").replace(prefix, "").toLowerCase().replaceAll("_", " ");
+}
+
+function cityLabel(c) {
+  return [c.name, c.state, c.country].filter(Boolean).join(", ");
+}
+
+// Cities
+
+function saveCities() {
+  store.set("cities", cities);
+  renderCities();
+}
+
+function renderCities() {
+  const list = $("#cities");
+  list.replaceChildren();
+  $("#no-cities").hidden = cities.length > 0;
+  $("#advise").disabled = cities.length === 0;
+  const providers = new Set();
+  cities.forEach((city, i) => {
+    const card = $("#city-card").content.firstElementChild.cloneNode(true);
+    card.querySelector(".name").textContent = cityLabel(city);
+    card.querySelector(".remove").addEventListener("click", () => {
+      cities.splice(i, 1);
+      saveCities();
+    });
+    list.append(card);
+    api("/v1/weather", {
+      lat: city.latitude,
+      lon: city.longitude,
+      country: city.countryCode,
+      units: store.get("units", ""),
+    }).then((w) => {
+      const u = w.units || {};
+      card.querySelector(".temp").textContent = Math.round(w.temperature) + " " + (u.temperature || "");
+      card.querySelector(".desc").textContent = w.description;
+      card.querySelector(".wind").textContent = Math.round(w.windSpeed) + " " + (u.windSpeed || "");
+      card.querySelector(".humidity").textContent = w.humidity + "%";
+      card.querySelector(".uv").textContent = w.uvIndex.toFixed(1);
+      if (w.provider && w.provider.attribution && !providers.has(w.provider.attribution)) {
+        providers.add(w.provider.attribution);
+        $("#attribution").textContent = [...providers].join(" · ");
+      }
+    }).catch((err) => {
+      card.querySelector(".error").textContent = err.message;
+    });
+  });
+}
+
+$("#search").addEventListener("submit", async (ev) => {
+  ev.preventDefault();
+  const list = $("#candidates");
+  list.replaceChildren();
+  try {
+    const resp = await api("/v1/locations", { q: $("#query").value, limit: 5 });
+    if (resp.candidates.length === 0) {
+      const li = document.createElement("li");
+      li.className = "muted";
+      li.textContent = "No places found.";
+      list.append(li);
+    }
+    for (const c of resp.candidates) {
+      const li = document.createElement("li");
+      const button = document.createElement("button");
+      button.type = "button";
+      button.textContent = cityLabel(c);
+      button.addEventListener("click", () => {
+        list.replaceChildren();
+        $("#query").value = "";
+        cities.push({
+          name: c.name,
+          state: c.state,
+          country: c.country,
+          countryCode: c.countryCode,
+          latitude: c.latitude,
+          longitude: c.longitude,
+        });
+        saveCities();
+      });
+      li.append(button);
+      list.append(li);
+    }
+  } catch (err) {
+    const li = document.createElement("li");
+    li.className = "muted";
+    li.textContent = err.message;
+    list.append(li);
+  }
+});
+
+// Advice
+
+let adviceStream = null;
+
+$("#advise").addEventListener("click", () => {
+  if (adviceStream) adviceStream.close();
+  const out = $("#advice");
+  const status = $("#advice-status");
+  out.replaceChildren();
+  status.textContent = "Starting…";
+  $("#advise").disabled = true;
+
+  const params = {
+    city: cities.map((c) => [c.name, c.state, c.countryCode || c.country].filter(Boolean).join(",")),
+    units: store.get("units", ""),
+    format: "html",
+  };
+  // The server sends an escaped HTML fragment, so it is safe to render.
+  let html = "";
+  const es = new EventSource("/v1/advice/stream?" + query(params, true));
+  adviceStream = es;
+  const done = (message) => {
+    es.close();
+    adviceStream = null;
+    status.textContent = message;
+    $("#advise").disabled = cities.length === 0;
+  };
+  es.addEventListener("progress", (ev) => {
+    const p = JSON.parse(ev.data).progress;
+    if (p.stage === "KEEPALIVE") return;
+    status.textContent = enumLabel(p.stage, "").replace(/^./, (s) => s.toUpperCase()) + (p.location ? ": " + p.location : "");
+  });
+  es.addEventListener("chunk", (ev) => {
+    html += JSON.parse(ev.data).chunk;
+    out.innerHTML = html;
+    status.textContent = "Writing…";
+  });
+  es.addEventListener("complete", (ev) => {
+    const msg = JSON.parse(ev.data);
+    if (msg.chunk) {
+      html += msg.chunk;
+      out.innerHTML = html;
+    }
+    done(msg.fallback ? "The model was unavailable; this is template advice." : "");
+  });
+  es.addEventListener("error", (ev) => {
+    if (ev.data) {
+      done(JSON.parse(ev.data).message);
+    } else if (es.readyState === EventSource.CLOSED) {
+      done("Advice failed. Check the server log, or your API token in Settings.");
+    }
+  });
+});
+
+// Alerts
+
+async function loadAlerts() {
+  const body = $("#alerts tbody");
+  const status = $("#alerts-status");
+  try {
+    const resp = await api("/v1/alerts");
+    body.replaceChildren();
+    $("#alerts").hidden = resp.subscriptions.length === 0;
+    status.textContent = resp.subscriptions.length === 0 ? "No alert subscriptions." : "";
+    for (const sub of resp.subscriptions) {
+      const c = sub.condition || {};
+      const row = document.createElement("tr");
+      const cells = [
+        [sub.city.location, sub.city.country].filter(Boolean).join(", "),
+        [enumLabel(c.metric, "ALERT_METRIC_"), enumLabel(c.operator, "ALERT_OPERATOR_"), c.threshold, c.unit, enumLabel(c.window, "ALERT_WINDOW_")].filter((v) => v !== "").join(" "),
+        sub.recipient,
+        sub.lastAlertedDate || "never",
+      ];
+      for (const text of cells) {
+        const td = document.createElement("td");
+        td.textContent = text;
+        row.append(td);
+      }
+      body.append(row);
+    }
+  } catch (err) {
+    $("#alerts").hidden = true;
+    status.textContent = err.message;
+  }
+}
+
+function watchAlerts() {
+  const es = new EventSource("/v1/alerts/stream?" + query({}, true));
+  es.addEventListener("alert", (ev) => {
+    const a = JSON.parse(ev.data);
+    const li = document.createElement("li");
+    const when = document.createElement("time");
+    when.textContent = new Date(Number(a.time) * 1000).toLocaleString();
+    const title = document.createElement("strong");
+    title.textContent = a.title;
+    const desc = document.createElement("div");
+    desc.textContent = a.description + " on " + a.date;
+    li.append(when, title, desc);
+    $("#fired").prepend(li);
+    loadAlerts();
+  });
+  // A failed subscription, such as alerts being disabled, isn't retried.
+  es.addEventListener("error", () => {
+    if (es.readyState === EventSource.CLOSED) es.close();
+  });
+}
+
+// Settings
+
+$("#token").value = store.get("token", "");
+$("#token").addEventListener("change", (ev) => {
+  store.set("token", ev.target.value.trim());
+  renderCities();
+  loadAlerts();
+});
+$("#units").value = store.get("units", "");
+$("#units").addEventListener("change", (ev) => {
+  store.set("units", ev.target.value);
+  renderCities();
+});
+
+renderCities();
+loadAlerts();
+watchAlerts();
+setInterval(() => {
+  renderCities();
+  loadAlerts();
+}, refreshInterval);
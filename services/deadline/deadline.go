@@ -0,0 +1,106 @@
+// Package deadline gives RPCs a server-side time limit, so a client that
+// sets no deadline can't hold a handler, its upstream calls and its quota
+// forever.
+package deadline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+)
+
+var imposedDeadlines = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rpc_server_deadline_imposed_total",
+		Help: "RPCs whose deadline was set or shortened by the server",
+	},
+	[]string{"method"},
+)
+
+// Timeouts maps a service ("weather.WeatherService") or a method
+// ("advisor.AdvisorService/StreamAdvice") to its time limit. A method's
+// entry overrides its service's, and zero means no limit.
+type Timeouts map[string]time.Duration
+
+// Parse reads a comma-separated list of name=duration pairs, e.g.
+// "weather.WeatherService=15s,advisor.AdvisorService/ChatStream=0".
+func Parse(s string) (Timeouts, error) {
+	t := make(Timeouts)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		name = strings.Trim(strings.TrimSpace(name), "/")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("timeout %q is not name=duration", pair)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("timeout for %s: %v", name, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("timeout for %s must not be negative, got %s", name, d)
+		}
+		t[name] = d
+	}
+	return t, nil
+}
+
+// For returns the limit of a full gRPC method name such as
+// "/weather.WeatherService/GetCurrentWeather", or zero.
+func (t Timeouts) For(fullMethod string) time.Duration {
+	method := strings.TrimPrefix(fullMethod, "/")
+	if d, ok := t[method]; ok {
+		return d
+	}
+	service, _, _ := strings.Cut(method, "/")
+	return t[service]
+}
+
+// limit bounds ctx by the method's timeout. A shorter deadline set by the
+// client is kept.
+func (t Timeouts) limit(ctx context.Context, fullMethod string) (context.Context, context.CancelFunc) {
+	d := t.For(fullMethod)
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if dl, ok := ctx.Deadline(); ok && time.Until(dl) <= d {
+		return ctx, func() {}
+	}
+	imposedDeadlines.WithLabelValues(fullMethod).Inc()
+	return context.WithTimeout(ctx, d)
+}
+
+// UnaryServerInterceptor enforces the timeouts on unary RPCs. Install it
+// first after logging, so authentication is bounded too.
+func (t Timeouts) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := t.limit(ctx, info.FullMethod)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor enforces the timeouts on streaming RPCs. Leave
+// streams that are meant to stay open, such as dashboards, without one.
+func (t Timeouts) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := t.limit(ss.Context(), info.FullMethod)
+		defer cancel()
+		return handler(srv, &limitedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type limitedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *limitedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,114 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/notify"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var digestDeliveries = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "digest_deliveries_total",
+		Help: "Scheduled digests, by result",
+	},
+	[]string{"result"},
+)
+
+// lateLimit is how late a digest may still go out, e.g. after a restart.
+// Older digests are skipped rather than sending a morning digest at noon.
+const lateLimit = time.Hour
+
+// Generator produces the digest text for a subscription.
+type Generator func(ctx context.Context, sub *Subscription) (string, error)
+
+// Scheduler delivers the subscriptions that are due each time Tick is
+// called, which the server does every minute, the resolution of delivery
+// times.
+type Scheduler struct {
+	store    Store
+	generate Generator
+	notifier notify.Notifier
+}
+
+func NewScheduler(store Store, generate Generator, notifier notify.Notifier) *Scheduler {
+	return &Scheduler{store: store, generate: generate, notifier: notifier}
+}
+
+// Tick delivers the digests due now. Failed deliveries are logged and
+// retried on the next tick; only failing to read the subscriptions is
+// returned.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	now := time.Now()
+	subs, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing digest subscriptions failed: %v", err)
+	}
+	for _, sub := range subs {
+		since := sub.LastDelivered
+		if since.IsZero() {
+			since = sub.CreatedAt
+		}
+		due, err := sub.NextDelivery(since)
+		if err != nil {
+			slog.Error("digest schedule invalid", "subscription", sub.ID, "error", err)
+			continue
+		}
+		if now.Before(due) {
+			continue
+		}
+		if now.Sub(due) > lateLimit {
+			slog.Warn("skipping late digest delivery", "subscription", sub.ID, "due", due.Format(time.RFC3339))
+			digestDeliveries.WithLabelValues("skipped").Inc()
+			s.markDelivered(ctx, sub, now)
+			continue
+		}
+		// A failure leaves the digest due, so the next tick retries it
+		// until it is too late.
+		if err := s.deliver(ctx, sub, now); err != nil {
+			slog.Error("digest delivery failed", "subscription", sub.ID, "error", err)
+			digestDeliveries.WithLabelValues("failed").Inc()
+			continue
+		}
+		digestDeliveries.WithLabelValues("delivered").Inc()
+		s.markDelivered(ctx, sub, now)
+	}
+	return nil
+}
+
+func (s *Scheduler) deliver(ctx context.Context, sub *Subscription, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	text, err := s.generate(ctx, sub)
+	if err != nil {
+		return err
+	}
+	loc, _ := time.LoadLocation(sub.TimeZone)
+	return s.notifier.Notify(ctx, &notify.Message{
+		Kind:           notify.KindDigest,
+		SubscriptionID: sub.ID,
+		Recipient:      sub.Recipient,
+		Title:          "Weather digest for " + now.In(loc).Format("Monday, 2 January"),
+		Body:           text,
+		Time:           now,
+		Webhooks:       sub.Webhooks,
+	})
+}
+
+// markDelivered re-reads the subscription so a concurrent update or delete
+// isn't overwritten.
+func (s *Scheduler) markDelivered(ctx context.Context, sub *Subscription, now time.Time) {
+	current, err := s.store.Get(ctx, sub.ID)
+	if err != nil {
+		return
+	}
+	current.LastDelivered = now
+	if err := s.store.Save(ctx, current); err != nil {
+		slog.Error("saving digest subscription failed", "subscription", sub.ID, "error", err)
+	}
+}
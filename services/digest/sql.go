@@ -0,0 +1,125 @@
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/storage"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// SQLStore keeps subscriptions in the database, so digests keep going out
+// after a restart.
+type SQLStore struct {
+	db *storage.DB
+}
+
+func NewSQLStore(db *storage.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+func (s *SQLStore) Save(ctx context.Context, sub *Subscription) error {
+	req, err := protojson.Marshal(sub.Request)
+	if err != nil {
+		return fmt.Errorf("encode subscription %s: %v", sub.ID, err)
+	}
+	webhooks, err := json.Marshal(sub.Webhooks)
+	if err != nil {
+		return fmt.Errorf("encode subscription %s: %v", sub.ID, err)
+	}
+	_, err = s.db.Exec(ctx, `INSERT INTO digest_subscriptions (id, recipient, request, hour, minute, time_zone, created_at, last_delivered, webhooks)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET recipient = excluded.recipient, request = excluded.request, hour = excluded.hour,
+			minute = excluded.minute, time_zone = excluded.time_zone, created_at = excluded.created_at,
+			last_delivered = excluded.last_delivered, webhooks = excluded.webhooks`,
+		sub.ID, sub.Recipient, string(req), sub.Hour, sub.Minute, sub.TimeZone, unixMilli(sub.CreatedAt), unixMilli(sub.LastDelivered),
+		string(webhooks))
+	if err != nil {
+		return fmt.Errorf("write subscription %s: %v", sub.ID, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (*Subscription, error) {
+	sub, err := scanSubscription(s.db.QueryRow(ctx, `SELECT id, recipient, request, hour, minute, time_zone, created_at, last_delivered,
+		webhooks FROM digest_subscriptions WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read subscription %s: %v", id, err)
+	}
+	return sub, nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.Exec(ctx, `DELETE FROM digest_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete subscription %s: %v", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) List(ctx context.Context) ([]*Subscription, error) {
+	rows, err := s.db.Query(ctx, `SELECT id, recipient, request, hour, minute, time_zone, created_at, last_delivered,
+		webhooks FROM digest_subscriptions ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %v", err)
+	}
+	defer rows.Close()
+
+	var out []*Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list subscriptions: %v", err)
+		}
+		out = append(out, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list subscriptions: %v", err)
+	}
+	return out, nil
+}
+
+func scanSubscription(row interface{ Scan(...any) error }) (*Subscription, error) {
+	var sub Subscription
+	var req, webhooks string
+	var created, delivered int64
+	if err := row.Scan(&sub.ID, &sub.Recipient, &req, &sub.Hour, &sub.Minute, &sub.TimeZone, &created, &delivered, &webhooks); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(webhooks), &sub.Webhooks); err != nil {
+		return nil, fmt.Errorf("decode webhooks of %s: %v", sub.ID, err)
+	}
+	sub.Request = &advisorpb.AdvisorRequest{}
+	if err := protojson.Unmarshal([]byte(req), sub.Request); err != nil {
+		return nil, fmt.Errorf("decode request of %s: %v", sub.ID, err)
+	}
+	sub.CreatedAt = fromUnixMilli(created)
+	sub.LastDelivered = fromUnixMilli(delivered)
+	return &sub, nil
+}
+
+// unixMilli and fromUnixMilli store the zero time as 0.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,64 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/notify"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+)
+
+var ErrNotFound = errors.New("digest subscription not found")
+
+// Subscription asks for advice to be generated every day at a local time
+// and handed to the notifiers.
+type Subscription struct {
+	ID        string
+	Recipient string
+	// Request is the advice to generate. It is run as given, without a
+	// session.
+	Request *advisorpb.AdvisorRequest
+	// Hour and Minute are the delivery time in TimeZone.
+	Hour, Minute int
+	// TimeZone is an IANA name; empty means UTC.
+	TimeZone      string
+	CreatedAt     time.Time
+	LastDelivered time.Time
+	// Webhooks are chat channels the digest is posted to as well.
+	Webhooks []notify.Webhook
+}
+
+// ParseDeliveryTime parses "HH:MM" on a 24-hour clock.
+func ParseDeliveryTime(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("delivery time %q is not HH:MM", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// NextDelivery returns the first delivery time strictly after after.
+func (s *Subscription) NextDelivery(after time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown time zone %q", s.TimeZone)
+	}
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.Hour, s.Minute, 0, 0, loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, s.Hour, s.Minute, 0, 0, loc)
+	}
+	return next, nil
+}
+
+// Store keeps digest subscriptions.
+type Store interface {
+	// Save creates or replaces the subscription with sub.ID.
+	Save(ctx context.Context, sub *Subscription) error
+	Get(ctx context.Context, id string) (*Subscription, error)
+	Delete(ctx context.Context, id string) error
+	// List returns every subscription, oldest first.
+	List(ctx context.Context) ([]*Subscription, error)
+}
@@ -0,0 +1,223 @@
+// Package events publishes weather observations, generated advice and the
+// weather alerts that fire to a NATS subject or Kafka topic, so analytics
+// pipelines and other consumers can follow the server without calling it.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/alert"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var published = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "event_bus_events_total",
+		Help: "Events published to the event bus, by type and result",
+	},
+	[]string{"type", "result"},
+)
+
+// Event types. On NATS each is a subject under the configured one; on Kafka
+// they share the topic and are told apart by the type header and field.
+const (
+	TypeObservation = "observation"
+	TypeAdvice      = "advice"
+	TypeAlert       = "alert"
+)
+
+// Envelope is what is published for every event, as JSON.
+type Envelope struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Key groups related events: the location for observations and
+	// alerts, the session for advice. Kafka partitions by it.
+	Key  string          `json:"key,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Transport sends a batch of envelopes to a broker.
+type Transport interface {
+	Send(ctx context.Context, batch []*Envelope) error
+	Close() error
+}
+
+const (
+	// queueSize is how many events wait for the broker before new ones are
+	// dropped.
+	queueSize = 1024
+	// maxBatch is how many queued events are sent together.
+	maxBatch = 100
+	// sendTimeout bounds one batch.
+	sendTimeout = 10 * time.Second
+)
+
+// Bus queues events and sends them in the background, so a slow or
+// unreachable broker never holds up a request. Events that don't fit in the
+// queue are dropped and counted; consumers needing every event should use
+// the stores instead. A nil Bus drops everything silently.
+type Bus struct {
+	transport Transport
+	done      chan struct{}
+
+	// mu guards queue against Publish after Close.
+	mu     sync.RWMutex
+	queue  chan *Envelope
+	closed bool
+}
+
+func New(transport Transport) *Bus {
+	b := &Bus{transport: transport, queue: make(chan *Envelope, queueSize), done: make(chan struct{})}
+	go b.run()
+	return b
+}
+
+// Publish queues an event of type eventType with data encoded as JSON.
+func (b *Bus) Publish(eventType, key string, data any) {
+	if b == nil {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("encoding event failed", "type", eventType, "error", err)
+		published.WithLabelValues(eventType, "failed").Inc()
+		return
+	}
+	env := &Envelope{ID: uuid.NewString(), Type: eventType, Time: time.Now().UTC(), Key: key, Data: raw}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		published.WithLabelValues(eventType, "dropped").Inc()
+		return
+	}
+	select {
+	case b.queue <- env:
+	default:
+		published.WithLabelValues(eventType, "dropped").Inc()
+	}
+}
+
+func (b *Bus) run() {
+	defer close(b.done)
+	for env := range b.queue {
+		batch := []*Envelope{env}
+	fill:
+		for len(batch) < maxBatch {
+			select {
+			case env, ok := <-b.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, env)
+			default:
+				break fill
+			}
+		}
+		b.send(batch)
+	}
+}
+
+func (b *Bus) send(batch []*Envelope) {
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	result := "published"
+	if err := b.transport.Send(ctx, batch); err != nil {
+		slog.Error("publishing events failed", "events", len(batch), "error", err)
+		result = "failed"
+	}
+	for _, env := range batch {
+		published.WithLabelValues(env.Type, result).Inc()
+	}
+}
+
+// Close sends the events still queued, waiting up to timeout, and closes
+// the connection to the broker.
+func (b *Bus) Close(timeout time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-time.After(timeout):
+		slog.Warn("event bus closed with events unsent", "events", len(b.queue))
+	}
+	if err := b.transport.Close(); err != nil {
+		slog.Warn("closing event bus failed", "error", err)
+	}
+}
+
+// Advice is the data of an advice event.
+type Advice struct {
+	// ID is the advice's history record, when history is enabled.
+	ID        string   `json:"id,omitempty"`
+	SessionID string   `json:"session_id,omitempty"`
+	Model     string   `json:"model"`
+	Cities    []string `json:"cities"`
+	// Advice is Markdown.
+	Advice string `json:"advice"`
+	// Streamed is set for StreamAdvice, unset for GetAdvice.
+	Streamed bool `json:"streamed"`
+}
+
+// Alert is the data of an alert event.
+type Alert struct {
+	SubscriptionID string  `json:"subscription_id"`
+	Recipient      string  `json:"recipient,omitempty"`
+	Location       string  `json:"location"`
+	Metric         string  `json:"metric"`
+	Operator       string  `json:"operator"`
+	Threshold      float64 `json:"threshold"`
+	Unit           string  `json:"unit,omitempty"`
+	Window         string  `json:"window"`
+	Date           string  `json:"date"`
+	Value          float64 `json:"value"`
+	Description    string  `json:"description"`
+	Title          string  `json:"title"`
+	// Body is Markdown.
+	Body string `json:"body"`
+}
+
+// PublishAlerts publishes the alerts that fire until ctx is done.
+func (b *Bus) PublishAlerts(ctx context.Context, feed *alert.Feed) {
+	fired, cancel := feed.Subscribe(64)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-fired:
+			sub, cond := ev.Subscription, ev.Subscription.Condition
+			b.Publish(TypeAlert, sub.Location, Alert{
+				SubscriptionID: sub.ID,
+				Recipient:      sub.Recipient,
+				Location:       sub.Location,
+				Metric:         string(cond.Metric),
+				Operator:       string(cond.Operator),
+				Threshold:      cond.Threshold,
+				Unit:           cond.Unit,
+				Window:         string(cond.Window),
+				Date:           ev.Date,
+				Value:          ev.Value,
+				Description:    ev.Description,
+				Title:          ev.Title,
+				Body:           ev.Body,
+			})
+		}
+	}
+}
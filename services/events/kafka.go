@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka publishes every event to one topic, keyed by the event's key so
+// events about one location stay in order, with the type in a "type"
+// header.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka writes to topic on brokers. The topic must exist; it isn't
+// created.
+func NewKafka(brokers []string, topic string) *Kafka {
+	return &Kafka{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// The bus batches already; don't wait for more.
+		BatchTimeout: 10 * time.Millisecond,
+		BatchSize:    maxBatch,
+	}}
+}
+
+func (k *Kafka) Send(ctx context.Context, batch []*Envelope) error {
+	msgs := make([]kafka.Message, 0, len(batch))
+	for _, env := range batch {
+		payload, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:     []byte(env.Key),
+			Value:   payload,
+			Headers: []kafka.Header{{Key: "type", Value: []byte(env.Type)}},
+			Time:    env.Time,
+		})
+	}
+	return k.writer.WriteMessages(ctx, msgs...)
+}
+
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes each event to <subject>.<type>, e.g. weather.observation,
+// so consumers can subscribe to one type or to <subject>.>.
+type NATS struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATS connects to the servers in url, a comma-separated list such as
+// nats://localhost:4222. Credentials may be given in the URL. The
+// connection is re-made in the background when it drops.
+func NewNATS(url, subject, name string) (*NATS, error) {
+	conn, err := nats.Connect(url,
+		nats.Name(name),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			slog.Warn("NATS connection lost, reconnecting", "error", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			slog.Info("reconnected to NATS", "server", c.ConnectedUrlRedacted())
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to NATS: %v", err)
+	}
+	return &NATS{conn: conn, subject: subject}, nil
+}
+
+func (n *NATS) Send(ctx context.Context, batch []*Envelope) error {
+	if !n.conn.IsConnected() {
+		return fmt.Errorf("not connected to NATS")
+	}
+	for _, env := range batch {
+		payload, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		msg := nats.NewMsg(n.subject + "." + env.Type)
+		msg.Header.Set(nats.MsgIdHdr, env.ID)
+		msg.Data = payload
+		if err := n.conn.PublishMsg(msg); err != nil {
+			return err
+		}
+	}
+	return n.conn.FlushWithContext(ctx)
+}
+
+func (n *NATS) Close() error {
+	return n.conn.Drain()
+}
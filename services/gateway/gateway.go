@@ -0,0 +1,282 @@
+// Package gateway serves the unary weather and advisor RPCs as HTTP/JSON
+// through grpc-gateway, with the streaming RPCs bridged to WebSockets and
+// Server-Sent Events by hand. Requests are forwarded over a gRPC
+// connection to the server itself, so they go through the same
+// interceptors (logging, auth, identity) as any other client.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"github.com/pixperk/effinarounf/services/requestid"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxBodyBytes caps POST bodies. Advice requests are at most ten cities.
+const maxBodyBytes = 1 << 20
+
+// accessTokenParam is the query parameter a bearer token may be passed in
+// on the streaming routes.
+const accessTokenParam = "access_token"
+
+// forwardedHeaders are copied from the HTTP request into the RPC metadata.
+// The request ID is handled separately by outgoingContext.
+var forwardedHeaders = []string{"authorization", "traceparent", "tracestate", "baggage"}
+
+// queryAliases are the short query parameter names the GET routes accept,
+// by request message. WeatherRequest serves both /v1/weather and
+// /v1/air-quality.
+var queryAliases = map[protoreflect.FullName]map[string]string{
+	"weather.WeatherRequest":         {"lat": "latitude", "lon": "longitude", "country": "country_code", "units": "unit_system"},
+	"weather.DailyForecastRequest":   {"lat": "latitude", "lon": "longitude", "start": "start_date", "end": "end_date"},
+	"weather.HourlyForecastRequest":  {"lat": "latitude", "lon": "longitude"},
+	"advisor.SearchLocationsRequest": {"q": "query"},
+}
+
+var (
+	marshaler   = protojson.MarshalOptions{EmitUnpopulated: true}
+	unmarshaler = protojson.UnmarshalOptions{}
+)
+
+// NewHandler returns a handler serving the unary routes bound in
+// shared/proto/gateway.yaml by calling conn, the advice WebSocket at
+// /v1/advice/ws, advice Server-Sent Events at /v1/advice/stream, alerts as
+// Server-Sent Events at /v1/alerts/stream, the OpenAPI document at
+// /openapi.json and Swagger UI at /docs/.
+func NewHandler(conn grpc.ClientConnInterface) (http.Handler, error) {
+	gw := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{MarshalOptions: marshaler, UnmarshalOptions: unmarshaler}),
+		runtime.WithIncomingHeaderMatcher(forwardedHeader),
+		runtime.SetQueryParameterParser(queryParser{}),
+	)
+	ctx := context.Background()
+	if err := weatherpb.RegisterWeatherServiceHandlerClient(ctx, gw, weatherpb.NewWeatherServiceClient(conn)); err != nil {
+		return nil, fmt.Errorf("registering weather routes: %v", err)
+	}
+	if err := advisorpb.RegisterAdvisorServiceHandlerClient(ctx, gw, advisorpb.NewAdvisorServiceClient(conn)); err != nil {
+		return nil, fmt.Errorf("registering advisor routes: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		// grpc-gateway forwards the request ID header like any other, so
+		// a generated one is set on the request itself.
+		r.Header.Set(requestid.Header, requestID(w, r))
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		gw.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(OpenAPI())
+	})
+	mux.HandleFunc("GET /docs/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, swaggerUI)
+	})
+	mux.Handle("GET /docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently))
+	mux.Handle("GET /v1/advice/ws", &adviceSocket{client: advisorpb.NewAdvisorServiceClient(conn)})
+	mux.Handle("GET /v1/advice/stream", &adviceEvents{client: advisorpb.NewAdvisorServiceClient(conn)})
+	mux.Handle("GET /v1/alerts/stream", &alertEvents{client: advisorpb.NewAdvisorServiceClient(conn)})
+	return mux, nil
+}
+
+// forwardedHeader passes the request ID and trace headers on as RPC
+// metadata. grpc-gateway forwards Authorization itself.
+func forwardedHeader(key string) (string, bool) {
+	key = strings.ToLower(key)
+	if key == requestid.Header || (key != "authorization" && slices.Contains(forwardedHeaders, key)) {
+		return key, true
+	}
+	return "", false
+}
+
+// queryParser fills GET requests the way the gateway always has: short
+// names from queryAliases, enums by their short name ("metric" for
+// UNIT_SYSTEM_METRIC), and unknown parameters rejected rather than
+// ignored. The rest is grpc-gateway's parser.
+type queryParser struct{}
+
+func (queryParser) Parse(msg proto.Message, values url.Values, filter *utilities.DoubleArray) error {
+	desc := msg.ProtoReflect().Descriptor()
+	canonical, err := canonicalQuery(desc, values, queryAliases[desc.FullName()])
+	if err != nil {
+		return err
+	}
+	return (&runtime.DefaultQueryParser{}).Parse(msg, canonical, filter)
+}
+
+// canonicalQuery renames the parameters of values to the proto names of
+// desc's fields and enum values to their full names.
+func canonicalQuery(desc protoreflect.MessageDescriptor, values url.Values, aliases map[string]string) (url.Values, error) {
+	out := make(url.Values, len(values))
+	fields := desc.Fields()
+	for key, vals := range values {
+		name := key
+		if alias, ok := aliases[key]; ok {
+			name = alias
+		}
+		fd := fields.ByJSONName(name)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(name))
+		}
+		if fd == nil || fd.Message() != nil || fd.IsMap() {
+			return nil, fmt.Errorf("unknown query parameter %q", key)
+		}
+		for _, raw := range vals {
+			if fd.Kind() == protoreflect.EnumKind {
+				v, err := parseScalar(fd, raw)
+				if err != nil {
+					return nil, fmt.Errorf("query parameter %q: %v", key, err)
+				}
+				raw = string(fd.Enum().Values().ByNumber(v.Enum()).Name())
+			}
+			out.Add(string(fd.Name()), raw)
+		}
+	}
+	return out, nil
+}
+
+// outgoingContext carries the forwarded headers of r as RPC metadata.
+func outgoingContext(ctx context.Context, w http.ResponseWriter, r *http.Request) context.Context {
+	md := metadata.Pairs(requestid.Header, requestID(w, r))
+	for _, name := range forwardedHeaders {
+		if v := r.Header.Values(name); len(v) > 0 {
+			md.Set(name, v...)
+		}
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// requestID returns the caller's request ID, or a new one, and answers
+// with it in the response header.
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	id := requestid.Sanitize(r.Header.Get(requestid.Header))
+	if id == "" {
+		id = requestid.New()
+	}
+	w.Header().Set(requestid.Header, id)
+	return id
+}
+
+// streamContext is outgoingContext for the WebSocket and Server-Sent Events
+// routes. Browsers can't set headers on those requests, so a bearer token
+// may also come as the access_token query parameter. Other routes don't
+// accept it, keeping tokens out of the URLs of ordinary requests.
+func streamContext(ctx context.Context, w http.ResponseWriter, r *http.Request) context.Context {
+	ctx = outgoingContext(ctx, w, r)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if token := r.URL.Query().Get(accessTokenParam); token != "" && len(md.Get("authorization")) == 0 {
+		md.Set("authorization", "Bearer "+token)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// bindQuery sets req's scalar fields from query parameters, matched by the
+// field's JSON or proto name after aliases are applied.
+func bindQuery(req proto.Message, query url.Values, aliases map[string]string) error {
+	msg := req.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for key, values := range query {
+		name := key
+		if alias, ok := aliases[key]; ok {
+			name = alias
+		}
+		fd := fields.ByJSONName(name)
+		if fd == nil {
+			fd = fields.ByName(protoreflect.Name(name))
+		}
+		if fd == nil || fd.Message() != nil || fd.IsMap() {
+			return fmt.Errorf("unknown query parameter %q", key)
+		}
+		for _, raw := range values {
+			v, err := parseScalar(fd, raw)
+			if err != nil {
+				return fmt.Errorf("query parameter %q: %v", key, err)
+			}
+			if fd.IsList() {
+				msg.Mutable(fd).List().Append(v)
+			} else {
+				msg.Set(fd, v)
+			}
+		}
+	}
+	return nil
+}
+
+// parseScalar converts a query parameter to the field's type. Enums take the
+// value's name, with or without the enum's prefix ("metric" for
+// UNIT_SYSTEM_METRIC), or its number.
+func parseScalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(raw)
+		return protoreflect.ValueOfBool(b), err
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(raw, 64)
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(raw, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(raw, 10, 32)
+		return protoreflect.ValueOfInt32(int32(n)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return protoreflect.ValueOfInt64(n), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(raw, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(n)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		return protoreflect.ValueOfUint64(n), err
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		upper := strings.ToUpper(raw)
+		for i := 0; i < values.Len(); i++ {
+			name := string(values.Get(i).Name())
+			if name == upper || strings.HasSuffix(name, "_"+upper) {
+				return protoreflect.ValueOfEnum(values.Get(i).Number()), nil
+			}
+		}
+		if n, err := strconv.ParseInt(raw, 10, 32); err == nil && values.ByNumber(protoreflect.EnumNumber(n)) != nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("unknown %s value %q", fd.Enum().Name(), raw)
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field type %s", fd.Kind())
+	}
+}
+
+// writeError sends a gRPC status as {"code", "message", "details"} with
+// the HTTP status grpc-gateway uses for it, so the streaming routes fail
+// like the unary ones.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body, mErr := marshaler.Marshal(st.Proto())
+	if mErr != nil {
+		body, _ = json.Marshal(map[string]any{"code": int(codes.Internal), "message": mErr.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	w.Write(body)
+}
@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pixperk/effinarounf/services/requestid"
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeWeather struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	got *weatherpb.WeatherRequest
+	md  metadata.MD
+}
+
+func (f *fakeWeather) GetCurrentWeather(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.WeatherResponse, error) {
+	f.got = req
+	f.md, _ = metadata.FromIncomingContext(ctx)
+	return &weatherpb.WeatherResponse{Temperature: 18.5, Description: "light rain", Humidity: 82}, nil
+}
+
+type fakeAdvisor struct {
+	advisorpb.UnimplementedAdvisorServiceServer
+	got *advisorpb.AdvisorRequest
+}
+
+func (f *fakeAdvisor) GetAdvice(ctx context.Context, req *advisorpb.AdvisorRequest) (*advisorpb.AdvisorResponse, error) {
+	f.got = req
+	if len(req.Cities) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one city is required")
+	}
+	return &advisorpb.AdvisorResponse{Advice: "Take an umbrella."}, nil
+}
+
+// newTestGateway serves the gateway over an in-memory gRPC connection to
+// the fakes.
+func newTestGateway(t *testing.T) (*httptest.Server, *fakeWeather, *fakeAdvisor) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	weather, advisor := &fakeWeather{}, &fakeAdvisor{}
+	weatherpb.RegisterWeatherServiceServer(s, weather)
+	advisorpb.RegisterAdvisorServiceServer(s, advisor)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	handler, err := NewHandler(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv, weather, advisor
+}
+
+func TestGetWeather(t *testing.T) {
+	srv, weather, _ := newTestGateway(t)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/weather?lat=51.5&lon=-0.13&units=imperial", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if resp.Header.Get(requestid.Header) == "" {
+		t.Error("no request ID in the response")
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["description"] != "light rain" || body["temperature"] != 18.5 {
+		t.Errorf("body = %v", body)
+	}
+
+	if weather.got.Latitude != 51.5 || weather.got.Longitude != -0.13 {
+		t.Errorf("coordinates = %v, %v, want 51.5, -0.13", weather.got.Latitude, weather.got.Longitude)
+	}
+	if weather.got.UnitSystem != weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL {
+		t.Errorf("unit system = %v, want imperial", weather.got.UnitSystem)
+	}
+	if got := weather.md.Get("authorization"); len(got) != 1 || got[0] != "Bearer secret" {
+		t.Errorf("authorization metadata = %q", got)
+	}
+	if got := weather.md.Get(requestid.Header); len(got) != 1 || got[0] != resp.Header.Get(requestid.Header) {
+		t.Errorf("request ID metadata = %q, response header %q", got, resp.Header.Get(requestid.Header))
+	}
+}
+
+func TestGetWeatherRejectsUnknownParameters(t *testing.T) {
+	srv, weather, _ := newTestGateway(t)
+
+	// access_token is only taken on the streaming routes.
+	for _, query := range []string{"lat=1&lon=2&altitude=3", "lat=1&lon=2&access_token=secret", "lat=north"} {
+		resp, err := http.Get(srv.URL + "/v1/weather?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, resp.StatusCode)
+		}
+	}
+	if weather.got != nil {
+		t.Errorf("RPC called with %v", weather.got)
+	}
+}
+
+func TestPostAdvice(t *testing.T) {
+	srv, _, advisor := newTestGateway(t)
+
+	resp, err := http.Post(srv.URL+"/v1/advice", "application/json",
+		strings.NewReader(`{"cities": [{"location": "London"}], "unitSystem": "metric"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["advice"] != "Take an umbrella." {
+		t.Errorf("advice = %v", body["advice"])
+	}
+	if len(advisor.got.Cities) != 1 || advisor.got.Cities[0].Location != "London" || advisor.got.UnitSystem != "metric" {
+		t.Errorf("request = %v", advisor.got)
+	}
+}
+
+func TestPostAdviceInvalidArgument(t *testing.T) {
+	srv, _, _ := newTestGateway(t)
+
+	resp, err := http.Post(srv.URL+"/v1/advice", "application/json", strings.NewReader(`{"cities": []}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if codes.Code(body.Code) != codes.InvalidArgument || body.Message != "at least one city is required" {
+		t.Errorf("error = %+v", body)
+	}
+}
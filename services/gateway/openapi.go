@@ -0,0 +1,35 @@
+package gateway
+
+import _ "embed"
+
+// openAPI is generated by protoc-gen-openapiv2 from the protos and the
+// HTTP rules in shared/proto/gateway.yaml; see the gen target.
+//
+//go:embed gateway.swagger.json
+var openAPI []byte
+
+// OpenAPI returns the OpenAPI 2.0 document describing the unary routes.
+func OpenAPI() []byte {
+	return openAPI
+}
+
+// swaggerUI loads Swagger UI from a CDN and points it at /openapi.json, so
+// the binary doesn't have to carry the UI's assets.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Weather Advisor API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
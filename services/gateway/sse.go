@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cityParam is the repeated query parameter naming the cities to advise on.
+const cityParam = "city"
+
+// adviceEvents streams StreamAdvice as Server-Sent Events. Each
+// StreamAdviceResponse is one event, named progress, chunk or complete,
+// whose data is the message in proto JSON and whose id is
+// "<stream_id>:<sequence>". An EventSource that reconnects sends the last
+// id back and gets the rest of that stream from ResendChunks instead of a
+// new generation; once nothing is left the answer is 204, which stops the
+// browser from reconnecting.
+type adviceEvents struct {
+	client advisorpb.AdvisorServiceClient
+}
+
+func (a *adviceEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := streamContext(r.Context(), w, r)
+
+	var stream grpc.ServerStreamingClient[advisorpb.StreamAdviceResponse]
+	var err error
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		id, seq, ok := parseEventID(last)
+		if !ok {
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid Last-Event-ID %q", last))
+			return
+		}
+		stream, err = a.client.ResendChunks(ctx, &advisorpb.ResendChunksRequest{StreamId: id, FromSequence: seq + 1})
+	} else {
+		req, qErr := adviceQuery(r.URL.Query())
+		if qErr != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "%v", qErr))
+			return
+		}
+		stream, err = a.client.StreamAdvice(ctx, req)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Failures before the first message get a plain HTTP error, which an
+	// EventSource does not retry.
+	msg, err := stream.Recv()
+	if err == io.EOF {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Stop nginx and similar proxies from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	rc := http.NewResponseController(w)
+	for {
+		if err := writeEvent(w, msg); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		msg, err = stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if r.Context().Err() == nil {
+				data, _ := marshaler.Marshal(status.Convert(err).Proto())
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+				rc.Flush()
+			}
+			return
+		}
+	}
+}
+
+func writeEvent(w io.Writer, msg *advisorpb.StreamAdviceResponse) error {
+	name := "chunk"
+	switch {
+	case msg.IsComplete:
+		name = "complete"
+	case msg.Progress != nil:
+		name = "progress"
+	}
+	data, err := marshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	// protojson output has no raw newlines, so one data line is enough.
+	_, err = fmt.Fprintf(w, "id: %s:%d\nevent: %s\ndata: %s\n\n", msg.StreamId, msg.Sequence, name, data)
+	return err
+}
+
+// alertEvents streams SubscribeAlerts as Server-Sent Events named alert,
+// filtered by the subscription_ids and recipient query parameters.
+// Keepalives become SSE comments, which EventSource ignores.
+type alertEvents struct {
+	client advisorpb.AdvisorServiceClient
+}
+
+func (a *alertEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := streamContext(r.Context(), w, r)
+	req := &advisorpb.SubscribeAlertsRequest{}
+	query := r.URL.Query()
+	query.Del(accessTokenParam)
+	if err := bindQuery(req, query, map[string]string{"subscription_id": "subscription_ids"}); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "%v", err))
+		return
+	}
+	stream, err := a.client.SubscribeAlerts(ctx, req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// The server sends headers once the subscription is in place, so
+	// failures such as alerts being disabled still get a plain HTTP error.
+	if _, err := stream.Header(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	rc.Flush()
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF && r.Context().Err() == nil {
+				data, _ := marshaler.Marshal(status.Convert(err).Proto())
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+				rc.Flush()
+			}
+			return
+		}
+		if ev.Keepalive {
+			io.WriteString(w, ": keepalive\n\n")
+		} else {
+			data, err := marshaler.Marshal(ev)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: alert\ndata: %s\n\n", data)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func parseEventID(id string) (string, uint64, bool) {
+	streamID, seq, ok := strings.Cut(id, ":")
+	if !ok || streamID == "" {
+		return "", 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return streamID, n, err == nil
+}
+
+// adviceQuery builds an AdvisorRequest from query parameters. Each city is
+// "name", "name,country" or "name,state,country"; the other parameters
+// bind to the request's scalar fields, e.g. unit_system or model.
+func adviceQuery(query url.Values) (*advisorpb.AdvisorRequest, error) {
+	req := &advisorpb.AdvisorRequest{}
+	for _, c := range query[cityParam] {
+		parts := strings.Split(c, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		city := &advisorpb.CityData{Location: parts[0]}
+		switch len(parts) {
+		case 1:
+		case 2:
+			city.Country = parts[1]
+		case 3:
+			city.State, city.Country = parts[1], parts[2]
+		default:
+			return nil, fmt.Errorf("city %q: want name[,state],country", c)
+		}
+		req.Cities = append(req.Cities, city)
+	}
+	if len(req.Cities) == 0 {
+		return nil, fmt.Errorf("at least one %s parameter is required", cityParam)
+	}
+	rest := url.Values{}
+	for key, values := range query {
+		if key != cityParam && key != accessTokenParam {
+			rest[key] = values
+		}
+	}
+	if err := bindQuery(req, rest, map[string]string{"units": "unit_system"}); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -0,0 +1,196 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// pingInterval is how often the server pings an idle-looking client.
+	pingInterval = 30 * time.Second
+	// readTimeout drops a client that has sent nothing, not even a pong,
+	// for two ping intervals. It also bounds the wait for the request.
+	readTimeout = 2 * pingInterval
+	// closeTimeout is how long the server waits for the client's close
+	// frame after sending its own.
+	closeTimeout = 5 * time.Second
+)
+
+var socketSessions = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_websocket_sessions_total",
+		Help: "Advice WebSocket sessions by how they ended",
+	},
+	[]string{"result"},
+)
+
+// adviceSocket bridges StreamAdvice to a WebSocket. The client sends one
+// text message, an AdvisorRequest in proto JSON, and receives every
+// StreamAdviceResponse as a text message in the same encoding. A
+// completed stream ends with close code 1000. A failed one sends
+// {"error": {"code", "message", "details"}} and closes with 4000 plus the
+// gRPC code, e.g. 4003 for INVALID_ARGUMENT. Closing the socket cancels the
+// RPC.
+type adviceSocket struct {
+	client advisorpb.AdvisorServiceClient
+}
+
+func (a *adviceSocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The request context is not cancelled for hijacked connections; the
+	// reader below cancels when the client goes away.
+	ctx, cancel := context.WithCancel(streamContext(context.WithoutCancel(r.Context()), w, r))
+	defer cancel()
+
+	ws, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.conn.Close()
+
+	requests := make(chan []byte, 1)
+	peerGone := make(chan error, 1)
+	go func() {
+		defer cancel()
+		peerGone <- readLoop(ws, requests)
+	}()
+
+	var body []byte
+	select {
+	case body = <-requests:
+	case err := <-peerGone:
+		socketSessions.WithLabelValues(endReason(err)).Inc()
+		return
+	}
+
+	req := &advisorpb.AdvisorRequest{}
+	if err := unmarshaler.Unmarshal(body, req); err != nil {
+		a.fail(ws, status.Errorf(codes.InvalidArgument, "decoding request: %v", err), peerGone)
+		return
+	}
+
+	stop := keepalive(ws, ctx.Done())
+	defer stop()
+
+	stream, err := a.client.StreamAdvice(ctx, req)
+	if err != nil {
+		a.fail(ws, err, peerGone)
+		return
+	}
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			ws.writeClose(closeNormal, "")
+			awaitClose(peerGone)
+			socketSessions.WithLabelValues("completed").Inc()
+			return
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// The client closed or vanished; the RPC was cancelled.
+				socketSessions.WithLabelValues(endReason(<-peerGone)).Inc()
+				return
+			}
+			a.fail(ws, err, peerGone)
+			return
+		}
+		data, err := marshaler.Marshal(msg)
+		if err != nil {
+			a.fail(ws, status.Errorf(codes.Internal, "encoding response: %v", err), peerGone)
+			return
+		}
+		if err := ws.writeText(data); err != nil {
+			socketSessions.WithLabelValues("write_failed").Inc()
+			return
+		}
+	}
+}
+
+// fail reports err to the client and closes the socket.
+func (a *adviceSocket) fail(ws *wsConn, err error, peerGone <-chan error) {
+	st := status.Convert(err)
+	detail, mErr := marshaler.Marshal(st.Proto())
+	if mErr == nil {
+		ws.writeText([]byte(`{"error":` + string(detail) + `}`))
+	}
+	ws.writeClose(4000+int(st.Code()), st.Message())
+	awaitClose(peerGone)
+	socketSessions.WithLabelValues("failed").Inc()
+}
+
+// readLoop reads client frames until the connection ends, handing the
+// first data message to requests. Later messages are ignored; the protocol
+// has nothing for the client to say once the stream has started.
+func readLoop(ws *wsConn, requests chan<- []byte) error {
+	extend := func() { ws.conn.SetReadDeadline(time.Now().Add(readTimeout)) }
+	extend()
+	first := true
+	for {
+		opcode, payload, err := ws.readMessage(extend)
+		if err != nil {
+			return err
+		}
+		if !first {
+			continue
+		}
+		if opcode != opText {
+			return ws.fail(closeUnsupported, "expected a text message")
+		}
+		first = false
+		requests <- payload
+	}
+}
+
+// keepalive pings the client every pingInterval until done is closed or
+// stop is called.
+func keepalive(ws *wsConn, done <-chan struct{}) (stop func()) {
+	stopped := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+				if err := ws.writeFrame(opPing, nil); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(stopped) }
+}
+
+// awaitClose gives the client closeTimeout to answer our close frame.
+func awaitClose(peerGone <-chan error) {
+	select {
+	case <-peerGone:
+	case <-time.After(closeTimeout):
+	}
+}
+
+// endReason labels how the client side of a session ended.
+func endReason(err error) string {
+	var ce *closeError
+	switch {
+	case errors.As(err, &ce):
+		return "client_closed"
+	case errors.Is(err, io.EOF):
+		return "disconnected"
+	default:
+		slog.Debug("websocket read failed", "error", err)
+		return "read_failed"
+	}
+}
@@ -0,0 +1,275 @@
+package gateway
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The server side of RFC 6455, as much as the advice bridge needs: text
+// messages, ping/pong and the closing handshake. Extensions and
+// subprotocols are not negotiated.
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes.
+const (
+	closeNormal      = 1000
+	closeProtocol    = 1002
+	closeUnsupported = 1003
+	// closeNoStatus is reported for a close frame without a code; it is
+	// never sent.
+	closeNoStatus = 1005
+	closeTooBig   = 1009
+)
+
+// maxControlPayload is the largest ping, pong or close payload.
+const maxControlPayload = 125
+
+// maxMessageBytes caps a client message; the only one expected is the
+// advice request.
+const maxMessageBytes = 1 << 20
+
+// websocketGUID is appended to the client key to compute the accept key.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var errClosed = errors.New("websocket closed")
+
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+
+	// mu serializes frame writes from the bridge and the keepalive.
+	mu sync.Mutex
+	// closeSent is set once a close frame has been written; nothing may
+	// follow it.
+	closeSent bool
+}
+
+// closeError is a close frame received from the client.
+type closeError struct {
+	code   int
+	reason string
+}
+
+func (e *closeError) Error() string {
+	return fmt.Sprintf("websocket closed by peer: %d %s", e.code, e.reason)
+}
+
+// upgrade performs the opening handshake and takes over the connection.
+// Headers already set on w are sent with the 101 response. On failure it
+// has already written an HTTP error response.
+func upgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "expected a WebSocket upgrade request", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("not a websocket upgrade")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("unsupported websocket version %q", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, fmt.Errorf("invalid websocket key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	header := w.Header().Clone()
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijacking connection: %v", err)
+	}
+	// The HTTP server's deadlines no longer apply once hijacked.
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	header.Write(rw)
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, br: rw.Reader}, nil
+}
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeFrame sends one unfragmented, unmasked frame, as servers must.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closeSent {
+		return errClosed
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	header := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *wsConn) writeText(payload []byte) error {
+	return c.writeFrame(opText, payload)
+}
+
+// writeClose starts (or answers) the closing handshake. The reason is cut
+// to fit a control frame; closeNoStatus sends a close frame without a code.
+func (c *wsConn) writeClose(code int, reason string) error {
+	if code == closeNoStatus {
+		return c.writeFrame(opClose, nil)
+	}
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > maxControlPayload {
+		payload = payload[:maxControlPayload]
+	}
+	return c.writeFrame(opClose, payload)
+}
+
+// readMessage returns the next data message, answering pings and calling
+// onFrame for every frame received so the caller can track liveness. A
+// close frame from the client is answered and returned as *closeError.
+func (c *wsConn) readMessage(onFrame func()) (byte, []byte, error) {
+	var message []byte
+	var messageOp byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		onFrame()
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil && err != errClosed {
+				return 0, nil, err
+			}
+		case opPong:
+		case opClose:
+			ce := &closeError{code: closeNoStatus}
+			if len(payload) >= 2 {
+				ce.code = int(binary.BigEndian.Uint16(payload))
+				ce.reason = string(payload[2:])
+			}
+			c.writeClose(ce.code, "")
+			return 0, nil, ce
+		case opText, opBinary, opContinuation:
+			if (opcode == opContinuation) != (messageOp != 0) {
+				return 0, nil, c.fail(closeProtocol, "unexpected continuation frame")
+			}
+			if opcode != opContinuation {
+				messageOp = opcode
+			}
+			if len(message)+len(payload) > maxMessageBytes {
+				return 0, nil, c.fail(closeTooBig, "message too large")
+			}
+			message = append(message, payload...)
+			if fin {
+				return messageOp, message, nil
+			}
+		default:
+			return 0, nil, c.fail(closeProtocol, "unknown opcode")
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(closeProtocol, "reserved bits set")
+	}
+	opcode = head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(closeProtocol, "client frames must be masked")
+	}
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= opClose && (length > maxControlPayload || !fin) {
+		return false, 0, nil, c.fail(closeProtocol, "invalid control frame")
+	}
+	if length > maxMessageBytes {
+		return false, 0, nil, c.fail(closeTooBig, "message too large")
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// fail closes the connection with a protocol error and returns it.
+func (c *wsConn) fail(code int, reason string) error {
+	c.writeClose(code, reason)
+	return fmt.Errorf("websocket protocol error: %s", reason)
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileStore appends records to a JSON Lines file so history survives
+// restarts. Records are also kept in memory (up to max) for reads; the file
+// itself is never rewritten.
+type FileStore struct {
+	mu   sync.Mutex
+	file *os.File
+	mem  *MemoryStore
+}
+
+func NewFileStore(path string, max int) (*FileStore, error) {
+	mem := NewMemoryStore(max)
+
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			var rec Record
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				continue
+			}
+			mem.add(&rec)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read history file failed: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("open history file failed: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open history file failed: %v", err)
+	}
+	return &FileStore{file: file, mem: mem}, nil
+}
+
+func (f *FileStore) Save(ctx context.Context, rec *Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record failed: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write history failed: %v", err)
+	}
+	return f.mem.Save(ctx, rec)
+}
+
+func (f *FileStore) Get(ctx context.Context, id string) (*Record, error) {
+	return f.mem.Get(ctx, id)
+}
+
+func (f *FileStore) List(ctx context.Context, sessionID string) ([]*Record, error) {
+	return f.mem.List(ctx, sessionID)
+}
+
+func (f *FileStore) Close() error {
+	return f.file.Close()
+}
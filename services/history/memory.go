@@ -0,0 +1,64 @@
+package history
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore keeps the most recent records in memory. When full, the oldest
+// record is dropped.
+type MemoryStore struct {
+	mu      sync.Mutex
+	max     int
+	records []*Record
+	byID    map[string]*Record
+}
+
+func NewMemoryStore(max int) *MemoryStore {
+	return &MemoryStore{max: max, byID: make(map[string]*Record)}
+}
+
+func (m *MemoryStore) Save(_ context.Context, rec *Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.add(rec)
+	return nil
+}
+
+// add stores a copy of rec. Callers must hold m.mu.
+func (m *MemoryStore) add(rec *Record) {
+	stored := *rec
+	m.records = append(m.records, &stored)
+	m.byID[stored.ID] = &stored
+	if m.max > 0 && len(m.records) > m.max {
+		delete(m.byID, m.records[0].ID)
+		m.records = m.records[1:]
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, ok := m.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	out := *rec
+	return &out, nil
+}
+
+func (m *MemoryStore) List(_ context.Context, sessionID string) ([]*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*Record
+	for _, rec := range m.records {
+		if sessionID != "" && rec.SessionID != sessionID {
+			continue
+		}
+		copied := *rec
+		out = append(out, &copied)
+	}
+	return out, nil
+}
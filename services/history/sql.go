@@ -0,0 +1,90 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/storage"
+)
+
+// SQLStore keeps every record in the database, so history survives
+// restarts and is shared by replicas using the same database.
+type SQLStore struct {
+	db *storage.DB
+}
+
+func NewSQLStore(db *storage.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+func (s *SQLStore) Save(ctx context.Context, rec *Record) error {
+	cities, _ := json.Marshal(rec.Cities)
+	weather, _ := json.Marshal(rec.Weather)
+	_, err := s.db.Exec(ctx, `INSERT INTO advice_history (id, session_id, cities, weather, advice, model, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		rec.ID, rec.SessionID, string(cities), string(weather), rec.Advice, rec.Model, rec.CreatedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("write history failed: %v", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (*Record, error) {
+	rec, err := scanRecord(s.db.QueryRow(ctx, `SELECT id, session_id, cities, weather, advice, model, created_at
+		FROM advice_history WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history failed: %v", err)
+	}
+	return rec, nil
+}
+
+func (s *SQLStore) List(ctx context.Context, sessionID string) ([]*Record, error) {
+	query := `SELECT id, session_id, cities, weather, advice, model, created_at FROM advice_history`
+	var args []any
+	if sessionID != "" {
+		query += ` WHERE session_id = ?`
+		args = append(args, sessionID)
+	}
+	rows, err := s.db.Query(ctx, query+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read history failed: %v", err)
+	}
+	defer rows.Close()
+
+	var out []*Record
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("read history failed: %v", err)
+		}
+		out = append(out, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read history failed: %v", err)
+	}
+	return out, nil
+}
+
+func scanRecord(row interface{ Scan(...any) error }) (*Record, error) {
+	var rec Record
+	var cities, weather string
+	var created int64
+	if err := row.Scan(&rec.ID, &rec.SessionID, &cities, &weather, &rec.Advice, &rec.Model, &created); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(cities), &rec.Cities); err != nil {
+		return nil, fmt.Errorf("decode cities of %s: %v", rec.ID, err)
+	}
+	if err := json.Unmarshal([]byte(weather), &rec.Weather); err != nil {
+		return nil, fmt.Errorf("decode weather of %s: %v", rec.ID, err)
+	}
+	rec.CreatedAt = time.UnixMilli(created)
+	return &rec, nil
+}
@@ -0,0 +1,28 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("advice record not found")
+
+// Record is one piece of generated advice with the weather it was based on.
+type Record struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id,omitempty"`
+	Cities    []string  `json:"cities"`
+	Weather   []string  `json:"weather"`
+	Advice    string    `json:"advice"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdviceStore keeps generated advice so it can be looked up later.
+type AdviceStore interface {
+	Save(ctx context.Context, rec *Record) error
+	Get(ctx context.Context, id string) (*Record, error)
+	// List returns records oldest first. An empty sessionID lists everything.
+	List(ctx context.Context, sessionID string) ([]*Record, error)
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/requestid"
+)
+
+// Tuning for upstream APIs. Every service talks to a handful of hosts, so
+// idle connections are kept per host rather than globally.
+const (
+	maxIdleConns        = 100
+	maxIdleConnsPerHost = 20
+	idleConnTimeout     = 90 * time.Second
+	tlsSessionCacheSize = 64
+)
+
+// New returns a client meant to be created once and shared, so requests
+// reuse pooled keep-alive connections and resumed TLS sessions instead of
+// dialling and handshaking every time. Requests made with an RPC's context
+// carry its request ID in X-Request-Id.
+func New(timeout time.Duration) *http.Client {
+	transport := newTransport(&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second})
+	transport.Proxy = http.ProxyFromEnvironment
+	return &http.Client{Timeout: timeout, Transport: requestIDTransport{transport}}
+}
+
+// NewPublic is New for URLs users supply. It only connects to public
+// addresses, so a URL can't reach the server itself or its private
+// network, doesn't use a proxy and doesn't follow redirects.
+func NewPublic(timeout time.Duration) *http.Client {
+	transport := newTransport(&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second, Control: publicOnly})
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     requestIDTransport{transport},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// ErrNotPublic is returned when a public client would connect to a
+// loopback, private, link-local or otherwise non-public address.
+var ErrNotPublic = errors.New("address is not public")
+
+// publicOnly runs after DNS resolution, so a public name resolving to a
+// private address is refused too.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || cgnat.Contains(ip) {
+		return fmt.Errorf("%s: %w", ip, ErrNotPublic)
+	}
+	return nil
+}
+
+// cgnat is the shared address space carriers use, which IsPrivate leaves
+// out.
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+func newTransport(dialer *net.Dialer) *http.Transport {
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ClientSessionCache: tls.NewLRUClientSessionCache(tlsSessionCacheSize),
+		},
+	}
+}
+
+// requestIDTransport forwards the request ID from the request's context to
+// the upstream, unless the caller set the header itself.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := requestid.FromContext(req.Context())
+	if id == "" || req.Header.Get(requestid.Header) != "" {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set(requestid.Header, id)
+	return t.base.RoundTrip(req)
+}
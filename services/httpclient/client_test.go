@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// BenchmarkDo compares the shared pooled client with a new client per
+// call, which dials for every request. conns/op is the number of TCP
+// connections the server accepted per request.
+func BenchmarkDo(b *testing.B) {
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	do := func(b *testing.B, c *http.Client) {
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			b.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	b.Run("pooled", func(b *testing.B) {
+		c := New(5 * time.Second)
+		defer c.CloseIdleConnections()
+		conns.Store(0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			do(b, c)
+		}
+		b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+	})
+
+	b.Run("fresh", func(b *testing.B) {
+		conns.Store(0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			c := &http.Client{Timeout: 5 * time.Second, Transport: newTransport(&net.Dialer{Timeout: 5 * time.Second})}
+			do(b, c)
+			c.CloseIdleConnections()
+		}
+		b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+	})
+}
@@ -0,0 +1,49 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MaxBodyBytes caps how much of an upstream response is read. Open-Meteo
+// responses are a few KB, so 1 MiB leaves plenty of headroom. main can
+// override it from UPSTREAM_MAX_BODY_BYTES.
+var MaxBodyBytes int64 = 1 << 20
+
+// ErrBodyTooLarge is returned when a response exceeds MaxBodyBytes.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// DecodeJSON decodes a JSON response into v. It rejects non-JSON content
+// types (application/json and application/*+json are accepted) and stops
+// reading after MaxBodyBytes, so a misbehaving upstream can't exhaust
+// memory.
+func DecodeJSON(resp *http.Response, v any) error {
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/json" && !(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))) {
+			return fmt.Errorf("unexpected content type %q", ct)
+		}
+	}
+	if resp.ContentLength > MaxBodyBytes {
+		return fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, resp.ContentLength)
+	}
+
+	// Read one byte past the limit so an oversized body is reported as such
+	// rather than as truncated JSON.
+	limited := &io.LimitedReader{R: resp.Body, N: MaxBodyBytes + 1}
+	if err := json.NewDecoder(limited).Decode(v); err != nil {
+		if limited.N <= 0 {
+			return fmt.Errorf("%w: over %d bytes", ErrBodyTooLarge, MaxBodyBytes)
+		}
+		return err
+	}
+	if limited.N <= 0 {
+		return fmt.Errorf("%w: over %d bytes", ErrBodyTooLarge, MaxBodyBytes)
+	}
+	return nil
+}
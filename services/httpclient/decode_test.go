@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// jsonBody returns a JSON object exactly n bytes long.
+func jsonBody(n int) string {
+	const wrap = `{"s":""}`
+	return `{"s":"` + strings.Repeat("a", n-len(wrap)) + `"}`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	defer func(old int64) { MaxBodyBytes = old }(MaxBodyBytes)
+	MaxBodyBytes = 64
+
+	for _, tc := range []struct {
+		name    string
+		body    string
+		chunked bool // stream the body so no Content-Length is sent
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "exact limit", body: jsonBody(64)},
+		{name: "exact limit chunked", body: jsonBody(64), chunked: true},
+		{name: "over limit", body: jsonBody(65), wantErr: ErrBodyTooLarge},
+		{name: "over limit chunked", body: jsonBody(65), chunked: true, wantErr: ErrBodyTooLarge},
+		{name: "truncated", body: jsonBody(40)[:30], anyErr: true},
+		{name: "truncated chunked", body: jsonBody(40)[:30], chunked: true, anyErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if tc.chunked {
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var v struct{ S string }
+			err = DecodeJSON(resp, &v)
+			switch {
+			case tc.wantErr != nil:
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+			case tc.anyErr:
+				if err == nil || errors.Is(err, ErrBodyTooLarge) {
+					t.Fatalf("err = %v, want a decode error", err)
+				}
+			case err != nil:
+				t.Fatalf("err = %v, want nil", err)
+			case len(v.S) != 64-len(`{"s":""}`):
+				t.Fatalf("decoded %d bytes of s, want %d", len(v.S), 64-len(`{"s":""}`))
+			}
+		})
+	}
+}
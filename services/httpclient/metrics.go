@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var upstreamDuration = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "upstream_request_duration_seconds",
+		Help:    "Upstream HTTP latency until response headers, by upstream and HTTP status",
+		Buckets: []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	},
+	[]string{"upstream", "status"},
+)
+
+// Do sends req with client and records its latency under upstream, a short
+// fixed name such as "forecast" or "geocoding". The status label is the
+// HTTP status code, or "error" when no response arrived.
+func Do(client *http.Client, upstream string, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamDuration.WithLabelValues(upstream, status).Observe(time.Since(start).Seconds())
+	return resp, err
+}
@@ -0,0 +1,136 @@
+// Package identity records who is calling an RPC so interceptors and
+// handlers can attribute metrics and quotas to a client.
+package identity
+
+import (
+	"context"
+	"crypto/x509"
+	"log/slog"
+
+	"github.com/pixperk/effinarounf/services/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Anonymous labels requests with no established identity in metrics.
+const Anonymous = "anonymous"
+
+// SourceAPIKey marks identities established by a tenant API key.
+const SourceAPIKey = "api_key"
+
+// Identity is a caller established by the transport or a credential.
+type Identity struct {
+	// Subject names the caller, e.g. the client certificate's common name.
+	Subject string
+	// Source is how Subject was established, e.g. "mtls".
+	Source string
+	// Tenant is the customer the caller belongs to, when the credential
+	// names one.
+	Tenant string
+}
+
+var clientRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "grpc_client_requests_total",
+		Help: "RPCs by calling client identity and method",
+	},
+	[]string{"client", "method"},
+)
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying id.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the caller's identity, if one was established.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	return id, ok
+}
+
+// Subject returns the caller's subject, or Anonymous.
+func Subject(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id.Subject
+	}
+	return Anonymous
+}
+
+// fromPeer reads the identity from a verified client certificate. Plaintext
+// connections and TLS without client certificates have none.
+func fromPeer(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+	if subject := certSubject(info.State.VerifiedChains[0][0]); subject != "" {
+		return Identity{Subject: subject, Source: "mtls"}, true
+	}
+	return Identity{}, false
+}
+
+// certSubject prefers the common name and falls back to the first DNS or
+// URI SAN, since newer certificates often leave the CN empty.
+func certSubject(cert *x509.Certificate) string {
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	}
+	return ""
+}
+
+// withPeer adds the client certificate identity unless an earlier
+// interceptor already established one.
+func withPeer(ctx context.Context, method string) context.Context {
+	if _, ok := FromContext(ctx); !ok {
+		if id, ok := fromPeer(ctx); ok {
+			ctx = NewContext(ctx, id)
+		}
+	}
+	subject := Subject(ctx)
+	clientRequests.WithLabelValues(subject, method).Inc()
+	logging.AddAttrs(ctx, slog.String("client", subject))
+	return ctx
+}
+
+// UnaryServerInterceptor attaches the client certificate identity to the
+// request context and counts the call per client.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withPeer(ctx, info.FullMethod), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, WrapStream(ss, withPeer(ss.Context(), info.FullMethod)))
+	}
+}
+
+// WrapStream returns ss with its context replaced by ctx, for stream
+// interceptors that add values to the context.
+func WrapStream(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{ServerStream: ss, ctx: ctx}
+}
+
+// contextStream replaces a stream's context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
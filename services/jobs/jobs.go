@@ -0,0 +1,179 @@
+// Package jobs runs the server's periodic background work on cron-style
+// schedules: refreshing popular weather, pruning expired rows, delivering
+// digests and rotating logs.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	jobRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "job_runs_total",
+			Help: "Background job runs, by job and result",
+		},
+		[]string{"job", "result"},
+	)
+	jobDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "job_duration_seconds",
+			Help:    "Background job run time",
+			Buckets: []float64{.01, .1, .5, 1, 5, 15, 60, 300},
+		},
+		[]string{"job"},
+	)
+	jobLastSuccess = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "job_last_success_timestamp_seconds",
+			Help: "Unix time of each job's last successful run",
+		},
+		[]string{"job"},
+	)
+)
+
+// Func is one run of a job.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule Schedule
+	run      Func
+	timeout  time.Duration
+}
+
+// Scheduler runs jobs on their schedules. A job's runs never overlap: a run
+// that is still going when the next one is due makes that one wait.
+type Scheduler struct {
+	jobs      []*job
+	overrides map[string]string
+}
+
+// NewScheduler returns a scheduler whose default schedules are replaced by
+// overrides, keyed by job name; "off" disables a job.
+func NewScheduler(overrides map[string]string) *Scheduler {
+	return &Scheduler{overrides: overrides}
+}
+
+// ParseOverrides reads "name=schedule;name=schedule", the format of the
+// JOB_SCHEDULES setting. Cron fields are space-separated, so entries are
+// separated by semicolons.
+func ParseOverrides(s string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(s, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		name, spec = strings.TrimSpace(name), strings.TrimSpace(spec)
+		if !ok || name == "" || spec == "" {
+			return nil, fmt.Errorf("job schedule %q: want name=schedule", entry)
+		}
+		if spec != "off" {
+			if _, err := ParseSchedule(spec); err != nil {
+				return nil, err
+			}
+		}
+		out[name] = spec
+	}
+	return out, nil
+}
+
+// Add registers a job with its default schedule. Each run gets timeout to
+// finish, or no limit if it is 0.
+func (s *Scheduler) Add(name, spec string, timeout time.Duration, run Func) error {
+	if override, ok := s.overrides[name]; ok {
+		spec = override
+	}
+	if spec == "off" {
+		slog.Info("background job disabled", "job", name)
+		return nil
+	}
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %v", name, err)
+	}
+	s.jobs = append(s.jobs, &job{name: name, schedule: schedule, run: run, timeout: timeout})
+	return nil
+}
+
+// Unknown returns the overridden job names that were never added, most
+// likely typos.
+func (s *Scheduler) Unknown() []string {
+	added := make(map[string]bool)
+	for _, j := range s.jobs {
+		added[j.name] = true
+	}
+	var out []string
+	for name, spec := range s.overrides {
+		if !added[name] && spec != "off" {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Run runs the jobs until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	for _, j := range s.jobs {
+		go j.loop(ctx)
+	}
+	<-ctx.Done()
+}
+
+func (j *job) loop(ctx context.Context) {
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Error("background job schedule never fires", "job", j.name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		j.once(ctx)
+	}
+}
+
+func (j *job) once(ctx context.Context) {
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := j.safeRun(ctx)
+	jobDuration.WithLabelValues(j.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		slog.Error("background job failed", "job", j.name, "error", err)
+		jobRuns.WithLabelValues(j.name, "failed").Inc()
+		return
+	}
+	jobRuns.WithLabelValues(j.name, "ok").Inc()
+	jobLastSuccess.WithLabelValues(j.name).SetToCurrentTime()
+}
+
+// safeRun turns a panic into an error, so one broken job doesn't take the
+// server down.
+func (j *job) safeRun(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run(ctx)
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNextLocalHour(t *testing.T) {
+	for _, zone := range []string{"UTC", "Asia/Kolkata", "Asia/Kathmandu", "America/St_Johns"} {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			t.Fatalf("load %s: %v", zone, err)
+		}
+		from := time.Date(2026, time.March, 3, 10, 17, 42, 0, loc)
+		for _, tc := range []struct {
+			spec string
+			want time.Time
+		}{
+			{"@daily", time.Date(2026, time.March, 4, 0, 0, 0, 0, loc)},
+			{"@hourly", time.Date(2026, time.March, 3, 11, 0, 0, 0, loc)},
+			{"0 9 * * *", time.Date(2026, time.March, 4, 9, 0, 0, 0, loc)},
+			{"30 14 * * *", time.Date(2026, time.March, 3, 14, 30, 0, 0, loc)},
+			{"*/5 * * * *", time.Date(2026, time.March, 3, 10, 20, 0, 0, loc)},
+		} {
+			s, err := ParseSchedule(tc.spec)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.spec, err)
+			}
+			if got := s.Next(from); !got.Equal(tc.want) {
+				t.Errorf("%s: Next(%q) = %v, want %v", zone, tc.spec, got, tc.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package logging builds the server's structured logger and logs one line
+// per RPC.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/requestid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// New returns a logger writing format ("json" or "text") to w at level
+// ("debug", "info", "warn" or "error").
+func New(format, level string, w io.Writer) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "json":
+		return slog.New(contextHandler{slog.NewJSONHandler(w, opts)}), nil
+	case "text":
+		return slog.New(contextHandler{slog.NewTextHandler(w, opts)}), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want json or text)", format)
+	}
+}
+
+// contextHandler adds the request ID to every record logged with the
+// RPC's context, not only the per-RPC line.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// fields collects attributes inner interceptors and handlers add to the
+// request log line.
+type fields struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+type fieldsKey struct{}
+
+// AddAttrs adds attributes to the log line of the RPC ctx belongs to. It
+// does nothing outside a logged RPC.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	f, ok := ctx.Value(fieldsKey{}).(*fields)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	f.attrs = append(f.attrs, attrs...)
+	f.mu.Unlock()
+}
+
+// requestID returns the sanitized x-request-id the caller sent, or a new
+// one.
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(requestid.Header); len(values) > 0 {
+		if id := requestid.Sanitize(values[0]); id != "" {
+			return id
+		}
+	}
+	return requestid.New()
+}
+
+// begin starts an RPC: it settles its request ID, echoes it in the response
+// headers and tags the server span with it.
+func begin(ctx context.Context) (context.Context, *fields) {
+	id := requestID(ctx)
+	ctx = requestid.NewContext(ctx, id)
+	grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", id))
+	f := &fields{}
+	return context.WithValue(ctx, fieldsKey{}, f), f
+}
+
+// withRequestID appends the request ID to an error's message, keeping its
+// code and details, so a caller can quote it when reporting a failure. An
+// error from a downstream service that already names the ID is left alone.
+func withRequestID(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	id := requestid.FromContext(ctx)
+	p := status.Convert(err).Proto()
+	if strings.Contains(p.Message, id) {
+		return err
+	}
+	p.Message += " (request id " + id + ")"
+	return status.FromProto(p).Err()
+}
+
+func finish(ctx context.Context, logger *slog.Logger, f *fields, method string, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	f.mu.Lock()
+	attrs = append(attrs, f.attrs...)
+	f.mu.Unlock()
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	logger.LogAttrs(ctx, levelFor(code), "rpc", attrs...)
+}
+
+// levelFor logs server-side failures as errors and caller mistakes as
+// warnings.
+func levelFor(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// UnaryServerInterceptor logs method, peer, duration, status code and
+// request ID of every call. The ID is the caller's x-request-id or a new
+// one; it is returned in the x-request-id response header and appended to
+// error messages. Install it first so rejected calls are logged and tagged
+// too.
+func UnaryServerInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, f := begin(ctx)
+		resp, err := handler(ctx, req)
+		finish(ctx, logger, f, info.FullMethod, start, err)
+		return resp, withRequestID(ctx, err)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs; the
+// line is written when the stream ends.
+func StreamServerInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, f := begin(ss.Context())
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		finish(ctx, logger, f, info.FullMethod, start, err)
+		return withRequestID(ctx, err)
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,180 @@
+// Package markdown converts the Markdown the model writes to plain text or
+// to a safe HTML fragment, for clients and notification channels that
+// can't render Markdown.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format is what Markdown is converted to.
+type Format int
+
+const (
+	// Plain drops the markup, for terminals and SMS.
+	Plain Format = iota
+	// HTML is an escaped fragment with only p, h1-h6, ul/ol/li, strong, em,
+	// code and http(s) links.
+	HTML
+	// Slack is Slack's mrkdwn, which has no headings and marks bold with
+	// single asterisks.
+	Slack
+)
+
+var (
+	mdHeading = regexp.MustCompile(`^#{1,6}\s+`)
+	mdBullet  = regexp.MustCompile(`^\s*[-*+]\s+`)
+	mdNumber  = regexp.MustCompile(`^\s*\d+[.)]\s+`)
+	mdStrong  = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdEm      = regexp.MustCompile(`\*([^*\s][^*]*?)\*`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// Converter converts Markdown one line at a time, so streamed chunks can
+// be converted as they arrive. A nil Converter passes Markdown through
+// unchanged.
+type Converter struct {
+	format  Format
+	pending string
+	// list is the open HTML list element ("ul" or "ol"), if any.
+	list string
+}
+
+func NewConverter(format Format) *Converter {
+	return &Converter{format: format}
+}
+
+// Convert converts a whole text.
+func Convert(format Format, text string) string {
+	f := NewConverter(format)
+	return f.Write(text) + f.Flush()
+}
+
+// Write returns the converted form of every line completed by text and
+// holds back the trailing partial line.
+func (f *Converter) Write(text string) string {
+	if f == nil {
+		return text
+	}
+	buf := f.pending + text
+	i := strings.LastIndexByte(buf, '\n')
+	if i < 0 {
+		f.pending = buf
+		return ""
+	}
+	f.pending = buf[i+1:]
+
+	var out strings.Builder
+	for _, line := range strings.Split(buf[:i], "\n") {
+		out.WriteString(f.line(line))
+	}
+	return out.String()
+}
+
+// Flush converts the held-back partial line and closes any open list.
+func (f *Converter) Flush() string {
+	if f == nil {
+		return ""
+	}
+	var out string
+	if f.pending != "" {
+		out = f.line(f.pending)
+		f.pending = ""
+	}
+	return out + f.closeList()
+}
+
+func (f *Converter) line(line string) string {
+	switch f.format {
+	case HTML:
+		return f.htmlLine(line)
+	case Slack:
+		return slackLine(line)
+	}
+	return plainLine(line)
+}
+
+func plainLine(line string) string {
+	line = mdHeading.ReplaceAllString(line, "")
+	line = mdBullet.ReplaceAllString(line, "- ")
+	line = mdLink.ReplaceAllString(line, "$1 ($2)")
+	line = mdStrong.ReplaceAllString(line, "$1$2")
+	line = mdEm.ReplaceAllString(line, "$1")
+	line = mdCode.ReplaceAllString(line, "$1")
+	return line + "\n"
+}
+
+// slackLine escapes the characters mrkdwn reserves and rewrites the
+// markup. Headings become bold lines.
+func slackLine(line string) string {
+	line = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(line)
+	heading := mdHeading.MatchString(line)
+	line = mdHeading.ReplaceAllString(line, "")
+	line = mdBullet.ReplaceAllString(line, "• ")
+	line = mdLink.ReplaceAllString(line, "<$2|$1>")
+	// Bold is marked with a placeholder so the italics pass leaves it be.
+	line = mdStrong.ReplaceAllString(line, "\x00$1$2\x00")
+	line = mdEm.ReplaceAllString(line, "_${1}_")
+	line = strings.ReplaceAll(line, "\x00", "*")
+	if heading && line != "" {
+		line = "*" + line + "*"
+	}
+	return line + "\n"
+}
+
+func (f *Converter) htmlLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "":
+		return f.closeList()
+	case mdHeading.MatchString(trimmed):
+		tag := "h" + strconv.Itoa(strings.IndexFunc(trimmed, func(r rune) bool { return r != '#' }))
+		return f.closeList() + "<" + tag + ">" + inlineHTML(mdHeading.ReplaceAllString(trimmed, "")) + "</" + tag + ">\n"
+	case mdBullet.MatchString(line):
+		return f.openList("ul") + "<li>" + inlineHTML(mdBullet.ReplaceAllString(line, "")) + "</li>\n"
+	case mdNumber.MatchString(line):
+		return f.openList("ol") + "<li>" + inlineHTML(mdNumber.ReplaceAllString(line, "")) + "</li>\n"
+	default:
+		return f.closeList() + "<p>" + inlineHTML(trimmed) + "</p>\n"
+	}
+}
+
+func (f *Converter) openList(kind string) string {
+	if f.list == kind {
+		return ""
+	}
+	out := f.closeList()
+	f.list = kind
+	return out + "<" + kind + ">\n"
+}
+
+func (f *Converter) closeList() string {
+	if f.list == "" {
+		return ""
+	}
+	out := "</" + f.list + ">\n"
+	f.list = ""
+	return out
+}
+
+// inlineHTML escapes text and then converts emphasis, code and links. Only
+// http(s) links become anchors, so model output can't inject scripts.
+func inlineHTML(text string) string {
+	text = html.EscapeString(text)
+	text = mdLink.ReplaceAllStringFunc(text, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		label, href := parts[1], parts[2]
+		if !strings.HasPrefix(href, "http://") && !strings.HasPrefix(href, "https://") {
+			return label
+		}
+		return `<a href="` + href + `" rel="nofollow noopener">` + label + "</a>"
+	})
+	text = mdStrong.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = mdEm.ReplaceAllString(text, "<em>$1</em>")
+	text = mdCode.ReplaceAllString(text, "<code>$1</code>")
+	return text
+}
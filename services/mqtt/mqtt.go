@@ -0,0 +1,265 @@
+// Package mqtt publishes current conditions for configured locations, and
+// the weather alerts that fire, to an MQTT broker for home-automation
+// systems to consume.
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pixperk/effinarounf/services/alert"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var publishes = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mqtt_publishes_total",
+		Help: "Messages published to the MQTT broker, by kind and result",
+	},
+	[]string{"kind", "result"},
+)
+
+// publishTimeout bounds how long one publish waits for the broker.
+const publishTimeout = 10 * time.Second
+
+// Config is the broker and what to publish.
+type Config struct {
+	// Broker is a URL such as tcp://localhost:1883 or tls://broker:8883.
+	Broker             string
+	ClientID           string
+	Username, Password string
+	// TopicPrefix starts every topic, e.g. "weather".
+	TopicPrefix string
+	// QoS is 0, 1 or 2.
+	QoS       byte
+	Locations []Location
+}
+
+// Location is a place whose current conditions are published to
+// <prefix>/<name>/current.
+type Location struct {
+	Name                string
+	Latitude, Longitude float64
+}
+
+// ParseLocations reads a semicolon-separated list of name=lat,lon, e.g.
+// "home=51.51,-0.13;cabin=61.1,10.4".
+func ParseLocations(s string) ([]Location, error) {
+	var out []Location
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, coords, ok := strings.Cut(entry, "=")
+		rawLat, rawLon, ok2 := strings.Cut(coords, ",")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("location %q is not name=lat,lon", entry)
+		}
+		loc := Location{Name: TopicSegment(name)}
+		if loc.Name == "" {
+			return nil, fmt.Errorf("location %q has no name", entry)
+		}
+		if seen[loc.Name] {
+			return nil, fmt.Errorf("location %s is listed twice", loc.Name)
+		}
+		seen[loc.Name] = true
+		var err error
+		if loc.Latitude, err = strconv.ParseFloat(strings.TrimSpace(rawLat), 64); err != nil || loc.Latitude < -90 || loc.Latitude > 90 {
+			return nil, fmt.Errorf("location %s: invalid latitude %q", loc.Name, rawLat)
+		}
+		if loc.Longitude, err = strconv.ParseFloat(strings.TrimSpace(rawLon), 64); err != nil || loc.Longitude < -180 || loc.Longitude > 180 {
+			return nil, fmt.Errorf("location %s: invalid longitude %q", loc.Name, rawLon)
+		}
+		out = append(out, loc)
+	}
+	return out, nil
+}
+
+// TopicSegment turns a location name into one topic level: lower case,
+// with runs of anything but letters and digits replaced by a dash, so it
+// never contains the / + # that MQTT treats specially.
+func TopicSegment(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// Publisher holds the broker connection. The connection is made in the
+// background and re-made when it drops; messages published while it is
+// down fail, and the next run publishes fresh conditions.
+//
+// <prefix>/status is "online" while the publisher is connected and
+// "offline" (the broker's last will for it) otherwise.
+type Publisher struct {
+	cfg     Config
+	client  paho.Client
+	weather weatherpb.WeatherServiceServer
+}
+
+func New(cfg Config, weather weatherpb.WeatherServiceServer) *Publisher {
+	status := cfg.TopicPrefix + "/status"
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetWill(status, "offline", 1, true).
+		SetOnConnectHandler(func(c paho.Client) {
+			slog.Info("connected to MQTT broker", "broker", cfg.Broker)
+			c.Publish(status, 1, true, "online")
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			slog.Warn("MQTT connection lost, reconnecting", "broker", cfg.Broker, "error", err)
+		})
+	p := &Publisher{cfg: cfg, client: paho.NewClient(opts), weather: weather}
+	p.client.Connect()
+	return p
+}
+
+// Close marks the publisher offline and disconnects.
+func (p *Publisher) Close() {
+	if p.client.IsConnectionOpen() {
+		p.client.Publish(p.cfg.TopicPrefix+"/status", 1, true, "offline").WaitTimeout(time.Second)
+	}
+	p.client.Disconnect(250)
+}
+
+// currentJSON is how current conditions are encoded: the weather
+// service's response with its field names.
+var currentJSON = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// PublishCurrent publishes the current conditions of every location as a
+// retained message, so subscribers get the latest reading as soon as they
+// subscribe. Locations that fail are logged and skipped; the last of their
+// errors is returned.
+func (p *Publisher) PublishCurrent(ctx context.Context) error {
+	var lastErr error
+	for _, loc := range p.cfg.Locations {
+		resp, err := p.weather.GetCurrentWeather(ctx, &weatherpb.WeatherRequest{Latitude: loc.Latitude, Longitude: loc.Longitude})
+		if err != nil {
+			slog.Error("MQTT current conditions failed", "location", loc.Name, "error", err)
+			lastErr = err
+			continue
+		}
+		resp.Location = loc.Name
+		payload, err := currentJSON.Marshal(resp)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := p.publish(ctx, "current", p.topic(loc.Name, "current"), true, payload); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// alertMessage is the payload of an alert.
+type alertMessage struct {
+	SubscriptionID string  `json:"subscription_id"`
+	Recipient      string  `json:"recipient,omitempty"`
+	Location       string  `json:"location"`
+	Metric         string  `json:"metric"`
+	Operator       string  `json:"operator"`
+	Threshold      float64 `json:"threshold"`
+	Unit           string  `json:"unit,omitempty"`
+	Window         string  `json:"window"`
+	Date           string  `json:"date"`
+	Value          float64 `json:"value"`
+	Description    string  `json:"description"`
+	Title          string  `json:"title"`
+	// Body is Markdown.
+	Body string `json:"body"`
+	Time int64  `json:"time"`
+}
+
+// PublishAlerts publishes the alerts that fire to <prefix>/<location>/alerts
+// until ctx is done. Alerts aren't retained: they are events, not state.
+func (p *Publisher) PublishAlerts(ctx context.Context, feed *alert.Feed) {
+	events, cancel := feed.Subscribe(64)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-events:
+			sub, cond := ev.Subscription, ev.Subscription.Condition
+			payload, err := json.Marshal(alertMessage{
+				SubscriptionID: sub.ID,
+				Recipient:      sub.Recipient,
+				Location:       sub.Location,
+				Metric:         string(cond.Metric),
+				Operator:       string(cond.Operator),
+				Threshold:      cond.Threshold,
+				Unit:           cond.Unit,
+				Window:         string(cond.Window),
+				Date:           ev.Date,
+				Value:          ev.Value,
+				Description:    ev.Description,
+				Title:          ev.Title,
+				Body:           ev.Body,
+				Time:           ev.Time.Unix(),
+			})
+			if err != nil {
+				continue
+			}
+			p.publish(ctx, "alert", p.topic(TopicSegment(sub.Location), "alerts"), false, payload)
+		}
+	}
+}
+
+func (p *Publisher) topic(location, kind string) string {
+	return p.cfg.TopicPrefix + "/" + location + "/" + kind
+}
+
+func (p *Publisher) publish(ctx context.Context, kind, topic string, retained bool, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	// Messages published while connecting are queued and, with a clean
+	// session, never sent.
+	var err error
+	if !p.client.IsConnectionOpen() {
+		err = errors.New("not connected to the broker")
+	} else {
+		tok := p.client.Publish(topic, p.cfg.QoS, retained, payload)
+		select {
+		case <-tok.Done():
+			err = tok.Error()
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		slog.Error("MQTT publish failed", "topic", topic, "error", err)
+		publishes.WithLabelValues(kind, "failed").Inc()
+		return fmt.Errorf("publish to %s: %v", topic, err)
+	}
+	publishes.WithLabelValues(kind, "published").Inc()
+	return nil
+}
@@ -0,0 +1,255 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/markdown"
+)
+
+// Webhook kinds.
+const (
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+	// WebhookGeneric is any URL, posted signed JSON, see Signed.
+	WebhookGeneric = "generic"
+)
+
+// Webhook is a channel a subscription's messages are posted to, in
+// addition to the server's own channels.
+type Webhook struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+	// Secret is the signing key of a generic webhook.
+	Secret string `json:"secret,omitempty"`
+}
+
+// minSecretLength is the shortest signing key a generic webhook accepts.
+const minSecretLength = 16
+
+// webhookHosts are the hosts each kind may post to. Only the chat
+// services' own webhook endpoints are allowed, so a subscription can't
+// make the server send requests to arbitrary, possibly internal, URLs.
+var webhookHosts = map[string]struct {
+	hosts []string
+	path  string
+}{
+	WebhookSlack:   {[]string{"hooks.slack.com"}, "/services/"},
+	WebhookDiscord: {[]string{"discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com"}, "/api/webhooks/"},
+}
+
+// Validate checks that the URL is an https webhook URL of the chat
+// service w.Kind names. A generic webhook may have any http(s) URL; Signed
+// refuses to connect to addresses that aren't public.
+func (w Webhook) Validate() error {
+	if w.Kind == WebhookGeneric {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil {
+			return fmt.Errorf("generic webhook URL must be an http(s) URL")
+		}
+		if len(w.Secret) < minSecretLength {
+			return fmt.Errorf("generic webhook secret must be at least %d characters", minSecretLength)
+		}
+		return nil
+	}
+	allowed, ok := webhookHosts[w.Kind]
+	if !ok {
+		return fmt.Errorf("unknown webhook kind %q", w.Kind)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "https" || u.User != nil || u.Port() != "" {
+		return fmt.Errorf("%s webhook URL must be https://%s%s...", w.Kind, allowed.hosts[0], allowed.path)
+	}
+	for _, host := range allowed.hosts {
+		if strings.EqualFold(u.Hostname(), host) && strings.HasPrefix(u.Path, allowed.path) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s webhook URL must be https://%s%s...", w.Kind, allowed.hosts[0], allowed.path)
+}
+
+// Redacted is the URL with its path, which holds the webhook's secret,
+// hidden.
+func (w Webhook) Redacted() string {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + "/…"
+}
+
+// Slack posts messages to the Slack incoming webhooks of their
+// subscription, as a header and the body in mrkdwn.
+type Slack struct {
+	client *http.Client
+}
+
+func NewSlack(client *http.Client) *Slack {
+	return &Slack{client: client}
+}
+
+func (s *Slack) Name() string { return WebhookSlack }
+
+// Slack's limits on a header block and on a section's text.
+const (
+	slackHeaderMax  = 150
+	slackSectionMax = 3000
+)
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string     `json:"type"`
+	Text *slackText `json:"text,omitempty"`
+}
+
+func (s *Slack) Notify(ctx context.Context, msg *Message) error {
+	if !hasWebhook(msg, WebhookSlack) {
+		return nil
+	}
+	blocks := []slackBlock{{Type: "header", Text: &slackText{"plain_text", truncate(msg.Title, slackHeaderMax)}}}
+	for _, chunk := range split(markdown.Convert(markdown.Slack, msg.Body), slackSectionMax) {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{"mrkdwn", chunk}})
+	}
+	// text is what notifications and clients without blocks show.
+	payload := map[string]any{"text": msg.Title, "blocks": blocks}
+	return postWebhooks(ctx, s.client, msg, WebhookSlack, payload)
+}
+
+// Discord posts messages to the Discord webhooks of their subscription, as
+// an embed. Discord renders the Markdown itself.
+type Discord struct {
+	client *http.Client
+}
+
+func NewDiscord(client *http.Client) *Discord {
+	return &Discord{client: client}
+}
+
+func (d *Discord) Name() string { return WebhookDiscord }
+
+// Discord's limits on an embed's title and description.
+const (
+	discordTitleMax       = 256
+	discordDescriptionMax = 4096
+)
+
+// discordColors are the embed accent colors by message kind.
+var discordColors = map[string]int{
+	KindDigest: 0x3498db,
+	KindAlert:  0xe67e22,
+}
+
+func (d *Discord) Notify(ctx context.Context, msg *Message) error {
+	if !hasWebhook(msg, WebhookDiscord) {
+		return nil
+	}
+	embed := map[string]any{
+		"title":       truncate(msg.Title, discordTitleMax),
+		"description": truncate(msg.Body, discordDescriptionMax),
+		"color":       discordColors[msg.Kind],
+		"timestamp":   msg.Time.UTC().Format(time.RFC3339),
+	}
+	return postWebhooks(ctx, d.client, msg, WebhookDiscord, map[string]any{"embeds": []any{embed}})
+}
+
+func hasWebhook(msg *Message, kind string) bool {
+	for _, w := range msg.Webhooks {
+		if w.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// postWebhooks posts payload to each of msg's webhooks of kind. A failing
+// webhook doesn't stop the others.
+func postWebhooks(ctx context.Context, client *http.Client, msg *Message, kind string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s message: %v", kind, err)
+	}
+	var errs []string
+	for _, w := range msg.Webhooks {
+		if w.Kind != kind {
+			continue
+		}
+		if err := postWebhook(ctx, client, w.URL, body); err != nil {
+			// The URL is a secret, so only its host goes in the error.
+			errs = append(errs, fmt.Sprintf("%s: %v", w.Redacted(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s webhook failed: %s", kind, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func postWebhook(ctx context.Context, client *http.Client, target string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapping, which quotes the URL.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return uerr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if len(bytes.TrimSpace(detail)) == 0 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// truncate cuts s to at most n characters, ending with an ellipsis when
+// anything was cut.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// split cuts text into chunks of at most n characters, at line breaks
+// where it can.
+func split(text string, n int) []string {
+	text = strings.TrimSpace(text)
+	var chunks []string
+	for text != "" {
+		r := []rune(text)
+		if len(r) <= n {
+			chunks = append(chunks, text)
+			break
+		}
+		cut := string(r[:n])
+		if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+			cut = cut[:i]
+		}
+		chunks = append(chunks, strings.TrimSpace(cut))
+		text = strings.TrimSpace(text[len(cut):])
+	}
+	return chunks
+}
@@ -0,0 +1,255 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/markdown"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// SMTPConfig is the mail server email is sent through.
+type SMTPConfig struct {
+	// Addr is host:port. Port 465 is TLS from the start; on other ports
+	// the connection is upgraded with STARTTLS when the server offers it.
+	Addr string
+	// Username and Password, if set, authenticate with PLAIN, which is
+	// only sent over TLS or to localhost.
+	Username, Password string
+	From               string
+}
+
+// Email sends messages to the address in each recipient's preferences, or
+// to the recipient itself when it is an email address. Messages for
+// recipients without an address are skipped.
+type Email struct {
+	cfg        SMTPConfig
+	from       *mail.Address
+	recipients Recipients
+	html       *htmltemplate.Template
+	text       *texttemplate.Template
+}
+
+// templateData is what the email templates see: the message, its body
+// converted from Markdown, and its Kind, SubscriptionID and Title.
+type templateData struct {
+	*Message
+	HTML htmltemplate.HTML
+	Text string
+}
+
+// NewEmail sends through the server in cfg. templateDir, if set, holds
+// email.html.tmpl and email.txt.tmpl replacing the built-in templates.
+func NewEmail(cfg SMTPConfig, recipients Recipients, templateDir string) (*Email, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %v", cfg.From, err)
+	}
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %v", cfg.Addr, err)
+	}
+
+	files := func(name string) ([]byte, error) { return defaultTemplates.ReadFile("templates/" + name) }
+	if templateDir != "" {
+		files = func(name string) ([]byte, error) { return os.ReadFile(filepath.Join(templateDir, name)) }
+	}
+	htmlSrc, err := files("email.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("email template: %v", err)
+	}
+	textSrc, err := files("email.txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("email template: %v", err)
+	}
+	html, err := htmltemplate.New("email.html.tmpl").Parse(string(htmlSrc))
+	if err != nil {
+		return nil, fmt.Errorf("email template: %v", err)
+	}
+	text, err := texttemplate.New("email.txt.tmpl").Parse(string(textSrc))
+	if err != nil {
+		return nil, fmt.Errorf("email template: %v", err)
+	}
+	return &Email{cfg: cfg, from: from, recipients: recipients, html: html, text: text}, nil
+}
+
+func (e *Email) Name() string { return "email" }
+
+func (e *Email) Notify(ctx context.Context, msg *Message) error {
+	pref, wanted := e.recipients.lookup(msg)
+	if !wanted {
+		return nil
+	}
+	to := pref.Email
+	if to == "" {
+		if addr, err := mail.ParseAddress(msg.Recipient); err == nil {
+			to = addr.Address
+		}
+	}
+	if to == "" {
+		slog.Debug("no email address for recipient, skipping", "recipient", msg.Recipient, "subscription", msg.SubscriptionID)
+		return nil
+	}
+
+	body, err := e.render(msg, to, pref.Format)
+	if err != nil {
+		return err
+	}
+	return e.send(ctx, to, body)
+}
+
+// render builds the whole email: headers, then either a plain-text body or
+// HTML with a plain-text alternative.
+func (e *Email) render(msg *Message, to, format string) ([]byte, error) {
+	data := templateData{
+		Message: msg,
+		HTML:    htmltemplate.HTML(markdown.Convert(markdown.HTML, msg.Body)),
+		Text:    markdown.Convert(markdown.Plain, msg.Body),
+	}
+	var text bytes.Buffer
+	if err := e.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("render email: %v", err)
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", e.from.String())
+	header("To", to)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Title))
+	header("Date", msg.Time.Format(time.RFC1123Z))
+	header("Message-ID", messageID(e.from.Address))
+	header("MIME-Version", "1.0")
+
+	if format == "text" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, text.Bytes()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var html bytes.Buffer
+	if err := e.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("render email: %v", err)
+	}
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	header("Content-Type", `multipart/alternative; boundary="`+parts.Boundary()+`"`)
+	buf.WriteString("\r\n")
+	for _, part := range []struct {
+		contentType string
+		body        []byte
+	}{{"text/plain", text.Bytes()}, {"text/html", html.Bytes()}} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, data []byte) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write(data); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndexByte(from, '@'); at >= 0 {
+		domain = from[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// send delivers one email. net/smtp takes no context, so ctx's deadline
+// is applied to the connection instead.
+func (e *Email) send(ctx context.Context, to string, body []byte) error {
+	host, port, _ := net.SplitHostPort(e.cfg.Addr)
+	tlsConfig := &tls.Config{ServerName: host}
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if port == "465" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", e.cfg.Addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", e.cfg.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to SMTP server: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake: %v", err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok && port != "465" {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SMTP STARTTLS: %v", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return fmt.Errorf("SMTP auth: %v", err)
+		}
+	}
+	if err := c.Mail(e.from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM: %v", err)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return fmt.Errorf("SMTP RCPT TO %s: %v", to, err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %v", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("SMTP DATA: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP DATA: %v", err)
+	}
+	return c.Quit()
+}
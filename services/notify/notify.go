@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var notifications = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "notifications_total",
+		Help: "Notifications handed to notifiers, by kind and result",
+	},
+	[]string{"notifier", "kind", "result"},
+)
+
+// Message kinds.
+const (
+	// KindDigest is a scheduled advice digest.
+	KindDigest = "digest"
+	// KindAlert is a weather alert whose condition the forecast met.
+	KindAlert = "alert"
+)
+
+// Message is one notification for one recipient.
+type Message struct {
+	Kind string `json:"kind"`
+	// SubscriptionID is the subscription that produced the message.
+	SubscriptionID string `json:"subscription_id"`
+	// Recipient is the label the subscriber gave, if any.
+	Recipient string `json:"recipient,omitempty"`
+	Title     string `json:"title"`
+	// Body is Markdown unless the subscription asked for another format.
+	Body string    `json:"body"`
+	Time time.Time `json:"time"`
+	// Webhooks are the subscription's chat webhooks. Their URLs are
+	// secrets, so they are left out of the JSON form.
+	Webhooks []Webhook `json:"-"`
+}
+
+// Notifier delivers messages to one channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// Multi delivers every message to each notifier in turn. A failing notifier
+// doesn't stop the others; the failures are returned together.
+type Multi []Notifier
+
+func (m Multi) Name() string { return "multi" }
+
+func (m Multi) Notify(ctx context.Context, msg *Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			slog.Error("notification failed", "notifier", n.Name(), "error", err)
+			notifications.WithLabelValues(n.Name(), msg.Kind, "failed").Inc()
+			errs = append(errs, err)
+			continue
+		}
+		notifications.WithLabelValues(n.Name(), msg.Kind, "delivered").Inc()
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes messages to the server log. It is the notifier used when no
+// delivery channel is configured.
+type Log struct{}
+
+func (Log) Name() string { return "log" }
+
+func (Log) Notify(_ context.Context, msg *Message) error {
+	slog.Info("notification", "kind", msg.Kind, "subscription", msg.SubscriptionID, "recipient", msg.Recipient, "title", msg.Title, "body", msg.Body)
+	return nil
+}
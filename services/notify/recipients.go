@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Preference is how one recipient wants to be notified.
+type Preference struct {
+	// Email is the address email is sent to; none means no email.
+	Email string `yaml:"email"`
+	// Format is "html" (HTML with a plain-text alternative, the default)
+	// or "text".
+	Format string `yaml:"format"`
+	// Kinds limits delivery to these message kinds ("digest", "alert");
+	// empty means every kind.
+	Kinds []string `yaml:"kinds"`
+}
+
+// Recipients maps the recipient label of a subscription to its delivery
+// preferences.
+type Recipients map[string]Preference
+
+// RecipientsFile is the recipients file.
+type RecipientsFile struct {
+	Recipients Recipients `yaml:"recipients"`
+}
+
+// LoadRecipients reads a recipients file.
+func LoadRecipients(path string) (Recipients, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("recipients file: %v", err)
+	}
+	var f RecipientsFile
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("recipients file %s: %v", path, err)
+	}
+	for name, p := range f.Recipients {
+		if p.Email != "" {
+			if _, err := mail.ParseAddress(p.Email); err != nil {
+				return nil, fmt.Errorf("recipient %s: invalid email %q", name, p.Email)
+			}
+		}
+		switch p.Format {
+		case "", "html", "text":
+		default:
+			return nil, fmt.Errorf("recipient %s: unknown format %q (want html or text)", name, p.Format)
+		}
+		for _, kind := range p.Kinds {
+			if kind != KindDigest && kind != KindAlert {
+				return nil, fmt.Errorf("recipient %s: unknown kind %q (want %s or %s)", name, kind, KindDigest, KindAlert)
+			}
+		}
+	}
+	return f.Recipients, nil
+}
+
+// lookup returns the preferences for msg's recipient and whether it wants
+// messages of msg's kind. A recipient missing from the file gets the
+// defaults.
+func (r Recipients) lookup(msg *Message) (Preference, bool) {
+	p := r[msg.Recipient]
+	if len(p.Kinds) == 0 {
+		return p, true
+	}
+	for _, kind := range p.Kinds {
+		if kind == msg.Kind {
+			return p, true
+		}
+	}
+	return p, false
+}
@@ -0,0 +1,240 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var webhookDeliveries = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "webhook_deliveries_total",
+		Help: "Generic webhook delivery attempts, by result",
+	},
+	[]string{"result"},
+)
+
+// Headers of a generic webhook delivery.
+const (
+	// EventIDHeader is the event's ID. It is the same on every retry, so
+	// receivers can drop repeats.
+	EventIDHeader = "X-Webhook-Id"
+	// TimestampHeader is when the attempt was sent, in Unix seconds.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader is Sign of the timestamp and body.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Sign returns the signature of a delivery: "sha256=" and the hex
+// HMAC-SHA256, keyed with the webhook's secret, of the timestamp, a dot
+// and the body. Signing the timestamp lets receivers reject old
+// deliveries replayed at them.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedBackoff is the wait before each retry.
+var signedBackoff = []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+
+// maxRetryAfter caps how long a receiver's Retry-After can hold a
+// delivery up.
+const maxRetryAfter = time.Minute
+
+// Signed posts messages to the generic webhooks of their subscription as
+// JSON events signed with the webhook's secret. Network errors, 408, 429
+// and 5xx responses are retried with backoff; a delivery that still fails
+// is written to the dead-letter log rather than failing the message, so
+// the other channels' deliveries aren't repeated for it.
+type Signed struct {
+	client     *http.Client
+	deadLetter *DeadLetter
+}
+
+// NewSigned posts with client, which should refuse private addresses
+// (httpclient.NewPublic), since the URLs come from subscribers.
+func NewSigned(client *http.Client, deadLetter *DeadLetter) *Signed {
+	return &Signed{client: client, deadLetter: deadLetter}
+}
+
+func (s *Signed) Name() string { return "webhook" }
+
+// signedEvent is the body of a delivery.
+type signedEvent struct {
+	ID string `json:"id"`
+	*Message
+}
+
+func (s *Signed) Notify(ctx context.Context, msg *Message) error {
+	if !hasWebhook(msg, WebhookGeneric) {
+		return nil
+	}
+	id := uuid.NewString()
+	body, err := json.Marshal(signedEvent{ID: id, Message: msg})
+	if err != nil {
+		return fmt.Errorf("encode webhook event: %v", err)
+	}
+	var errs []error
+	for _, w := range msg.Webhooks {
+		if w.Kind != WebhookGeneric {
+			continue
+		}
+		attempts, err := s.deliver(ctx, w, id, body)
+		if err == nil {
+			continue
+		}
+		webhookDeliveries.WithLabelValues("dead_lettered").Inc()
+		if err := s.deadLetter.write(deadLetterEntry{
+			Time:           time.Now(),
+			EventID:        id,
+			SubscriptionID: msg.SubscriptionID,
+			Webhook:        w.Redacted(),
+			Attempts:       attempts,
+			Error:          err.Error(),
+			Event:          body,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// permanentError is a failure retrying won't fix.
+type permanentError struct{ error }
+
+// deliver posts body until it is accepted, fails permanently, the retries
+// run out or ctx's deadline would pass before the next one. It returns the
+// number of attempts.
+func (s *Signed) deliver(ctx context.Context, w Webhook, id string, body []byte) (int, error) {
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.post(ctx, w, id, body)
+		if err == nil {
+			webhookDeliveries.WithLabelValues("delivered").Inc()
+			return attempt, nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt > len(signedBackoff) {
+			return attempt, err
+		}
+		wait := max(signedBackoff[attempt-1], retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return attempt, err
+		}
+		webhookDeliveries.WithLabelValues("retried").Inc()
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// post makes one attempt. On 429 and 503 it also returns the receiver's
+// Retry-After.
+func (s *Signed) post(ctx context.Context, w Webhook, id string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, permanentError{err}
+	}
+	now := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, id)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now, 10))
+	req.Header.Set(SignatureHeader, Sign(w.Secret, now, body))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapping, which quotes the URL.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		if errors.Is(err, httpclient.ErrNotPublic) {
+			return 0, permanentError{err}
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch code := resp.StatusCode; {
+	case code/100 == 2:
+		return 0, nil
+	case code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return min(time.Duration(secs)*time.Second, maxRetryAfter), fmt.Errorf("status %d", code)
+	case code == http.StatusRequestTimeout || code >= 500:
+		return 0, fmt.Errorf("status %d", code)
+	default:
+		return 0, permanentError{fmt.Errorf("status %d", code)}
+	}
+}
+
+// DeadLetter records generic webhook deliveries that failed for good, as
+// JSON lines, so they can be looked into and replayed.
+type DeadLetter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+type deadLetterEntry struct {
+	Time           time.Time `json:"time"`
+	EventID        string    `json:"event_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	// Webhook is the redacted URL.
+	Webhook  string          `json:"webhook"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Event    json.RawMessage `json:"event"`
+}
+
+// NewDeadLetter appends dead letters to path. With no path they are
+// written to the server log.
+func NewDeadLetter(path string) (*DeadLetter, error) {
+	if path == "" {
+		return &DeadLetter{}, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("dead-letter file: %v", err)
+	}
+	return &DeadLetter{file: f}, nil
+}
+
+func (d *DeadLetter) write(e deadLetterEntry) error {
+	if d == nil || d.file == nil {
+		slog.Error("webhook delivery failed, dead-lettered", "event_id", e.EventID, "subscription", e.SubscriptionID,
+			"webhook", e.Webhook, "attempts", e.Attempts, "error", e.Error, "event", string(e.Event))
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode dead letter: %v", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write dead letter: %v", err)
+	}
+	return nil
+}
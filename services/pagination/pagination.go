@@ -0,0 +1,110 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	advisorpb "github.com/pixperk/effinarounf/shared/proto/advisorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// cursor is what a page token encodes. Filter ties the token to the query
+// that produced it so it can't be replayed against different filters.
+type cursor struct {
+	Offset int    `json:"o"`
+	Filter uint64 `json:"f"`
+}
+
+// Paginate filters items by time, orders them and returns one page. key
+// identifies the collection (for example a session ID) and is folded into the
+// page token along with the filters. Items are expected oldest first.
+func Paginate[T any](items []T, timeOf func(T) time.Time, key string, req *advisorpb.PageRequest) ([]T, *advisorpb.PageResponse, error) {
+	if req == nil {
+		req = &advisorpb.PageRequest{}
+	}
+	if req.PageSize < 0 {
+		return nil, nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	if req.StartTime != 0 && req.EndTime != 0 && req.EndTime <= req.StartTime {
+		return nil, nil, status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	}
+
+	size := int(req.PageSize)
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	filter := filterHash(key, req)
+	offset := 0
+	if req.PageToken != "" {
+		c, err := decodeToken(req.PageToken)
+		if err != nil || c.Filter != filter || c.Offset < 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		offset = c.Offset
+	}
+
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		at := timeOf(item).Unix()
+		if req.StartTime != 0 && at < req.StartTime {
+			continue
+		}
+		if req.EndTime != 0 && at >= req.EndTime {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if req.NewestFirst {
+			return timeOf(matched[i]).After(timeOf(matched[j]))
+		}
+		return timeOf(matched[i]).Before(timeOf(matched[j]))
+	})
+
+	resp := &advisorpb.PageResponse{TotalSize: int32(len(matched))}
+	if offset >= len(matched) {
+		return nil, resp, nil
+	}
+	end := offset + size
+	if end < len(matched) {
+		resp.NextPageToken = encodeToken(cursor{Offset: end, Filter: filter})
+	} else {
+		end = len(matched)
+	}
+	return matched[offset:end], resp, nil
+}
+
+func filterHash(key string, req *advisorpb.PageRequest) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%d|%t", key, req.StartTime, req.EndTime, req.NewestFirst)
+	return h.Sum64()
+}
+
+func encodeToken(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeToken(token string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
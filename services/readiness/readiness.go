@@ -0,0 +1,150 @@
+// Package readiness answers Kubernetes liveness and readiness probes. A
+// server is ready while every dependency it needs answers; results are
+// cached so frequent probes don't turn into a stream of upstream calls.
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var probeUp = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "readiness_probe_up",
+		Help: "Whether the last readiness check of a dependency passed (1) or failed (0)",
+	},
+	[]string{"probe"},
+)
+
+// Probe checks that one dependency is reachable.
+type Probe struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type result struct {
+	err     error
+	checked time.Time
+}
+
+// Checker runs the probes and remembers their results for ttl.
+type Checker struct {
+	probes  []Probe
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu       sync.Mutex
+	results  map[string]result
+	draining atomic.Bool
+}
+
+// New returns a Checker that reuses each probe's result for ttl and gives
+// every check up to timeout.
+func New(ttl, timeout time.Duration, probes ...Probe) *Checker {
+	return &Checker{probes: probes, ttl: ttl, timeout: timeout, results: make(map[string]result)}
+}
+
+// Drain makes the server report not ready from now on, so traffic moves
+// away while it shuts down.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// check returns every probe's result, rerunning the stale ones in
+// parallel.
+func (c *Checker) check(ctx context.Context) map[string]error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	var resMu sync.Mutex
+	for _, p := range c.probes {
+		if r, ok := c.results[p.Name]; ok && now.Sub(r.checked) < c.ttl {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			err := p.Check(ctx)
+			resMu.Lock()
+			c.results[p.Name] = result{err: err, checked: now}
+			resMu.Unlock()
+			if err != nil {
+				probeUp.WithLabelValues(p.Name).Set(0)
+			} else {
+				probeUp.WithLabelValues(p.Name).Set(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	out := make(map[string]error, len(c.probes))
+	for _, p := range c.probes {
+		out[p.Name] = c.results[p.Name].err
+	}
+	return out
+}
+
+// ServeHTTP answers /readyz: 200 when every probe passes, 503 otherwise or
+// while draining, with each probe's status as JSON.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := struct {
+		Ready  bool              `json:"ready"`
+		Checks map[string]string `json:"checks"`
+	}{Ready: true, Checks: make(map[string]string)}
+	for name, err := range c.check(r.Context()) {
+		report.Checks[name] = "ok"
+		if err != nil {
+			report.Checks[name] = err.Error()
+			report.Ready = false
+		}
+	}
+	if c.draining.Load() {
+		report.Checks["server"] = "shutting down"
+		report.Ready = false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// Live answers /healthz. It only shows the process is serving HTTP, so a
+// dependency outage never gets the pod restarted.
+func Live(w http.ResponseWriter, r *http.Request) {
+	io.WriteString(w, "ok\n")
+}
+
+// HTTPProbe passes when a GET of url answers with a 2xx status.
+func HTTPProbe(name string, client *http.Client, url string) Probe {
+	return Probe{Name: name, Check: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s returned %s", name, resp.Status)
+		}
+		return nil
+	}}
+}
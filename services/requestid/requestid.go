@@ -0,0 +1,49 @@
+// Package requestid carries a per-request ID from the edge of the server
+// to its logs, spans, upstream calls and error messages, so one failing
+// call can be followed end to end.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header and gRPC metadata key the ID travels in.
+const Header = "x-request-id"
+
+// maxLength bounds a caller-supplied ID.
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New returns a fresh random ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Sanitize returns a caller-supplied ID fit for logs and headers: printable
+// ASCII without spaces, at most 128 characters. It returns "" when nothing
+// usable is left, so a new ID is generated instead.
+func Sanitize(id string) string {
+	if len(id) > maxLength {
+		id = id[:maxLength]
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return ""
+		}
+	}
+	return id
+}
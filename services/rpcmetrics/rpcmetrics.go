@@ -0,0 +1,119 @@
+// Package rpcmetrics records how long every gRPC call the server handles
+// takes and how many are running, by method and status code. Unary calls
+// and streams get separate histograms, since streams such as dashboards
+// and alert subscriptions stay open for minutes or hours.
+package rpcmetrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Default bucket upper bounds in seconds. Unary advice calls wait on the
+// LLM, so the unary buckets go up to a minute.
+const (
+	DefaultUnaryBuckets  = "0.005,0.01,0.025,0.05,0.1,0.25,0.5,1,2.5,5,10,30,60"
+	DefaultStreamBuckets = "0.1,0.5,1,2.5,5,10,30,60,300,900,1800,3600"
+)
+
+// ParseBuckets reads comma-separated, increasing, positive bucket upper
+// bounds in seconds.
+func ParseBuckets(s string) ([]float64, error) {
+	var out []float64
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		b, err := strconv.ParseFloat(raw, 64)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("bucket %q is not a positive number of seconds", raw)
+		}
+		if len(out) > 0 && b <= out[len(out)-1] {
+			return nil, fmt.Errorf("buckets must increase, got %g after %g", b, out[len(out)-1])
+		}
+		out = append(out, b)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no buckets")
+	}
+	return out, nil
+}
+
+// Metrics holds the collectors. There is one per process.
+type Metrics struct {
+	unary    *prometheus.HistogramVec
+	stream   *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+}
+
+// New registers the collectors with the default registry, with the given
+// bucket upper bounds in seconds.
+func New(unaryBuckets, streamBuckets []float64) *Metrics {
+	return &Metrics{
+		unary: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time to handle unary RPCs, by method and status code",
+			Buckets: unaryBuckets,
+		}, []string{"method", "code"}),
+		stream: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_stream_seconds",
+			Help:    "How long streaming RPCs stayed open, by method and status code",
+			Buckets: streamBuckets,
+		}, []string{"method", "code"}),
+		inFlight: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_in_flight",
+			Help: "RPCs being handled, by method",
+		}, []string{"method"}),
+	}
+}
+
+// Init exports an in-flight gauge of zero for every method s serves, so
+// methods show up before their first call. Call it once the services are
+// registered.
+func (m *Metrics) Init(s *grpc.Server) {
+	for service, info := range s.GetServiceInfo() {
+		for _, method := range info.Methods {
+			m.inFlight.WithLabelValues("/" + service + "/" + method.Name)
+		}
+	}
+}
+
+// UnaryServerInterceptor records unary calls. Install it first, so calls
+// rejected by the other interceptors are counted too.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		done := m.start(m.unary, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streaming calls from start to end.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := m.start(m.stream, info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+		return err
+	}
+}
+
+func (m *Metrics) start(hist *prometheus.HistogramVec, method string) func(error) {
+	gauge := m.inFlight.WithLabelValues(method)
+	gauge.Inc()
+	start := time.Now()
+	return func(err error) {
+		gauge.Dec()
+		hist.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// activityProfile is the weather an activity is comfortable in. Temperatures
+// are °C and wind km/h.
+type activityProfile struct {
+	minC, maxC  float64
+	maxWindKmh  float64
+	rainPenalty int
+	// wantsSun marks activities that are much worse under cloud.
+	wantsSun bool
+}
+
+var activityProfiles = map[string]activityProfile{
+	"running": {minC: 5, maxC: 22, maxWindKmh: 30, rainPenalty: 25},
+	"cycling": {minC: 8, maxC: 27, maxWindKmh: 25, rainPenalty: 40},
+	"picnic":  {minC: 18, maxC: 30, maxWindKmh: 20, rainPenalty: 70},
+	"beach":   {minC: 24, maxC: 35, maxWindKmh: 25, rainPenalty: 70, wantsSun: true},
+}
+
+// Activities lists the activities RateActivity knows, sorted.
+func Activities() []string {
+	names := make([]string, 0, len(activityProfiles))
+	for name := range activityProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RateActivity scores metric weather for an activity from 0 (don't) to 100
+// (ideal), with the reasons points were taken off. Values in the reasons
+// use conv.
+func RateActivity(activity string, w *weatherpb.WeatherResponse, conv units.Conventions) (int, []string, error) {
+	p, ok := activityProfiles[activity]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown activity %q", activity)
+	}
+
+	score := 100
+	var reasons []string
+	penalize := func(points int, reason string, args ...any) {
+		score -= points
+		reasons = append(reasons, fmt.Sprintf(reason, args...))
+	}
+
+	temp := w.FeelsLike
+	switch {
+	case temp < p.minC:
+		penalize(capped(3*(p.minC-temp), 50), "too cold: feels like %.0f%s, best above %.0f%s",
+			conv.Temp(temp), conv.Temperature, conv.Temp(p.minC), conv.Temperature)
+	case temp > p.maxC:
+		penalize(capped(4*(temp-p.maxC), 60), "too hot: feels like %.0f%s, best below %.0f%s",
+			conv.Temp(temp), conv.Temperature, conv.Temp(p.maxC), conv.Temperature)
+	}
+	if w.Temperature >= humidHeatC && w.Humidity >= humidHeatHumPct {
+		penalize(15, "humid heat (%d%% humidity)", w.Humidity)
+	}
+	if w.WindSpeed > p.maxWindKmh {
+		penalize(capped(2*(w.WindSpeed-p.maxWindKmh), 40), "windy: %.0f %s",
+			conv.Wind(w.WindSpeed), conv.WindSpeed)
+	}
+
+	switch {
+	case isThunderstorm(w.WeatherCode):
+		penalize(score, "thunderstorms")
+	case isSnow(w.WeatherCode):
+		penalize(p.rainPenalty, "snow")
+	case isPrecipitation(w.WeatherCode):
+		penalize(p.rainPenalty, "%s", w.Description)
+	case isFog(w.WeatherCode) && activity == "cycling":
+		penalize(20, "fog reduces visibility")
+	}
+
+	if p.wantsSun {
+		if !w.IsDay {
+			penalize(50, "after dark")
+		} else if w.WeatherCode > 2 {
+			penalize(25, "no sunshine (%s)", w.Description)
+		}
+	}
+	if w.IsDay && w.UvIndex >= 8 && activity != "beach" {
+		penalize(10, "very high UV (%.0f)", w.UvIndex)
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	if len(reasons) == 0 {
+		reasons = append(reasons, "conditions are ideal")
+	}
+	return score, reasons, nil
+}
+
+func capped(points, max float64) int {
+	return int(math.Round(math.Min(points, max)))
+}
+
+// outdoorProfile is used by RateDay when no activity is given: general
+// sightseeing and errands.
+var outdoorProfile = activityProfile{minC: 15, maxC: 28, maxWindKmh: 30, rainPenalty: 60}
+
+// RateDay scores a forecast day for an activity, or for general outdoor
+// plans when activity is empty. The daytime high stands in for the
+// temperature and the rain penalty scales with the chance of rain. The
+// first reason always summarises the day.
+func RateDay(activity string, d *weatherpb.DailyConditions, conv units.Conventions) (int, []string, error) {
+	p := outdoorProfile
+	if activity != "" {
+		var ok bool
+		if p, ok = activityProfiles[activity]; !ok {
+			return 0, nil, fmt.Errorf("unknown activity %q", activity)
+		}
+	}
+
+	score := 100
+	reasons := []string{fmt.Sprintf("%s, high %.0f%s, %d%% chance of rain, wind up to %.0f %s",
+		d.Description, conv.Temp(d.TempMax), conv.Temperature, d.PrecipitationProbability,
+		conv.Wind(d.WindSpeedMax), conv.WindSpeed)}
+	penalize := func(points int, reason string, args ...any) {
+		score -= points
+		reasons = append(reasons, fmt.Sprintf(reason, args...))
+	}
+
+	switch {
+	case d.TempMax < p.minC:
+		penalize(capped(3*(p.minC-d.TempMax), 50), "too cold")
+	case d.TempMax > p.maxC:
+		penalize(capped(4*(d.TempMax-p.maxC), 60), "too hot")
+	}
+	if d.WindSpeedMax > p.maxWindKmh {
+		penalize(capped(2*(d.WindSpeedMax-p.maxWindKmh), 40), "windy")
+	}
+	switch {
+	case isThunderstorm(d.WeatherCode):
+		penalize(score, "thunderstorms")
+	case isSnow(d.WeatherCode) || isPrecipitation(d.WeatherCode):
+		penalize(capped(float64(p.rainPenalty)*math.Max(float64(d.PrecipitationProbability), 50)/100, 100), "%s likely", d.Description)
+	case d.PrecipitationProbability >= 30:
+		penalize(capped(float64(p.rainPenalty)*float64(d.PrecipitationProbability)/100, 100), "risk of showers")
+	}
+	if p.wantsSun && d.WeatherCode > 2 {
+		penalize(25, "little sunshine")
+	}
+	if d.UvIndexMax >= 8 && !p.wantsSun {
+		penalize(10, "very high UV (%.0f) around midday", d.UvIndexMax)
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	return score, reasons, nil
+}
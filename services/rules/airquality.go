@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// Limits for low-exposure hours. Exercise uses the US EPA "good" band
+// because people with asthma react at moderate levels; cyclists also want
+// mild temperatures and light wind. Ventilation only cares about outdoor
+// air, plus not letting in extreme heat or cold.
+const (
+	goodAQI            = 50
+	goodPM25           = 12.0
+	exerciseMinC       = 5.0
+	exerciseMaxC       = 27.0
+	exerciseMaxWindKmh = 30.0
+	ventilateMinC      = 0.0
+	ventilateMaxC      = 30.0
+	maxWindows         = 3
+)
+
+// Window is a run of consecutive forecast hours. End is exclusive.
+type Window struct {
+	Start  time.Time
+	End    time.Time
+	MaxAQI int32
+	AvgAQI float64
+}
+
+// ExposurePlan holds the best windows for outdoor exercise and for opening
+// windows at home, cleanest first.
+type ExposurePlan struct {
+	Exercise    []Window
+	Ventilation []Window
+}
+
+func goodForExercise(h *weatherpb.HourlyConditions) bool {
+	return h.UsAqi <= goodAQI && h.Pm2_5 <= goodPM25 &&
+		h.Temperature >= exerciseMinC && h.Temperature <= exerciseMaxC &&
+		h.WindSpeed <= exerciseMaxWindKmh
+}
+
+func goodForVentilation(h *weatherpb.HourlyConditions) bool {
+	return h.UsAqi <= goodAQI && h.Pm2_5 <= goodPM25 &&
+		h.Temperature >= ventilateMinC && h.Temperature <= ventilateMaxC
+}
+
+// ExposureWindows finds low-exposure hours in a forecast. Hours must be in
+// metric units, oldest first.
+func ExposureWindows(hours []*weatherpb.HourlyConditions) ExposurePlan {
+	return ExposurePlan{
+		Exercise:    windows(hours, goodForExercise),
+		Ventilation: windows(hours, goodForVentilation),
+	}
+}
+
+func windows(hours []*weatherpb.HourlyConditions, ok func(*weatherpb.HourlyConditions) bool) []Window {
+	var out []Window
+	var cur *Window
+	var sum float64
+	var n int
+	flush := func() {
+		if cur != nil {
+			cur.AvgAQI = sum / float64(n)
+			out = append(out, *cur)
+			cur = nil
+		}
+	}
+	for i, h := range hours {
+		if !ok(h) {
+			flush()
+			continue
+		}
+		if cur != nil && h.Time-hours[i-1].Time != 3600 {
+			flush()
+		}
+		start := time.Unix(h.Time, 0)
+		if cur == nil {
+			cur = &Window{Start: start}
+			sum, n = 0, 0
+		}
+		cur.End = start.Add(time.Hour)
+		if h.UsAqi > cur.MaxAQI {
+			cur.MaxAQI = h.UsAqi
+		}
+		sum += float64(h.UsAqi)
+		n++
+	}
+	flush()
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].AvgAQI != out[j].AvgAQI {
+			return out[i].AvgAQI < out[j].AvgAQI
+		}
+		return out[i].End.Sub(out[i].Start) > out[j].End.Sub(out[j].Start)
+	})
+	if len(out) > maxWindows {
+		out = out[:maxWindows]
+	}
+	return out
+}
+
+// FormatExposure describes a plan for the LLM prompt, in the city's local
+// time.
+func FormatExposure(city string, plan ExposurePlan, utcOffsetSeconds int32) string {
+	loc := time.FixedZone("local", int(utcOffsetSeconds))
+	var b strings.Builder
+	fmt.Fprintf(&b, "Air quality for %s: ", city)
+	if len(plan.Exercise) == 0 {
+		b.WriteString("no low-pollution hours for outdoor exercise in the next 24h (people with asthma should exercise indoors)")
+	} else {
+		b.WriteString("best hours for outdoor exercise or cycling ")
+		b.WriteString(formatWindows(plan.Exercise, loc))
+	}
+	b.WriteString("; ")
+	if len(plan.Ventilation) == 0 {
+		b.WriteString("keep windows closed, outdoor air is not clean in the next 24h")
+	} else {
+		b.WriteString("best hours to open windows ")
+		b.WriteString(formatWindows(plan.Ventilation, loc))
+	}
+	return b.String()
+}
+
+func formatWindows(ws []Window, loc *time.Location) string {
+	parts := make([]string, 0, len(ws))
+	for _, w := range ws {
+		parts = append(parts, fmt.Sprintf("%s-%s (AQI up to %d)", w.Start.In(loc).Format("15:04"), w.End.In(loc).Format("15:04"), w.MaxAQI))
+	}
+	return strings.Join(parts, ", ")
+}
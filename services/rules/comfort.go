@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// Comfort is the objective part of clothing advice, computed from the data
+// so the model doesn't have to guess it.
+type Comfort struct {
+	// Layers is how many clothing layers to wear, from 1 (t-shirt) to 4
+	// (base layer, mid layer, insulation and a shell).
+	Layers    int
+	Umbrella  bool
+	Sunscreen string
+}
+
+// ComfortFor works out layers, umbrella and sunscreen for metric weather.
+// Strong wind counts as one layer colder.
+func ComfortFor(w *weatherpb.WeatherResponse) Comfort {
+	feels := w.FeelsLike
+	if w.WindSpeed >= strongWindKmh {
+		feels -= 5
+	}
+
+	var c Comfort
+	switch {
+	case feels >= 24:
+		c.Layers = 1
+	case feels >= 15:
+		c.Layers = 2
+	case feels >= 5:
+		c.Layers = 3
+	default:
+		c.Layers = 4
+	}
+	c.Umbrella = (isPrecipitation(w.WeatherCode) || isThunderstorm(w.WeatherCode)) && !isSnow(w.WeatherCode)
+	c.Sunscreen = sunscreenFor(w)
+	return c
+}
+
+// sunscreenFor follows the WHO UV index bands.
+func sunscreenFor(w *weatherpb.WeatherResponse) string {
+	switch {
+	case !w.IsDay || w.UvIndex < 3:
+		return "none"
+	case w.UvIndex < 6:
+		return "SPF 15+"
+	case w.UvIndex < 8:
+		return "SPF 30+"
+	default:
+		return "SPF 50+, avoid midday sun"
+	}
+}
+
+// ComfortLine is the prompt row for a city's computed facts.
+func ComfortLine(city string, w *weatherpb.WeatherResponse) string {
+	c := ComfortFor(w)
+	umbrella := "no"
+	if c.Umbrella {
+		umbrella = "yes"
+	}
+	return fmt.Sprintf("%s facts|layers %d|umbrella %s|sunscreen %s (UV %.0f)", city, c.Layers, umbrella, c.Sunscreen, w.UvIndex)
+}
+
+// formatComfort is the human-readable version used by TemplateAdvice.
+func formatComfort(c Comfort) string {
+	var parts []string
+	parts = append(parts, fmt.Sprintf("%d layer", c.Layers))
+	if c.Layers > 1 {
+		parts[0] += "s"
+	}
+	if c.Umbrella {
+		parts = append(parts, "umbrella")
+	}
+	if c.Sunscreen != "none" {
+		parts = append(parts, "sunscreen "+c.Sunscreen)
+	}
+	return strings.Join(parts, ", ")
+}
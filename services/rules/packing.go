@@ -0,0 +1,175 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// TripTypes lists the trip types PackingList knows; the first is the
+// default.
+var TripTypes = []string{"leisure", "business", "beach", "hiking"}
+
+// TripDay is one forecast day at one destination, in metric units.
+type TripDay struct {
+	City string
+	Day  *weatherpb.DailyConditions
+}
+
+// PackingItem is one line of a packing list.
+type PackingItem struct {
+	Category  string
+	Name      string
+	Quantity  int
+	Reason    string
+	Essential bool
+}
+
+// tripExtremes is the worst weather over the trip and where it happens.
+type tripExtremes struct {
+	coldest, hottest, wettest, sunniest, windiest TripDay
+	rain, snow                                    bool
+}
+
+func extremesOf(days []TripDay) tripExtremes {
+	e := tripExtremes{coldest: days[0], hottest: days[0], wettest: days[0], sunniest: days[0], windiest: days[0]}
+	for _, d := range days {
+		if d.Day.TempMin < e.coldest.Day.TempMin {
+			e.coldest = d
+		}
+		if d.Day.TempMax > e.hottest.Day.TempMax {
+			e.hottest = d
+		}
+		if d.Day.PrecipitationProbability > e.wettest.Day.PrecipitationProbability {
+			e.wettest = d
+		}
+		if d.Day.UvIndexMax > e.sunniest.Day.UvIndexMax {
+			e.sunniest = d
+		}
+		if d.Day.WindSpeedMax > e.windiest.Day.WindSpeedMax {
+			e.windiest = d
+		}
+		if isSnow(d.Day.WeatherCode) {
+			e.snow = true
+		}
+		if d.Day.PrecipitationProbability >= 40 || (isPrecipitation(d.Day.WeatherCode) && !isSnow(d.Day.WeatherCode)) || isThunderstorm(d.Day.WeatherCode) {
+			e.rain = true
+		}
+	}
+	return e
+}
+
+// PackingList builds a packing list for a trip from its forecast days. The
+// trip lasts as many days as there are distinct dates; the list covers the
+// worst weather at any destination.
+func PackingList(tripType string, days []TripDay, conv units.Conventions) ([]PackingItem, error) {
+	if tripType == "" {
+		tripType = TripTypes[0]
+	}
+	if !slices.Contains(TripTypes, tripType) {
+		return nil, fmt.Errorf("unknown trip type %q", tripType)
+	}
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no forecast days")
+	}
+
+	dates := make(map[string]bool)
+	for _, d := range days {
+		dates[d.Day.Date] = true
+	}
+	length := len(dates)
+	e := extremesOf(days)
+	at := func(d TripDay, what string, value float64, unit string) string {
+		return fmt.Sprintf("%s of %.0f%s in %s on %s", what, value, unit, d.City, d.Day.Date)
+	}
+	low := at(e.coldest, "low", conv.Temp(e.coldest.Day.TempMin), conv.Temperature)
+	high := at(e.hottest, "high", conv.Temp(e.hottest.Day.TempMax), conv.Temperature)
+
+	var items []PackingItem
+	add := func(category, name string, qty int, reason string, essential bool) {
+		items = append(items, PackingItem{Category: category, Name: name, Quantity: qty, Reason: reason, Essential: essential})
+	}
+
+	// Clothing for the number of days, washing once a week.
+	perDay := min(length, 7)
+	add("clothing", "Underwear", perDay+1, fmt.Sprintf("%d-day trip", length), true)
+	add("clothing", "Socks (pairs)", perDay+1, fmt.Sprintf("%d-day trip", length), true)
+	add("clothing", "Tops", perDay, fmt.Sprintf("%d-day trip", length), true)
+	add("clothing", "Trousers", max(1, (perDay+2)/3), fmt.Sprintf("%d-day trip", length), true)
+	add("clothing", "Sleepwear", 1, "", false)
+
+	switch {
+	case e.coldest.Day.TempMin < freezingC:
+		add("clothing", "Insulated winter coat", 1, low, true)
+		add("clothing", "Hat, gloves and scarf", 1, low, true)
+		add("clothing", "Thermal base layer", min(length, 3), low, false)
+	case e.coldest.Day.TempMin < 10:
+		add("clothing", "Warm jacket", 1, low, true)
+		add("clothing", "Sweater or fleece", 1, low, false)
+	case e.coldest.Day.TempMin < 18:
+		add("clothing", "Light jacket or sweater", 1, low, false)
+	}
+	if e.hottest.Day.TempMax >= 25 {
+		add("clothing", "Shorts or light skirts", min(length, 3), high, false)
+		add("sun", "Sun hat", 1, high, false)
+	}
+
+	if e.rain {
+		wet := fmt.Sprintf("%d%% chance of rain in %s on %s", e.wettest.Day.PrecipitationProbability, e.wettest.City, e.wettest.Day.Date)
+		if tripType == "hiking" {
+			add("rain", "Waterproof jacket and trousers", 1, wet, true)
+		} else {
+			add("rain", "Compact umbrella", 1, wet, true)
+			add("rain", "Water-resistant jacket", 1, wet, false)
+		}
+	}
+	if e.snow {
+		add("clothing", "Waterproof boots with grip", 1, "snow forecast", true)
+	}
+	if e.windiest.Day.WindSpeedMax >= 40 {
+		add("clothing", "Windproof layer", 1, fmt.Sprintf("gusts up to %.0f %s in %s on %s",
+			conv.Wind(e.windiest.Day.WindSpeedMax), conv.WindSpeed, e.windiest.City, e.windiest.Day.Date), false)
+	}
+
+	if uv := e.sunniest.Day.UvIndexMax; uv >= 3 {
+		reason := fmt.Sprintf("UV index up to %.0f in %s on %s", uv, e.sunniest.City, e.sunniest.Day.Date)
+		spf := "Sunscreen SPF 15+"
+		if uv >= 6 {
+			spf = "Sunscreen SPF 30+"
+		}
+		if uv >= 8 {
+			spf = "Sunscreen SPF 50+"
+		}
+		add("sun", spf, 1, reason, uv >= 6)
+		add("sun", "Sunglasses", 1, reason, false)
+	}
+
+	switch tripType {
+	case "business":
+		add("clothing", "Business outfits", perDay, "business trip", true)
+		add("clothing", "Dress shoes", 1, "business trip", true)
+	case "beach":
+		add("clothing", "Swimwear", 2, "beach trip", true)
+		add("gear", "Beach towel", 1, "beach trip", false)
+		add("clothing", "Sandals", 1, "beach trip", false)
+	case "hiking":
+		add("clothing", "Hiking boots", 1, "hiking trip", true)
+		add("gear", "Daypack", 1, "hiking trip", true)
+		add("gear", "Water bottle", 1, "hiking trip", true)
+		add("gear", "First-aid kit", 1, "hiking trip", false)
+	default:
+		add("clothing", "Comfortable walking shoes", 1, "sightseeing", true)
+	}
+
+	add("essentials", "Travel documents and ID", 1, "", true)
+	add("essentials", "Phone charger", 1, "", true)
+	add("essentials", "Toiletries", 1, "", true)
+
+	// Keep the list grouped by category in a fixed order.
+	order := map[string]int{"essentials": 0, "clothing": 1, "rain": 2, "sun": 3, "gear": 4}
+	sort.SliceStable(items, func(i, j int) bool { return order[items[i].Category] < order[items[j].Category] })
+	return items, nil
+}
@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// Thresholds for conditions that are dangerous to vulnerable people (older
+// adults, young children, people with heart or lung conditions, outdoor
+// workers). Wind speeds are in km/h.
+const (
+	extremeHeatC    = 35.0
+	heatC           = 32.0
+	extremeColdC    = -15.0
+	freezingC       = 0.0
+	galeWindKmh     = 62.0
+	strongWindKmh   = 50.0
+	humidHeatC      = 30.0
+	humidHeatHumPct = 70
+)
+
+func isThunderstorm(code int32) bool {
+	return code >= 95 && code <= 99
+}
+
+func isPrecipitation(code int32) bool {
+	return (code >= 51 && code <= 67) || (code >= 71 && code <= 77) || (code >= 80 && code <= 86)
+}
+
+// Severity ranks warnings. The values match advisorpb.Warning_Severity.
+type Severity int32
+
+const (
+	SeverityMinor    Severity = 1
+	SeverityModerate Severity = 2
+	SeveritySevere   Severity = 3
+	SeverityExtreme  Severity = 4
+)
+
+// Warning codes are stable identifiers downstream systems can filter on;
+// headlines follow the local weather service's wording and may change.
+const (
+	CodeExtremeHeat  = "HEAT_EXTREME"
+	CodeHeat         = "HEAT"
+	CodeExtremeCold  = "COLD_EXTREME"
+	CodeIce          = "ICE"
+	CodeThunderstorm = "THUNDERSTORM"
+	CodeDamagingWind = "WIND_DAMAGING"
+	CodeStrongWind   = "WIND_STRONG"
+)
+
+// Warning is one dangerous condition in a city. Start and End are Unix
+// seconds; End is zero while the condition is ongoing.
+type Warning struct {
+	Code     string
+	Severity Severity
+	City     string
+	Headline string
+	Text     string
+	Start    int64
+	End      int64
+}
+
+// SafetyWarnings returns fixed-text warnings for dangerous conditions in a
+// city, most severe first. The wording never comes from the LLM so critical
+// warnings don't depend on model compliance. w must be in metric units;
+// conv decides how values and warning names are presented.
+func SafetyWarnings(city string, w *weatherpb.WeatherResponse, conv units.Conventions) []Warning {
+	var warnings []Warning
+	add := func(code string, severity Severity, headline, text string, args ...any) {
+		warnings = append(warnings, Warning{
+			Code:     code,
+			Severity: severity,
+			City:     city,
+			Headline: headline,
+			Text:     fmt.Sprintf("%s: %s"+text, append([]any{city, headline}, args...)...),
+			Start:    w.Timestamp,
+		})
+	}
+	hottest := w.Temperature
+	if w.FeelsLike > hottest {
+		hottest = w.FeelsLike
+	}
+
+	switch {
+	case hottest >= extremeHeatC:
+		add(CodeExtremeHeat, SeverityExtreme, conv.Terms.ExtremeHeat, " (%.0f%s). Risk of heatstroke. Older adults, young children, pregnant people and anyone with heart or lung conditions should stay in cool indoor spaces, drink water regularly and avoid exertion between 11:00 and 16:00. Never leave children or pets in vehicles.", conv.Temp(hottest), conv.Temperature)
+	case hottest >= heatC || (hottest >= humidHeatC && w.Humidity >= humidHeatHumPct):
+		add(CodeHeat, SeverityModerate, conv.Terms.Heat, " (%.0f%s, %d%% humidity). Limit strenuous activity, seek shade and check on vulnerable neighbours.", conv.Temp(hottest), conv.Temperature, w.Humidity)
+	}
+
+	switch {
+	case w.Temperature <= extremeColdC:
+		add(CodeExtremeCold, SeveritySevere, conv.Terms.ExtremeCold, " (%.0f%s). Frostbite and hypothermia can occur quickly. Cover exposed skin and keep outdoor time short, especially for older adults and infants.", conv.Temp(w.Temperature), conv.Temperature)
+	case w.Temperature <= freezingC && isPrecipitation(w.WeatherCode):
+		add(CodeIce, SeverityModerate, conv.Terms.Ice, " (%.0f%s with %s). Roads and pavements may be icy. Wear footwear with grip and avoid unnecessary travel; falls are a serious risk for older adults.", conv.Temp(w.Temperature), conv.Temperature, w.Description)
+	}
+
+	if isThunderstorm(w.WeatherCode) {
+		add(CodeThunderstorm, SeveritySevere, conv.Terms.Thunderstorm, ". Go indoors and stay away from open ground, tall trees and water until 30 minutes after the last thunder.")
+	}
+
+	switch {
+	case w.WindSpeed >= galeWindKmh:
+		add(CodeDamagingWind, SeveritySevere, conv.Terms.DamagingWind, " (%.0f %s). Falling branches and debris are likely. Avoid wooded areas and coastal paths and secure loose objects.", conv.Wind(w.WindSpeed), conv.WindSpeed)
+	case w.WindSpeed >= strongWindKmh:
+		add(CodeStrongWind, SeverityMinor, conv.Terms.StrongWind, " (%.0f %s). Take care cycling and near trees.", conv.Wind(w.WindSpeed), conv.WindSpeed)
+	}
+
+	SortWarnings(warnings)
+	return warnings
+}
+
+// SortWarnings orders warnings most severe first, keeping the original
+// order within a severity.
+func SortWarnings(warnings []Warning) {
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Severity > warnings[j].Severity })
+}
+
+// SafetyBlock formats warnings as a block that is prepended to the advice,
+// most severe first across all cities. It returns an empty string when there is nothing to warn about.
+func SafetyBlock(warnings []Warning) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	ranked := append([]Warning(nil), warnings...)
+	SortWarnings(ranked)
+
+	var b strings.Builder
+	b.WriteString("⚠️  SAFETY WARNINGS\n")
+	for _, warning := range ranked {
+		b.WriteString("- ")
+		b.WriteString(warning.Text)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
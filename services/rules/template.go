@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// CityWeather is one city's conditions, in metric units, with the
+// conventions used to present them.
+type CityWeather struct {
+	Name    string
+	Weather *weatherpb.WeatherResponse
+	Conv    units.Conventions
+}
+
+func isSnow(code int32) bool {
+	return (code >= 71 && code <= 77) || code == 85 || code == 86
+}
+
+func isFog(code int32) bool {
+	return code == 45 || code == 48
+}
+
+func clothingFor(tempC float64) string {
+	switch {
+	case tempC < 0:
+		return "a heavy winter coat, hat, gloves and warm footwear"
+	case tempC < 10:
+		return "a warm coat and layers"
+	case tempC < 18:
+		return "a light jacket or sweater"
+	case tempC < 25:
+		return "light clothing, with a layer for the evening"
+	default:
+		return "light, breathable clothing, a hat and sunscreen"
+	}
+}
+
+func activityFor(w *weatherpb.WeatherResponse) string {
+	switch {
+	case isThunderstorm(w.WeatherCode):
+		return "Stay indoors: museums, cafés or a cinema."
+	case isSnow(w.WeatherCode):
+		return "Good for a short winter walk; allow extra travel time."
+	case isPrecipitation(w.WeatherCode):
+		return "Plan indoor activities or short trips between showers."
+	case isFog(w.WeatherCode):
+		return "Visibility is poor; take care driving or cycling."
+	case w.WindSpeed >= strongWindKmh:
+		return "Avoid exposed areas such as coastlines and hilltops."
+	case w.Temperature >= heatC:
+		return "Keep outdoor plans to the morning or evening."
+	case w.Temperature >= 10 && w.WeatherCode <= 3:
+		return "A good day to be outside: parks, walking tours or cycling."
+	default:
+		return "Fine for errands and short walks."
+	}
+}
+
+// TemplateAdvice builds deterministic advice from fixed rules. It is used
+// when the LLM is unavailable, so the service still answers something
+// useful; safety warnings are added separately by SafetyBlock.
+func TemplateAdvice(cities []CityWeather) string {
+	var b strings.Builder
+	for i, c := range cities {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		w, conv := c.Weather, c.Conv
+		fmt.Fprintf(&b, "%s: %s, %.0f%s, wind %.0f %s.\n", c.Name, w.Description,
+			conv.Temp(w.Temperature), conv.Temperature, conv.Wind(w.WindSpeed), conv.WindSpeed)
+		comfort := ComfortFor(w)
+		fmt.Fprintf(&b, "- Wear %s (%s).\n", clothingFor(w.Temperature), formatComfort(comfort))
+		switch {
+		case isSnow(w.WeatherCode):
+			b.WriteString("- Snow expected: waterproof boots with good grip.\n")
+		case comfort.Umbrella:
+			b.WriteString("- Take an umbrella or a waterproof jacket.\n")
+		}
+		if w.WindSpeed >= strongWindKmh {
+			b.WriteString("- Strong wind: secure loose items and skip the umbrella if it's gusty.\n")
+		}
+		fmt.Fprintf(&b, "- %s\n", activityFor(w))
+	}
+	return b.String()
+}
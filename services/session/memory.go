@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	session   Session
+	expiresAt time.Time
+}
+
+type MemoryStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]memoryEntry
+}
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:      ttl,
+		sessions: make(map[string]memoryEntry),
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(m.sessions, id)
+		return nil, ErrNotFound
+	}
+
+	sess := entry.session
+	sess.Messages = append([]Message(nil), entry.session.Messages...)
+	return &sess, nil
+}
+
+func (m *MemoryStore) Save(_ context.Context, sess *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.evictExpired(now)
+
+	stored := *sess
+	stored.Messages = append([]Message(nil), sess.Messages...)
+	stored.UpdatedAt = now
+	m.sessions[sess.ID] = memoryEntry{session: stored, expiresAt: now.Add(m.ttl)}
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// evictExpired runs on every write so abandoned sessions don't pile up.
+// Callers must hold m.mu.
+func (m *MemoryStore) evictExpired(now time.Time) {
+	for id, entry := range m.sessions {
+		if now.After(entry.expiresAt) {
+			delete(m.sessions, id)
+		}
+	}
+}
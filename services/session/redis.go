@@ -0,0 +1,206 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redisKeyPrefix = "weather-advisor:session:"
+
+// RedisStore shares sessions between server replicas. It speaks just enough
+// RESP to issue GET, SET EX and DEL, and keeps a small pool of connections.
+type RedisStore struct {
+	addr     string
+	password string
+	db       int
+	ttl      time.Duration
+	pool     chan *redisConn
+}
+
+type redisConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+func NewRedisStore(addr, password string, db int, ttl time.Duration) *RedisStore {
+	return &RedisStore{
+		addr:     addr,
+		password: password,
+		db:       db,
+		ttl:      ttl,
+		pool:     make(chan *redisConn, 8),
+	}
+}
+
+func (r *RedisStore) Get(ctx context.Context, id string) (*Session, error) {
+	reply, err := r.do(ctx, "GET", redisKeyPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNotFound
+	}
+
+	var sess Session
+	if err := json.Unmarshal(reply, &sess); err != nil {
+		return nil, fmt.Errorf("decode session %s: %v", id, err)
+	}
+	return &sess, nil
+}
+
+func (r *RedisStore) Save(ctx context.Context, sess *Session) error {
+	stored := *sess
+	stored.UpdatedAt = time.Now()
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encode session %s: %v", sess.ID, err)
+	}
+
+	ttl := int64(r.ttl / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	_, err = r.do(ctx, "SET", redisKeyPrefix+sess.ID, string(data), "EX", strconv.FormatInt(ttl, 10))
+	return err
+}
+
+func (r *RedisStore) Delete(ctx context.Context, id string) error {
+	_, err := r.do(ctx, "DEL", redisKeyPrefix+id)
+	return err
+}
+
+func (r *RedisStore) Close() {
+	for {
+		select {
+		case c := <-r.pool:
+			c.conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+func (r *RedisStore) do(ctx context.Context, args ...string) ([]byte, error) {
+	c, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetDeadline(deadline)
+	} else {
+		c.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	}
+
+	reply, err := c.command(args...)
+	if err != nil {
+		var redisErr redisError
+		if !errors.As(err, &redisErr) {
+			// Protocol or network failure: the connection state is unknown.
+			c.conn.Close()
+			return nil, fmt.Errorf("redis %s failed: %v", args[0], err)
+		}
+		r.release(c)
+		return nil, fmt.Errorf("redis %s failed: %v", args[0], err)
+	}
+
+	r.release(c)
+	return reply, nil
+}
+
+func (r *RedisStore) acquire(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.pool:
+		return c, nil
+	default:
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis dial failed: %v", err)
+	}
+	c := &redisConn{conn: conn, rd: bufio.NewReader(conn)}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if r.password != "" {
+		if _, err := c.command("AUTH", r.password); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis auth failed: %v", err)
+		}
+	}
+	if r.db != 0 {
+		if _, err := c.command("SELECT", strconv.Itoa(r.db)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis select failed: %v", err)
+		}
+	}
+	return c, nil
+}
+
+func (r *RedisStore) release(c *redisConn) {
+	select {
+	case r.pool <- c:
+	default:
+		c.conn.Close()
+	}
+}
+
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func (c *redisConn) command(args ...string) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply decodes a single RESP reply. A nil bulk string is returned as a
+// nil slice so callers can tell a missing key from an empty value.
+func (c *redisConn) readReply() ([]byte, error) {
+	line, err := c.rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.rd, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("unexpected reply %q", line)
+	}
+}
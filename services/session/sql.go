@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/storage"
+)
+
+// SQLStore keeps sessions in the database, so they survive restarts and
+// follow the user between replicas sharing it. Expired rows are ignored
+// on read and removed by Prune.
+type SQLStore struct {
+	db  *storage.DB
+	ttl time.Duration
+}
+
+func NewSQLStore(db *storage.DB, ttl time.Duration) *SQLStore {
+	return &SQLStore{db: db, ttl: ttl}
+}
+
+func (s *SQLStore) Get(ctx context.Context, id string) (*Session, error) {
+	var messages string
+	var updated int64
+	err := s.db.QueryRow(ctx, `SELECT messages, updated_at FROM sessions WHERE id = ? AND expires_at > ?`,
+		id, time.Now().UnixMilli()).Scan(&messages, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session %s: %v", id, err)
+	}
+
+	sess := &Session{ID: id, UpdatedAt: time.UnixMilli(updated)}
+	if err := json.Unmarshal([]byte(messages), &sess.Messages); err != nil {
+		return nil, fmt.Errorf("decode session %s: %v", id, err)
+	}
+	return sess, nil
+}
+
+func (s *SQLStore) Save(ctx context.Context, sess *Session) error {
+	messages, err := json.Marshal(sess.Messages)
+	if err != nil {
+		return fmt.Errorf("encode session %s: %v", sess.ID, err)
+	}
+	now := time.Now()
+	_, err = s.db.Exec(ctx, `INSERT INTO sessions (id, messages, updated_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET messages = excluded.messages, updated_at = excluded.updated_at, expires_at = excluded.expires_at`,
+		sess.ID, string(messages), now.UnixMilli(), now.Add(s.ttl).UnixMilli())
+	if err != nil {
+		return fmt.Errorf("write session %s: %v", sess.ID, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.db.Exec(ctx, `DELETE FROM sessions WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete session %s: %v", id, err)
+	}
+	return nil
+}
+
+// Prune deletes expired sessions and returns how many there were.
+func (s *SQLStore) Prune(ctx context.Context) (int64, error) {
+	res, err := s.db.Exec(ctx, `DELETE FROM sessions WHERE expires_at <= ?`, time.Now().UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("prune sessions: %v", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,36 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("session not found")
+
+type Message struct {
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	At      time.Time `json:"at"`
+}
+
+type Session struct {
+	ID        string    `json:"id"`
+	Messages  []Message `json:"messages"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionStore keeps conversation state between requests. Implementations
+// expire sessions that haven't been saved within their TTL.
+type SessionStore interface {
+	Get(ctx context.Context, id string) (*Session, error)
+	Save(ctx context.Context, sess *Session) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Trim drops the oldest messages so at most max remain.
+func (s *Session) Trim(max int) {
+	if max > 0 && len(s.Messages) > max {
+		s.Messages = append([]Message(nil), s.Messages[len(s.Messages)-max:]...)
+	}
+}
@@ -0,0 +1,104 @@
+package sink
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sinkEvents = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "advisor_sink_events_total",
+		Help: "Advice stream events handed to output sinks, by result",
+	},
+	[]string{"sink", "result"},
+)
+
+// Event is one piece of a streamed advice, as seen by the client.
+type Event struct {
+	StreamID     string `json:"stream_id"`
+	SessionID    string `json:"session_id,omitempty"`
+	Sequence     uint64 `json:"sequence"`
+	Chunk        string `json:"chunk,omitempty"`
+	Complete     bool   `json:"complete,omitempty"`
+	FinishReason string `json:"finish_reason,omitempty"`
+	Fallback     bool   `json:"fallback,omitempty"`
+	// Aborted marks a stream that failed or was cancelled before it
+	// completed; FinishReason holds the status code. No more events follow.
+	Aborted bool      `json:"aborted,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Sink receives a copy of every advice stream. Write is called from one
+// goroutine per sink, in stream order.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, ev Event) error
+}
+
+// Fanout tees events to several sinks. Each sink has its own queue and
+// worker, so a slow or failing sink only loses its own events and never
+// holds up the client stream.
+type Fanout struct {
+	queues []chan Event
+	sinks  []Sink
+	wg     sync.WaitGroup
+}
+
+// NewFanout starts a worker per sink with a queue of buffer events. Events
+// for a sink whose queue is full are dropped.
+func NewFanout(sinks []Sink, buffer int) *Fanout {
+	f := &Fanout{sinks: sinks}
+	for _, s := range sinks {
+		q := make(chan Event, buffer)
+		f.queues = append(f.queues, q)
+		f.wg.Add(1)
+		go f.run(s, q)
+	}
+	return f
+}
+
+func (f *Fanout) run(s Sink, q <-chan Event) {
+	defer f.wg.Done()
+	for ev := range q {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := s.Write(ctx, ev)
+		cancel()
+		if err != nil {
+			slog.Error("stream sink failed", "sink", s.Name(), "error", err)
+			sinkEvents.WithLabelValues(s.Name(), "failed").Inc()
+			continue
+		}
+		sinkEvents.WithLabelValues(s.Name(), "delivered").Inc()
+	}
+}
+
+// Publish queues ev for every sink without blocking. It is a no-op on a nil
+// Fanout.
+func (f *Fanout) Publish(ev Event) {
+	if f == nil {
+		return
+	}
+	for i, q := range f.queues {
+		select {
+		case q <- ev:
+		default:
+			sinkEvents.WithLabelValues(f.sinks[i].Name(), "dropped").Inc()
+		}
+	}
+}
+
+// Close stops accepting events and waits for the queues to drain.
+func (f *Fanout) Close() {
+	if f == nil {
+		return
+	}
+	for _, q := range f.queues {
+		close(q)
+	}
+	f.wg.Wait()
+}
@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// webhookPendingTTL bounds how long an unfinished stream's chunks are kept.
+const webhookPendingTTL = 10 * time.Minute
+
+// Webhook POSTs each finished advice as one JSON document. Chunks are
+// collected per stream and sent when the stream completes, so the receiver
+// gets one call per advice rather than one per chunk.
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+	pending    map[string]*webhookAdvice
+}
+
+type webhookAdvice struct {
+	StreamID     string    `json:"stream_id"`
+	SessionID    string    `json:"session_id,omitempty"`
+	Advice       string    `json:"advice"`
+	FinishReason string    `json:"finish_reason,omitempty"`
+	Fallback     bool      `json:"fallback,omitempty"`
+	Chunks       uint64    `json:"chunks"`
+	StartedAt    time.Time `json:"started_at"`
+	CompletedAt  time.Time `json:"completed_at"`
+
+	text strings.Builder
+}
+
+func NewWebhook(url string, httpClient *http.Client) *Webhook {
+	return &Webhook{url: url, httpClient: httpClient, pending: make(map[string]*webhookAdvice)}
+}
+
+func (w *Webhook) Name() string { return "webhook" }
+
+func (w *Webhook) Write(ctx context.Context, ev Event) error {
+	// Streams that failed never complete; forget them after a while.
+	for id, adv := range w.pending {
+		if ev.Time.Sub(adv.StartedAt) > webhookPendingTTL {
+			delete(w.pending, id)
+		}
+	}
+
+	// Only finished advice is posted.
+	if ev.Aborted {
+		delete(w.pending, ev.StreamID)
+		return nil
+	}
+
+	adv, ok := w.pending[ev.StreamID]
+	if !ok {
+		adv = &webhookAdvice{StreamID: ev.StreamID, SessionID: ev.SessionID, StartedAt: ev.Time}
+		w.pending[ev.StreamID] = adv
+	}
+	adv.text.WriteString(ev.Chunk)
+	adv.Chunks = ev.Sequence
+	if !ev.Complete {
+		return nil
+	}
+	delete(w.pending, ev.StreamID)
+
+	adv.Advice = adv.text.String()
+	adv.FinishReason = ev.FinishReason
+	adv.Fallback = ev.Fallback
+	adv.CompletedAt = ev.Time
+	body, err := json.Marshal(adv)
+	if err != nil {
+		return fmt.Errorf("failed to encode advice: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
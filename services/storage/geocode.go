@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GeocodeCache keeps geocoding results, which rarely change, so a city
+// looked up before skips the geocoding API until its entry expires.
+type GeocodeCache struct {
+	db  *DB
+	ttl time.Duration
+}
+
+func NewGeocodeCache(db *DB, ttl time.Duration) *GeocodeCache {
+	return &GeocodeCache{db: db, ttl: ttl}
+}
+
+// Get returns the results cached for query, if they haven't expired.
+func (c *GeocodeCache) Get(ctx context.Context, query string) ([]byte, bool, error) {
+	var results string
+	err := c.db.QueryRow(ctx, `SELECT results FROM geocode_cache WHERE query = ? AND expires_at > ?`,
+		query, time.Now().UnixMilli()).Scan(&results)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("read geocode cache: %v", err)
+	}
+	return []byte(results), true, nil
+}
+
+// Put caches results for query.
+func (c *GeocodeCache) Put(ctx context.Context, query string, results []byte) error {
+	_, err := c.db.Exec(ctx, `INSERT INTO geocode_cache (query, results, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (query) DO UPDATE SET results = excluded.results, expires_at = excluded.expires_at`,
+		query, string(results), time.Now().Add(c.ttl).UnixMilli())
+	if err != nil {
+		return fmt.Errorf("write geocode cache: %v", err)
+	}
+	return nil
+}
+
+// Flush deletes every entry and returns how many there were.
+func (c *GeocodeCache) Flush(ctx context.Context) (int64, error) {
+	res, err := c.db.Exec(ctx, `DELETE FROM geocode_cache`)
+	if err != nil {
+		return 0, fmt.Errorf("flush geocode cache: %v", err)
+	}
+	return res.RowsAffected()
+}
+
+// Prune deletes expired entries and returns how many there were.
+func (c *GeocodeCache) Prune(ctx context.Context) (int64, error) {
+	res, err := c.db.Exec(ctx, `DELETE FROM geocode_cache WHERE expires_at <= ?`, time.Now().UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("prune geocode cache: %v", err)
+	}
+	return res.RowsAffected()
+}
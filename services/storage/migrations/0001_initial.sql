@@ -0,0 +1,45 @@
+-- Advice history, sessions, digest subscriptions, issued API keys and the
+-- geocode cache. Times are Unix milliseconds and lists are JSON. IF NOT
+-- EXISTS adopts databases created before migrations were introduced.
+
+CREATE TABLE IF NOT EXISTS advice_history (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	cities TEXT NOT NULL,
+	weather TEXT NOT NULL,
+	advice TEXT NOT NULL,
+	model TEXT NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS advice_history_session ON advice_history (session_id, created_at);
+
+CREATE TABLE IF NOT EXISTS sessions (
+	id TEXT PRIMARY KEY,
+	messages TEXT NOT NULL,
+	updated_at BIGINT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS digest_subscriptions (
+	id TEXT PRIMARY KEY,
+	recipient TEXT NOT NULL,
+	request TEXT NOT NULL,
+	hour INTEGER NOT NULL,
+	minute INTEGER NOT NULL,
+	time_zone TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	last_delivered BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	key_sha256 TEXT PRIMARY KEY,
+	tenant TEXT NOT NULL,
+	created_at BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS geocode_cache (
+	query TEXT PRIMARY KEY,
+	results TEXT NOT NULL,
+	expires_at BIGINT NOT NULL
+);
@@ -0,0 +1,18 @@
+-- Weather alert subscriptions. "window" is reserved in Postgres, hence
+-- alert_window.
+
+CREATE TABLE alert_subscriptions (
+	id TEXT PRIMARY KEY,
+	recipient TEXT NOT NULL,
+	location TEXT NOT NULL,
+	latitude DOUBLE PRECISION NOT NULL,
+	longitude DOUBLE PRECISION NOT NULL,
+	metric TEXT NOT NULL,
+	operator TEXT NOT NULL,
+	threshold DOUBLE PRECISION NOT NULL,
+	unit TEXT NOT NULL,
+	alert_window TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	last_alerted_date TEXT NOT NULL,
+	last_alerted_at BIGINT NOT NULL
+);
@@ -0,0 +1,5 @@
+-- Chat webhooks of digest and alert subscriptions, as a JSON array of
+-- {"kind", "url"}.
+
+ALTER TABLE digest_subscriptions ADD COLUMN webhooks TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE alert_subscriptions ADD COLUMN webhooks TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// AdminHandler serves API key management under /admin/api-keys:
+//
+//	POST   /admin/api-keys           issues a key for {"tenant": name}
+//	DELETE /admin/api-keys/{sha256}  revokes a key by its digest
+//
+// The key itself is only ever shown in the POST response; the store keeps
+// its digest. It needs a KeyStore set and does no authentication of its
+// own; mount it behind a check.
+func (r *Registry) AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/api-keys", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Tenant string `json:"tenant"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := r.quotas[body.Tenant]; !ok || body.Tenant == Default {
+			http.Error(w, "unknown tenant "+body.Tenant, http.StatusBadRequest)
+			return
+		}
+		raw := make([]byte, 32)
+		rand.Read(raw)
+		key := base64.RawURLEncoding.EncodeToString(raw)
+		digest := keyDigest(key)
+		if err := r.issued.Add(req.Context(), digest, body.Tenant); err != nil {
+			slog.ErrorContext(req.Context(), "issuing API key failed", "error", err)
+			http.Error(w, "issuing API key failed", http.StatusInternalServerError)
+			return
+		}
+		slog.InfoContext(req.Context(), "issued API key", "tenant", body.Tenant, "sha256", digest)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"tenant": body.Tenant, "api_key": key, "sha256": digest})
+	})
+	mux.HandleFunc("DELETE /admin/api-keys/{sha256}", func(w http.ResponseWriter, req *http.Request) {
+		digest := req.PathValue("sha256")
+		err := r.issued.Revoke(req.Context(), digest)
+		switch {
+		case errors.Is(err, ErrKeyNotFound):
+			http.Error(w, "no issued key has that digest", http.StatusNotFound)
+			return
+		case err != nil:
+			slog.ErrorContext(req.Context(), "revoking API key failed", "error", err)
+			http.Error(w, "revoking API key failed", http.StatusInternalServerError)
+			return
+		}
+		slog.InfoContext(req.Context(), "revoked API key", "sha256", digest)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
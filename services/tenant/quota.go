@@ -0,0 +1,215 @@
+package tenant
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/identity"
+	"github.com/pixperk/effinarounf/services/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	tenantRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tenant_requests_total",
+			Help: "RPCs by tenant and result: allowed, or the quota that rejected them",
+		},
+		[]string{"tenant", "result"},
+	)
+	tenantTokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tenant_llm_tokens_total",
+			Help: "LLM tokens used by tenant",
+		},
+		[]string{"tenant"},
+	)
+	tenantTokensToday = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "tenant_llm_tokens_today",
+			Help: "LLM tokens used by tenant in the current UTC day",
+		},
+		[]string{"tenant"},
+	)
+)
+
+// quotaState is one tenant's limits and usage. Usage is kept in memory, so
+// a restart starts the day afresh.
+type quotaState struct {
+	name     string
+	quota    Quota
+	requests *rate.Limiter
+
+	mu     sync.Mutex
+	day    string
+	tokens int64
+}
+
+func newQuotaState(name string, q Quota) *quotaState {
+	s := &quotaState{name: name, quota: q}
+	if q.RequestsPerMinute > 0 {
+		s.requests = rate.NewLimiter(rate.Limit(float64(q.RequestsPerMinute)/60), q.RequestsPerMinute)
+	}
+	return s
+}
+
+// roll starts a new day when the UTC date has changed. Callers must hold
+// s.mu.
+func (s *quotaState) roll(now time.Time) {
+	if day := now.UTC().Format("2006-01-02"); s.day != day {
+		s.day, s.tokens = day, 0
+	}
+}
+
+// tokensLeft reports whether the tenant may still call the LLM today.
+func (s *quotaState) tokensLeft(now time.Time) bool {
+	if s.quota.TokensPerDay == 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roll(now)
+	return s.tokens < s.quota.TokensPerDay
+}
+
+func (s *quotaState) charge(tokens int64, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roll(now)
+	s.tokens += tokens
+	tenantTokens.WithLabelValues(s.name).Add(float64(tokens))
+	tenantTokensToday.WithLabelValues(s.name).Set(float64(s.tokens))
+}
+
+// healthPrefix marks health checks, which no tenant pays for.
+const healthPrefix = "/grpc.health.v1.Health/"
+
+type stateKey struct{}
+
+// Name returns the tenant of the call ctx belongs to, or "" when tenants
+// are off.
+func Name(ctx context.Context) string {
+	if s, ok := ctx.Value(stateKey{}).(*quotaState); ok {
+		return s.name
+	}
+	return ""
+}
+
+// AddTokens charges LLM tokens to the tenant of the call ctx belongs to.
+// The call that crosses the daily quota finishes; the tenant's next LLM
+// call is refused. It does nothing when tenants are off.
+func AddTokens(ctx context.Context, tokens int32) {
+	if s, ok := ctx.Value(stateKey{}).(*quotaState); ok {
+		s.charge(int64(tokens), time.Now())
+	}
+}
+
+// authenticate establishes the caller's identity from an x-api-key header,
+// as the subject "tenant:<name>". Unknown keys are rejected; calls without
+// one pass through to the other credentials. Install it before the JWT
+// verifier, which lets API key callers through.
+func (r *Registry) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(APIKeyHeader)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	name, ok, err := r.tenantForKey(ctx, values[0])
+	if err != nil {
+		slog.ErrorContext(ctx, "API key lookup failed", "error", err)
+		return nil, status.Error(codes.Unavailable, "API key lookup failed")
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unknown API key")
+	}
+	return identity.NewContext(ctx, identity.Identity{Subject: "tenant:" + name, Source: identity.SourceAPIKey, Tenant: name}), nil
+}
+
+// enforce resolves the caller's tenant, applies its request quota and, for
+// the LLM methods, its daily token quota.
+func (r *Registry) enforce(ctx context.Context, llm bool) (context.Context, error) {
+	s := r.quotas[r.resolve(ctx)]
+	logging.AddAttrs(ctx, slog.String("tenant", s.name))
+	if s.requests != nil && !s.requests.Allow() {
+		tenantRequests.WithLabelValues(s.name, "requests").Inc()
+		return nil, status.Errorf(codes.ResourceExhausted, "tenant %s is over its %d requests per minute", s.name, s.quota.RequestsPerMinute)
+	}
+	if llm && !s.tokensLeft(time.Now()) {
+		tenantRequests.WithLabelValues(s.name, "tokens").Inc()
+		return nil, status.Errorf(codes.ResourceExhausted, "tenant %s has used its %d LLM tokens for today", s.name, s.quota.TokensPerDay)
+	}
+	tenantRequests.WithLabelValues(s.name, "allowed").Inc()
+	return context.WithValue(ctx, stateKey{}, s), nil
+}
+
+// UnaryServerInterceptor authenticates API keys. Install it before the JWT
+// verifier.
+func (r *Registry) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := r.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming RPCs.
+func (r *Registry) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := r.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, identity.WrapStream(ss, ctx))
+	}
+}
+
+// UnaryQuotaInterceptor holds every call but health checks to its tenant's
+// quotas. Calls to llmMethods are also refused once the tenant's tokens for
+// the day are used up. Install it after the identity interceptors.
+func (r *Registry) UnaryQuotaInterceptor(llmMethods []string) grpc.UnaryServerInterceptor {
+	llm := methodSet(llmMethods)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthPrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := r.enforce(ctx, llm[info.FullMethod])
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamQuotaInterceptor is UnaryQuotaInterceptor for streaming RPCs.
+func (r *Registry) StreamQuotaInterceptor(llmMethods []string) grpc.StreamServerInterceptor {
+	llm := methodSet(llmMethods)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthPrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := r.enforce(ss.Context(), llm[info.FullMethod])
+		if err != nil {
+			return err
+		}
+		return handler(srv, identity.WrapStream(ss, ctx))
+	}
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		set[m] = true
+	}
+	return set
+}
@@ -0,0 +1,52 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/storage"
+)
+
+// SQLKeyStore keeps issued API keys in the database.
+type SQLKeyStore struct {
+	db *storage.DB
+}
+
+func NewSQLKeyStore(db *storage.DB) *SQLKeyStore {
+	return &SQLKeyStore{db: db}
+}
+
+func (s *SQLKeyStore) Add(ctx context.Context, digest, tenant string) error {
+	_, err := s.db.Exec(ctx, `INSERT INTO api_keys (key_sha256, tenant, created_at) VALUES (?, ?, ?)`,
+		digest, tenant, time.Now().UnixMilli())
+	if err != nil {
+		return fmt.Errorf("add API key: %v", err)
+	}
+	return nil
+}
+
+func (s *SQLKeyStore) Revoke(ctx context.Context, digest string) error {
+	res, err := s.db.Exec(ctx, `DELETE FROM api_keys WHERE key_sha256 = ?`, digest)
+	if err != nil {
+		return fmt.Errorf("revoke API key: %v", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+func (s *SQLKeyStore) Lookup(ctx context.Context, digest string) (string, error) {
+	var tenant string
+	err := s.db.QueryRow(ctx, `SELECT tenant FROM api_keys WHERE key_sha256 = ?`, digest).Scan(&tenant)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("look up API key: %v", err)
+	}
+	return tenant, nil
+}
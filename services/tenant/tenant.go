@@ -0,0 +1,164 @@
+// Package tenant tells the customers of a shared deployment apart and
+// holds each to its own request and LLM token quotas, so one of them can't
+// use up everyone's Gemini budget.
+package tenant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pixperk/effinarounf/services/identity"
+	"go.yaml.in/yaml/v2"
+)
+
+// Default is the tenant of callers no tenant claims.
+const Default = "default"
+
+// APIKeyHeader is the metadata key API keys are sent in.
+const APIKeyHeader = "x-api-key"
+
+// Quota limits one tenant. Zero leaves a limit off.
+type Quota struct {
+	RequestsPerMinute int   `yaml:"requests_per_minute"`
+	TokensPerDay      int64 `yaml:"tokens_per_day"`
+}
+
+// Spec is one tenant in the tenants file.
+type Spec struct {
+	Quota `yaml:",inline"`
+	// APIKeySHA256 are the hex SHA-256 digests of the tenant's API keys, so
+	// the file itself holds no secrets.
+	APIKeySHA256 []string `yaml:"api_key_sha256"`
+	// Subjects are caller identities (token subjects or certificate
+	// names) that belong to the tenant.
+	Subjects []string `yaml:"subjects"`
+}
+
+// File is the tenants file.
+type File struct {
+	// Default applies to callers outside every listed tenant.
+	Default Quota           `yaml:"default"`
+	Tenants map[string]Spec `yaml:"tenants"`
+}
+
+// Registry resolves callers to tenants and tracks their quotas.
+type Registry struct {
+	quotas   map[string]*quotaState
+	keys     map[string]string
+	subjects map[string]string
+	// issued holds the API keys issued at runtime, if any.
+	issued KeyStore
+}
+
+// KeyStore holds API keys issued at runtime, on top of the tenants file.
+// Keys are identified by their hex SHA-256 digest.
+type KeyStore interface {
+	Add(ctx context.Context, digest, tenant string) error
+	// Revoke returns ErrKeyNotFound for unknown keys.
+	Revoke(ctx context.Context, digest string) error
+	// Lookup returns the key's tenant, or "" for an unknown key.
+	Lookup(ctx context.Context, digest string) (string, error)
+}
+
+// ErrKeyNotFound is returned when revoking an unknown key.
+var ErrKeyNotFound = errors.New("API key not found")
+
+// SetKeyStore accepts the keys in ks as well as those in the tenants file.
+func (r *Registry) SetKeyStore(ks KeyStore) {
+	r.issued = ks
+}
+
+// Load reads a tenants file.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tenants file: %v", err)
+	}
+	var f File
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("tenants file %s: %v", path, err)
+	}
+	return New(f)
+}
+
+// New builds a Registry from a parsed tenants file.
+func New(f File) (*Registry, error) {
+	r := &Registry{
+		quotas:   map[string]*quotaState{Default: newQuotaState(Default, f.Default)},
+		keys:     make(map[string]string),
+		subjects: make(map[string]string),
+	}
+	for name, spec := range f.Tenants {
+		if name == "" || name == Default {
+			return nil, fmt.Errorf("tenant name %q is reserved", name)
+		}
+		if spec.RequestsPerMinute < 0 || spec.TokensPerDay < 0 {
+			return nil, fmt.Errorf("tenant %s: quotas must not be negative", name)
+		}
+		r.quotas[name] = newQuotaState(name, spec.Quota)
+		for _, digest := range spec.APIKeySHA256 {
+			digest = strings.ToLower(strings.TrimSpace(digest))
+			if b, err := hex.DecodeString(digest); err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("tenant %s: api_key_sha256 %q is not a hex SHA-256 digest", name, digest)
+			}
+			if other, ok := r.keys[digest]; ok {
+				return nil, fmt.Errorf("tenants %s and %s share an API key", other, name)
+			}
+			r.keys[digest] = name
+		}
+		for _, subject := range spec.Subjects {
+			if other, ok := r.subjects[subject]; ok && other != name {
+				return nil, fmt.Errorf("subject %q belongs to both %s and %s", subject, other, name)
+			}
+			r.subjects[subject] = name
+		}
+	}
+	return r, nil
+}
+
+// Len returns the number of tenants, the default one included.
+func (r *Registry) Len() int {
+	return len(r.quotas)
+}
+
+// tenantForKey returns the tenant owning an API key. Keys in the tenants
+// file are checked before issued ones, and an issued key whose tenant has
+// since left the file is not accepted.
+func (r *Registry) tenantForKey(ctx context.Context, key string) (string, bool, error) {
+	digest := keyDigest(key)
+	if name, ok := r.keys[digest]; ok {
+		return name, true, nil
+	}
+	if r.issued == nil {
+		return "", false, nil
+	}
+	name, err := r.issued.Lookup(ctx, digest)
+	if err != nil {
+		return "", false, err
+	}
+	_, ok := r.quotas[name]
+	return name, ok && name != "", nil
+}
+
+func keyDigest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// resolve names the caller's tenant: the one its credential carries if it
+// is known, else the one its subject is listed under, else Default.
+func (r *Registry) resolve(ctx context.Context) string {
+	id, _ := identity.FromContext(ctx)
+	if _, ok := r.quotas[id.Tenant]; ok && id.Tenant != "" {
+		return id.Tenant
+	}
+	if name, ok := r.subjects[identity.Subject(ctx)]; ok {
+		return name
+	}
+	return Default
+}
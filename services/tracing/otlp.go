@@ -0,0 +1,327 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	queueSize     = 2048
+	batchSize     = 256
+	flushInterval = 5 * time.Second
+)
+
+var exportedSpans = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "tracing_spans_total",
+		Help: "Finished spans by export result",
+	},
+	[]string{"result"},
+)
+
+// exporter queues finished spans and posts them in batches as OTLP/HTTP
+// JSON. A full queue drops spans rather than slowing requests down.
+type exporter struct {
+	url        string
+	resource   resource
+	httpClient *http.Client
+
+	queue chan *span
+	done  chan struct{}
+	// stopped is closed once the final batch has been sent.
+	stopped chan struct{}
+}
+
+func newExporter(endpoint, serviceName string) *exporter {
+	e := &exporter{
+		url: strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		resource: resource{Attributes: []keyValue{
+			{Key: "service.name", Value: anyValueOf(attribute.StringValue(serviceName))},
+		}},
+		// Not the shared upstream client: that one is instrumented, and
+		// exporting would then trace itself.
+		httpClient: httpclient.New(10 * time.Second),
+		queue:      make(chan *span, queueSize),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (e *exporter) enqueue(s *span) {
+	select {
+	case e.queue <- s:
+	default:
+		exportedSpans.WithLabelValues("dropped").Inc()
+	}
+}
+
+func (e *exporter) run() {
+	defer close(e.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []*span
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			slog.Warn("exporting spans failed", "spans", len(batch), "error", err)
+			exportedSpans.WithLabelValues("failed").Add(float64(len(batch)))
+		} else {
+			exportedSpans.WithLabelValues("exported").Add(float64(len(batch)))
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			for {
+				select {
+				case s := <-e.queue:
+					batch = append(batch, s)
+					if len(batch) >= batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *exporter) shutdown(ctx context.Context) error {
+	close(e.done)
+	select {
+	case <-e.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *exporter) send(batch []*span) error {
+	// Group by instrumentation scope, as OTLP expects.
+	var scopes []scopeSpans
+	index := make(map[scope]int)
+	for _, s := range batch {
+		i, ok := index[s.tracer.scope]
+		if !ok {
+			i = len(scopes)
+			index[s.tracer.scope] = i
+			scopes = append(scopes, scopeSpans{Scope: s.tracer.scope})
+		}
+		scopes[i].Spans = append(scopes[i].Spans, s.otlp())
+	}
+	body, err := json.Marshal(exportRequest{ResourceSpans: []resourceSpans{{Resource: e.resource, ScopeSpans: scopes}}})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// The types below are the OTLP/JSON encoding of ExportTraceServiceRequest.
+// IDs are hex and 64-bit integers are decimal strings, per the spec.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+type spanJSON struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	TraceState        string      `json:"traceState,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []eventJSON `json:"events,omitempty"`
+	Links             []linkJSON  `json:"links,omitempty"`
+	Status            statusJSON  `json:"status"`
+}
+
+type eventJSON struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type linkJSON struct {
+	TraceID    string     `json:"traceId"`
+	SpanID     string     `json:"spanId"`
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type statusJSON struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	DoubleValue *float64    `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue `json:"arrayValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+func (s *span) otlp() spanJSON {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := spanJSON{
+		TraceID:           s.spanContext.TraceID().String(),
+		SpanID:            s.spanContext.SpanID().String(),
+		TraceState:        s.spanContext.TraceState().String(),
+		Name:              s.name,
+		Kind:              int(s.kind),
+		StartTimeUnixNano: unixNano(s.start),
+		EndTimeUnixNano:   unixNano(s.end),
+		Attributes:        keyValues(s.attrs),
+		Status:            statusJSON{Code: otlpStatus(s.status), Message: s.statusMsg},
+	}
+	if s.parent.IsValid() {
+		out.ParentSpanID = s.parent.String()
+	}
+	for _, ev := range s.events {
+		out.Events = append(out.Events, eventJSON{TimeUnixNano: unixNano(ev.time), Name: ev.name, Attributes: keyValues(ev.attrs)})
+	}
+	for _, l := range s.links {
+		out.Links = append(out.Links, linkJSON{
+			TraceID:    l.SpanContext.TraceID().String(),
+			SpanID:     l.SpanContext.SpanID().String(),
+			Attributes: keyValues(l.Attributes),
+		})
+	}
+	return out
+}
+
+// otlpStatus maps the API codes (Unset, Error, Ok) to OTLP's (Unset, Ok,
+// Error).
+func otlpStatus(code codes.Code) int {
+	switch code {
+	case codes.Ok:
+		return 1
+	case codes.Error:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func keyValues(attrs []attribute.KeyValue) []keyValue {
+	out := make([]keyValue, 0, len(attrs))
+	for _, a := range attrs {
+		out = append(out, keyValue{Key: string(a.Key), Value: anyValueOf(a.Value)})
+	}
+	return out
+}
+
+func anyValueOf(v attribute.Value) anyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return anyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return anyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return anyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		var values []anyValue
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, anyValueOf(attribute.BoolValue(b)))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		var values []anyValue
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, anyValueOf(attribute.Int64Value(i)))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		var values []anyValue
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, anyValueOf(attribute.Float64Value(f)))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		var values []anyValue
+		for _, s := range v.AsStringSlice() {
+			values = append(values, anyValueOf(attribute.StringValue(s)))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	default:
+		s := v.Emit()
+		return anyValue{StringValue: &s}
+	}
+}
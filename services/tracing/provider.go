@@ -0,0 +1,269 @@
+// Package tracing is a small OpenTelemetry tracer provider that batches
+// finished spans to an OTLP/HTTP collector. Instrumentation uses the
+// standard otel API, so any collector, Jaeger or Tempo can receive them.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+const (
+	// maxAttributes and maxEvents cap what one span records.
+	maxAttributes = 64
+	maxEvents     = 32
+)
+
+// Provider creates spans and hands the sampled ones to the exporter when
+// they end. It is safe for concurrent use.
+type Provider struct {
+	embedded.TracerProvider
+
+	exporter *exporter
+	// sampleRatio is the share of new traces recorded. Spans with a
+	// parent follow the parent's decision so traces stay whole.
+	sampleRatio float64
+}
+
+// NewProvider returns a provider exporting to the OTLP/HTTP endpoint (for
+// example http://localhost:4318) as serviceName. Call Shutdown to flush.
+func NewProvider(endpoint, serviceName string, sampleRatio float64) *Provider {
+	return &Provider{exporter: newExporter(endpoint, serviceName), sampleRatio: sampleRatio}
+}
+
+// Tracer returns a tracer for an instrumentation scope.
+func (p *Provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	cfg := trace.NewTracerConfig(opts...)
+	return &tracer{provider: p, scope: scope{Name: name, Version: cfg.InstrumentationVersion()}}
+}
+
+// Shutdown exports the spans still queued, waiting at most until ctx is
+// done.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.exporter.shutdown(ctx)
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type tracer struct {
+	embedded.Tracer
+
+	provider *Provider
+	scope    scope
+}
+
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	if cfg.NewRoot() {
+		parent = trace.SpanContext{}
+	}
+
+	sc := trace.SpanContextConfig{SpanID: newSpanID(), TraceState: parent.TraceState()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID()
+		sc.TraceFlags = parent.TraceFlags()
+	} else {
+		sc.TraceID = newTraceID()
+		sc.TraceFlags = sc.TraceFlags.WithSampled(sampled(sc.TraceID, t.provider.sampleRatio))
+	}
+	s := &span{
+		tracer:      t,
+		spanContext: trace.NewSpanContext(sc),
+		name:        name,
+		kind:        trace.ValidateSpanKind(cfg.SpanKind()),
+		start:       cfg.Timestamp(),
+	}
+	if parent.IsValid() {
+		s.parent = parent.SpanID()
+	}
+	if s.start.IsZero() {
+		s.start = time.Now()
+	}
+	s.SetAttributes(cfg.Attributes()...)
+	for _, l := range cfg.Links() {
+		s.AddLink(l)
+	}
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+// sampled decides from the trace ID, so every service sampling at the same
+// ratio keeps the same traces.
+func sampled(id trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return binary.BigEndian.Uint64(id[8:])>>1 < uint64(ratio*(1<<63))
+}
+
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	rand.Read(id[:])
+	return id
+}
+
+type event struct {
+	name  string
+	time  time.Time
+	attrs []attribute.KeyValue
+}
+
+// span records while open and is read-only once ended.
+type span struct {
+	embedded.Span
+
+	tracer      *tracer
+	spanContext trace.SpanContext
+	parent      trace.SpanID
+	kind        trace.SpanKind
+
+	mu         sync.Mutex
+	name       string
+	start, end time.Time
+	attrs      []attribute.KeyValue
+	events     []event
+	links      []trace.Link
+	status     codes.Code
+	statusMsg  string
+	ended      bool
+}
+
+func (s *span) SpanContext() trace.SpanContext { return s.spanContext }
+
+func (s *span) TracerProvider() trace.TracerProvider { return s.tracer.provider }
+
+func (s *span) IsRecording() bool {
+	if !s.spanContext.IsSampled() {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.ended
+}
+
+func (s *span) SetName(name string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range kv {
+		if i := indexOf(s.attrs, attr.Key); i >= 0 {
+			s.attrs[i] = attr
+		} else if len(s.attrs) < maxAttributes {
+			s.attrs = append(s.attrs, attr)
+		}
+	}
+}
+
+func indexOf(attrs []attribute.KeyValue, key attribute.Key) int {
+	for i, a := range attrs {
+		if a.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *span) AddEvent(name string, opts ...trace.EventOption) {
+	if !s.IsRecording() {
+		return
+	}
+	cfg := trace.NewEventConfig(opts...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) < maxEvents {
+		at := cfg.Timestamp()
+		if at.IsZero() {
+			at = time.Now()
+		}
+		s.events = append(s.events, event{name: name, time: at, attrs: cfg.Attributes()})
+	}
+}
+
+func (s *span) AddLink(link trace.Link) {
+	if !s.IsRecording() || !link.SpanContext.IsValid() {
+		return
+	}
+	s.mu.Lock()
+	s.links = append(s.links, link)
+	s.mu.Unlock()
+}
+
+func (s *span) RecordError(err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	opts = append(opts, trace.WithAttributes(
+		attribute.String("exception.type", fmt.Sprintf("%T", err)),
+		attribute.String("exception.message", err.Error()),
+	))
+	s.AddEvent("exception", opts...)
+}
+
+// SetStatus follows the API rule that Ok beats Error beats Unset, which is
+// also the numeric order of the codes.
+func (s *span) SetStatus(code codes.Code, description string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if code < s.status {
+		return
+	}
+	s.status = code
+	s.statusMsg = ""
+	if code == codes.Error {
+		s.statusMsg = description
+	}
+}
+
+func (s *span) End(opts ...trace.SpanEndOption) {
+	if !s.IsRecording() {
+		return
+	}
+	cfg := trace.NewSpanEndConfig(opts...)
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = cfg.Timestamp()
+	if s.end.IsZero() {
+		s.end = time.Now()
+	}
+	s.mu.Unlock()
+	s.tracer.provider.exporter.enqueue(s)
+}
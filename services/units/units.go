@@ -0,0 +1,211 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+// Terms holds the headline wording used for safety warnings, so a location
+// sees the names its own weather service uses.
+type Terms struct {
+	ExtremeHeat  string
+	Heat         string
+	ExtremeCold  string
+	Ice          string
+	Thunderstorm string
+	DamagingWind string
+	StrongWind   string
+}
+
+// Conventions describes how weather should be presented for a location.
+type Conventions struct {
+	System      weatherpb.UnitSystem
+	Temperature string
+	WindSpeed   string
+	Pressure    string
+	Terms       Terms
+}
+
+var defaultTerms = Terms{
+	ExtremeHeat:  "EXTREME HEAT",
+	Heat:         "HEAT CAUTION",
+	ExtremeCold:  "EXTREME COLD",
+	Ice:          "ICE RISK",
+	Thunderstorm: "THUNDERSTORM",
+	DamagingWind: "DAMAGING WIND",
+	StrongWind:   "STRONG WIND",
+}
+
+var countryTerms = map[string]Terms{
+	"US": {
+		ExtremeHeat:  "EXCESSIVE HEAT WARNING",
+		Heat:         "HEAT ADVISORY",
+		ExtremeCold:  "EXTREME COLD WARNING",
+		Ice:          "WINTER WEATHER ADVISORY",
+		Thunderstorm: "SEVERE THUNDERSTORM WARNING",
+		DamagingWind: "HIGH WIND WARNING",
+		StrongWind:   "WIND ADVISORY",
+	},
+	"GB": {
+		ExtremeHeat:  "EXTREME HEAT WARNING",
+		Heat:         "HEAT HEALTH ALERT",
+		ExtremeCold:  "COLD HEALTH ALERT",
+		Ice:          "ICE WARNING",
+		Thunderstorm: "THUNDERSTORM WARNING",
+		DamagingWind: "SEVERE GALE WARNING",
+		StrongWind:   "WIND WARNING",
+	},
+	"CA": {
+		ExtremeHeat:  "HEAT WARNING",
+		Heat:         "SPECIAL WEATHER STATEMENT (HEAT)",
+		ExtremeCold:  "EXTREME COLD WARNING",
+		Ice:          "FREEZING RAIN WARNING",
+		Thunderstorm: "SEVERE THUNDERSTORM WARNING",
+		DamagingWind: "WIND WARNING",
+		StrongWind:   "WIND ADVISORY",
+	},
+	"AU": {
+		ExtremeHeat:  "EXTREME HEATWAVE WARNING",
+		Heat:         "HEATWAVE WARNING",
+		ExtremeCold:  "EXTREME COLD",
+		Ice:          "FROST WARNING",
+		Thunderstorm: "SEVERE THUNDERSTORM WARNING",
+		DamagingWind: "DAMAGING WINDS WARNING",
+		StrongWind:   "STRONG WIND WARNING",
+	},
+	"IN": {
+		ExtremeHeat:  "SEVERE HEAT WAVE",
+		Heat:         "HEAT WAVE",
+		ExtremeCold:  "SEVERE COLD WAVE",
+		Ice:          "COLD WAVE",
+		Thunderstorm: "THUNDERSTORM WARNING",
+		DamagingWind: "SQUALL WARNING",
+		StrongWind:   "STRONG WIND WARNING",
+	},
+}
+
+// countrySystems lists countries that don't use the metric default.
+var countrySystems = map[string]weatherpb.UnitSystem{
+	"US": weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL,
+	"LR": weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL,
+	"MM": weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL,
+	"GB": weatherpb.UnitSystem_UNIT_SYSTEM_UK,
+	"NO": weatherpb.UnitSystem_UNIT_SYSTEM_SI,
+	"SE": weatherpb.UnitSystem_UNIT_SYSTEM_SI,
+	"FI": weatherpb.UnitSystem_UNIT_SYSTEM_SI,
+	"DK": weatherpb.UnitSystem_UNIT_SYSTEM_SI,
+	"RU": weatherpb.UnitSystem_UNIT_SYSTEM_SI,
+}
+
+// Resolve picks the conventions for a country. An explicit preference wins
+// over the country default; the country still decides warning terminology.
+func Resolve(countryCode string, preference weatherpb.UnitSystem) Conventions {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+
+	system := preference
+	if system == weatherpb.UnitSystem_UNIT_SYSTEM_AUTO {
+		system = weatherpb.UnitSystem_UNIT_SYSTEM_METRIC
+		if s, ok := countrySystems[countryCode]; ok {
+			system = s
+		}
+	}
+
+	conv := Conventions{System: system, Terms: defaultTerms}
+	if terms, ok := countryTerms[countryCode]; ok {
+		conv.Terms = terms
+	}
+
+	switch system {
+	case weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL:
+		conv.Temperature, conv.WindSpeed, conv.Pressure = "°F", "mph", "inHg"
+	case weatherpb.UnitSystem_UNIT_SYSTEM_UK:
+		conv.Temperature, conv.WindSpeed, conv.Pressure = "°C", "mph", "hPa"
+	case weatherpb.UnitSystem_UNIT_SYSTEM_SI:
+		conv.Temperature, conv.WindSpeed, conv.Pressure = "°C", "m/s", "hPa"
+	default:
+		conv.System = weatherpb.UnitSystem_UNIT_SYSTEM_METRIC
+		conv.Temperature, conv.WindSpeed, conv.Pressure = "°C", "km/h", "hPa"
+	}
+	return conv
+}
+
+func (c Conventions) Units() *weatherpb.Units {
+	return &weatherpb.Units{
+		System:      c.System,
+		Temperature: c.Temperature,
+		WindSpeed:   c.WindSpeed,
+		Pressure:    c.Pressure,
+	}
+}
+
+// Temp converts a temperature in °C.
+func (c Conventions) Temp(celsius float64) float64 {
+	if c.Temperature == "°F" {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+// Wind converts a wind speed in km/h.
+func (c Conventions) Wind(kmh float64) float64 {
+	switch c.WindSpeed {
+	case "mph":
+		return kmh / 1.609344
+	case "m/s":
+		return kmh / 3.6
+	}
+	return kmh
+}
+
+// Press converts a pressure in hPa.
+func (c Conventions) Press(hPa float64) float64 {
+	if c.Pressure == "inHg" {
+		return hPa * 0.0295299830714
+	}
+	return hPa
+}
+
+// ParseSystem maps a user-facing name ("metric", "imperial", "uk", "si") to a
+// unit system. Empty or unknown names mean AUTO.
+func ParseSystem(name string) weatherpb.UnitSystem {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "metric":
+		return weatherpb.UnitSystem_UNIT_SYSTEM_METRIC
+	case "imperial", "us":
+		return weatherpb.UnitSystem_UNIT_SYSTEM_IMPERIAL
+	case "uk":
+		return weatherpb.UnitSystem_UNIT_SYSTEM_UK
+	case "si":
+		return weatherpb.UnitSystem_UNIT_SYSTEM_SI
+	}
+	return weatherpb.UnitSystem_UNIT_SYSTEM_AUTO
+}
+
+// NormalizeWind converts a provider wind speed to km/h, the unit every
+// service works in internally.
+func NormalizeWind(value float64, unit string) (float64, error) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "km/h", "kmh", "kph":
+		return value, nil
+	case "m/s", "ms":
+		return value * 3.6, nil
+	case "mph", "mp/h":
+		return value * 1.609344, nil
+	case "kn", "kt", "knots":
+		return value * 1.852, nil
+	}
+	return 0, fmt.Errorf("unknown wind speed unit %q", unit)
+}
+
+// NormalizeTemp converts a provider temperature to °C.
+func NormalizeTemp(value float64, unit string) (float64, error) {
+	switch strings.TrimSpace(unit) {
+	case "°C", "C", "celsius":
+		return value, nil
+	case "°F", "F", "fahrenheit":
+		return (value - 32) * 5 / 9, nil
+	}
+	return 0, fmt.Errorf("unknown temperature unit %q", unit)
+}
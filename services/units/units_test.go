@@ -0,0 +1,70 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeWind(t *testing.T) {
+	for _, tc := range []struct {
+		unit string
+		in   float64
+		want float64
+	}{
+		{"km/h", 10, 10},
+		{"kmh", 10, 10},
+		{"kph", 10, 10},
+		{" KM/H ", 10, 10},
+		{"m/s", 10, 36},
+		{"ms", 10, 36},
+		{"mph", 10, 16.09344},
+		{"mp/h", 10, 16.09344},
+		{"kn", 10, 18.52},
+		{"kt", 10, 18.52},
+		{"knots", 10, 18.52},
+	} {
+		got, err := NormalizeWind(tc.in, tc.unit)
+		if err != nil {
+			t.Errorf("NormalizeWind(%v, %q): %v", tc.in, tc.unit, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("NormalizeWind(%v, %q) = %v, want %v", tc.in, tc.unit, got, tc.want)
+		}
+	}
+	for _, unit := range []string{"", "ft/s", "beaufort"} {
+		if _, err := NormalizeWind(10, unit); err == nil {
+			t.Errorf("NormalizeWind(10, %q): want an error", unit)
+		}
+	}
+}
+
+func TestNormalizeTemp(t *testing.T) {
+	for _, tc := range []struct {
+		unit string
+		in   float64
+		want float64
+	}{
+		{"°C", 21.5, 21.5},
+		{"C", 21.5, 21.5},
+		{"celsius", 21.5, 21.5},
+		{" °C ", 21.5, 21.5},
+		{"°F", 212, 100},
+		{"F", 32, 0},
+		{"fahrenheit", -40, -40},
+	} {
+		got, err := NormalizeTemp(tc.in, tc.unit)
+		if err != nil {
+			t.Errorf("NormalizeTemp(%v, %q): %v", tc.in, tc.unit, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("NormalizeTemp(%v, %q) = %v, want %v", tc.in, tc.unit, got, tc.want)
+		}
+	}
+	for _, unit := range []string{"", "K", "kelvin"} {
+		if _, err := NormalizeTemp(10, unit); err == nil {
+			t.Errorf("NormalizeTemp(10, %q): want an error", unit)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package validate rejects malformed requests before they reach a handler.
+// Constraints are declared per proto field, so every message carrying a
+// field is held to the same limits wherever it is nested, and the caller
+// gets an INVALID_ARGUMENT naming the exact field path.
+package validate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var rejectedRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "validation_rejected_total",
+		Help: "Requests rejected by the validation interceptor",
+	},
+	[]string{"method"},
+)
+
+// Rule constrains one proto field.
+type Rule struct {
+	// Field is the field's full name, e.g. "weather.WeatherRequest.latitude".
+	Field protoreflect.FullName
+	// Required rejects a blank string, an unset message or an empty list.
+	Required bool
+	// MaxItems caps a repeated field and MaxLen the characters of a string.
+	// Zero leaves them off.
+	MaxItems int
+	MaxLen   int
+	// HasRange bounds a number to [Min, Max]; NaN is always rejected.
+	HasRange bool
+	Min, Max float64
+}
+
+// Required makes a field mandatory.
+func Required(field protoreflect.FullName) Rule {
+	return Rule{Field: field, Required: true}
+}
+
+// MaxItems caps the length of a repeated field.
+func MaxItems(field protoreflect.FullName, n int) Rule {
+	return Rule{Field: field, MaxItems: n}
+}
+
+// MaxLen caps the characters of a string field.
+func MaxLen(field protoreflect.FullName, n int) Rule {
+	return Rule{Field: field, MaxLen: n}
+}
+
+// Range bounds a numeric field, inclusive.
+func Range(field protoreflect.FullName, min, max float64) Rule {
+	return Rule{Field: field, HasRange: true, Min: min, Max: max}
+}
+
+// Latitude and Longitude bound coordinate fields.
+func Latitude(field protoreflect.FullName) Rule  { return Range(field, -90, 90) }
+func Longitude(field protoreflect.FullName) Rule { return Range(field, -180, 180) }
+
+// Validator checks messages against a set of rules.
+type Validator struct {
+	rules map[protoreflect.FullName][]Rule
+}
+
+// New builds a Validator. Every rule must name a field of a registered
+// message, and suit its type, so a typo fails at startup rather than
+// silently checking nothing.
+func New(rules ...Rule) (*Validator, error) {
+	v := &Validator{rules: make(map[protoreflect.FullName][]Rule)}
+	for _, r := range rules {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(r.Field)
+		if err != nil {
+			return nil, fmt.Errorf("validation rule for %s: %v", r.Field, err)
+		}
+		fd, ok := d.(protoreflect.FieldDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("validation rule for %s: not a field", r.Field)
+		}
+		if r.MaxItems > 0 && !fd.IsList() {
+			return nil, fmt.Errorf("validation rule for %s: max items on a field that isn't repeated", r.Field)
+		}
+		if r.MaxLen > 0 && fd.Kind() != protoreflect.StringKind {
+			return nil, fmt.Errorf("validation rule for %s: max length on a field that isn't a string", r.Field)
+		}
+		if r.HasRange && (fd.IsList() || !isNumber(fd.Kind())) {
+			return nil, fmt.Errorf("validation rule for %s: range on a field that isn't a number", r.Field)
+		}
+		v.rules[r.Field] = append(v.rules[r.Field], r)
+	}
+	return v, nil
+}
+
+// Check returns an INVALID_ARGUMENT status for the first broken rule in
+// msg, or nil. Values that aren't proto messages pass.
+func (v *Validator) Check(msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if err := v.check(m.ProtoReflect(), ""); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func (v *Validator) check(m protoreflect.Message, prefix string) error {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		for _, r := range v.rules[fd.FullName()] {
+			if err := checkField(m, fd, r, path); err != nil {
+				return err
+			}
+		}
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !m.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				if err := v.check(list.Get(j).Message(), fmt.Sprintf("%s[%d].", path, j)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := v.check(m.Get(fd).Message(), path+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkField(m protoreflect.Message, fd protoreflect.FieldDescriptor, r Rule, path string) error {
+	if r.Required && isEmpty(m, fd) {
+		return fmt.Errorf("%s is required", path)
+	}
+	if r.MaxItems > 0 {
+		if n := m.Get(fd).List().Len(); n > r.MaxItems {
+			return fmt.Errorf("%s has %d items (at most %d)", path, n, r.MaxItems)
+		}
+	}
+	if r.MaxLen > 0 {
+		if n := utf8.RuneCountInString(m.Get(fd).String()); n > r.MaxLen {
+			return fmt.Errorf("%s is %d characters (at most %d)", path, n, r.MaxLen)
+		}
+	}
+	// Unset optional fields have nothing to range check.
+	if r.HasRange && (!fd.HasPresence() || m.Has(fd)) {
+		x := toFloat(m.Get(fd), fd.Kind())
+		if math.IsNaN(x) || x < r.Min || x > r.Max {
+			return fmt.Errorf("%s is %v, outside [%v, %v]", path, x, r.Min, r.Max)
+		}
+	}
+	return nil
+}
+
+func isEmpty(m protoreflect.Message, fd protoreflect.FieldDescriptor) bool {
+	switch {
+	case fd.IsList():
+		return m.Get(fd).List().Len() == 0
+	case fd.Kind() == protoreflect.StringKind:
+		return strings.TrimSpace(m.Get(fd).String()) == ""
+	default:
+		return !m.Has(fd)
+	}
+}
+
+func isNumber(k protoreflect.Kind) bool {
+	switch k {
+	case protoreflect.DoubleKind, protoreflect.FloatKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	}
+	return false
+}
+
+func toFloat(v protoreflect.Value, k protoreflect.Kind) float64 {
+	switch k {
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		return v.Float()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(v.Uint())
+	default:
+		return float64(v.Int())
+	}
+}
+
+// UnaryServerInterceptor rejects invalid requests. Install it after
+// authentication, so unauthenticated callers learn nothing about the API.
+func (v *Validator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := v.Check(req); err != nil {
+			rejectedRequests.WithLabelValues(info.FullMethod).Inc()
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor checks every message the client sends. An
+// invalid message fails the RecvMsg that read it, which ends the stream.
+func (v *Validator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatedStream{ServerStream: ss, v: v, method: info.FullMethod})
+	}
+}
+
+type validatedStream struct {
+	grpc.ServerStream
+	v      *Validator
+	method string
+}
+
+func (s *validatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if err := s.v.Check(m); err != nil {
+		rejectedRequests.WithLabelValues(s.method).Inc()
+		return err
+	}
+	return nil
+}
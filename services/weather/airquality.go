@@ -0,0 +1,114 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+const forecastHours = 24
+
+type openMeteoHourly struct {
+	UTCOffsetSeconds int32 `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time        []int64    `json:"time"`
+		Temperature []*float64 `json:"temperature_2m"`
+		WindSpeed   []*float64 `json:"wind_speed_10m"`
+		USAQI       []*int32   `json:"us_aqi"`
+		PM25        []*float64 `json:"pm2_5"`
+	} `json:"hourly"`
+	HourlyUnits struct {
+		Temperature string `json:"temperature_2m"`
+		WindSpeed   string `json:"wind_speed_10m"`
+	} `json:"hourly_units"`
+}
+
+// openMeteoAirQualityProvider credits the Open-Meteo air quality API, which
+// serves CAMS forecasts.
+func openMeteoAirQualityProvider() *weatherpb.Provider {
+	return &weatherpb.Provider{
+		Name:        "Open-Meteo Air Quality",
+		Url:         "https://open-meteo.com/en/docs/air-quality-api",
+		License:     "CC BY 4.0",
+		Attribution: "Air quality data by Open-Meteo.com (CAMS)",
+		Model:       "cams",
+	}
+}
+
+func (s *weatherService) GetAirQualityForecast(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.AirQualityForecast, error) {
+	weatherURL := fmt.Sprintf("%s?latitude=%f&longitude=%f&hourly=temperature_2m,wind_speed_10m&temperature_unit=celsius&wind_speed_unit=kmh&forecast_hours=%d&timeformat=unixtime&timezone=auto",
+		ForecastURL, req.Latitude, req.Longitude, forecastHours)
+	airURL := fmt.Sprintf("%s?latitude=%f&longitude=%f&hourly=us_aqi,pm2_5&forecast_hours=%d&timeformat=unixtime&timezone=auto",
+		AirQualityURL, req.Latitude, req.Longitude, forecastHours)
+
+	var weatherData, airData openMeteoHourly
+	if err := s.getJSON(ctx, "forecast", weatherURL, &weatherData); err != nil {
+		return nil, fmt.Errorf("hourly forecast failed: %v", err)
+	}
+	if err := s.getJSON(ctx, "air_quality", airURL, &airData); err != nil {
+		return nil, fmt.Errorf("air quality forecast failed: %v", err)
+	}
+
+	// Join the two series on their timestamps; hours missing from either
+	// are dropped.
+	air := make(map[int64]int, len(airData.Hourly.Time))
+	for i, t := range airData.Hourly.Time {
+		air[t] = i
+	}
+
+	forecast := &weatherpb.AirQualityForecast{
+		UtcOffsetSeconds: weatherData.UTCOffsetSeconds,
+		Providers:        []*weatherpb.Provider{openMeteoProvider(), openMeteoAirQualityProvider()},
+	}
+	h := weatherData.Hourly
+	for i, t := range h.Time {
+		j, ok := air[t]
+		if !ok || i >= len(h.Temperature) || i >= len(h.WindSpeed) || h.Temperature[i] == nil || h.WindSpeed[i] == nil {
+			continue
+		}
+		if j >= len(airData.Hourly.USAQI) || j >= len(airData.Hourly.PM25) || airData.Hourly.USAQI[j] == nil || airData.Hourly.PM25[j] == nil {
+			continue
+		}
+		tempC, err := units.NormalizeTemp(*h.Temperature[i], weatherData.HourlyUnits.Temperature)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		windKmh, err := units.NormalizeWind(*h.WindSpeed[i], weatherData.HourlyUnits.WindSpeed)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		forecast.Hours = append(forecast.Hours, &weatherpb.HourlyConditions{
+			Time:        t,
+			Temperature: tempC,
+			WindSpeed:   windKmh,
+			UsAqi:       *airData.Hourly.USAQI[j],
+			Pm2_5:       *airData.Hourly.PM25[j],
+		})
+	}
+	if len(forecast.Hours) == 0 {
+		return nil, fmt.Errorf("no overlapping forecast hours")
+	}
+	return forecast, nil
+}
+
+// getJSON fetches url from upstream ("forecast" or "air_quality") into v.
+func (s *weatherService) getJSON(ctx context.Context, upstream, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpclient.Do(s.httpClient, upstream, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API status: %d", resp.StatusCode)
+	}
+	return httpclient.DecodeJSON(resp, v)
+}
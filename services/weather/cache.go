@@ -0,0 +1,160 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var cacheLookups = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "weather_cache_lookups_total",
+		Help: "Current weather cache lookups, by result",
+	},
+	[]string{"result"},
+)
+
+// currentCache keeps current conditions by location, rounded to two
+// decimals (about a kilometre), the precision responses are labelled with.
+// It counts how often each location is asked for, so the popular ones can
+// be refreshed before they expire.
+type currentCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+}
+
+// maxCacheEntries bounds the cache should the refresh job, which forgets
+// unpopular locations, be turned off.
+const maxCacheEntries = 10000
+
+type cacheEntry struct {
+	lat, lon float64
+	reading  *reading
+	hits     int
+}
+
+func newCurrentCache(ttl time.Duration) *currentCache {
+	return &currentCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+func cacheKey(lat, lon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", lat, lon)
+}
+
+func (c *currentCache) get(lat, lon float64) (*reading, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[cacheKey(lat, lon)]
+	if !ok {
+		// Remember the miss, so a location gets popular from its first
+		// request.
+		if len(c.entries) < maxCacheEntries {
+			c.entries[cacheKey(lat, lon)] = &cacheEntry{lat: lat, lon: lon, hits: 1}
+		}
+		cacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	e.hits++
+	if e.reading == nil || time.Since(e.reading.fetched) > c.ttl {
+		cacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	cacheLookups.WithLabelValues("hit").Inc()
+	return e.reading, true
+}
+
+func (c *currentCache) put(lat, lon float64, r *reading) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := cacheKey(lat, lon)
+	if e, ok := c.entries[key]; ok {
+		e.reading = r
+		return
+	}
+	if len(c.entries) >= maxCacheEntries {
+		return
+	}
+	c.entries[key] = &cacheEntry{lat: lat, lon: lon, reading: r}
+}
+
+// popular returns up to n locations asked for since the last call, most
+// requested first, and forgets the expired ones nobody asked for.
+func (c *currentCache) popular(n int) []*cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var asked []*cacheEntry
+	for key, e := range c.entries {
+		if e.hits > 0 {
+			asked = append(asked, &cacheEntry{lat: e.lat, lon: e.lon, hits: e.hits})
+			e.hits = 0
+			continue
+		}
+		if e.reading == nil || time.Since(e.reading.fetched) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+	sort.Slice(asked, func(i, j int) bool { return asked[i].hits > asked[j].hits })
+	if len(asked) > n {
+		asked = asked[:n]
+	}
+	return asked
+}
+
+// flush drops every cached reading and returns how many there were.
+// Locations keep their hit counts, so the refresh job refills the popular
+// ones.
+func (c *currentCache) flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, e := range c.entries {
+		if e.reading != nil {
+			e.reading = nil
+			n++
+		}
+	}
+	return n
+}
+
+// FlushCache drops the cached current conditions and returns how many
+// there were. It does nothing when caching is off.
+func (s *weatherService) FlushCache() int {
+	if s.cache == nil {
+		return 0
+	}
+	return s.cache.flush()
+}
+
+// RefreshPopular fetches the current weather again for the n locations
+// asked for most since the last refresh, so their next requests are served
+// from the cache. It does nothing when caching is off.
+func (s *weatherService) RefreshPopular(ctx context.Context, n int) error {
+	if s.cache == nil {
+		return nil
+	}
+	locations := s.cache.popular(n)
+	failed := 0
+	var lastErr error
+	for _, loc := range locations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := s.fetchCurrent(ctx, loc.lat, loc.lon)
+		if err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		s.cache.put(loc.lat, loc.lon, r)
+	}
+	if failed > 0 {
+		return fmt.Errorf("refreshing %d of %d locations failed: %v", failed, len(locations), lastErr)
+	}
+	return nil
+}
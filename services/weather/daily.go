@@ -0,0 +1,138 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxForecastDays is as far ahead as Open-Meteo forecasts.
+	maxForecastDays = 16
+	// defaultForecastDays is the window used when no end_date is given.
+	defaultForecastDays = 7
+)
+
+type openMeteoDaily struct {
+	UTCOffsetSeconds int32 `json:"utc_offset_seconds"`
+	Daily            struct {
+		Time              []string   `json:"time"`
+		WeatherCode       []*int32   `json:"weather_code"`
+		TempMax           []*float64 `json:"temperature_2m_max"`
+		TempMin           []*float64 `json:"temperature_2m_min"`
+		PrecipProbability []*int32   `json:"precipitation_probability_max"`
+		PrecipSum         []*float64 `json:"precipitation_sum"`
+		WindSpeedMax      []*float64 `json:"wind_speed_10m_max"`
+		UVIndexMax        []*float64 `json:"uv_index_max"`
+	} `json:"daily"`
+	DailyUnits struct {
+		TempMax      string `json:"temperature_2m_max"`
+		TempMin      string `json:"temperature_2m_min"`
+		WindSpeedMax string `json:"wind_speed_10m_max"`
+	} `json:"daily_units"`
+}
+
+// GetDailyForecast fetches the full 16-day forecast and keeps the requested
+// dates, so "today" is always the location's own today.
+func (s *weatherService) GetDailyForecast(ctx context.Context, req *weatherpb.DailyForecastRequest) (*weatherpb.DailyForecast, error) {
+	start, end, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	url := fmt.Sprintf("%s?latitude=%f&longitude=%f&daily=weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max,precipitation_sum,wind_speed_10m_max,uv_index_max&temperature_unit=celsius&wind_speed_unit=kmh&precipitation_unit=mm&forecast_days=%d&timezone=auto",
+		ForecastURL, req.Latitude, req.Longitude, maxForecastDays)
+	var data openMeteoDaily
+	if err := s.getJSON(ctx, "forecast", url, &data); err != nil {
+		return nil, fmt.Errorf("daily forecast failed: %v", err)
+	}
+
+	d := data.Daily
+	if start.IsZero() && len(d.Time) > 0 {
+		start, _ = time.Parse(time.DateOnly, d.Time[0])
+	}
+	if end.IsZero() {
+		end = start.AddDate(0, 0, defaultForecastDays-1)
+	}
+
+	forecast := &weatherpb.DailyForecast{
+		UtcOffsetSeconds: data.UTCOffsetSeconds,
+		Provider:         openMeteoProvider(),
+	}
+	for i, date := range d.Time {
+		day, err := time.Parse(time.DateOnly, date)
+		if err != nil || day.Before(start) || day.After(end) {
+			continue
+		}
+		if i >= len(d.WeatherCode) || i >= len(d.TempMax) || i >= len(d.TempMin) || i >= len(d.WindSpeedMax) ||
+			d.WeatherCode[i] == nil || d.TempMax[i] == nil || d.TempMin[i] == nil || d.WindSpeedMax[i] == nil {
+			continue
+		}
+		maxC, err := units.NormalizeTemp(*d.TempMax[i], data.DailyUnits.TempMax)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		minC, err := units.NormalizeTemp(*d.TempMin[i], data.DailyUnits.TempMin)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		windKmh, err := units.NormalizeWind(*d.WindSpeedMax[i], data.DailyUnits.WindSpeedMax)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		forecast.Days = append(forecast.Days, &weatherpb.DailyConditions{
+			Date:                     date,
+			WeatherCode:              *d.WeatherCode[i],
+			Description:              getWeatherDescription(*d.WeatherCode[i]),
+			TempMax:                  maxC,
+			TempMin:                  minC,
+			PrecipitationProbability: valueAt(d.PrecipProbability, i),
+			PrecipitationSum:         valueAt(d.PrecipSum, i),
+			WindSpeedMax:             windKmh,
+			UvIndexMax:               valueAt(d.UVIndexMax, i),
+		})
+	}
+	if len(forecast.Days) == 0 {
+		return nil, status.Errorf(codes.OutOfRange, "no forecast for the requested dates (at most %d days ahead)", maxForecastDays)
+	}
+	return forecast, nil
+}
+
+// parseDateRange checks the optional YYYY-MM-DD bounds. Zero times mean the
+// bound was not given.
+func parseDateRange(startDate, endDate string) (start, end time.Time, err error) {
+	if startDate != "" {
+		if start, err = time.Parse(time.DateOnly, startDate); err != nil {
+			return start, end, fmt.Errorf("start_date %q is not YYYY-MM-DD", startDate)
+		}
+	}
+	if endDate != "" {
+		if end, err = time.Parse(time.DateOnly, endDate); err != nil {
+			return start, end, fmt.Errorf("end_date %q is not YYYY-MM-DD", endDate)
+		}
+	}
+	if !start.IsZero() && !end.IsZero() {
+		if end.Before(start) {
+			return start, end, fmt.Errorf("end_date %s is before start_date %s", endDate, startDate)
+		}
+		if end.Sub(start) >= maxForecastDays*24*time.Hour {
+			return start, end, fmt.Errorf("date range is longer than %d days", maxForecastDays)
+		}
+	}
+	return start, end, nil
+}
+
+// valueAt returns the i-th value of an Open-Meteo series, or zero when the
+// series is short or the value is missing.
+func valueAt[T int32 | float64](series []*T, i int) T {
+	if i >= len(series) || series[i] == nil {
+		var zero T
+		return zero
+	}
+	return *series[i]
+}
@@ -0,0 +1,93 @@
+package weather
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	defaultDashboardInterval = 60 * time.Second
+	minDashboardInterval     = 15 * time.Second
+	maxDashboardLocations    = 25
+	// dashboardFetchers bounds concurrent upstream calls per stream.
+	dashboardFetchers = 5
+)
+
+var dashboardStreams = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "weather_dashboard_streams",
+		Help: "Open StreamDashboard streams",
+	},
+)
+
+func (s *weatherService) StreamDashboard(req *weatherpb.DashboardRequest, stream weatherpb.WeatherService_StreamDashboardServer) error {
+	interval := defaultDashboardInterval
+	if req.IntervalSeconds > 0 {
+		interval = time.Duration(req.IntervalSeconds) * time.Second
+	}
+	if interval < minDashboardInterval {
+		interval = minDashboardInterval
+	}
+
+	dashboardStreams.Inc()
+	defer dashboardStreams.Dec()
+
+	ctx := stream.Context()
+	snapshots := make([]*weatherpb.LocationSnapshot, len(req.Locations))
+	for i, loc := range req.Locations {
+		snapshots[i] = &weatherpb.LocationSnapshot{Id: loc.Id}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.refreshDashboard(ctx, req, snapshots)
+		if err := stream.Send(&weatherpb.DashboardSnapshot{
+			Locations: snapshots,
+			SentAt:    time.Now().Unix(),
+		}); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshDashboard updates every snapshot in place. A failed refresh keeps
+// the previous reading and records the error.
+func (s *weatherService) refreshDashboard(ctx context.Context, req *weatherpb.DashboardRequest, snapshots []*weatherpb.LocationSnapshot) {
+	sem := make(chan struct{}, dashboardFetchers)
+	var wg sync.WaitGroup
+	for i, loc := range req.Locations {
+		wg.Add(1)
+		go func(snap *weatherpb.LocationSnapshot, loc *weatherpb.DashboardLocation) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			w, err := s.GetCurrentWeather(ctx, &weatherpb.WeatherRequest{
+				Latitude:    loc.Latitude,
+				Longitude:   loc.Longitude,
+				CountryCode: loc.CountryCode,
+				UnitSystem:  req.UnitSystem,
+			})
+			if err != nil {
+				snap.Error = err.Error()
+				return
+			}
+			snap.Weather = w
+			snap.UpdatedAt = w.Timestamp
+			snap.Error = ""
+		}(snapshots[i], loc)
+	}
+	wg.Wait()
+}
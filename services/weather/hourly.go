@@ -0,0 +1,76 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pixperk/effinarounf/services/units"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+)
+
+const (
+	// defaultForecastHours is how many hours are returned when none are asked for.
+	defaultForecastHours = 48
+	// maxForecastHours covers the whole 16-day forecast.
+	maxForecastHours = maxForecastDays * 24
+)
+
+type openMeteoHourlyForecast struct {
+	UTCOffsetSeconds int32 `json:"utc_offset_seconds"`
+	Hourly           struct {
+		Time              []int64    `json:"time"`
+		Temperature       []*float64 `json:"temperature_2m"`
+		Precipitation     []*float64 `json:"precipitation"`
+		PrecipProbability []*int32   `json:"precipitation_probability"`
+		WindSpeed         []*float64 `json:"wind_speed_10m"`
+	} `json:"hourly"`
+	HourlyUnits struct {
+		Temperature string `json:"temperature_2m"`
+		WindSpeed   string `json:"wind_speed_10m"`
+	} `json:"hourly_units"`
+}
+
+// GetHourlyForecast fetches the hours from the current one on. Hours without
+// a temperature are dropped; missing rain and wind count as zero.
+func (s *weatherService) GetHourlyForecast(ctx context.Context, req *weatherpb.HourlyForecastRequest) (*weatherpb.HourlyForecast, error) {
+	hours := int(req.Hours)
+	if hours == 0 {
+		hours = defaultForecastHours
+	}
+	url := fmt.Sprintf("%s?latitude=%f&longitude=%f&hourly=temperature_2m,precipitation,precipitation_probability,wind_speed_10m&temperature_unit=celsius&wind_speed_unit=kmh&precipitation_unit=mm&forecast_hours=%d&timeformat=unixtime&timezone=auto",
+		ForecastURL, req.Latitude, req.Longitude, hours)
+	var data openMeteoHourlyForecast
+	if err := s.getJSON(ctx, "forecast", url, &data); err != nil {
+		return nil, fmt.Errorf("hourly forecast failed: %v", err)
+	}
+
+	forecast := &weatherpb.HourlyForecast{
+		UtcOffsetSeconds: data.UTCOffsetSeconds,
+		Provider:         openMeteoProvider(),
+	}
+	h := data.Hourly
+	for i, t := range h.Time {
+		if i >= len(h.Temperature) || h.Temperature[i] == nil {
+			continue
+		}
+		tempC, err := units.NormalizeTemp(*h.Temperature[i], data.HourlyUnits.Temperature)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		windKmh, err := units.NormalizeWind(valueAt(h.WindSpeed, i), data.HourlyUnits.WindSpeed)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected provider units: %v", err)
+		}
+		forecast.Hours = append(forecast.Hours, &weatherpb.HourlyConditions{
+			Time:                     t,
+			Temperature:              tempC,
+			WindSpeed:                windKmh,
+			Precipitation:            valueAt(h.Precipitation, i),
+			PrecipitationProbability: valueAt(h.PrecipProbability, i),
+		})
+	}
+	if len(forecast.Hours) == 0 {
+		return nil, fmt.Errorf("no forecast hours")
+	}
+	return forecast, nil
+}
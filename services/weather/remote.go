@@ -0,0 +1,62 @@
+package weather
+
+import (
+	"context"
+
+	"github.com/pixperk/effinarounf/services/requestid"
+	"github.com/pixperk/effinarounf/services/tenant"
+	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// remoteService serves the unary weather RPCs by calling a weather service
+// over gRPC, so the advisor can run in a process of its own. StreamDashboard
+// is not forwarded; the advisor doesn't use it.
+type remoteService struct {
+	weatherpb.UnimplementedWeatherServiceServer
+	client weatherpb.WeatherServiceClient
+}
+
+// NewRemoteService returns a WeatherServiceServer backed by the weather
+// service at the other end of conn. Each call carries the caller's bearer
+// token and request ID, so the weather service authenticates the same
+// caller and logs the same ID.
+func NewRemoteService(conn grpc.ClientConnInterface) weatherpb.WeatherServiceServer {
+	return &remoteService{client: weatherpb.NewWeatherServiceClient(conn)}
+}
+
+func (s *remoteService) GetCurrentWeather(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.WeatherResponse, error) {
+	return s.client.GetCurrentWeather(forwardContext(ctx), req)
+}
+
+func (s *remoteService) GetAirQualityForecast(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.AirQualityForecast, error) {
+	return s.client.GetAirQualityForecast(forwardContext(ctx), req)
+}
+
+func (s *remoteService) GetDailyForecast(ctx context.Context, req *weatherpb.DailyForecastRequest) (*weatherpb.DailyForecast, error) {
+	return s.client.GetDailyForecast(forwardContext(ctx), req)
+}
+
+func (s *remoteService) GetHourlyForecast(ctx context.Context, req *weatherpb.HourlyForecastRequest) (*weatherpb.HourlyForecast, error) {
+	return s.client.GetHourlyForecast(forwardContext(ctx), req)
+}
+
+// forwardContext copies the incoming authorization, API key and the request
+// ID into the outgoing metadata. Calls made outside an RPC, such as scheduled
+// digests, go out without a token.
+func forwardContext(ctx context.Context) context.Context {
+	md := metadata.MD{}
+	if in, ok := metadata.FromIncomingContext(ctx); ok {
+		if auth := in.Get("authorization"); len(auth) > 0 {
+			md.Set("authorization", auth...)
+		}
+		if key := in.Get(tenant.APIKeyHeader); len(key) > 0 {
+			md.Set(tenant.APIKeyHeader, key...)
+		}
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		md.Set(requestid.Header, id)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
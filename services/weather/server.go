@@ -2,38 +2,50 @@ package weather
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/pixperk/effinarounf/services/httpclient"
+	"github.com/pixperk/effinarounf/services/units"
 	weatherpb "github.com/pixperk/effinarounf/shared/proto/weatherpb"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// Upstream API endpoints, overridable for mirrors and self-hosted
+// Open-Meteo instances.
 var (
-	weatherRequests = promauto.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "weather_requests_total",
-			Help: "Total weather requests",
-		},
-		[]string{"status"},
-	)
-	weatherDuration = promauto.NewHistogram(
-		prometheus.HistogramOpts{
-			Name: "weather_request_duration_seconds",
-			Help: "Weather request duration",
-		},
-	)
+	ForecastURL   = "https://api.open-meteo.com/v1/forecast"
+	AirQualityURL = "https://air-quality-api.open-meteo.com/v1/air-quality"
+)
+
+var weatherRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "weather_requests_total",
+		Help: "Total weather requests",
+	},
+	[]string{"status"},
 )
 
 type weatherService struct {
 	weatherpb.UnimplementedWeatherServiceServer
+	httpClient *http.Client
+	cache      *currentCache
+	// observe is called with every reading fetched from Open-Meteo; nil
+	// disables it.
+	observe func(*Observation)
 }
 
-func NewWeatherService() weatherpb.WeatherServiceServer {
-	return &weatherService{}
+// NewWeatherService serves weather from Open-Meteo. Current conditions are
+// cached for cacheTTL, or not at all if it is 0. observe, if not nil, is
+// called with each reading fetched upstream, but not with cache hits.
+func NewWeatherService(httpClient *http.Client, cacheTTL time.Duration, observe func(*Observation)) *weatherService {
+	s := &weatherService{httpClient: httpClient, observe: observe}
+	if cacheTTL > 0 {
+		s.cache = newCurrentCache(cacheTTL)
+	}
+	return s
 }
 
 type OpenMeteoResponse struct {
@@ -43,6 +55,8 @@ type OpenMeteoResponse struct {
 		WindSpeed   float64 `json:"wind_speed_10m"`
 		WindDir     int32   `json:"wind_direction_10m"`
 		WeatherCode int32   `json:"weather_code"`
+		UVIndex     float64 `json:"uv_index"`
+		IsDay       int32   `json:"is_day"`
 	} `json:"current"`
 	CurrentUnits struct {
 		Temperature string `json:"temperature_2m"`
@@ -50,6 +64,21 @@ type OpenMeteoResponse struct {
 	} `json:"current_units"`
 }
 
+// openMeteoModel is the model selection Open-Meteo uses when none is named.
+const openMeteoModel = "best_match"
+
+// openMeteoProvider is attached to every response. Open-Meteo data is
+// licensed CC BY 4.0, which requires the attribution to be shown.
+func openMeteoProvider() *weatherpb.Provider {
+	return &weatherpb.Provider{
+		Name:        "Open-Meteo",
+		Url:         "https://open-meteo.com/",
+		License:     "CC BY 4.0",
+		Attribution: "Weather data by Open-Meteo.com",
+		Model:       openMeteoModel,
+	}
+}
+
 func getWeatherDescription(code int32) string {
 	descriptions := map[int32]string{
 		0: "clear sky", 1: "mainly clear", 2: "partly cloudy", 3: "overcast",
@@ -65,45 +94,143 @@ func getWeatherDescription(code int32) string {
 }
 
 func (s *weatherService) GetCurrentWeather(ctx context.Context, req *weatherpb.WeatherRequest) (*weatherpb.WeatherResponse, error) {
-	timer := prometheus.NewTimer(weatherDuration)
-	defer timer.ObserveDuration()
+	reading, err := s.current(ctx, req.Latitude, req.Longitude)
+	if err != nil {
+		weatherRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	conv := units.Resolve(req.CountryCode, req.UnitSystem)
+	pressure := int32(1013) // Default pressure since not available in free tier
 
-	url := fmt.Sprintf("https://api.open-meteo.com/v1/forecast?latitude=%f&longitude=%f&current=temperature_2m,relative_humidity_2m,wind_speed_10m,wind_direction_10m,weather_code&timezone=auto",
-		req.Latitude, req.Longitude)
+	response := &weatherpb.WeatherResponse{
+		Location:      fmt.Sprintf("%.2f,%.2f", req.Latitude, req.Longitude),
+		Temperature:   conv.Temp(reading.tempC),
+		FeelsLike:     conv.Temp(reading.tempC), // Open-Meteo doesn't provide feels_like in free tier
+		TempMin:       conv.Temp(reading.tempC), // Using current temp as min/max
+		TempMax:       conv.Temp(reading.tempC),
+		Pressure:      pressure,
+		PressureValue: conv.Press(float64(pressure)),
+		Humidity:      reading.humidity,
+		WindSpeed:     conv.Wind(reading.windKmh),
+		WindDeg:       reading.windDir,
+		Timestamp:     reading.fetched.Unix(),
+		Description:   getWeatherDescription(reading.code),
+		WeatherCode:   reading.code,
+		Units:         conv.Units(),
+		Provider:      openMeteoProvider(),
+		UvIndex:       reading.uvIndex,
+		IsDay:         reading.isDay,
+	}
+
+	weatherRequests.WithLabelValues("success").Inc()
+	return response, nil
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+// reading is the current weather at a location in metric units, before
+// conversion to the units a request asks for.
+type reading struct {
+	tempC, windKmh, uvIndex float64
+	humidity, windDir, code int32
+	isDay                   bool
+	fetched                 time.Time
+}
+
+// current returns the weather at a location, from the cache while it is
+// fresh.
+func (s *weatherService) current(ctx context.Context, lat, lon float64) (*reading, error) {
+	if s.cache == nil {
+		return s.fetchCurrent(ctx, lat, lon)
+	}
+	if r, ok := s.cache.get(lat, lon); ok {
+		return r, nil
+	}
+	r, err := s.fetchCurrent(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.put(lat, lon, r)
+	return r, nil
+}
+
+func (s *weatherService) fetchCurrent(ctx context.Context, lat, lon float64) (*reading, error) {
+	url := fmt.Sprintf("%s?latitude=%f&longitude=%f&current=temperature_2m,relative_humidity_2m,wind_speed_10m,wind_direction_10m,weather_code,uv_index,is_day&temperature_unit=celsius&wind_speed_unit=kmh&timezone=auto",
+		ForecastURL, lat, lon)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request failed: %v", err)
+	}
+	resp, err := httpclient.Do(s.httpClient, "forecast", httpReq)
 	if err != nil {
-		weatherRequests.WithLabelValues("error").Inc()
 		return nil, fmt.Errorf("API request failed: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		weatherRequests.WithLabelValues("error").Inc()
 		return nil, fmt.Errorf("API status: %d", resp.StatusCode)
 	}
 
 	var weatherData OpenMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&weatherData); err != nil {
-		weatherRequests.WithLabelValues("error").Inc()
+	if err := httpclient.DecodeJSON(resp, &weatherData); err != nil {
 		return nil, fmt.Errorf("decode failed: %v", err)
 	}
 
-	response := &weatherpb.WeatherResponse{
-		Location:    fmt.Sprintf("%.2f,%.2f", req.Latitude, req.Longitude),
-		Temperature: weatherData.Current.Temperature,
-		FeelsLike:   weatherData.Current.Temperature, // Open-Meteo doesn't provide feels_like in free tier
-		TempMin:     weatherData.Current.Temperature, // Using current temp as min/max
-		TempMax:     weatherData.Current.Temperature,
-		Pressure:    1013, // Default pressure since not available in free tier
-		Humidity:    weatherData.Current.Humidity,
-		WindSpeed:   weatherData.Current.WindSpeed,
-		WindDeg:     weatherData.Current.WindDir,
-		Timestamp:   time.Now().Unix(),
-		Description: getWeatherDescription(weatherData.Current.WeatherCode),
+	// Open-Meteo is asked for °C and km/h, but trust the units it reports
+	// rather than the ones we asked for.
+	tempC, err := units.NormalizeTemp(weatherData.Current.Temperature, weatherData.CurrentUnits.Temperature)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected provider units: %v", err)
+	}
+	windKmh, err := units.NormalizeWind(weatherData.Current.WindSpeed, weatherData.CurrentUnits.WindSpeed)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected provider units: %v", err)
 	}
 
-	weatherRequests.WithLabelValues("success").Inc()
-	return response, nil
+	r := &reading{
+		tempC:    tempC,
+		windKmh:  windKmh,
+		uvIndex:  weatherData.Current.UVIndex,
+		humidity: weatherData.Current.Humidity,
+		windDir:  weatherData.Current.WindDir,
+		code:     weatherData.Current.WeatherCode,
+		isDay:    weatherData.Current.IsDay == 1,
+		fetched:  time.Now(),
+	}
+	if s.observe != nil {
+		s.observe(r.observation(lat, lon))
+	}
+	return r, nil
+}
+
+// Observation is a reading of the current weather as fetched from
+// Open-Meteo, in metric units.
+type Observation struct {
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	TemperatureC  float64   `json:"temperature_c"`
+	WindKmh       float64   `json:"wind_kmh"`
+	WindDirection int32     `json:"wind_direction"`
+	Humidity      int32     `json:"humidity"`
+	UVIndex       float64   `json:"uv_index"`
+	WeatherCode   int32     `json:"weather_code"`
+	Description   string    `json:"description"`
+	IsDay         bool      `json:"is_day"`
+	Time          time.Time `json:"time"`
+}
+
+func (r *reading) observation(lat, lon float64) *Observation {
+	return &Observation{
+		Latitude:      lat,
+		Longitude:     lon,
+		TemperatureC:  r.tempC,
+		WindKmh:       r.windKmh,
+		WindDirection: r.windDir,
+		Humidity:      r.humidity,
+		UVIndex:       r.uvIndex,
+		WeatherCode:   r.code,
+		Description:   getWeatherDescription(r.code),
+		IsDay:         r.isDay,
+		Time:          r.fetched,
+	}
 }
@@ -0,0 +1,19 @@
+package weather
+
+import "github.com/pixperk/effinarounf/services/validate"
+
+// ValidationRules are the field constraints the validation interceptor
+// enforces on weather requests.
+var ValidationRules = []validate.Rule{
+	validate.Latitude("weather.WeatherRequest.latitude"),
+	validate.Longitude("weather.WeatherRequest.longitude"),
+	validate.Latitude("weather.DailyForecastRequest.latitude"),
+	validate.Longitude("weather.DailyForecastRequest.longitude"),
+	validate.Latitude("weather.HourlyForecastRequest.latitude"),
+	validate.Longitude("weather.HourlyForecastRequest.longitude"),
+	validate.Range("weather.HourlyForecastRequest.hours", 0, maxForecastHours),
+	validate.Latitude("weather.DashboardLocation.latitude"),
+	validate.Longitude("weather.DashboardLocation.longitude"),
+	validate.Required("weather.DashboardRequest.locations"),
+	validate.MaxItems("weather.DashboardRequest.locations", maxDashboardLocations),
+}
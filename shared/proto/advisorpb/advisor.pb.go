@@ -21,30 +21,4569 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CityOrder int32
+
+const (
+	// Keep the order of AdvisorRequest.cities.
+	CityOrder_CITY_ORDER_REQUESTED    CityOrder = 0
+	CityOrder_CITY_ORDER_ALPHABETICAL CityOrder = 1
+)
+
+// Enum value maps for CityOrder.
+var (
+	CityOrder_name = map[int32]string{
+		0: "CITY_ORDER_REQUESTED",
+		1: "CITY_ORDER_ALPHABETICAL",
+	}
+	CityOrder_value = map[string]int32{
+		"CITY_ORDER_REQUESTED":    0,
+		"CITY_ORDER_ALPHABETICAL": 1,
+	}
+)
+
+func (x CityOrder) Enum() *CityOrder {
+	p := new(CityOrder)
+	*p = x
+	return p
+}
+
+func (x CityOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CityOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[0].Descriptor()
+}
+
+func (CityOrder) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[0]
+}
+
+func (x CityOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CityOrder.Descriptor instead.
+func (CityOrder) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{0}
+}
+
+type AdviceFormat int32
+
+const (
+	// As written by the model.
+	AdviceFormat_ADVICE_FORMAT_MARKDOWN AdviceFormat = 0
+	// Markdown markup removed, for terminals and SMS.
+	AdviceFormat_ADVICE_FORMAT_PLAIN AdviceFormat = 1
+	// Escaped HTML fragment with only p, h1-h6, ul/ol/li, strong, em, code
+	// and http(s) links.
+	AdviceFormat_ADVICE_FORMAT_HTML AdviceFormat = 2
+)
+
+// Enum value maps for AdviceFormat.
+var (
+	AdviceFormat_name = map[int32]string{
+		0: "ADVICE_FORMAT_MARKDOWN",
+		1: "ADVICE_FORMAT_PLAIN",
+		2: "ADVICE_FORMAT_HTML",
+	}
+	AdviceFormat_value = map[string]int32{
+		"ADVICE_FORMAT_MARKDOWN": 0,
+		"ADVICE_FORMAT_PLAIN":    1,
+		"ADVICE_FORMAT_HTML":     2,
+	}
+)
+
+func (x AdviceFormat) Enum() *AdviceFormat {
+	p := new(AdviceFormat)
+	*p = x
+	return p
+}
+
+func (x AdviceFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdviceFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[1].Descriptor()
+}
+
+func (AdviceFormat) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[1]
+}
+
+func (x AdviceFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AdviceFormat.Descriptor instead.
+func (AdviceFormat) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{1}
+}
+
+// AdviceCategory narrows the advice to one topic.
+type AdviceCategory int32
+
+const (
+	// Everything: summary, clothing, activities, places and warnings.
+	AdviceCategory_ADVICE_CATEGORY_ALL AdviceCategory = 0
+	// Getting around and sightseeing: delays, what to visit, what to pack.
+	AdviceCategory_ADVICE_CATEGORY_TRAVEL AdviceCategory = 1
+	// What to wear, layer by layer.
+	AdviceCategory_ADVICE_CATEGORY_CLOTHING AdviceCategory = 2
+	// Outdoor exercise and sports, and the best hours for them.
+	AdviceCategory_ADVICE_CATEGORY_SPORTS AdviceCategory = 3
+	// Heat, cold, UV, air quality and pollen.
+	AdviceCategory_ADVICE_CATEGORY_HEALTH AdviceCategory = 4
+)
+
+// Enum value maps for AdviceCategory.
+var (
+	AdviceCategory_name = map[int32]string{
+		0: "ADVICE_CATEGORY_ALL",
+		1: "ADVICE_CATEGORY_TRAVEL",
+		2: "ADVICE_CATEGORY_CLOTHING",
+		3: "ADVICE_CATEGORY_SPORTS",
+		4: "ADVICE_CATEGORY_HEALTH",
+	}
+	AdviceCategory_value = map[string]int32{
+		"ADVICE_CATEGORY_ALL":      0,
+		"ADVICE_CATEGORY_TRAVEL":   1,
+		"ADVICE_CATEGORY_CLOTHING": 2,
+		"ADVICE_CATEGORY_SPORTS":   3,
+		"ADVICE_CATEGORY_HEALTH":   4,
+	}
+)
+
+func (x AdviceCategory) Enum() *AdviceCategory {
+	p := new(AdviceCategory)
+	*p = x
+	return p
+}
+
+func (x AdviceCategory) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdviceCategory) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[2].Descriptor()
+}
+
+func (AdviceCategory) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[2]
+}
+
+func (x AdviceCategory) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AdviceCategory.Descriptor instead.
+func (AdviceCategory) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{2}
+}
+
+type WebhookKind int32
+
+const (
+	WebhookKind_WEBHOOK_KIND_UNSPECIFIED WebhookKind = 0
+	// A Slack incoming webhook, https://hooks.slack.com/services/...
+	WebhookKind_WEBHOOK_KIND_SLACK WebhookKind = 1
+	// A Discord channel webhook, https://discord.com/api/webhooks/...
+	WebhookKind_WEBHOOK_KIND_DISCORD WebhookKind = 2
+	// Any public http(s) URL, sent each message as signed JSON.
+	WebhookKind_WEBHOOK_KIND_GENERIC WebhookKind = 3
+)
+
+// Enum value maps for WebhookKind.
+var (
+	WebhookKind_name = map[int32]string{
+		0: "WEBHOOK_KIND_UNSPECIFIED",
+		1: "WEBHOOK_KIND_SLACK",
+		2: "WEBHOOK_KIND_DISCORD",
+		3: "WEBHOOK_KIND_GENERIC",
+	}
+	WebhookKind_value = map[string]int32{
+		"WEBHOOK_KIND_UNSPECIFIED": 0,
+		"WEBHOOK_KIND_SLACK":       1,
+		"WEBHOOK_KIND_DISCORD":     2,
+		"WEBHOOK_KIND_GENERIC":     3,
+	}
+)
+
+func (x WebhookKind) Enum() *WebhookKind {
+	p := new(WebhookKind)
+	*p = x
+	return p
+}
+
+func (x WebhookKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WebhookKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[3].Descriptor()
+}
+
+func (WebhookKind) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[3]
+}
+
+func (x WebhookKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WebhookKind.Descriptor instead.
+func (WebhookKind) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{3}
+}
+
+// AlertMetric is the daily forecast value an alert watches.
+type AlertMetric int32
+
+const (
+	AlertMetric_ALERT_METRIC_UNSPECIFIED AlertMetric = 0
+	// Daily high and low temperature.
+	AlertMetric_ALERT_METRIC_TEMP_MAX AlertMetric = 1
+	AlertMetric_ALERT_METRIC_TEMP_MIN AlertMetric = 2
+	// Total precipitation over the day.
+	AlertMetric_ALERT_METRIC_PRECIPITATION AlertMetric = 3
+	// Highest chance of precipitation during the day, in percent.
+	AlertMetric_ALERT_METRIC_PRECIPITATION_PROBABILITY AlertMetric = 4
+	// Strongest sustained wind of the day.
+	AlertMetric_ALERT_METRIC_WIND_SPEED AlertMetric = 5
+	AlertMetric_ALERT_METRIC_UV_INDEX   AlertMetric = 6
+)
+
+// Enum value maps for AlertMetric.
+var (
+	AlertMetric_name = map[int32]string{
+		0: "ALERT_METRIC_UNSPECIFIED",
+		1: "ALERT_METRIC_TEMP_MAX",
+		2: "ALERT_METRIC_TEMP_MIN",
+		3: "ALERT_METRIC_PRECIPITATION",
+		4: "ALERT_METRIC_PRECIPITATION_PROBABILITY",
+		5: "ALERT_METRIC_WIND_SPEED",
+		6: "ALERT_METRIC_UV_INDEX",
+	}
+	AlertMetric_value = map[string]int32{
+		"ALERT_METRIC_UNSPECIFIED":               0,
+		"ALERT_METRIC_TEMP_MAX":                  1,
+		"ALERT_METRIC_TEMP_MIN":                  2,
+		"ALERT_METRIC_PRECIPITATION":             3,
+		"ALERT_METRIC_PRECIPITATION_PROBABILITY": 4,
+		"ALERT_METRIC_WIND_SPEED":                5,
+		"ALERT_METRIC_UV_INDEX":                  6,
+	}
+)
+
+func (x AlertMetric) Enum() *AlertMetric {
+	p := new(AlertMetric)
+	*p = x
+	return p
+}
+
+func (x AlertMetric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertMetric) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[4].Descriptor()
+}
+
+func (AlertMetric) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[4]
+}
+
+func (x AlertMetric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertMetric.Descriptor instead.
+func (AlertMetric) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{4}
+}
+
+type AlertOperator int32
+
+const (
+	AlertOperator_ALERT_OPERATOR_UNSPECIFIED AlertOperator = 0
+	AlertOperator_ALERT_OPERATOR_ABOVE       AlertOperator = 1
+	AlertOperator_ALERT_OPERATOR_AT_LEAST    AlertOperator = 2
+	AlertOperator_ALERT_OPERATOR_BELOW       AlertOperator = 3
+	AlertOperator_ALERT_OPERATOR_AT_MOST     AlertOperator = 4
+)
+
+// Enum value maps for AlertOperator.
+var (
+	AlertOperator_name = map[int32]string{
+		0: "ALERT_OPERATOR_UNSPECIFIED",
+		1: "ALERT_OPERATOR_ABOVE",
+		2: "ALERT_OPERATOR_AT_LEAST",
+		3: "ALERT_OPERATOR_BELOW",
+		4: "ALERT_OPERATOR_AT_MOST",
+	}
+	AlertOperator_value = map[string]int32{
+		"ALERT_OPERATOR_UNSPECIFIED": 0,
+		"ALERT_OPERATOR_ABOVE":       1,
+		"ALERT_OPERATOR_AT_LEAST":    2,
+		"ALERT_OPERATOR_BELOW":       3,
+		"ALERT_OPERATOR_AT_MOST":     4,
+	}
+)
+
+func (x AlertOperator) Enum() *AlertOperator {
+	p := new(AlertOperator)
+	*p = x
+	return p
+}
+
+func (x AlertOperator) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertOperator) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[5].Descriptor()
+}
+
+func (AlertOperator) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[5]
+}
+
+func (x AlertOperator) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertOperator.Descriptor instead.
+func (AlertOperator) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{5}
+}
+
+// AlertWindow is which forecast days an alert looks at, in the location's
+// own time zone.
+type AlertWindow int32
+
+const (
+	AlertWindow_ALERT_WINDOW_TODAY       AlertWindow = 0
+	AlertWindow_ALERT_WINDOW_TOMORROW    AlertWindow = 1
+	AlertWindow_ALERT_WINDOW_NEXT_3_DAYS AlertWindow = 2
+	AlertWindow_ALERT_WINDOW_NEXT_7_DAYS AlertWindow = 3
+)
+
+// Enum value maps for AlertWindow.
+var (
+	AlertWindow_name = map[int32]string{
+		0: "ALERT_WINDOW_TODAY",
+		1: "ALERT_WINDOW_TOMORROW",
+		2: "ALERT_WINDOW_NEXT_3_DAYS",
+		3: "ALERT_WINDOW_NEXT_7_DAYS",
+	}
+	AlertWindow_value = map[string]int32{
+		"ALERT_WINDOW_TODAY":       0,
+		"ALERT_WINDOW_TOMORROW":    1,
+		"ALERT_WINDOW_NEXT_3_DAYS": 2,
+		"ALERT_WINDOW_NEXT_7_DAYS": 3,
+	}
+)
+
+func (x AlertWindow) Enum() *AlertWindow {
+	p := new(AlertWindow)
+	*p = x
+	return p
+}
+
+func (x AlertWindow) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertWindow) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[6].Descriptor()
+}
+
+func (AlertWindow) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[6]
+}
+
+func (x AlertWindow) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertWindow.Descriptor instead.
+func (AlertWindow) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{6}
+}
+
+type Warning_Severity int32
+
+const (
+	Warning_SEVERITY_UNSPECIFIED Warning_Severity = 0
+	Warning_MINOR                Warning_Severity = 1
+	Warning_MODERATE             Warning_Severity = 2
+	Warning_SEVERE               Warning_Severity = 3
+	Warning_EXTREME              Warning_Severity = 4
+)
+
+// Enum value maps for Warning_Severity.
+var (
+	Warning_Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "MINOR",
+		2: "MODERATE",
+		3: "SEVERE",
+		4: "EXTREME",
+	}
+	Warning_Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"MINOR":                1,
+		"MODERATE":             2,
+		"SEVERE":               3,
+		"EXTREME":              4,
+	}
+)
+
+func (x Warning_Severity) Enum() *Warning_Severity {
+	p := new(Warning_Severity)
+	*p = x
+	return p
+}
+
+func (x Warning_Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Warning_Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[7].Descriptor()
+}
+
+func (Warning_Severity) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[7]
+}
+
+func (x Warning_Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Warning_Severity.Descriptor instead.
+func (Warning_Severity) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{10, 0}
+}
+
+type ProgressEvent_Stage int32
+
+const (
+	ProgressEvent_STAGE_UNSPECIFIED  ProgressEvent_Stage = 0
+	ProgressEvent_GEOCODED           ProgressEvent_Stage = 1
+	ProgressEvent_WEATHER_FETCHED    ProgressEvent_Stage = 2
+	ProgressEvent_CITY_FAILED        ProgressEvent_Stage = 3
+	ProgressEvent_GENERATION_STARTED ProgressEvent_Stage = 4
+	// Sent when the stream has been quiet for a while, so proxies
+	// don't drop it as idle. Carries no other fields; ignore it.
+	ProgressEvent_KEEPALIVE ProgressEvent_Stage = 5
+)
+
+// Enum value maps for ProgressEvent_Stage.
+var (
+	ProgressEvent_Stage_name = map[int32]string{
+		0: "STAGE_UNSPECIFIED",
+		1: "GEOCODED",
+		2: "WEATHER_FETCHED",
+		3: "CITY_FAILED",
+		4: "GENERATION_STARTED",
+		5: "KEEPALIVE",
+	}
+	ProgressEvent_Stage_value = map[string]int32{
+		"STAGE_UNSPECIFIED":  0,
+		"GEOCODED":           1,
+		"WEATHER_FETCHED":    2,
+		"CITY_FAILED":        3,
+		"GENERATION_STARTED": 4,
+		"KEEPALIVE":          5,
+	}
+)
+
+func (x ProgressEvent_Stage) Enum() *ProgressEvent_Stage {
+	p := new(ProgressEvent_Stage)
+	*p = x
+	return p
+}
+
+func (x ProgressEvent_Stage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProgressEvent_Stage) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_advisor_proto_enumTypes[8].Descriptor()
+}
+
+func (ProgressEvent_Stage) Type() protoreflect.EnumType {
+	return &file_shared_proto_advisor_proto_enumTypes[8]
+}
+
+func (x ProgressEvent_Stage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProgressEvent_Stage.Descriptor instead.
+func (ProgressEvent_Stage) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{12, 0}
+}
+
 type CityData struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	State    string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Country  string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	// The advice leads with the primary city. At most one city should be
+	// primary; the first one marked wins.
+	Primary bool `protobuf:"varint,4,opt,name=primary,proto3" json:"primary,omitempty"`
+	// Known coordinates skip geocoding. Set both or neither; location is
+	// then only a display name and may be empty. country, if it is an ISO
+	// code, picks the unit conventions.
+	Latitude      *float64 `protobuf:"fixed64,5,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude     *float64 `protobuf:"fixed64,6,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CityData) Reset() {
+	*x = CityData{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CityData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityData) ProtoMessage() {}
+
+func (x *CityData) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityData.ProtoReflect.Descriptor instead.
+func (*CityData) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CityData) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CityData) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CityData) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CityData) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+func (x *CityData) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *CityData) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+type AdvisorRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// At most 10 after repeats are dropped. A repeated city (same location,
+	// state, country and coordinates) is only looked up once.
+	Cities    []*CityData `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	SessionId string      `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// "metric", "imperial", "uk" or "si". Empty uses each city's local units.
+	UnitSystem string `protobuf:"bytes,3,opt,name=unit_system,json=unitSystem,proto3" json:"unit_system,omitempty"`
+	// Skip cities that fail instead of failing the whole request. The
+	// failures are reported in AdvisorResponse.errors.
+	BestEffort bool `protobuf:"varint,4,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	// Gemini model to use instead of the server default, e.g.
+	// "gemini-2.5-flash". Must be one the server allows.
+	Model string `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	// Per-request generation overrides. Unset fields use the server config.
+	Generation *GenerationConfig `protobuf:"bytes,6,opt,name=generation,proto3" json:"generation,omitempty"`
+	// How the remaining cities are ordered after the primary city, in both
+	// the advice text and the structured response.
+	Order CityOrder `protobuf:"varint,7,opt,name=order,proto3,enum=advisor.CityOrder" json:"order,omitempty"`
+	// Format of the advice text, including the safety block. Streams are
+	// converted line by line, so chunks end on line boundaries.
+	Format AdviceFormat `protobuf:"varint,8,opt,name=format,proto3,enum=advisor.AdviceFormat" json:"format,omitempty"`
+	// Optional details about the person asking, so the advice is personal.
+	Profile *UserProfile `protobuf:"bytes,9,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Topic to focus the advice on. Safety warnings are always included.
+	Category AdviceCategory `protobuf:"varint,10,opt,name=category,proto3,enum=advisor.AdviceCategory" json:"category,omitempty"`
+	// Language to write the advice in, as a BCP 47 tag such as "de" or
+	// "pt-BR". Empty is English. The safety block and template advice are
+	// always in English.
+	Language      string `protobuf:"bytes,11,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdvisorRequest) Reset() {
+	*x = AdvisorRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvisorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvisorRequest) ProtoMessage() {}
+
+func (x *AdvisorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvisorRequest.ProtoReflect.Descriptor instead.
+func (*AdvisorRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AdvisorRequest) GetCities() []*CityData {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *AdvisorRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AdvisorRequest) GetUnitSystem() string {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return ""
+}
+
+func (x *AdvisorRequest) GetBestEffort() bool {
+	if x != nil {
+		return x.BestEffort
+	}
+	return false
+}
+
+func (x *AdvisorRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AdvisorRequest) GetGeneration() *GenerationConfig {
+	if x != nil {
+		return x.Generation
+	}
+	return nil
+}
+
+func (x *AdvisorRequest) GetOrder() CityOrder {
+	if x != nil {
+		return x.Order
+	}
+	return CityOrder_CITY_ORDER_REQUESTED
+}
+
+func (x *AdvisorRequest) GetFormat() AdviceFormat {
+	if x != nil {
+		return x.Format
+	}
+	return AdviceFormat_ADVICE_FORMAT_MARKDOWN
+}
+
+func (x *AdvisorRequest) GetProfile() *UserProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *AdvisorRequest) GetCategory() AdviceCategory {
+	if x != nil {
+		return x.Category
+	}
+	return AdviceCategory_ADVICE_CATEGORY_ALL
+}
+
+func (x *AdvisorRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+type UserProfile struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Feels the cold, or the heat, more than most. At most one may be set.
+	RunsCold     bool `protobuf:"varint,1,opt,name=runs_cold,json=runsCold,proto3" json:"runs_cold,omitempty"`
+	RunsHot      bool `protobuf:"varint,2,opt,name=runs_hot,json=runsHot,proto3" json:"runs_hot,omitempty"`
+	HasKids      bool `protobuf:"varint,3,opt,name=has_kids,json=hasKids,proto3" json:"has_kids,omitempty"`
+	CyclesToWork bool `protobuf:"varint,4,opt,name=cycles_to_work,json=cyclesToWork,proto3" json:"cycles_to_work,omitempty"`
+	// Free-form, e.g. "pollen" or "dust". At most 10, 40 characters each.
+	Allergies     []string `protobuf:"bytes,5,rep,name=allergies,proto3" json:"allergies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
+func (*UserProfile) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UserProfile) GetRunsCold() bool {
+	if x != nil {
+		return x.RunsCold
+	}
+	return false
+}
+
+func (x *UserProfile) GetRunsHot() bool {
+	if x != nil {
+		return x.RunsHot
+	}
+	return false
+}
+
+func (x *UserProfile) GetHasKids() bool {
+	if x != nil {
+		return x.HasKids
+	}
+	return false
+}
+
+func (x *UserProfile) GetCyclesToWork() bool {
+	if x != nil {
+		return x.CyclesToWork
+	}
+	return false
+}
+
+func (x *UserProfile) GetAllergies() []string {
+	if x != nil {
+		return x.Allergies
+	}
+	return nil
+}
+
+type GenerationConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 0 to 2.
+	Temperature *float32 `protobuf:"fixed32,1,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	// 0 to 1.
+	TopP *float32 `protobuf:"fixed32,2,opt,name=top_p,json=topP,proto3,oneof" json:"top_p,omitempty"`
+	// 1 to 8192.
+	MaxOutputTokens *int32 `protobuf:"varint,3,opt,name=max_output_tokens,json=maxOutputTokens,proto3,oneof" json:"max_output_tokens,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GenerationConfig) Reset() {
+	*x = GenerationConfig{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerationConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerationConfig) ProtoMessage() {}
+
+func (x *GenerationConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerationConfig.ProtoReflect.Descriptor instead.
+func (*GenerationConfig) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerationConfig) GetTemperature() float32 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *GenerationConfig) GetTopP() float32 {
+	if x != nil && x.TopP != nil {
+		return *x.TopP
+	}
+	return 0
+}
+
+func (x *GenerationConfig) GetMaxOutputTokens() int32 {
+	if x != nil && x.MaxOutputTokens != nil {
+		return *x.MaxOutputTokens
+	}
+	return 0
+}
+
+type CityError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position in the request's cities once repeated cities are dropped.
+	Index         int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Location      string `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Stage         string `protobuf:"bytes,3,opt,name=stage,proto3" json:"stage,omitempty"`
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CityError) Reset() {
+	*x = CityError{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CityError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityError) ProtoMessage() {}
+
+func (x *CityError) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityError.ProtoReflect.Descriptor instead.
+func (*CityError) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CityError) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CityError) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CityError) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *CityError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TokenUsage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Model            string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	PromptTokens     int32                  `protobuf:"varint,2,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	ResponseTokens   int32                  `protobuf:"varint,3,opt,name=response_tokens,json=responseTokens,proto3" json:"response_tokens,omitempty"`
+	TotalTokens      int32                  `protobuf:"varint,4,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	EstimatedCostUsd float64                `protobuf:"fixed64,5,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TokenUsage) Reset() {
+	*x = TokenUsage{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenUsage) ProtoMessage() {}
+
+func (x *TokenUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenUsage.ProtoReflect.Descriptor instead.
+func (*TokenUsage) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TokenUsage) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *TokenUsage) GetPromptTokens() int32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetResponseTokens() int32 {
+	if x != nil {
+		return x.ResponseTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetTotalTokens() int32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *TokenUsage) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+// DataSource credits a provider whose data went into the advice.
+type DataSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	License       string                 `protobuf:"bytes,3,opt,name=license,proto3" json:"license,omitempty"`
+	Attribution   string                 `protobuf:"bytes,4,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	Model         string                 `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	ModelRunTime  int64                  `protobuf:"varint,6,opt,name=model_run_time,json=modelRunTime,proto3" json:"model_run_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataSource) Reset() {
+	*x = DataSource{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataSource) ProtoMessage() {}
+
+func (x *DataSource) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataSource.ProtoReflect.Descriptor instead.
+func (*DataSource) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DataSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DataSource) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DataSource) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *DataSource) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+func (x *DataSource) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *DataSource) GetModelRunTime() int64 {
+	if x != nil {
+		return x.ModelRunTime
+	}
+	return 0
+}
+
+// TimeWindow is a run of forecast hours, Unix seconds, end exclusive.
+type TimeWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int64                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	MaxAqi        int32                  `protobuf:"varint,3,opt,name=max_aqi,json=maxAqi,proto3" json:"max_aqi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeWindow) Reset() {
+	*x = TimeWindow{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeWindow) ProtoMessage() {}
+
+func (x *TimeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeWindow.ProtoReflect.Descriptor instead.
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TimeWindow) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *TimeWindow) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *TimeWindow) GetMaxAqi() int32 {
+	if x != nil {
+		return x.MaxAqi
+	}
+	return 0
+}
+
+// CityExposure lists the cleanest-air hours in the next 24h, best first.
+type CityExposure struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Low pollution, mild temperature and light wind: suits cycling and
+	// people with asthma.
+	Exercise []*TimeWindow `protobuf:"bytes,2,rep,name=exercise,proto3" json:"exercise,omitempty"`
+	// Clean enough outdoor air to ventilate the home.
+	Ventilation      []*TimeWindow `protobuf:"bytes,3,rep,name=ventilation,proto3" json:"ventilation,omitempty"`
+	UtcOffsetSeconds int32         `protobuf:"varint,4,opt,name=utc_offset_seconds,json=utcOffsetSeconds,proto3" json:"utc_offset_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CityExposure) Reset() {
+	*x = CityExposure{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CityExposure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityExposure) ProtoMessage() {}
+
+func (x *CityExposure) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityExposure.ProtoReflect.Descriptor instead.
+func (*CityExposure) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CityExposure) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CityExposure) GetExercise() []*TimeWindow {
+	if x != nil {
+		return x.Exercise
+	}
+	return nil
+}
+
+func (x *CityExposure) GetVentilation() []*TimeWindow {
+	if x != nil {
+		return x.Ventilation
+	}
+	return nil
+}
+
+func (x *CityExposure) GetUtcOffsetSeconds() int32 {
+	if x != nil {
+		return x.UtcOffsetSeconds
+	}
+	return 0
+}
+
+// CitySummary is the structured weather for one city, in display units.
+type CitySummary struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Location        string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Primary         bool                   `protobuf:"varint,2,opt,name=primary,proto3" json:"primary,omitempty"`
+	Temperature     float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TemperatureUnit string                 `protobuf:"bytes,4,opt,name=temperature_unit,json=temperatureUnit,proto3" json:"temperature_unit,omitempty"`
+	Description     string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CitySummary) Reset() {
+	*x = CitySummary{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CitySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CitySummary) ProtoMessage() {}
+
+func (x *CitySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CitySummary.ProtoReflect.Descriptor instead.
+func (*CitySummary) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CitySummary) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *CitySummary) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+func (x *CitySummary) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *CitySummary) GetTemperatureUnit() string {
+	if x != nil {
+		return x.TemperatureUnit
+	}
+	return ""
+}
+
+func (x *CitySummary) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+// Warning is a dangerous condition found by the rules engine, never by the
+// model, so it can be filtered on without parsing the advice text.
+type Warning struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stable code: HEAT_EXTREME, HEAT, COLD_EXTREME, ICE, THUNDERSTORM,
+	// WIND_DAMAGING or WIND_STRONG.
+	Code     string           `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Severity Warning_Severity `protobuf:"varint,2,opt,name=severity,proto3,enum=advisor.Warning_Severity" json:"severity,omitempty"`
+	Location string           `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	// Local weather service wording, e.g. "HEAT ADVISORY" in the US.
+	Headline string `protobuf:"bytes,4,opt,name=headline,proto3" json:"headline,omitempty"`
+	// The full guidance, as shown in the safety block.
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// Unix seconds. end is 0 while the condition is ongoing.
+	Start         int64 `protobuf:"varint,6,opt,name=start,proto3" json:"start,omitempty"`
+	End           int64 `protobuf:"varint,7,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Warning) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Warning) GetSeverity() Warning_Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Warning_SEVERITY_UNSPECIFIED
+}
+
+func (x *Warning) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Warning) GetHeadline() string {
+	if x != nil {
+		return x.Headline
+	}
+	return ""
+}
+
+func (x *Warning) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Warning) GetStart() int64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Warning) GetEnd() int64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type AdvisorResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Advice    string                 `protobuf:"bytes,1,opt,name=advice,proto3" json:"advice,omitempty"`
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Errors    []*CityError           `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Usage     *TokenUsage            `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Providers whose data was used. Their attribution must be displayed.
+	Sources  []*DataSource   `protobuf:"bytes,5,rep,name=sources,proto3" json:"sources,omitempty"`
+	Exposure []*CityExposure `protobuf:"bytes,6,rep,name=exposure,proto3" json:"exposure,omitempty"`
+	// Cities with data, primary first and then in the requested order.
+	Cities []*CitySummary `protobuf:"bytes,7,rep,name=cities,proto3" json:"cities,omitempty"`
+	// ID of the stored advice, for GetAdviceRecord. Empty if history is off.
+	AdviceId string `protobuf:"bytes,8,opt,name=advice_id,json=adviceId,proto3" json:"advice_id,omitempty"`
+	// The advice came from the rule-based templates because the LLM was
+	// unavailable; it is not AI-generated.
+	Fallback bool `protobuf:"varint,9,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	// Safety warnings for all cities, most severe first. The advice text
+	// starts with the same warnings as prose.
+	Warnings []*Warning `protobuf:"bytes,10,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// The advice was reused from a recent, near-identical request; no
+	// tokens were spent generating it.
+	Cached        bool `protobuf:"varint,11,opt,name=cached,proto3" json:"cached,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdvisorResponse) Reset() {
+	*x = AdvisorResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdvisorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdvisorResponse) ProtoMessage() {}
+
+func (x *AdvisorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdvisorResponse.ProtoReflect.Descriptor instead.
+func (*AdvisorResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AdvisorResponse) GetAdvice() string {
+	if x != nil {
+		return x.Advice
+	}
+	return ""
+}
+
+func (x *AdvisorResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AdvisorResponse) GetErrors() []*CityError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetExposure() []*CityExposure {
+	if x != nil {
+		return x.Exposure
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetCities() []*CitySummary {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetAdviceId() string {
+	if x != nil {
+		return x.AdviceId
+	}
+	return ""
+}
+
+func (x *AdvisorResponse) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+func (x *AdvisorResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *AdvisorResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+// ProgressEvent reports work done before the advice text starts, and keeps
+// quiet streams alive.
+type ProgressEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Stage    ProgressEvent_Stage    `protobuf:"varint,1,opt,name=stage,proto3,enum=advisor.ProgressEvent_Stage" json:"stage,omitempty"`
+	Location string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	// Cities finished (fetched or failed) out of total.
+	Completed     int32  `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`
+	Total         int32  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Message       string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgressEvent) Reset() {
+	*x = ProgressEvent{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgressEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgressEvent) ProtoMessage() {}
+
+func (x *ProgressEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgressEvent.ProtoReflect.Descriptor instead.
+func (*ProgressEvent) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ProgressEvent) GetStage() ProgressEvent_Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return ProgressEvent_STAGE_UNSPECIFIED
+}
+
+func (x *ProgressEvent) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ProgressEvent) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ProgressEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type StreamAdviceResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Chunk      string                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	IsComplete bool                   `protobuf:"varint,2,opt,name=is_complete,json=isComplete,proto3" json:"is_complete,omitempty"`
+	// Set on the final message only.
+	Usage    *TokenUsage `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	StreamId string      `protobuf:"bytes,4,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	// Starts at 1 and increases by one for every message on the stream.
+	Sequence uint64 `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Set on the final message: the number of messages in the stream
+	// (including this one) and the hex SHA-256 of all chunk text in order.
+	ChunkCount uint64 `protobuf:"varint,6,opt,name=chunk_count,json=chunkCount,proto3" json:"chunk_count,omitempty"`
+	Sha256     string `protobuf:"bytes,7,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// Set on the final message, as in AdvisorResponse.
+	Sources []*DataSource `protobuf:"bytes,8,rep,name=sources,proto3" json:"sources,omitempty"`
+	// Set on the final message: why generation stopped ("STOP",
+	// "MAX_TOKENS", "SAFETY", "RECITATION", "OTHER", "NO_DATA" when no
+	// city had weather, "FALLBACK" for template advice or "CACHED") and the byte
+	// length of all chunk text.
+	FinishReason string `protobuf:"bytes,9,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	AdviceLength uint64 `protobuf:"varint,10,opt,name=advice_length,json=adviceLength,proto3" json:"advice_length,omitempty"`
+	// Set on progress messages, which carry no chunk text.
+	Progress *ProgressEvent `protobuf:"bytes,11,opt,name=progress,proto3" json:"progress,omitempty"`
+	// Set on the final message, as in AdvisorResponse.
+	Fallback bool `protobuf:"varint,12,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	// Set on the message carrying the safety block, which is sent before
+	// any model output; most severe first.
+	Warnings []*Warning `protobuf:"bytes,13,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	// Set on the final message when the advice came from the semantic
+	// cache; finish_reason is then "CACHED".
+	Cached        bool `protobuf:"varint,14,opt,name=cached,proto3" json:"cached,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAdviceResponse) Reset() {
+	*x = StreamAdviceResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAdviceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAdviceResponse) ProtoMessage() {}
+
+func (x *StreamAdviceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAdviceResponse.ProtoReflect.Descriptor instead.
+func (*StreamAdviceResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamAdviceResponse) GetChunk() string {
+	if x != nil {
+		return x.Chunk
+	}
+	return ""
+}
+
+func (x *StreamAdviceResponse) GetIsComplete() bool {
+	if x != nil {
+		return x.IsComplete
+	}
+	return false
+}
+
+func (x *StreamAdviceResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *StreamAdviceResponse) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *StreamAdviceResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamAdviceResponse) GetChunkCount() uint64 {
+	if x != nil {
+		return x.ChunkCount
+	}
+	return 0
+}
+
+func (x *StreamAdviceResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *StreamAdviceResponse) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *StreamAdviceResponse) GetFinishReason() string {
+	if x != nil {
+		return x.FinishReason
+	}
+	return ""
+}
+
+func (x *StreamAdviceResponse) GetAdviceLength() uint64 {
+	if x != nil {
+		return x.AdviceLength
+	}
+	return 0
+}
+
+func (x *StreamAdviceResponse) GetProgress() *ProgressEvent {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *StreamAdviceResponse) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+func (x *StreamAdviceResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *StreamAdviceResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+type ResendChunksRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	StreamId     string                 `protobuf:"bytes,1,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"`
+	FromSequence uint64                 `protobuf:"varint,2,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"`
+	// Zero means through the end of the stream.
+	ToSequence    uint64 `protobuf:"varint,3,opt,name=to_sequence,json=toSequence,proto3" json:"to_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendChunksRequest) Reset() {
+	*x = ResendChunksRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendChunksRequest) ProtoMessage() {}
+
+func (x *ResendChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendChunksRequest.ProtoReflect.Descriptor instead.
+func (*ResendChunksRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ResendChunksRequest) GetStreamId() string {
+	if x != nil {
+		return x.StreamId
+	}
+	return ""
+}
+
+func (x *ResendChunksRequest) GetFromSequence() uint64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
+func (x *ResendChunksRequest) GetToSequence() uint64 {
+	if x != nil {
+		return x.ToSequence
+	}
+	return 0
+}
+
+type ServerInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerInfoRequest) Reset() {
+	*x = ServerInfoRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfoRequest) ProtoMessage() {}
+
+func (x *ServerInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerInfoRequest.ProtoReflect.Descriptor instead.
+func (*ServerInfoRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{15}
+}
+
+type ServerInfoResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	StreamingEnabled  bool                   `protobuf:"varint,1,opt,name=streaming_enabled,json=streamingEnabled,proto3" json:"streaming_enabled,omitempty"`
+	TemplateOnly      bool                   `protobuf:"varint,2,opt,name=template_only,json=templateOnly,proto3" json:"template_only,omitempty"`
+	ForecastSupported bool                   `protobuf:"varint,3,opt,name=forecast_supported,json=forecastSupported,proto3" json:"forecast_supported,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ServerInfoResponse) Reset() {
+	*x = ServerInfoResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerInfoResponse) ProtoMessage() {}
+
+func (x *ServerInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerInfoResponse.ProtoReflect.Descriptor instead.
+func (*ServerInfoResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ServerInfoResponse) GetStreamingEnabled() bool {
+	if x != nil {
+		return x.StreamingEnabled
+	}
+	return false
+}
+
+func (x *ServerInfoResponse) GetTemplateOnly() bool {
+	if x != nil {
+		return x.TemplateOnly
+	}
+	return false
+}
+
+func (x *ServerInfoResponse) GetForecastSupported() bool {
+	if x != nil {
+		return x.ForecastSupported
+	}
+	return false
+}
+
+// PageRequest is shared by every list RPC.
+type PageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 0 uses the server default; larger values are capped.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token from the previous page. Only valid with the same filters.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Inclusive lower and exclusive upper bound, Unix seconds. 0 is unbounded.
+	StartTime     int64 `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       int64 `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	NewestFirst   bool  `protobuf:"varint,5,opt,name=newest_first,json=newestFirst,proto3" json:"newest_first,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageRequest) Reset() {
+	*x = PageRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageRequest) ProtoMessage() {}
+
+func (x *PageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageRequest.ProtoReflect.Descriptor instead.
+func (*PageRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PageRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PageRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *PageRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *PageRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *PageRequest) GetNewestFirst() bool {
+	if x != nil {
+		return x.NewestFirst
+	}
+	return false
+}
+
+type PageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,1,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of items matching the filters across all pages.
+	TotalSize     int32 `protobuf:"varint,2,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageResponse) Reset() {
+	*x = PageResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageResponse) ProtoMessage() {}
+
+func (x *PageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageResponse.ProtoReflect.Descriptor instead.
+func (*PageResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PageResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *PageResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type ListHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Page          *PageRequest           `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryRequest) Reset() {
+	*x = ListHistoryRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryRequest) ProtoMessage() {}
+
+func (x *ListHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListHistoryRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ListHistoryRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type HistoryEntry struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Role    string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Unix seconds.
+	CreatedAt     int64 `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HistoryEntry) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ListHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Page          *PageResponse          `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryResponse) Reset() {
+	*x = ListHistoryResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryResponse) ProtoMessage() {}
+
+func (x *ListHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListHistoryResponse) GetPage() *PageResponse {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// AdviceRecord is advice as stored in the history.
+type AdviceRecord struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Cities    []string               `protobuf:"bytes,3,rep,name=cities,proto3" json:"cities,omitempty"`
+	// The weather lines the advice was generated from.
+	Weather []string `protobuf:"bytes,4,rep,name=weather,proto3" json:"weather,omitempty"`
+	Advice  string   `protobuf:"bytes,5,opt,name=advice,proto3" json:"advice,omitempty"`
+	Model   string   `protobuf:"bytes,6,opt,name=model,proto3" json:"model,omitempty"`
+	// Unix seconds.
+	CreatedAt     int64 `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdviceRecord) Reset() {
+	*x = AdviceRecord{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdviceRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdviceRecord) ProtoMessage() {}
+
+func (x *AdviceRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdviceRecord.ProtoReflect.Descriptor instead.
+func (*AdviceRecord) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AdviceRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AdviceRecord) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *AdviceRecord) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *AdviceRecord) GetWeather() []string {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *AdviceRecord) GetAdvice() string {
+	if x != nil {
+		return x.Advice
+	}
+	return ""
+}
+
+func (x *AdviceRecord) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AdviceRecord) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ListAdviceHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only advice from this session. Empty lists all advice.
+	SessionId     string       `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Page          *PageRequest `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdviceHistoryRequest) Reset() {
+	*x = ListAdviceHistoryRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdviceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdviceHistoryRequest) ProtoMessage() {}
+
+func (x *ListAdviceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdviceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListAdviceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListAdviceHistoryRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ListAdviceHistoryRequest) GetPage() *PageRequest {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type ListAdviceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AdviceRecord        `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Page          *PageResponse          `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdviceHistoryResponse) Reset() {
+	*x = ListAdviceHistoryResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdviceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdviceHistoryResponse) ProtoMessage() {}
+
+func (x *ListAdviceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdviceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListAdviceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListAdviceHistoryResponse) GetRecords() []*AdviceRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListAdviceHistoryResponse) GetPage() *PageResponse {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type GetAdviceRecordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAdviceRecordRequest) Reset() {
+	*x = GetAdviceRecordRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAdviceRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAdviceRecordRequest) ProtoMessage() {}
+
+func (x *GetAdviceRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAdviceRecordRequest.ProtoReflect.Descriptor instead.
+func (*GetAdviceRecordRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetAdviceRecordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CompareModelsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Cities []*CityData            `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	// Exactly two models. Empty compares the server's default model with
+	// its pro/flash counterpart.
+	Models        []string          `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"`
+	UnitSystem    string            `protobuf:"bytes,3,opt,name=unit_system,json=unitSystem,proto3" json:"unit_system,omitempty"`
+	Generation    *GenerationConfig `protobuf:"bytes,4,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareModelsRequest) Reset() {
+	*x = CompareModelsRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareModelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareModelsRequest) ProtoMessage() {}
+
+func (x *CompareModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareModelsRequest.ProtoReflect.Descriptor instead.
+func (*CompareModelsRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CompareModelsRequest) GetCities() []*CityData {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *CompareModelsRequest) GetModels() []string {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+func (x *CompareModelsRequest) GetUnitSystem() string {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return ""
+}
+
+func (x *CompareModelsRequest) GetGeneration() *GenerationConfig {
+	if x != nil {
+		return x.Generation
+	}
+	return nil
+}
+
+type ModelResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Model     string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Advice    string                 `protobuf:"bytes,2,opt,name=advice,proto3" json:"advice,omitempty"`
+	LatencyMs int64                  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Usage     *TokenUsage            `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Set instead of advice when this model failed.
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelResult) Reset() {
+	*x = ModelResult{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelResult) ProtoMessage() {}
+
+func (x *ModelResult) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelResult.ProtoReflect.Descriptor instead.
+func (*ModelResult) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ModelResult) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ModelResult) GetAdvice() string {
+	if x != nil {
+		return x.Advice
+	}
+	return ""
+}
+
+func (x *ModelResult) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *ModelResult) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ModelResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CompareModelsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The weather rows both models were given.
+	Weather       []string       `protobuf:"bytes,1,rep,name=weather,proto3" json:"weather,omitempty"`
+	Results       []*ModelResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareModelsResponse) Reset() {
+	*x = CompareModelsResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareModelsResponse) ProtoMessage() {}
+
+func (x *CompareModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareModelsResponse.ProtoReflect.Descriptor instead.
+func (*CompareModelsResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CompareModelsResponse) GetWeather() []string {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *CompareModelsResponse) GetResults() []*ModelResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ChatRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ChatRequest_Start
+	//	*ChatRequest_Prompt
+	Payload       isChatRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatRequest) Reset() {
+	*x = ChatRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatRequest) ProtoMessage() {}
+
+func (x *ChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatRequest.ProtoReflect.Descriptor instead.
+func (*ChatRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChatRequest) GetPayload() isChatRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ChatRequest) GetStart() *AdvisorRequest {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatRequest_Start); ok {
+			return x.Start
+		}
+	}
+	return nil
+}
+
+func (x *ChatRequest) GetPrompt() string {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatRequest_Prompt); ok {
+			return x.Prompt
+		}
+	}
+	return ""
+}
+
+type isChatRequest_Payload interface {
+	isChatRequest_Payload()
+}
+
+type ChatRequest_Start struct {
+	// Must be the first message: the cities and options for the chat.
+	Start *AdvisorRequest `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type ChatRequest_Prompt struct {
+	// A follow-up question, sent after the previous turn completed.
+	Prompt string `protobuf:"bytes,2,opt,name=prompt,proto3,oneof"`
+}
+
+func (*ChatRequest_Start) isChatRequest_Payload() {}
+
+func (*ChatRequest_Prompt) isChatRequest_Payload() {}
+
+type ChatResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Chunk string                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// Turn 0 is the initial advice; each prompt starts a new turn.
+	Turn int32 `protobuf:"varint,2,opt,name=turn,proto3" json:"turn,omitempty"`
+	// Set on the last message of a turn, with the turn's usage.
+	TurnComplete  bool        `protobuf:"varint,3,opt,name=turn_complete,json=turnComplete,proto3" json:"turn_complete,omitempty"`
+	Usage         *TokenUsage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatResponse) Reset() {
+	*x = ChatResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatResponse) ProtoMessage() {}
+
+func (x *ChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatResponse.ProtoReflect.Descriptor instead.
+func (*ChatResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ChatResponse) GetChunk() string {
+	if x != nil {
+		return x.Chunk
+	}
+	return ""
+}
+
+func (x *ChatResponse) GetTurn() int32 {
+	if x != nil {
+		return x.Turn
+	}
+	return 0
+}
+
+func (x *ChatResponse) GetTurnComplete() bool {
+	if x != nil {
+		return x.TurnComplete
+	}
+	return false
+}
+
+func (x *ChatResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type RateActivityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// running, cycling, picnic or beach.
+	Activity   string      `protobuf:"bytes,1,opt,name=activity,proto3" json:"activity,omitempty"`
+	Cities     []*CityData `protobuf:"bytes,2,rep,name=cities,proto3" json:"cities,omitempty"`
+	UnitSystem string      `protobuf:"bytes,3,opt,name=unit_system,json=unitSystem,proto3" json:"unit_system,omitempty"`
+	// Ask the model for a one-line comment per city on top of the scores.
+	Explain       bool   `protobuf:"varint,4,opt,name=explain,proto3" json:"explain,omitempty"`
+	Model         string `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateActivityRequest) Reset() {
+	*x = RateActivityRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateActivityRequest) ProtoMessage() {}
+
+func (x *RateActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateActivityRequest.ProtoReflect.Descriptor instead.
+func (*RateActivityRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RateActivityRequest) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *RateActivityRequest) GetCities() []*CityData {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *RateActivityRequest) GetUnitSystem() string {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return ""
+}
+
+func (x *RateActivityRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
+func (x *RateActivityRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type ActivityRating struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// 0 (don't) to 100 (ideal), from fixed weather thresholds.
+	Score   int32    `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons []string `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// Set when explain was requested and the model answered.
+	Comment       string `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityRating) Reset() {
+	*x = ActivityRating{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityRating) ProtoMessage() {}
+
+func (x *ActivityRating) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityRating.ProtoReflect.Descriptor instead.
+func (*ActivityRating) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ActivityRating) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ActivityRating) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ActivityRating) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ActivityRating) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type RateActivityResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Activity string                 `protobuf:"bytes,1,opt,name=activity,proto3" json:"activity,omitempty"`
+	// In request order.
+	Ratings       []*ActivityRating `protobuf:"bytes,2,rep,name=ratings,proto3" json:"ratings,omitempty"`
+	Errors        []*CityError      `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Usage         *TokenUsage       `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateActivityResponse) Reset() {
+	*x = RateActivityResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateActivityResponse) ProtoMessage() {}
+
+func (x *RateActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateActivityResponse.ProtoReflect.Descriptor instead.
+func (*RateActivityResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RateActivityResponse) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *RateActivityResponse) GetRatings() []*ActivityRating {
+	if x != nil {
+		return x.Ratings
+	}
+	return nil
+}
+
+func (x *RateActivityResponse) GetErrors() []*CityError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *RateActivityResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type BestDayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	City  *CityData              `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// Local dates (YYYY-MM-DD), inclusive. Empty start_date means today and
+	// empty end_date a week from the start; at most 16 days ahead.
+	StartDate string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// Optional: running, cycling, picnic or beach. Empty rates general
+	// outdoor plans.
+	Activity string `protobuf:"bytes,4,opt,name=activity,proto3" json:"activity,omitempty"`
+	// How many of the best days to return (default 1).
+	Count      int32  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	UnitSystem string `protobuf:"bytes,6,opt,name=unit_system,json=unitSystem,proto3" json:"unit_system,omitempty"`
+	// Ask the model for a short justification of the pick.
+	Explain       bool   `protobuf:"varint,7,opt,name=explain,proto3" json:"explain,omitempty"`
+	Model         string `protobuf:"bytes,8,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BestDayRequest) Reset() {
+	*x = BestDayRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BestDayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BestDayRequest) ProtoMessage() {}
+
+func (x *BestDayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BestDayRequest.ProtoReflect.Descriptor instead.
+func (*BestDayRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BestDayRequest) GetCity() *CityData {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *BestDayRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *BestDayRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *BestDayRequest) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *BestDayRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *BestDayRequest) GetUnitSystem() string {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return ""
+}
+
+func (x *BestDayRequest) GetExplain() bool {
+	if x != nil {
+		return x.Explain
+	}
+	return false
+}
+
+func (x *BestDayRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type DayRating struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Local date, YYYY-MM-DD.
+	Date  string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Score int32  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	// The first reason summarises the day's weather.
+	Reasons       []string `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayRating) Reset() {
+	*x = DayRating{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayRating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayRating) ProtoMessage() {}
+
+func (x *DayRating) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayRating.ProtoReflect.Descriptor instead.
+func (*DayRating) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DayRating) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DayRating) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *DayRating) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type BestDayResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Location string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	// Best first; ties go to the earlier day.
+	Best []*DayRating `protobuf:"bytes,2,rep,name=best,proto3" json:"best,omitempty"`
+	// Every day in the window, in date order.
+	Days []*DayRating `protobuf:"bytes,3,rep,name=days,proto3" json:"days,omitempty"`
+	// Set when explain was requested and the model answered.
+	Justification string        `protobuf:"bytes,4,opt,name=justification,proto3" json:"justification,omitempty"`
+	Usage         *TokenUsage   `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"`
+	Sources       []*DataSource `protobuf:"bytes,6,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BestDayResponse) Reset() {
+	*x = BestDayResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BestDayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BestDayResponse) ProtoMessage() {}
+
+func (x *BestDayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BestDayResponse.ProtoReflect.Descriptor instead.
+func (*BestDayResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *BestDayResponse) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *BestDayResponse) GetBest() []*DayRating {
+	if x != nil {
+		return x.Best
+	}
+	return nil
+}
+
+func (x *BestDayResponse) GetDays() []*DayRating {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *BestDayResponse) GetJustification() string {
+	if x != nil {
+		return x.Justification
+	}
+	return ""
+}
+
+func (x *BestDayResponse) GetUsage() *TokenUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *BestDayResponse) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type SearchLocationsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Optional filters. country may be a name or an ISO code.
+	State   string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Country string `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	// Default 5, at most 20.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLocationsRequest) Reset() {
+	*x = SearchLocationsRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLocationsRequest) ProtoMessage() {}
+
+func (x *SearchLocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLocationsRequest.ProtoReflect.Descriptor instead.
+func (*SearchLocationsRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SearchLocationsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchLocationsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *SearchLocationsRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *SearchLocationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type LocationCandidate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,5,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,6,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Population    int64                  `protobuf:"varint,7,opt,name=population,proto3" json:"population,omitempty"`
+	Timezone      string                 `protobuf:"bytes,8,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationCandidate) Reset() {
+	*x = LocationCandidate{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationCandidate) ProtoMessage() {}
+
+func (x *LocationCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationCandidate.ProtoReflect.Descriptor instead.
+func (*LocationCandidate) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *LocationCandidate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LocationCandidate) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *LocationCandidate) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *LocationCandidate) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *LocationCandidate) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *LocationCandidate) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *LocationCandidate) GetPopulation() int64 {
+	if x != nil {
+		return x.Population
+	}
+	return 0
+}
+
+func (x *LocationCandidate) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type SearchLocationsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Best match first.
+	Candidates    []*LocationCandidate `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	Sources       []*DataSource        `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchLocationsResponse) Reset() {
+	*x = SearchLocationsResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchLocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchLocationsResponse) ProtoMessage() {}
+
+func (x *SearchLocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchLocationsResponse.ProtoReflect.Descriptor instead.
+func (*SearchLocationsResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SearchLocationsResponse) GetCandidates() []*LocationCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *SearchLocationsResponse) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type ListCitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCitiesRequest) Reset() {
+	*x = ListCitiesRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCitiesRequest) ProtoMessage() {}
+
+func (x *ListCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{40}
+}
+
+type ListCitiesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The cities clients offer before the user searches for a place, in
+	// the server's order. population is not set.
+	Cities        []*LocationCandidate `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCitiesResponse) Reset() {
+	*x = ListCitiesResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCitiesResponse) ProtoMessage() {}
+
+func (x *ListCitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListCitiesResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListCitiesResponse) GetCities() []*LocationCandidate {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+type PackingListRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Destinations []*CityData            `protobuf:"bytes,1,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	// Trip dates (YYYY-MM-DD), inclusive, within the 16-day forecast.
+	// Empty start_date means today and empty end_date a week from the start.
+	StartDate string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate   string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// leisure (default), business, beach or hiking.
+	TripType      string `protobuf:"bytes,4,opt,name=trip_type,json=tripType,proto3" json:"trip_type,omitempty"`
+	UnitSystem    string `protobuf:"bytes,5,opt,name=unit_system,json=unitSystem,proto3" json:"unit_system,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackingListRequest) Reset() {
+	*x = PackingListRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackingListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackingListRequest) ProtoMessage() {}
+
+func (x *PackingListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackingListRequest.ProtoReflect.Descriptor instead.
+func (*PackingListRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PackingListRequest) GetDestinations() []*CityData {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *PackingListRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *PackingListRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *PackingListRequest) GetTripType() string {
+	if x != nil {
+		return x.TripType
+	}
+	return ""
+}
+
+func (x *PackingListRequest) GetUnitSystem() string {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return ""
+}
+
+type PackingItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// essentials, clothing, rain, sun or gear.
+	Category string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity int32  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// The forecast detail that put the item on the list, if any.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Essential     bool   `protobuf:"varint,5,opt,name=essential,proto3" json:"essential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackingItem) Reset() {
+	*x = PackingItem{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackingItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackingItem) ProtoMessage() {}
+
+func (x *PackingItem) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackingItem.ProtoReflect.Descriptor instead.
+func (*PackingItem) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PackingItem) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *PackingItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackingItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PackingItem) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PackingItem) GetEssential() bool {
+	if x != nil {
+		return x.Essential
+	}
+	return false
+}
+
+type PackingListResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Grouped by category; one checkbox per item.
+	Items         []*PackingItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	TripDays      int32          `protobuf:"varint,2,opt,name=trip_days,json=tripDays,proto3" json:"trip_days,omitempty"`
+	Errors        []*CityError   `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	Sources       []*DataSource  `protobuf:"bytes,4,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PackingListResponse) Reset() {
+	*x = PackingListResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PackingListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackingListResponse) ProtoMessage() {}
+
+func (x *PackingListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackingListResponse.ProtoReflect.Descriptor instead.
+func (*PackingListResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PackingListResponse) GetItems() []*PackingItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PackingListResponse) GetTripDays() int32 {
+	if x != nil {
+		return x.TripDays
+	}
+	return 0
+}
+
+func (x *PackingListResponse) GetErrors() []*CityError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *PackingListResponse) GetSources() []*DataSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// Webhook is a channel a subscription's messages are also posted to.
+type Webhook struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  WebhookKind            `protobuf:"varint,1,opt,name=kind,proto3,enum=advisor.WebhookKind" json:"kind,omitempty"`
+	// The URL is a credential: responses show it with the path hidden.
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// For generic webhooks, the HMAC-SHA256 key deliveries are signed
+	// with. The server generates one when it is empty; it is returned only
+	// in the response that creates the subscription.
+	Secret        string `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Webhook) GetKind() WebhookKind {
+	if x != nil {
+		return x.Kind
+	}
+	return WebhookKind_WEBHOOK_KIND_UNSPECIFIED
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type DigestSubscription struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set by the server.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Who the digest is for, passed on to the notification channels.
+	Recipient string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// The advice to generate each day. session_id is ignored.
+	Request *AdvisorRequest `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	// Local delivery time as "HH:MM", 24-hour.
+	DeliveryTime string `protobuf:"bytes,4,opt,name=delivery_time,json=deliveryTime,proto3" json:"delivery_time,omitempty"`
+	// IANA time zone such as "Europe/London". Empty means UTC.
+	TimeZone string `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	// Set by the server, as Unix seconds.
+	NextDelivery  int64 `protobuf:"varint,6,opt,name=next_delivery,json=nextDelivery,proto3" json:"next_delivery,omitempty"`
+	CreatedAt     int64 `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastDelivered int64 `protobuf:"varint,8,opt,name=last_delivered,json=lastDelivered,proto3" json:"last_delivered,omitempty"`
+	// Posted to as well as the server's notification channels.
+	Webhooks      []*Webhook `protobuf:"bytes,9,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DigestSubscription) Reset() {
+	*x = DigestSubscription{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DigestSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DigestSubscription) ProtoMessage() {}
+
+func (x *DigestSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DigestSubscription.ProtoReflect.Descriptor instead.
+func (*DigestSubscription) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DigestSubscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DigestSubscription) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *DigestSubscription) GetRequest() *AdvisorRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *DigestSubscription) GetDeliveryTime() string {
+	if x != nil {
+		return x.DeliveryTime
+	}
+	return ""
+}
+
+func (x *DigestSubscription) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *DigestSubscription) GetNextDelivery() int64 {
+	if x != nil {
+		return x.NextDelivery
+	}
+	return 0
+}
+
+func (x *DigestSubscription) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *DigestSubscription) GetLastDelivered() int64 {
+	if x != nil {
+		return x.LastDelivered
+	}
+	return 0
+}
+
+func (x *DigestSubscription) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type CreateDigestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *DigestSubscription    `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDigestRequest) Reset() {
+	*x = CreateDigestRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDigestRequest) ProtoMessage() {}
+
+func (x *CreateDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDigestRequest.ProtoReflect.Descriptor instead.
+func (*CreateDigestRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CreateDigestRequest) GetSubscription() *DigestSubscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type ListDigestsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDigestsRequest) Reset() {
+	*x = ListDigestsRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDigestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDigestsRequest) ProtoMessage() {}
+
+func (x *ListDigestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDigestsRequest.ProtoReflect.Descriptor instead.
+func (*ListDigestsRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{48}
+}
+
+type ListDigestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*DigestSubscription  `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDigestsResponse) Reset() {
+	*x = ListDigestsResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDigestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDigestsResponse) ProtoMessage() {}
+
+func (x *ListDigestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDigestsResponse.ProtoReflect.Descriptor instead.
+func (*ListDigestsResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListDigestsResponse) GetSubscriptions() []*DigestSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type DeleteDigestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDigestRequest) Reset() {
+	*x = DeleteDigestRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDigestRequest) ProtoMessage() {}
+
+func (x *DeleteDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDigestRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDigestRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DeleteDigestRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteDigestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDigestResponse) Reset() {
+	*x = DeleteDigestResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDigestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDigestResponse) ProtoMessage() {}
+
+func (x *DeleteDigestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDigestResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDigestResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{51}
+}
+
+type AlertCondition struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Metric    AlertMetric            `protobuf:"varint,1,opt,name=metric,proto3,enum=advisor.AlertMetric" json:"metric,omitempty"`
+	Operator  AlertOperator          `protobuf:"varint,2,opt,name=operator,proto3,enum=advisor.AlertOperator" json:"operator,omitempty"`
+	Threshold float64                `protobuf:"fixed64,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Unit of threshold: "C" or "F" for temperatures, "km/h", "m/s", "mph"
+	// or "kn" for wind, "mm" or "in" for precipitation. Empty means °C,
+	// km/h and mm. Probabilities are percent and the UV index has no unit.
+	Unit          string      `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Window        AlertWindow `protobuf:"varint,5,opt,name=window,proto3,enum=advisor.AlertWindow" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertCondition) Reset() {
+	*x = AlertCondition{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertCondition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertCondition) ProtoMessage() {}
+
+func (x *AlertCondition) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertCondition.ProtoReflect.Descriptor instead.
+func (*AlertCondition) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *AlertCondition) GetMetric() AlertMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return AlertMetric_ALERT_METRIC_UNSPECIFIED
+}
+
+func (x *AlertCondition) GetOperator() AlertOperator {
+	if x != nil {
+		return x.Operator
+	}
+	return AlertOperator_ALERT_OPERATOR_UNSPECIFIED
+}
+
+func (x *AlertCondition) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertCondition) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *AlertCondition) GetWindow() AlertWindow {
+	if x != nil {
+		return x.Window
+	}
+	return AlertWindow_ALERT_WINDOW_TODAY
+}
+
+type AlertSubscription struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set by the server.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Who the alert is for, passed on to the notification channels.
+	Recipient string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// Geocoded once, when the alert is created.
+	City      *CityData       `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Condition *AlertCondition `protobuf:"bytes,4,opt,name=condition,proto3" json:"condition,omitempty"`
+	// Set by the server. An alert fires at most once per forecast day:
+	// last_alerted_date is the local date (YYYY-MM-DD) it last fired for.
+	CreatedAt       int64  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastAlertedDate string `protobuf:"bytes,6,opt,name=last_alerted_date,json=lastAlertedDate,proto3" json:"last_alerted_date,omitempty"`
+	LastAlertedAt   int64  `protobuf:"varint,7,opt,name=last_alerted_at,json=lastAlertedAt,proto3" json:"last_alerted_at,omitempty"`
+	// Posted to as well as the server's notification channels.
+	Webhooks      []*Webhook `protobuf:"bytes,8,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertSubscription) Reset() {
+	*x = AlertSubscription{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertSubscription) ProtoMessage() {}
+
+func (x *AlertSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertSubscription.ProtoReflect.Descriptor instead.
+func (*AlertSubscription) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AlertSubscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlertSubscription) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *AlertSubscription) GetCity() *CityData {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *AlertSubscription) GetCondition() *AlertCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+func (x *AlertSubscription) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AlertSubscription) GetLastAlertedDate() string {
+	if x != nil {
+		return x.LastAlertedDate
+	}
+	return ""
+}
+
+func (x *AlertSubscription) GetLastAlertedAt() int64 {
+	if x != nil {
+		return x.LastAlertedAt
+	}
+	return 0
+}
+
+func (x *AlertSubscription) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type CreateAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *AlertSubscription     `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlertRequest) Reset() {
+	*x = CreateAlertRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRequest) ProtoMessage() {}
+
+func (x *CreateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CreateAlertRequest) GetSubscription() *AlertSubscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type ListAlertsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{55}
+}
+
+type ListAlertsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*AlertSubscription   `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListAlertsResponse) GetSubscriptions() []*AlertSubscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type DeleteAlertRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
-	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
-	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CityData) Reset() {
-	*x = CityData{}
-	mi := &file_shared_proto_advisor_proto_msgTypes[0]
+func (x *DeleteAlertRequest) Reset() {
+	*x = DeleteAlertRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CityData) String() string {
+func (x *DeleteAlertRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CityData) ProtoMessage() {}
+func (*DeleteAlertRequest) ProtoMessage() {}
 
-func (x *CityData) ProtoReflect() protoreflect.Message {
-	mi := &file_shared_proto_advisor_proto_msgTypes[0]
+func (x *DeleteAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,54 +4594,145 @@ func (x *CityData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CityData.ProtoReflect.Descriptor instead.
-func (*CityData) Descriptor() ([]byte, []int) {
-	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use DeleteAlertRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *CityData) GetLocation() string {
+func (x *DeleteAlertRequest) GetId() string {
 	if x != nil {
-		return x.Location
+		return x.Id
 	}
 	return ""
 }
 
-func (x *CityData) GetState() string {
+type DeleteAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlertResponse) Reset() {
+	*x = DeleteAlertResponse{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlertResponse) ProtoMessage() {}
+
+func (x *DeleteAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[58]
 	if x != nil {
-		return x.State
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *CityData) GetCountry() string {
+// Deprecated: Use DeleteAlertResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertResponse) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{58}
+}
+
+type SubscribeAlertsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only alerts of these subscriptions; every subscription when empty.
+	SubscriptionIds []string `protobuf:"bytes,1,rep,name=subscription_ids,json=subscriptionIds,proto3" json:"subscription_ids,omitempty"`
+	// Only alerts of subscriptions for this recipient, when set.
+	Recipient     string `protobuf:"bytes,2,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeAlertsRequest) Reset() {
+	*x = SubscribeAlertsRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeAlertsRequest) ProtoMessage() {}
+
+func (x *SubscribeAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[59]
 	if x != nil {
-		return x.Country
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeAlertsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SubscribeAlertsRequest) GetSubscriptionIds() []string {
+	if x != nil {
+		return x.SubscriptionIds
+	}
+	return nil
+}
+
+func (x *SubscribeAlertsRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
 	}
 	return ""
 }
 
-type AdvisorRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cities        []*CityData            `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+// AlertEvent is an alert that fired, or a keepalive.
+type AlertEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set when the stream has been quiet for a while, so proxies don't
+	// drop it as idle. Carries no other fields; ignore it.
+	Keepalive    bool               `protobuf:"varint,1,opt,name=keepalive,proto3" json:"keepalive,omitempty"`
+	Subscription *AlertSubscription `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	// The local forecast date the condition is met on, YYYY-MM-DD.
+	Date string `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	// The forecast value, in the condition's unit.
+	Value float64 `protobuf:"fixed64,4,opt,name=value,proto3" json:"value,omitempty"`
+	// The forecast weather that day, e.g. "Heavy rain".
+	Description string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	// The notification sent to the push channels; body is Markdown.
+	Title string `protobuf:"bytes,6,opt,name=title,proto3" json:"title,omitempty"`
+	Body  string `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	// Unix seconds when the alert fired.
+	Time          int64 `protobuf:"varint,8,opt,name=time,proto3" json:"time,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdvisorRequest) Reset() {
-	*x = AdvisorRequest{}
-	mi := &file_shared_proto_advisor_proto_msgTypes[1]
+func (x *AlertEvent) Reset() {
+	*x = AlertEvent{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdvisorRequest) String() string {
+func (x *AlertEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdvisorRequest) ProtoMessage() {}
+func (*AlertEvent) ProtoMessage() {}
 
-func (x *AdvisorRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_shared_proto_advisor_proto_msgTypes[1]
+func (x *AlertEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,40 +4743,93 @@ func (x *AdvisorRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdvisorRequest.ProtoReflect.Descriptor instead.
-func (*AdvisorRequest) Descriptor() ([]byte, []int) {
-	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use AlertEvent.ProtoReflect.Descriptor instead.
+func (*AlertEvent) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *AdvisorRequest) GetCities() []*CityData {
+func (x *AlertEvent) GetKeepalive() bool {
 	if x != nil {
-		return x.Cities
+		return x.Keepalive
+	}
+	return false
+}
+
+func (x *AlertEvent) GetSubscription() *AlertSubscription {
+	if x != nil {
+		return x.Subscription
 	}
 	return nil
 }
 
-type AdvisorResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Advice        string                 `protobuf:"bytes,1,opt,name=advice,proto3" json:"advice,omitempty"`
+func (x *AlertEvent) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *AlertEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *AlertEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type ExportAuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix seconds, inclusive. Zero leaves that end open.
+	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,2,opt,name=until,proto3" json:"until,omitempty"`
+	// Only entries for this caller identity, when set.
+	Client        string `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AdvisorResponse) Reset() {
-	*x = AdvisorResponse{}
-	mi := &file_shared_proto_advisor_proto_msgTypes[2]
+func (x *ExportAuditLogRequest) Reset() {
+	*x = ExportAuditLogRequest{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AdvisorResponse) String() string {
+func (x *ExportAuditLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AdvisorResponse) ProtoMessage() {}
+func (*ExportAuditLogRequest) ProtoMessage() {}
 
-func (x *AdvisorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shared_proto_advisor_proto_msgTypes[2]
+func (x *ExportAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -157,41 +4840,73 @@ func (x *AdvisorResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AdvisorResponse.ProtoReflect.Descriptor instead.
-func (*AdvisorResponse) Descriptor() ([]byte, []int) {
-	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ExportAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ExportAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *AdvisorResponse) GetAdvice() string {
+func (x *ExportAuditLogRequest) GetSince() int64 {
 	if x != nil {
-		return x.Advice
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ExportAuditLogRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *ExportAuditLogRequest) GetClient() string {
+	if x != nil {
+		return x.Client
 	}
 	return ""
 }
 
-type StreamAdviceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chunk         string                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
-	IsComplete    bool                   `protobuf:"varint,2,opt,name=is_complete,json=isComplete,proto3" json:"is_complete,omitempty"`
+// AuditEntry records one call that may have used the LLM.
+type AuditEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unix seconds when the call ended.
+	Time      int64  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	RequestId string `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The caller identity, "anonymous", or "digest:<recipient>" for
+	// scheduled digests.
+	Client string `protobuf:"bytes,4,opt,name=client,proto3" json:"client,omitempty"`
+	// The full gRPC method, or "digest".
+	Method string   `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	Cities []string `protobuf:"bytes,6,rep,name=cities,proto3" json:"cities,omitempty"`
+	// Models called, in order of first use.
+	Models           []string `protobuf:"bytes,7,rep,name=models,proto3" json:"models,omitempty"`
+	PromptTokens     int64    `protobuf:"varint,8,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	ResponseTokens   int64    `protobuf:"varint,9,opt,name=response_tokens,json=responseTokens,proto3" json:"response_tokens,omitempty"`
+	TotalTokens      int64    `protobuf:"varint,10,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	EstimatedCostUsd float64  `protobuf:"fixed64,11,opt,name=estimated_cost_usd,json=estimatedCostUsd,proto3" json:"estimated_cost_usd,omitempty"`
+	// The gRPC status code, e.g. OK or RESOURCE_EXHAUSTED.
+	Outcome       string `protobuf:"bytes,12,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	DurationMs    int64  `protobuf:"varint,13,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamAdviceResponse) Reset() {
-	*x = StreamAdviceResponse{}
-	mi := &file_shared_proto_advisor_proto_msgTypes[3]
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_shared_proto_advisor_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamAdviceResponse) String() string {
+func (x *AuditEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamAdviceResponse) ProtoMessage() {}
+func (*AuditEntry) ProtoMessage() {}
 
-func (x *StreamAdviceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shared_proto_advisor_proto_msgTypes[3]
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_advisor_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,45 +4917,551 @@ func (x *StreamAdviceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamAdviceResponse.ProtoReflect.Descriptor instead.
-func (*StreamAdviceResponse) Descriptor() ([]byte, []int) {
-	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_shared_proto_advisor_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *StreamAdviceResponse) GetChunk() string {
+func (x *AuditEntry) GetId() string {
 	if x != nil {
-		return x.Chunk
+		return x.Id
 	}
 	return ""
 }
 
-func (x *StreamAdviceResponse) GetIsComplete() bool {
+func (x *AuditEntry) GetTime() int64 {
 	if x != nil {
-		return x.IsComplete
+		return x.Time
 	}
-	return false
+	return 0
+}
+
+func (x *AuditEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetModels() []string {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetPromptTokens() int64 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetResponseTokens() int64 {
+	if x != nil {
+		return x.ResponseTokens
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetEstimatedCostUsd() float64 {
+	if x != nil {
+		return x.EstimatedCostUsd
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
 }
 
 var File_shared_proto_advisor_proto protoreflect.FileDescriptor
 
 const file_shared_proto_advisor_proto_rawDesc = "" +
 	"\n" +
-	"\x1ashared/proto/advisor.proto\x12\aadvisor\"V\n" +
+	"\x1ashared/proto/advisor.proto\x12\aadvisor\"\xcf\x01\n" +
 	"\bCityData\x12\x1a\n" +
 	"\blocation\x18\x01 \x01(\tR\blocation\x12\x14\n" +
 	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
-	"\acountry\x18\x03 \x01(\tR\acountry\";\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x18\n" +
+	"\aprimary\x18\x04 \x01(\bR\aprimary\x12\x1f\n" +
+	"\blatitude\x18\x05 \x01(\x01H\x00R\blatitude\x88\x01\x01\x12!\n" +
+	"\tlongitude\x18\x06 \x01(\x01H\x01R\tlongitude\x88\x01\x01B\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitude\"\xc7\x03\n" +
 	"\x0eAdvisorRequest\x12)\n" +
-	"\x06cities\x18\x01 \x03(\v2\x11.advisor.CityDataR\x06cities\")\n" +
+	"\x06cities\x18\x01 \x03(\v2\x11.advisor.CityDataR\x06cities\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vunit_system\x18\x03 \x01(\tR\n" +
+	"unitSystem\x12\x1f\n" +
+	"\vbest_effort\x18\x04 \x01(\bR\n" +
+	"bestEffort\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x129\n" +
+	"\n" +
+	"generation\x18\x06 \x01(\v2\x19.advisor.GenerationConfigR\n" +
+	"generation\x12(\n" +
+	"\x05order\x18\a \x01(\x0e2\x12.advisor.CityOrderR\x05order\x12-\n" +
+	"\x06format\x18\b \x01(\x0e2\x15.advisor.AdviceFormatR\x06format\x12.\n" +
+	"\aprofile\x18\t \x01(\v2\x14.advisor.UserProfileR\aprofile\x123\n" +
+	"\bcategory\x18\n" +
+	" \x01(\x0e2\x17.advisor.AdviceCategoryR\bcategory\x12\x1a\n" +
+	"\blanguage\x18\v \x01(\tR\blanguage\"\xa4\x01\n" +
+	"\vUserProfile\x12\x1b\n" +
+	"\truns_cold\x18\x01 \x01(\bR\brunsCold\x12\x19\n" +
+	"\bruns_hot\x18\x02 \x01(\bR\arunsHot\x12\x19\n" +
+	"\bhas_kids\x18\x03 \x01(\bR\ahasKids\x12$\n" +
+	"\x0ecycles_to_work\x18\x04 \x01(\bR\fcyclesToWork\x12\x1c\n" +
+	"\tallergies\x18\x05 \x03(\tR\tallergies\"\xb4\x01\n" +
+	"\x10GenerationConfig\x12%\n" +
+	"\vtemperature\x18\x01 \x01(\x02H\x00R\vtemperature\x88\x01\x01\x12\x18\n" +
+	"\x05top_p\x18\x02 \x01(\x02H\x01R\x04topP\x88\x01\x01\x12/\n" +
+	"\x11max_output_tokens\x18\x03 \x01(\x05H\x02R\x0fmaxOutputTokens\x88\x01\x01B\x0e\n" +
+	"\f_temperatureB\b\n" +
+	"\x06_top_pB\x14\n" +
+	"\x12_max_output_tokens\"m\n" +
+	"\tCityError\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\tR\x05stage\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xc1\x01\n" +
+	"\n" +
+	"TokenUsage\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12#\n" +
+	"\rprompt_tokens\x18\x02 \x01(\x05R\fpromptTokens\x12'\n" +
+	"\x0fresponse_tokens\x18\x03 \x01(\x05R\x0eresponseTokens\x12!\n" +
+	"\ftotal_tokens\x18\x04 \x01(\x05R\vtotalTokens\x12,\n" +
+	"\x12estimated_cost_usd\x18\x05 \x01(\x01R\x10estimatedCostUsd\"\xaa\x01\n" +
+	"\n" +
+	"DataSource\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x18\n" +
+	"\alicense\x18\x03 \x01(\tR\alicense\x12 \n" +
+	"\vattribution\x18\x04 \x01(\tR\vattribution\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12$\n" +
+	"\x0emodel_run_time\x18\x06 \x01(\x03R\fmodelRunTime\"M\n" +
+	"\n" +
+	"TimeWindow\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x03R\x03end\x12\x17\n" +
+	"\amax_aqi\x18\x03 \x01(\x05R\x06maxAqi\"\xc0\x01\n" +
+	"\fCityExposure\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12/\n" +
+	"\bexercise\x18\x02 \x03(\v2\x13.advisor.TimeWindowR\bexercise\x125\n" +
+	"\vventilation\x18\x03 \x03(\v2\x13.advisor.TimeWindowR\vventilation\x12,\n" +
+	"\x12utc_offset_seconds\x18\x04 \x01(\x05R\x10utcOffsetSeconds\"\xb2\x01\n" +
+	"\vCitySummary\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12\x18\n" +
+	"\aprimary\x18\x02 \x01(\bR\aprimary\x12 \n" +
+	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12)\n" +
+	"\x10temperature_unit\x18\x04 \x01(\tR\x0ftemperatureUnit\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\"\xae\x02\n" +
+	"\aWarning\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x125\n" +
+	"\bseverity\x18\x02 \x01(\x0e2\x19.advisor.Warning.SeverityR\bseverity\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x1a\n" +
+	"\bheadline\x18\x04 \x01(\tR\bheadline\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05start\x18\x06 \x01(\x03R\x05start\x12\x10\n" +
+	"\x03end\x18\a \x01(\x03R\x03end\"V\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05MINOR\x10\x01\x12\f\n" +
+	"\bMODERATE\x10\x02\x12\n" +
+	"\n" +
+	"\x06SEVERE\x10\x03\x12\v\n" +
+	"\aEXTREME\x10\x04\"\xae\x03\n" +
 	"\x0fAdvisorResponse\x12\x16\n" +
-	"\x06advice\x18\x01 \x01(\tR\x06advice\"M\n" +
+	"\x06advice\x18\x01 \x01(\tR\x06advice\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12*\n" +
+	"\x06errors\x18\x03 \x03(\v2\x12.advisor.CityErrorR\x06errors\x12)\n" +
+	"\x05usage\x18\x04 \x01(\v2\x13.advisor.TokenUsageR\x05usage\x12-\n" +
+	"\asources\x18\x05 \x03(\v2\x13.advisor.DataSourceR\asources\x121\n" +
+	"\bexposure\x18\x06 \x03(\v2\x15.advisor.CityExposureR\bexposure\x12,\n" +
+	"\x06cities\x18\a \x03(\v2\x14.advisor.CitySummaryR\x06cities\x12\x1b\n" +
+	"\tadvice_id\x18\b \x01(\tR\badviceId\x12\x1a\n" +
+	"\bfallback\x18\t \x01(\bR\bfallback\x12,\n" +
+	"\bwarnings\x18\n" +
+	" \x03(\v2\x10.advisor.WarningR\bwarnings\x12\x16\n" +
+	"\x06cached\x18\v \x01(\bR\x06cached\"\xa8\x02\n" +
+	"\rProgressEvent\x122\n" +
+	"\x05stage\x18\x01 \x01(\x0e2\x1c.advisor.ProgressEvent.StageR\x05stage\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\x05R\tcompleted\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"y\n" +
+	"\x05Stage\x12\x15\n" +
+	"\x11STAGE_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bGEOCODED\x10\x01\x12\x13\n" +
+	"\x0fWEATHER_FETCHED\x10\x02\x12\x0f\n" +
+	"\vCITY_FAILED\x10\x03\x12\x16\n" +
+	"\x12GENERATION_STARTED\x10\x04\x12\r\n" +
+	"\tKEEPALIVE\x10\x05\"\xf9\x03\n" +
 	"\x14StreamAdviceResponse\x12\x14\n" +
 	"\x05chunk\x18\x01 \x01(\tR\x05chunk\x12\x1f\n" +
 	"\vis_complete\x18\x02 \x01(\bR\n" +
-	"isComplete2\x9a\x01\n" +
+	"isComplete\x12)\n" +
+	"\x05usage\x18\x03 \x01(\v2\x13.advisor.TokenUsageR\x05usage\x12\x1b\n" +
+	"\tstream_id\x18\x04 \x01(\tR\bstreamId\x12\x1a\n" +
+	"\bsequence\x18\x05 \x01(\x04R\bsequence\x12\x1f\n" +
+	"\vchunk_count\x18\x06 \x01(\x04R\n" +
+	"chunkCount\x12\x16\n" +
+	"\x06sha256\x18\a \x01(\tR\x06sha256\x12-\n" +
+	"\asources\x18\b \x03(\v2\x13.advisor.DataSourceR\asources\x12#\n" +
+	"\rfinish_reason\x18\t \x01(\tR\ffinishReason\x12#\n" +
+	"\radvice_length\x18\n" +
+	" \x01(\x04R\fadviceLength\x122\n" +
+	"\bprogress\x18\v \x01(\v2\x16.advisor.ProgressEventR\bprogress\x12\x1a\n" +
+	"\bfallback\x18\f \x01(\bR\bfallback\x12,\n" +
+	"\bwarnings\x18\r \x03(\v2\x10.advisor.WarningR\bwarnings\x12\x16\n" +
+	"\x06cached\x18\x0e \x01(\bR\x06cached\"x\n" +
+	"\x13ResendChunksRequest\x12\x1b\n" +
+	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12#\n" +
+	"\rfrom_sequence\x18\x02 \x01(\x04R\ffromSequence\x12\x1f\n" +
+	"\vto_sequence\x18\x03 \x01(\x04R\n" +
+	"toSequence\"\x13\n" +
+	"\x11ServerInfoRequest\"\x95\x01\n" +
+	"\x12ServerInfoResponse\x12+\n" +
+	"\x11streaming_enabled\x18\x01 \x01(\bR\x10streamingEnabled\x12#\n" +
+	"\rtemplate_only\x18\x02 \x01(\bR\ftemplateOnly\x12-\n" +
+	"\x12forecast_supported\x18\x03 \x01(\bR\x11forecastSupported\"\xa6\x01\n" +
+	"\vPageRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12!\n" +
+	"\fnewest_first\x18\x05 \x01(\bR\vnewestFirst\"U\n" +
+	"\fPageResponse\x12&\n" +
+	"\x0fnext_page_token\x18\x01 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x02 \x01(\x05R\ttotalSize\"]\n" +
+	"\x12ListHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12(\n" +
+	"\x04page\x18\x02 \x01(\v2\x14.advisor.PageRequestR\x04page\"[\n" +
+	"\fHistoryEntry\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\"q\n" +
+	"\x13ListHistoryResponse\x12/\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.advisor.HistoryEntryR\aentries\x12)\n" +
+	"\x04page\x18\x02 \x01(\v2\x15.advisor.PageResponseR\x04page\"\xbc\x01\n" +
+	"\fAdviceRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06cities\x18\x03 \x03(\tR\x06cities\x12\x18\n" +
+	"\aweather\x18\x04 \x03(\tR\aweather\x12\x16\n" +
+	"\x06advice\x18\x05 \x01(\tR\x06advice\x12\x14\n" +
+	"\x05model\x18\x06 \x01(\tR\x05model\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\"c\n" +
+	"\x18ListAdviceHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12(\n" +
+	"\x04page\x18\x02 \x01(\v2\x14.advisor.PageRequestR\x04page\"w\n" +
+	"\x19ListAdviceHistoryResponse\x12/\n" +
+	"\arecords\x18\x01 \x03(\v2\x15.advisor.AdviceRecordR\arecords\x12)\n" +
+	"\x04page\x18\x02 \x01(\v2\x15.advisor.PageResponseR\x04page\"(\n" +
+	"\x16GetAdviceRecordRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb5\x01\n" +
+	"\x14CompareModelsRequest\x12)\n" +
+	"\x06cities\x18\x01 \x03(\v2\x11.advisor.CityDataR\x06cities\x12\x16\n" +
+	"\x06models\x18\x02 \x03(\tR\x06models\x12\x1f\n" +
+	"\vunit_system\x18\x03 \x01(\tR\n" +
+	"unitSystem\x129\n" +
+	"\n" +
+	"generation\x18\x04 \x01(\v2\x19.advisor.GenerationConfigR\n" +
+	"generation\"\x9b\x01\n" +
+	"\vModelResult\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x16\n" +
+	"\x06advice\x18\x02 \x01(\tR\x06advice\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\x12)\n" +
+	"\x05usage\x18\x04 \x01(\v2\x13.advisor.TokenUsageR\x05usage\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"a\n" +
+	"\x15CompareModelsResponse\x12\x18\n" +
+	"\aweather\x18\x01 \x03(\tR\aweather\x12.\n" +
+	"\aresults\x18\x02 \x03(\v2\x14.advisor.ModelResultR\aresults\"c\n" +
+	"\vChatRequest\x12/\n" +
+	"\x05start\x18\x01 \x01(\v2\x17.advisor.AdvisorRequestH\x00R\x05start\x12\x18\n" +
+	"\x06prompt\x18\x02 \x01(\tH\x00R\x06promptB\t\n" +
+	"\apayload\"\x88\x01\n" +
+	"\fChatResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\tR\x05chunk\x12\x12\n" +
+	"\x04turn\x18\x02 \x01(\x05R\x04turn\x12#\n" +
+	"\rturn_complete\x18\x03 \x01(\bR\fturnComplete\x12)\n" +
+	"\x05usage\x18\x04 \x01(\v2\x13.advisor.TokenUsageR\x05usage\"\xad\x01\n" +
+	"\x13RateActivityRequest\x12\x1a\n" +
+	"\bactivity\x18\x01 \x01(\tR\bactivity\x12)\n" +
+	"\x06cities\x18\x02 \x03(\v2\x11.advisor.CityDataR\x06cities\x12\x1f\n" +
+	"\vunit_system\x18\x03 \x01(\tR\n" +
+	"unitSystem\x12\x18\n" +
+	"\aexplain\x18\x04 \x01(\bR\aexplain\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\"v\n" +
+	"\x0eActivityRating\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x05R\x05score\x12\x18\n" +
+	"\areasons\x18\x03 \x03(\tR\areasons\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\"\xbc\x01\n" +
+	"\x14RateActivityResponse\x12\x1a\n" +
+	"\bactivity\x18\x01 \x01(\tR\bactivity\x121\n" +
+	"\aratings\x18\x02 \x03(\v2\x17.advisor.ActivityRatingR\aratings\x12*\n" +
+	"\x06errors\x18\x03 \x03(\v2\x12.advisor.CityErrorR\x06errors\x12)\n" +
+	"\x05usage\x18\x04 \x01(\v2\x13.advisor.TokenUsageR\x05usage\"\xf4\x01\n" +
+	"\x0eBestDayRequest\x12%\n" +
+	"\x04city\x18\x01 \x01(\v2\x11.advisor.CityDataR\x04city\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\x12\x1a\n" +
+	"\bactivity\x18\x04 \x01(\tR\bactivity\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x05R\x05count\x12\x1f\n" +
+	"\vunit_system\x18\x06 \x01(\tR\n" +
+	"unitSystem\x12\x18\n" +
+	"\aexplain\x18\a \x01(\bR\aexplain\x12\x14\n" +
+	"\x05model\x18\b \x01(\tR\x05model\"O\n" +
+	"\tDayRating\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x05R\x05score\x12\x18\n" +
+	"\areasons\x18\x03 \x03(\tR\areasons\"\xfd\x01\n" +
+	"\x0fBestDayResponse\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12&\n" +
+	"\x04best\x18\x02 \x03(\v2\x12.advisor.DayRatingR\x04best\x12&\n" +
+	"\x04days\x18\x03 \x03(\v2\x12.advisor.DayRatingR\x04days\x12$\n" +
+	"\rjustification\x18\x04 \x01(\tR\rjustification\x12)\n" +
+	"\x05usage\x18\x05 \x01(\v2\x13.advisor.TokenUsageR\x05usage\x12-\n" +
+	"\asources\x18\x06 \x03(\v2\x13.advisor.DataSourceR\asources\"t\n" +
+	"\x16SearchLocationsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xf0\x01\n" +
+	"\x11LocationCandidate\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12!\n" +
+	"\fcountry_code\x18\x04 \x01(\tR\vcountryCode\x12\x1a\n" +
+	"\blatitude\x18\x05 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\x12\x1e\n" +
+	"\n" +
+	"population\x18\a \x01(\x03R\n" +
+	"population\x12\x1a\n" +
+	"\btimezone\x18\b \x01(\tR\btimezone\"\x84\x01\n" +
+	"\x17SearchLocationsResponse\x12:\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2\x1a.advisor.LocationCandidateR\n" +
+	"candidates\x12-\n" +
+	"\asources\x18\x02 \x03(\v2\x13.advisor.DataSourceR\asources\"\x13\n" +
+	"\x11ListCitiesRequest\"H\n" +
+	"\x12ListCitiesResponse\x122\n" +
+	"\x06cities\x18\x01 \x03(\v2\x1a.advisor.LocationCandidateR\x06cities\"\xc3\x01\n" +
+	"\x12PackingListRequest\x125\n" +
+	"\fdestinations\x18\x01 \x03(\v2\x11.advisor.CityDataR\fdestinations\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\x12\x1b\n" +
+	"\ttrip_type\x18\x04 \x01(\tR\btripType\x12\x1f\n" +
+	"\vunit_system\x18\x05 \x01(\tR\n" +
+	"unitSystem\"\x8f\x01\n" +
+	"\vPackingItem\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1c\n" +
+	"\tessential\x18\x05 \x01(\bR\tessential\"\xb9\x01\n" +
+	"\x13PackingListResponse\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.advisor.PackingItemR\x05items\x12\x1b\n" +
+	"\ttrip_days\x18\x02 \x01(\x05R\btripDays\x12*\n" +
+	"\x06errors\x18\x03 \x03(\v2\x12.advisor.CityErrorR\x06errors\x12-\n" +
+	"\asources\x18\x04 \x03(\v2\x13.advisor.DataSourceR\asources\"]\n" +
+	"\aWebhook\x12(\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x14.advisor.WebhookKindR\x04kind\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x03 \x01(\tR\x06secret\"\xd0\x02\n" +
+	"\x12DigestSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\x121\n" +
+	"\arequest\x18\x03 \x01(\v2\x17.advisor.AdvisorRequestR\arequest\x12#\n" +
+	"\rdelivery_time\x18\x04 \x01(\tR\fdeliveryTime\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\x12#\n" +
+	"\rnext_delivery\x18\x06 \x01(\x03R\fnextDelivery\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12%\n" +
+	"\x0elast_delivered\x18\b \x01(\x03R\rlastDelivered\x12,\n" +
+	"\bwebhooks\x18\t \x03(\v2\x10.advisor.WebhookR\bwebhooks\"V\n" +
+	"\x13CreateDigestRequest\x12?\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1b.advisor.DigestSubscriptionR\fsubscription\"\x14\n" +
+	"\x12ListDigestsRequest\"X\n" +
+	"\x13ListDigestsResponse\x12A\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1b.advisor.DigestSubscriptionR\rsubscriptions\"%\n" +
+	"\x13DeleteDigestRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x16\n" +
+	"\x14DeleteDigestResponse\"\xd2\x01\n" +
+	"\x0eAlertCondition\x12,\n" +
+	"\x06metric\x18\x01 \x01(\x0e2\x14.advisor.AlertMetricR\x06metric\x122\n" +
+	"\boperator\x18\x02 \x01(\x0e2\x16.advisor.AlertOperatorR\boperator\x12\x1c\n" +
+	"\tthreshold\x18\x03 \x01(\x01R\tthreshold\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x12,\n" +
+	"\x06window\x18\x05 \x01(\x0e2\x14.advisor.AlertWindowR\x06window\"\xc0\x02\n" +
+	"\x11AlertSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\x12%\n" +
+	"\x04city\x18\x03 \x01(\v2\x11.advisor.CityDataR\x04city\x125\n" +
+	"\tcondition\x18\x04 \x01(\v2\x17.advisor.AlertConditionR\tcondition\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\x12*\n" +
+	"\x11last_alerted_date\x18\x06 \x01(\tR\x0flastAlertedDate\x12&\n" +
+	"\x0flast_alerted_at\x18\a \x01(\x03R\rlastAlertedAt\x12,\n" +
+	"\bwebhooks\x18\b \x03(\v2\x10.advisor.WebhookR\bwebhooks\"T\n" +
+	"\x12CreateAlertRequest\x12>\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1a.advisor.AlertSubscriptionR\fsubscription\"\x13\n" +
+	"\x11ListAlertsRequest\"V\n" +
+	"\x12ListAlertsResponse\x12@\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1a.advisor.AlertSubscriptionR\rsubscriptions\"$\n" +
+	"\x12DeleteAlertRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13DeleteAlertResponse\"a\n" +
+	"\x16SubscribeAlertsRequest\x12)\n" +
+	"\x10subscription_ids\x18\x01 \x03(\tR\x0fsubscriptionIds\x12\x1c\n" +
+	"\trecipient\x18\x02 \x01(\tR\trecipient\"\xf4\x01\n" +
+	"\n" +
+	"AlertEvent\x12\x1c\n" +
+	"\tkeepalive\x18\x01 \x01(\bR\tkeepalive\x12>\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1a.advisor.AlertSubscriptionR\fsubscription\x12\x12\n" +
+	"\x04date\x18\x03 \x01(\tR\x04date\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x01R\x05value\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05title\x18\x06 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\a \x01(\tR\x04body\x12\x12\n" +
+	"\x04time\x18\b \x01(\x03R\x04time\"[\n" +
+	"\x15ExportAuditLogRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x02 \x01(\x03R\x05until\x12\x16\n" +
+	"\x06client\x18\x03 \x01(\tR\x06client\"\x89\x03\n" +
+	"\n" +
+	"AuditEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12\x16\n" +
+	"\x06client\x18\x04 \x01(\tR\x06client\x12\x16\n" +
+	"\x06method\x18\x05 \x01(\tR\x06method\x12\x16\n" +
+	"\x06cities\x18\x06 \x03(\tR\x06cities\x12\x16\n" +
+	"\x06models\x18\a \x03(\tR\x06models\x12#\n" +
+	"\rprompt_tokens\x18\b \x01(\x03R\fpromptTokens\x12'\n" +
+	"\x0fresponse_tokens\x18\t \x01(\x03R\x0eresponseTokens\x12!\n" +
+	"\ftotal_tokens\x18\n" +
+	" \x01(\x03R\vtotalTokens\x12,\n" +
+	"\x12estimated_cost_usd\x18\v \x01(\x01R\x10estimatedCostUsd\x12\x18\n" +
+	"\aoutcome\x18\f \x01(\tR\aoutcome\x12\x1f\n" +
+	"\vduration_ms\x18\r \x01(\x03R\n" +
+	"durationMs*B\n" +
+	"\tCityOrder\x12\x18\n" +
+	"\x14CITY_ORDER_REQUESTED\x10\x00\x12\x1b\n" +
+	"\x17CITY_ORDER_ALPHABETICAL\x10\x01*[\n" +
+	"\fAdviceFormat\x12\x1a\n" +
+	"\x16ADVICE_FORMAT_MARKDOWN\x10\x00\x12\x17\n" +
+	"\x13ADVICE_FORMAT_PLAIN\x10\x01\x12\x16\n" +
+	"\x12ADVICE_FORMAT_HTML\x10\x02*\x9b\x01\n" +
+	"\x0eAdviceCategory\x12\x17\n" +
+	"\x13ADVICE_CATEGORY_ALL\x10\x00\x12\x1a\n" +
+	"\x16ADVICE_CATEGORY_TRAVEL\x10\x01\x12\x1c\n" +
+	"\x18ADVICE_CATEGORY_CLOTHING\x10\x02\x12\x1a\n" +
+	"\x16ADVICE_CATEGORY_SPORTS\x10\x03\x12\x1a\n" +
+	"\x16ADVICE_CATEGORY_HEALTH\x10\x04*w\n" +
+	"\vWebhookKind\x12\x1c\n" +
+	"\x18WEBHOOK_KIND_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12WEBHOOK_KIND_SLACK\x10\x01\x12\x18\n" +
+	"\x14WEBHOOK_KIND_DISCORD\x10\x02\x12\x18\n" +
+	"\x14WEBHOOK_KIND_GENERIC\x10\x03*\xe5\x01\n" +
+	"\vAlertMetric\x12\x1c\n" +
+	"\x18ALERT_METRIC_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ALERT_METRIC_TEMP_MAX\x10\x01\x12\x19\n" +
+	"\x15ALERT_METRIC_TEMP_MIN\x10\x02\x12\x1e\n" +
+	"\x1aALERT_METRIC_PRECIPITATION\x10\x03\x12*\n" +
+	"&ALERT_METRIC_PRECIPITATION_PROBABILITY\x10\x04\x12\x1b\n" +
+	"\x17ALERT_METRIC_WIND_SPEED\x10\x05\x12\x19\n" +
+	"\x15ALERT_METRIC_UV_INDEX\x10\x06*\x9c\x01\n" +
+	"\rAlertOperator\x12\x1e\n" +
+	"\x1aALERT_OPERATOR_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ALERT_OPERATOR_ABOVE\x10\x01\x12\x1b\n" +
+	"\x17ALERT_OPERATOR_AT_LEAST\x10\x02\x12\x18\n" +
+	"\x14ALERT_OPERATOR_BELOW\x10\x03\x12\x1a\n" +
+	"\x16ALERT_OPERATOR_AT_MOST\x10\x04*|\n" +
+	"\vAlertWindow\x12\x16\n" +
+	"\x12ALERT_WINDOW_TODAY\x10\x00\x12\x19\n" +
+	"\x15ALERT_WINDOW_TOMORROW\x10\x01\x12\x1c\n" +
+	"\x18ALERT_WINDOW_NEXT_3_DAYS\x10\x02\x12\x1c\n" +
+	"\x18ALERT_WINDOW_NEXT_7_DAYS\x10\x032\xfc\f\n" +
 	"\x0eAdvisorService\x12>\n" +
 	"\tGetAdvice\x12\x17.advisor.AdvisorRequest\x1a\x18.advisor.AdvisorResponse\x12H\n" +
-	"\fStreamAdvice\x12\x17.advisor.AdvisorRequest\x1a\x1d.advisor.StreamAdviceResponse0\x01B\x18Z\x16shared/proto/advisorpbb\x06proto3"
+	"\fStreamAdvice\x12\x17.advisor.AdvisorRequest\x1a\x1d.advisor.StreamAdviceResponse0\x01\x12H\n" +
+	"\rGetServerInfo\x12\x1a.advisor.ServerInfoRequest\x1a\x1b.advisor.ServerInfoResponse\x12M\n" +
+	"\fResendChunks\x12\x1c.advisor.ResendChunksRequest\x1a\x1d.advisor.StreamAdviceResponse0\x01\x12H\n" +
+	"\vListHistory\x12\x1b.advisor.ListHistoryRequest\x1a\x1c.advisor.ListHistoryResponse\x12Z\n" +
+	"\x11ListAdviceHistory\x12!.advisor.ListAdviceHistoryRequest\x1a\".advisor.ListAdviceHistoryResponse\x12I\n" +
+	"\x0fGetAdviceRecord\x12\x1f.advisor.GetAdviceRecordRequest\x1a\x15.advisor.AdviceRecord\x12N\n" +
+	"\rCompareModels\x12\x1d.advisor.CompareModelsRequest\x1a\x1e.advisor.CompareModelsResponse\x12=\n" +
+	"\n" +
+	"ChatStream\x12\x14.advisor.ChatRequest\x1a\x15.advisor.ChatResponse(\x010\x01\x12K\n" +
+	"\fRateActivity\x12\x1c.advisor.RateActivityRequest\x1a\x1d.advisor.RateActivityResponse\x12<\n" +
+	"\aBestDay\x12\x17.advisor.BestDayRequest\x1a\x18.advisor.BestDayResponse\x12T\n" +
+	"\x0fSearchLocations\x12\x1f.advisor.SearchLocationsRequest\x1a .advisor.SearchLocationsResponse\x12E\n" +
+	"\n" +
+	"ListCities\x12\x1a.advisor.ListCitiesRequest\x1a\x1b.advisor.ListCitiesResponse\x12P\n" +
+	"\x13GeneratePackingList\x12\x1b.advisor.PackingListRequest\x1a\x1c.advisor.PackingListResponse\x12I\n" +
+	"\fCreateDigest\x12\x1c.advisor.CreateDigestRequest\x1a\x1b.advisor.DigestSubscription\x12H\n" +
+	"\vListDigests\x12\x1b.advisor.ListDigestsRequest\x1a\x1c.advisor.ListDigestsResponse\x12K\n" +
+	"\fDeleteDigest\x12\x1c.advisor.DeleteDigestRequest\x1a\x1d.advisor.DeleteDigestResponse\x12F\n" +
+	"\vCreateAlert\x12\x1b.advisor.CreateAlertRequest\x1a\x1a.advisor.AlertSubscription\x12E\n" +
+	"\n" +
+	"ListAlerts\x12\x1a.advisor.ListAlertsRequest\x1a\x1b.advisor.ListAlertsResponse\x12H\n" +
+	"\vDeleteAlert\x12\x1b.advisor.DeleteAlertRequest\x1a\x1c.advisor.DeleteAlertResponse\x12I\n" +
+	"\x0fSubscribeAlerts\x12\x1f.advisor.SubscribeAlertsRequest\x1a\x13.advisor.AlertEvent0\x01\x12G\n" +
+	"\x0eExportAuditLog\x12\x1e.advisor.ExportAuditLogRequest\x1a\x13.advisor.AuditEntry0\x01B\x18Z\x16shared/proto/advisorpbb\x06proto3"
 
 var (
 	file_shared_proto_advisor_proto_rawDescOnce sync.Once
@@ -254,24 +5475,194 @@ func file_shared_proto_advisor_proto_rawDescGZIP() []byte {
 	return file_shared_proto_advisor_proto_rawDescData
 }
 
-var file_shared_proto_advisor_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_shared_proto_advisor_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_shared_proto_advisor_proto_msgTypes = make([]protoimpl.MessageInfo, 63)
 var file_shared_proto_advisor_proto_goTypes = []any{
-	(*CityData)(nil),             // 0: advisor.CityData
-	(*AdvisorRequest)(nil),       // 1: advisor.AdvisorRequest
-	(*AdvisorResponse)(nil),      // 2: advisor.AdvisorResponse
-	(*StreamAdviceResponse)(nil), // 3: advisor.StreamAdviceResponse
+	(CityOrder)(0),                    // 0: advisor.CityOrder
+	(AdviceFormat)(0),                 // 1: advisor.AdviceFormat
+	(AdviceCategory)(0),               // 2: advisor.AdviceCategory
+	(WebhookKind)(0),                  // 3: advisor.WebhookKind
+	(AlertMetric)(0),                  // 4: advisor.AlertMetric
+	(AlertOperator)(0),                // 5: advisor.AlertOperator
+	(AlertWindow)(0),                  // 6: advisor.AlertWindow
+	(Warning_Severity)(0),             // 7: advisor.Warning.Severity
+	(ProgressEvent_Stage)(0),          // 8: advisor.ProgressEvent.Stage
+	(*CityData)(nil),                  // 9: advisor.CityData
+	(*AdvisorRequest)(nil),            // 10: advisor.AdvisorRequest
+	(*UserProfile)(nil),               // 11: advisor.UserProfile
+	(*GenerationConfig)(nil),          // 12: advisor.GenerationConfig
+	(*CityError)(nil),                 // 13: advisor.CityError
+	(*TokenUsage)(nil),                // 14: advisor.TokenUsage
+	(*DataSource)(nil),                // 15: advisor.DataSource
+	(*TimeWindow)(nil),                // 16: advisor.TimeWindow
+	(*CityExposure)(nil),              // 17: advisor.CityExposure
+	(*CitySummary)(nil),               // 18: advisor.CitySummary
+	(*Warning)(nil),                   // 19: advisor.Warning
+	(*AdvisorResponse)(nil),           // 20: advisor.AdvisorResponse
+	(*ProgressEvent)(nil),             // 21: advisor.ProgressEvent
+	(*StreamAdviceResponse)(nil),      // 22: advisor.StreamAdviceResponse
+	(*ResendChunksRequest)(nil),       // 23: advisor.ResendChunksRequest
+	(*ServerInfoRequest)(nil),         // 24: advisor.ServerInfoRequest
+	(*ServerInfoResponse)(nil),        // 25: advisor.ServerInfoResponse
+	(*PageRequest)(nil),               // 26: advisor.PageRequest
+	(*PageResponse)(nil),              // 27: advisor.PageResponse
+	(*ListHistoryRequest)(nil),        // 28: advisor.ListHistoryRequest
+	(*HistoryEntry)(nil),              // 29: advisor.HistoryEntry
+	(*ListHistoryResponse)(nil),       // 30: advisor.ListHistoryResponse
+	(*AdviceRecord)(nil),              // 31: advisor.AdviceRecord
+	(*ListAdviceHistoryRequest)(nil),  // 32: advisor.ListAdviceHistoryRequest
+	(*ListAdviceHistoryResponse)(nil), // 33: advisor.ListAdviceHistoryResponse
+	(*GetAdviceRecordRequest)(nil),    // 34: advisor.GetAdviceRecordRequest
+	(*CompareModelsRequest)(nil),      // 35: advisor.CompareModelsRequest
+	(*ModelResult)(nil),               // 36: advisor.ModelResult
+	(*CompareModelsResponse)(nil),     // 37: advisor.CompareModelsResponse
+	(*ChatRequest)(nil),               // 38: advisor.ChatRequest
+	(*ChatResponse)(nil),              // 39: advisor.ChatResponse
+	(*RateActivityRequest)(nil),       // 40: advisor.RateActivityRequest
+	(*ActivityRating)(nil),            // 41: advisor.ActivityRating
+	(*RateActivityResponse)(nil),      // 42: advisor.RateActivityResponse
+	(*BestDayRequest)(nil),            // 43: advisor.BestDayRequest
+	(*DayRating)(nil),                 // 44: advisor.DayRating
+	(*BestDayResponse)(nil),           // 45: advisor.BestDayResponse
+	(*SearchLocationsRequest)(nil),    // 46: advisor.SearchLocationsRequest
+	(*LocationCandidate)(nil),         // 47: advisor.LocationCandidate
+	(*SearchLocationsResponse)(nil),   // 48: advisor.SearchLocationsResponse
+	(*ListCitiesRequest)(nil),         // 49: advisor.ListCitiesRequest
+	(*ListCitiesResponse)(nil),        // 50: advisor.ListCitiesResponse
+	(*PackingListRequest)(nil),        // 51: advisor.PackingListRequest
+	(*PackingItem)(nil),               // 52: advisor.PackingItem
+	(*PackingListResponse)(nil),       // 53: advisor.PackingListResponse
+	(*Webhook)(nil),                   // 54: advisor.Webhook
+	(*DigestSubscription)(nil),        // 55: advisor.DigestSubscription
+	(*CreateDigestRequest)(nil),       // 56: advisor.CreateDigestRequest
+	(*ListDigestsRequest)(nil),        // 57: advisor.ListDigestsRequest
+	(*ListDigestsResponse)(nil),       // 58: advisor.ListDigestsResponse
+	(*DeleteDigestRequest)(nil),       // 59: advisor.DeleteDigestRequest
+	(*DeleteDigestResponse)(nil),      // 60: advisor.DeleteDigestResponse
+	(*AlertCondition)(nil),            // 61: advisor.AlertCondition
+	(*AlertSubscription)(nil),         // 62: advisor.AlertSubscription
+	(*CreateAlertRequest)(nil),        // 63: advisor.CreateAlertRequest
+	(*ListAlertsRequest)(nil),         // 64: advisor.ListAlertsRequest
+	(*ListAlertsResponse)(nil),        // 65: advisor.ListAlertsResponse
+	(*DeleteAlertRequest)(nil),        // 66: advisor.DeleteAlertRequest
+	(*DeleteAlertResponse)(nil),       // 67: advisor.DeleteAlertResponse
+	(*SubscribeAlertsRequest)(nil),    // 68: advisor.SubscribeAlertsRequest
+	(*AlertEvent)(nil),                // 69: advisor.AlertEvent
+	(*ExportAuditLogRequest)(nil),     // 70: advisor.ExportAuditLogRequest
+	(*AuditEntry)(nil),                // 71: advisor.AuditEntry
 }
 var file_shared_proto_advisor_proto_depIdxs = []int32{
-	0, // 0: advisor.AdvisorRequest.cities:type_name -> advisor.CityData
-	1, // 1: advisor.AdvisorService.GetAdvice:input_type -> advisor.AdvisorRequest
-	1, // 2: advisor.AdvisorService.StreamAdvice:input_type -> advisor.AdvisorRequest
-	2, // 3: advisor.AdvisorService.GetAdvice:output_type -> advisor.AdvisorResponse
-	3, // 4: advisor.AdvisorService.StreamAdvice:output_type -> advisor.StreamAdviceResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	9,  // 0: advisor.AdvisorRequest.cities:type_name -> advisor.CityData
+	12, // 1: advisor.AdvisorRequest.generation:type_name -> advisor.GenerationConfig
+	0,  // 2: advisor.AdvisorRequest.order:type_name -> advisor.CityOrder
+	1,  // 3: advisor.AdvisorRequest.format:type_name -> advisor.AdviceFormat
+	11, // 4: advisor.AdvisorRequest.profile:type_name -> advisor.UserProfile
+	2,  // 5: advisor.AdvisorRequest.category:type_name -> advisor.AdviceCategory
+	16, // 6: advisor.CityExposure.exercise:type_name -> advisor.TimeWindow
+	16, // 7: advisor.CityExposure.ventilation:type_name -> advisor.TimeWindow
+	7,  // 8: advisor.Warning.severity:type_name -> advisor.Warning.Severity
+	13, // 9: advisor.AdvisorResponse.errors:type_name -> advisor.CityError
+	14, // 10: advisor.AdvisorResponse.usage:type_name -> advisor.TokenUsage
+	15, // 11: advisor.AdvisorResponse.sources:type_name -> advisor.DataSource
+	17, // 12: advisor.AdvisorResponse.exposure:type_name -> advisor.CityExposure
+	18, // 13: advisor.AdvisorResponse.cities:type_name -> advisor.CitySummary
+	19, // 14: advisor.AdvisorResponse.warnings:type_name -> advisor.Warning
+	8,  // 15: advisor.ProgressEvent.stage:type_name -> advisor.ProgressEvent.Stage
+	14, // 16: advisor.StreamAdviceResponse.usage:type_name -> advisor.TokenUsage
+	15, // 17: advisor.StreamAdviceResponse.sources:type_name -> advisor.DataSource
+	21, // 18: advisor.StreamAdviceResponse.progress:type_name -> advisor.ProgressEvent
+	19, // 19: advisor.StreamAdviceResponse.warnings:type_name -> advisor.Warning
+	26, // 20: advisor.ListHistoryRequest.page:type_name -> advisor.PageRequest
+	29, // 21: advisor.ListHistoryResponse.entries:type_name -> advisor.HistoryEntry
+	27, // 22: advisor.ListHistoryResponse.page:type_name -> advisor.PageResponse
+	26, // 23: advisor.ListAdviceHistoryRequest.page:type_name -> advisor.PageRequest
+	31, // 24: advisor.ListAdviceHistoryResponse.records:type_name -> advisor.AdviceRecord
+	27, // 25: advisor.ListAdviceHistoryResponse.page:type_name -> advisor.PageResponse
+	9,  // 26: advisor.CompareModelsRequest.cities:type_name -> advisor.CityData
+	12, // 27: advisor.CompareModelsRequest.generation:type_name -> advisor.GenerationConfig
+	14, // 28: advisor.ModelResult.usage:type_name -> advisor.TokenUsage
+	36, // 29: advisor.CompareModelsResponse.results:type_name -> advisor.ModelResult
+	10, // 30: advisor.ChatRequest.start:type_name -> advisor.AdvisorRequest
+	14, // 31: advisor.ChatResponse.usage:type_name -> advisor.TokenUsage
+	9,  // 32: advisor.RateActivityRequest.cities:type_name -> advisor.CityData
+	41, // 33: advisor.RateActivityResponse.ratings:type_name -> advisor.ActivityRating
+	13, // 34: advisor.RateActivityResponse.errors:type_name -> advisor.CityError
+	14, // 35: advisor.RateActivityResponse.usage:type_name -> advisor.TokenUsage
+	9,  // 36: advisor.BestDayRequest.city:type_name -> advisor.CityData
+	44, // 37: advisor.BestDayResponse.best:type_name -> advisor.DayRating
+	44, // 38: advisor.BestDayResponse.days:type_name -> advisor.DayRating
+	14, // 39: advisor.BestDayResponse.usage:type_name -> advisor.TokenUsage
+	15, // 40: advisor.BestDayResponse.sources:type_name -> advisor.DataSource
+	47, // 41: advisor.SearchLocationsResponse.candidates:type_name -> advisor.LocationCandidate
+	15, // 42: advisor.SearchLocationsResponse.sources:type_name -> advisor.DataSource
+	47, // 43: advisor.ListCitiesResponse.cities:type_name -> advisor.LocationCandidate
+	9,  // 44: advisor.PackingListRequest.destinations:type_name -> advisor.CityData
+	52, // 45: advisor.PackingListResponse.items:type_name -> advisor.PackingItem
+	13, // 46: advisor.PackingListResponse.errors:type_name -> advisor.CityError
+	15, // 47: advisor.PackingListResponse.sources:type_name -> advisor.DataSource
+	3,  // 48: advisor.Webhook.kind:type_name -> advisor.WebhookKind
+	10, // 49: advisor.DigestSubscription.request:type_name -> advisor.AdvisorRequest
+	54, // 50: advisor.DigestSubscription.webhooks:type_name -> advisor.Webhook
+	55, // 51: advisor.CreateDigestRequest.subscription:type_name -> advisor.DigestSubscription
+	55, // 52: advisor.ListDigestsResponse.subscriptions:type_name -> advisor.DigestSubscription
+	4,  // 53: advisor.AlertCondition.metric:type_name -> advisor.AlertMetric
+	5,  // 54: advisor.AlertCondition.operator:type_name -> advisor.AlertOperator
+	6,  // 55: advisor.AlertCondition.window:type_name -> advisor.AlertWindow
+	9,  // 56: advisor.AlertSubscription.city:type_name -> advisor.CityData
+	61, // 57: advisor.AlertSubscription.condition:type_name -> advisor.AlertCondition
+	54, // 58: advisor.AlertSubscription.webhooks:type_name -> advisor.Webhook
+	62, // 59: advisor.CreateAlertRequest.subscription:type_name -> advisor.AlertSubscription
+	62, // 60: advisor.ListAlertsResponse.subscriptions:type_name -> advisor.AlertSubscription
+	62, // 61: advisor.AlertEvent.subscription:type_name -> advisor.AlertSubscription
+	10, // 62: advisor.AdvisorService.GetAdvice:input_type -> advisor.AdvisorRequest
+	10, // 63: advisor.AdvisorService.StreamAdvice:input_type -> advisor.AdvisorRequest
+	24, // 64: advisor.AdvisorService.GetServerInfo:input_type -> advisor.ServerInfoRequest
+	23, // 65: advisor.AdvisorService.ResendChunks:input_type -> advisor.ResendChunksRequest
+	28, // 66: advisor.AdvisorService.ListHistory:input_type -> advisor.ListHistoryRequest
+	32, // 67: advisor.AdvisorService.ListAdviceHistory:input_type -> advisor.ListAdviceHistoryRequest
+	34, // 68: advisor.AdvisorService.GetAdviceRecord:input_type -> advisor.GetAdviceRecordRequest
+	35, // 69: advisor.AdvisorService.CompareModels:input_type -> advisor.CompareModelsRequest
+	38, // 70: advisor.AdvisorService.ChatStream:input_type -> advisor.ChatRequest
+	40, // 71: advisor.AdvisorService.RateActivity:input_type -> advisor.RateActivityRequest
+	43, // 72: advisor.AdvisorService.BestDay:input_type -> advisor.BestDayRequest
+	46, // 73: advisor.AdvisorService.SearchLocations:input_type -> advisor.SearchLocationsRequest
+	49, // 74: advisor.AdvisorService.ListCities:input_type -> advisor.ListCitiesRequest
+	51, // 75: advisor.AdvisorService.GeneratePackingList:input_type -> advisor.PackingListRequest
+	56, // 76: advisor.AdvisorService.CreateDigest:input_type -> advisor.CreateDigestRequest
+	57, // 77: advisor.AdvisorService.ListDigests:input_type -> advisor.ListDigestsRequest
+	59, // 78: advisor.AdvisorService.DeleteDigest:input_type -> advisor.DeleteDigestRequest
+	63, // 79: advisor.AdvisorService.CreateAlert:input_type -> advisor.CreateAlertRequest
+	64, // 80: advisor.AdvisorService.ListAlerts:input_type -> advisor.ListAlertsRequest
+	66, // 81: advisor.AdvisorService.DeleteAlert:input_type -> advisor.DeleteAlertRequest
+	68, // 82: advisor.AdvisorService.SubscribeAlerts:input_type -> advisor.SubscribeAlertsRequest
+	70, // 83: advisor.AdvisorService.ExportAuditLog:input_type -> advisor.ExportAuditLogRequest
+	20, // 84: advisor.AdvisorService.GetAdvice:output_type -> advisor.AdvisorResponse
+	22, // 85: advisor.AdvisorService.StreamAdvice:output_type -> advisor.StreamAdviceResponse
+	25, // 86: advisor.AdvisorService.GetServerInfo:output_type -> advisor.ServerInfoResponse
+	22, // 87: advisor.AdvisorService.ResendChunks:output_type -> advisor.StreamAdviceResponse
+	30, // 88: advisor.AdvisorService.ListHistory:output_type -> advisor.ListHistoryResponse
+	33, // 89: advisor.AdvisorService.ListAdviceHistory:output_type -> advisor.ListAdviceHistoryResponse
+	31, // 90: advisor.AdvisorService.GetAdviceRecord:output_type -> advisor.AdviceRecord
+	37, // 91: advisor.AdvisorService.CompareModels:output_type -> advisor.CompareModelsResponse
+	39, // 92: advisor.AdvisorService.ChatStream:output_type -> advisor.ChatResponse
+	42, // 93: advisor.AdvisorService.RateActivity:output_type -> advisor.RateActivityResponse
+	45, // 94: advisor.AdvisorService.BestDay:output_type -> advisor.BestDayResponse
+	48, // 95: advisor.AdvisorService.SearchLocations:output_type -> advisor.SearchLocationsResponse
+	50, // 96: advisor.AdvisorService.ListCities:output_type -> advisor.ListCitiesResponse
+	53, // 97: advisor.AdvisorService.GeneratePackingList:output_type -> advisor.PackingListResponse
+	55, // 98: advisor.AdvisorService.CreateDigest:output_type -> advisor.DigestSubscription
+	58, // 99: advisor.AdvisorService.ListDigests:output_type -> advisor.ListDigestsResponse
+	60, // 100: advisor.AdvisorService.DeleteDigest:output_type -> advisor.DeleteDigestResponse
+	62, // 101: advisor.AdvisorService.CreateAlert:output_type -> advisor.AlertSubscription
+	65, // 102: advisor.AdvisorService.ListAlerts:output_type -> advisor.ListAlertsResponse
+	67, // 103: advisor.AdvisorService.DeleteAlert:output_type -> advisor.DeleteAlertResponse
+	69, // 104: advisor.AdvisorService.SubscribeAlerts:output_type -> advisor.AlertEvent
+	71, // 105: advisor.AdvisorService.ExportAuditLog:output_type -> advisor.AuditEntry
+	84, // [84:106] is the sub-list for method output_type
+	62, // [62:84] is the sub-list for method input_type
+	62, // [62:62] is the sub-list for extension type_name
+	62, // [62:62] is the sub-list for extension extendee
+	0,  // [0:62] is the sub-list for field type_name
 }
 
 func init() { file_shared_proto_advisor_proto_init() }
@@ -279,18 +5670,25 @@ func file_shared_proto_advisor_proto_init() {
 	if File_shared_proto_advisor_proto != nil {
 		return
 	}
+	file_shared_proto_advisor_proto_msgTypes[0].OneofWrappers = []any{}
+	file_shared_proto_advisor_proto_msgTypes[3].OneofWrappers = []any{}
+	file_shared_proto_advisor_proto_msgTypes[29].OneofWrappers = []any{
+		(*ChatRequest_Start)(nil),
+		(*ChatRequest_Prompt)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shared_proto_advisor_proto_rawDesc), len(file_shared_proto_advisor_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      9,
+			NumMessages:   63,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_shared_proto_advisor_proto_goTypes,
 		DependencyIndexes: file_shared_proto_advisor_proto_depIdxs,
+		EnumInfos:         file_shared_proto_advisor_proto_enumTypes,
 		MessageInfos:      file_shared_proto_advisor_proto_msgTypes,
 	}.Build()
 	File_shared_proto_advisor_proto = out.File
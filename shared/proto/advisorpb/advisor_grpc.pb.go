@@ -19,8 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AdvisorService_GetAdvice_FullMethodName    = "/advisor.AdvisorService/GetAdvice"
-	AdvisorService_StreamAdvice_FullMethodName = "/advisor.AdvisorService/StreamAdvice"
+	AdvisorService_GetAdvice_FullMethodName           = "/advisor.AdvisorService/GetAdvice"
+	AdvisorService_StreamAdvice_FullMethodName        = "/advisor.AdvisorService/StreamAdvice"
+	AdvisorService_GetServerInfo_FullMethodName       = "/advisor.AdvisorService/GetServerInfo"
+	AdvisorService_ResendChunks_FullMethodName        = "/advisor.AdvisorService/ResendChunks"
+	AdvisorService_ListHistory_FullMethodName         = "/advisor.AdvisorService/ListHistory"
+	AdvisorService_ListAdviceHistory_FullMethodName   = "/advisor.AdvisorService/ListAdviceHistory"
+	AdvisorService_GetAdviceRecord_FullMethodName     = "/advisor.AdvisorService/GetAdviceRecord"
+	AdvisorService_CompareModels_FullMethodName       = "/advisor.AdvisorService/CompareModels"
+	AdvisorService_ChatStream_FullMethodName          = "/advisor.AdvisorService/ChatStream"
+	AdvisorService_RateActivity_FullMethodName        = "/advisor.AdvisorService/RateActivity"
+	AdvisorService_BestDay_FullMethodName             = "/advisor.AdvisorService/BestDay"
+	AdvisorService_SearchLocations_FullMethodName     = "/advisor.AdvisorService/SearchLocations"
+	AdvisorService_ListCities_FullMethodName          = "/advisor.AdvisorService/ListCities"
+	AdvisorService_GeneratePackingList_FullMethodName = "/advisor.AdvisorService/GeneratePackingList"
+	AdvisorService_CreateDigest_FullMethodName        = "/advisor.AdvisorService/CreateDigest"
+	AdvisorService_ListDigests_FullMethodName         = "/advisor.AdvisorService/ListDigests"
+	AdvisorService_DeleteDigest_FullMethodName        = "/advisor.AdvisorService/DeleteDigest"
+	AdvisorService_CreateAlert_FullMethodName         = "/advisor.AdvisorService/CreateAlert"
+	AdvisorService_ListAlerts_FullMethodName          = "/advisor.AdvisorService/ListAlerts"
+	AdvisorService_DeleteAlert_FullMethodName         = "/advisor.AdvisorService/DeleteAlert"
+	AdvisorService_SubscribeAlerts_FullMethodName     = "/advisor.AdvisorService/SubscribeAlerts"
+	AdvisorService_ExportAuditLog_FullMethodName      = "/advisor.AdvisorService/ExportAuditLog"
 )
 
 // AdvisorServiceClient is the client API for AdvisorService service.
@@ -28,7 +48,59 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AdvisorServiceClient interface {
 	GetAdvice(ctx context.Context, in *AdvisorRequest, opts ...grpc.CallOption) (*AdvisorResponse, error)
+	// StreamAdvice ends in exactly one of two ways. On success the last
+	// message has is_complete set and carries usage, chunk_count, sha256,
+	// finish_reason and advice_length; the stream then closes with OK. On
+	// failure no is_complete message is sent and the stream closes with
+	// CANCELLED or DEADLINE_EXCEEDED (caller gave up), INVALID_ARGUMENT
+	// (bad model or generation override), RESOURCE_EXHAUSTED
+	// (rate limited), FAILED_PRECONDITION (blocked by the safety filter),
+	// UNAVAILABLE (model temporarily failing; retry later) or INTERNAL.
 	StreamAdvice(ctx context.Context, in *AdvisorRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamAdviceResponse], error)
+	GetServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error)
+	ResendChunks(ctx context.Context, in *ResendChunksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamAdviceResponse], error)
+	ListHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListHistoryResponse, error)
+	ListAdviceHistory(ctx context.Context, in *ListAdviceHistoryRequest, opts ...grpc.CallOption) (*ListAdviceHistoryResponse, error)
+	GetAdviceRecord(ctx context.Context, in *GetAdviceRecordRequest, opts ...grpc.CallOption) (*AdviceRecord, error)
+	// CompareModels runs the same weather through two models so operators
+	// can compare output, latency and token use.
+	CompareModels(ctx context.Context, in *CompareModelsRequest, opts ...grpc.CallOption) (*CompareModelsResponse, error)
+	// ChatStream answers the start message with advice for its cities, then
+	// answers each prompt on the same stream with the weather and earlier
+	// turns as context. Turns are answered one at a time.
+	ChatStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error)
+	// RateActivity scores how suitable each city's weather is for an
+	// activity. Scores never depend on the model; explain only adds comments.
+	RateActivity(ctx context.Context, in *RateActivityRequest, opts ...grpc.CallOption) (*RateActivityResponse, error)
+	// BestDay scans a city's daily forecast and returns the best day(s) in
+	// a date window for outdoor plans or an activity.
+	BestDay(ctx context.Context, in *BestDayRequest, opts ...grpc.CallOption) (*BestDayResponse, error)
+	// SearchLocations lists the places a name could mean, filtered by state
+	// and country, so clients can let the user pick one.
+	SearchLocations(ctx context.Context, in *SearchLocationsRequest, opts ...grpc.CallOption) (*SearchLocationsResponse, error)
+	// ListCities returns the server's catalog of suggested cities, so
+	// clients don't each keep their own list.
+	ListCities(ctx context.Context, in *ListCitiesRequest, opts ...grpc.CallOption) (*ListCitiesResponse, error)
+	// GeneratePackingList builds a structured packing list from the daily
+	// forecast at every destination over the trip dates.
+	GeneratePackingList(ctx context.Context, in *PackingListRequest, opts ...grpc.CallOption) (*PackingListResponse, error)
+	// Digests are generated on the server every day at the subscriber's
+	// local delivery time and handed to the notification channels.
+	CreateDigest(ctx context.Context, in *CreateDigestRequest, opts ...grpc.CallOption) (*DigestSubscription, error)
+	ListDigests(ctx context.Context, in *ListDigestsRequest, opts ...grpc.CallOption) (*ListDigestsResponse, error)
+	DeleteDigest(ctx context.Context, in *DeleteDigestRequest, opts ...grpc.CallOption) (*DeleteDigestResponse, error)
+	// Alerts are checked against the daily forecast on the server and
+	// handed to the notification channels when their condition is met.
+	CreateAlert(ctx context.Context, in *CreateAlertRequest, opts ...grpc.CallOption) (*AlertSubscription, error)
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+	DeleteAlert(ctx context.Context, in *DeleteAlertRequest, opts ...grpc.CallOption) (*DeleteAlertResponse, error)
+	// SubscribeAlerts streams alerts as they fire, from the time of the
+	// call; alerts that fired before are not replayed. The stream stays
+	// open until the client cancels it.
+	SubscribeAlerts(ctx context.Context, in *SubscribeAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AlertEvent], error)
+	// ExportAuditLog streams the audit log oldest first. Only callers named
+	// in AUDIT_EXPORTERS may read it; others get PERMISSION_DENIED.
+	ExportAuditLog(ctx context.Context, in *ExportAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AuditEntry], error)
 }
 
 type advisorServiceClient struct {
@@ -68,12 +140,294 @@ func (c *advisorServiceClient) StreamAdvice(ctx context.Context, in *AdvisorRequ
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type AdvisorService_StreamAdviceClient = grpc.ServerStreamingClient[StreamAdviceResponse]
 
+func (c *advisorServiceClient) GetServerInfo(ctx context.Context, in *ServerInfoRequest, opts ...grpc.CallOption) (*ServerInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerInfoResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_GetServerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ResendChunks(ctx context.Context, in *ResendChunksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamAdviceResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdvisorService_ServiceDesc.Streams[1], AdvisorService_ResendChunks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ResendChunksRequest, StreamAdviceResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ResendChunksClient = grpc.ServerStreamingClient[StreamAdviceResponse]
+
+func (c *advisorServiceClient) ListHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHistoryResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_ListHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ListAdviceHistory(ctx context.Context, in *ListAdviceHistoryRequest, opts ...grpc.CallOption) (*ListAdviceHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAdviceHistoryResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_ListAdviceHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) GetAdviceRecord(ctx context.Context, in *GetAdviceRecordRequest, opts ...grpc.CallOption) (*AdviceRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdviceRecord)
+	err := c.cc.Invoke(ctx, AdvisorService_GetAdviceRecord_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) CompareModels(ctx context.Context, in *CompareModelsRequest, opts ...grpc.CallOption) (*CompareModelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareModelsResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_CompareModels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ChatStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatRequest, ChatResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdvisorService_ServiceDesc.Streams[2], AdvisorService_ChatStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatRequest, ChatResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ChatStreamClient = grpc.BidiStreamingClient[ChatRequest, ChatResponse]
+
+func (c *advisorServiceClient) RateActivity(ctx context.Context, in *RateActivityRequest, opts ...grpc.CallOption) (*RateActivityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateActivityResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_RateActivity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) BestDay(ctx context.Context, in *BestDayRequest, opts ...grpc.CallOption) (*BestDayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BestDayResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_BestDay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) SearchLocations(ctx context.Context, in *SearchLocationsRequest, opts ...grpc.CallOption) (*SearchLocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchLocationsResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_SearchLocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ListCities(ctx context.Context, in *ListCitiesRequest, opts ...grpc.CallOption) (*ListCitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCitiesResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_ListCities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) GeneratePackingList(ctx context.Context, in *PackingListRequest, opts ...grpc.CallOption) (*PackingListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PackingListResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_GeneratePackingList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) CreateDigest(ctx context.Context, in *CreateDigestRequest, opts ...grpc.CallOption) (*DigestSubscription, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DigestSubscription)
+	err := c.cc.Invoke(ctx, AdvisorService_CreateDigest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ListDigests(ctx context.Context, in *ListDigestsRequest, opts ...grpc.CallOption) (*ListDigestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDigestsResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_ListDigests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) DeleteDigest(ctx context.Context, in *DeleteDigestRequest, opts ...grpc.CallOption) (*DeleteDigestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDigestResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_DeleteDigest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) CreateAlert(ctx context.Context, in *CreateAlertRequest, opts ...grpc.CallOption) (*AlertSubscription, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertSubscription)
+	err := c.cc.Invoke(ctx, AdvisorService_CreateAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) DeleteAlert(ctx context.Context, in *DeleteAlertRequest, opts ...grpc.CallOption) (*DeleteAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlertResponse)
+	err := c.cc.Invoke(ctx, AdvisorService_DeleteAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisorServiceClient) SubscribeAlerts(ctx context.Context, in *SubscribeAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AlertEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdvisorService_ServiceDesc.Streams[3], AdvisorService_SubscribeAlerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeAlertsRequest, AlertEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_SubscribeAlertsClient = grpc.ServerStreamingClient[AlertEvent]
+
+func (c *advisorServiceClient) ExportAuditLog(ctx context.Context, in *ExportAuditLogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AuditEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdvisorService_ServiceDesc.Streams[4], AdvisorService_ExportAuditLog_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportAuditLogRequest, AuditEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ExportAuditLogClient = grpc.ServerStreamingClient[AuditEntry]
+
 // AdvisorServiceServer is the server API for AdvisorService service.
 // All implementations must embed UnimplementedAdvisorServiceServer
 // for forward compatibility.
 type AdvisorServiceServer interface {
 	GetAdvice(context.Context, *AdvisorRequest) (*AdvisorResponse, error)
+	// StreamAdvice ends in exactly one of two ways. On success the last
+	// message has is_complete set and carries usage, chunk_count, sha256,
+	// finish_reason and advice_length; the stream then closes with OK. On
+	// failure no is_complete message is sent and the stream closes with
+	// CANCELLED or DEADLINE_EXCEEDED (caller gave up), INVALID_ARGUMENT
+	// (bad model or generation override), RESOURCE_EXHAUSTED
+	// (rate limited), FAILED_PRECONDITION (blocked by the safety filter),
+	// UNAVAILABLE (model temporarily failing; retry later) or INTERNAL.
 	StreamAdvice(*AdvisorRequest, grpc.ServerStreamingServer[StreamAdviceResponse]) error
+	GetServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error)
+	ResendChunks(*ResendChunksRequest, grpc.ServerStreamingServer[StreamAdviceResponse]) error
+	ListHistory(context.Context, *ListHistoryRequest) (*ListHistoryResponse, error)
+	ListAdviceHistory(context.Context, *ListAdviceHistoryRequest) (*ListAdviceHistoryResponse, error)
+	GetAdviceRecord(context.Context, *GetAdviceRecordRequest) (*AdviceRecord, error)
+	// CompareModels runs the same weather through two models so operators
+	// can compare output, latency and token use.
+	CompareModels(context.Context, *CompareModelsRequest) (*CompareModelsResponse, error)
+	// ChatStream answers the start message with advice for its cities, then
+	// answers each prompt on the same stream with the weather and earlier
+	// turns as context. Turns are answered one at a time.
+	ChatStream(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error
+	// RateActivity scores how suitable each city's weather is for an
+	// activity. Scores never depend on the model; explain only adds comments.
+	RateActivity(context.Context, *RateActivityRequest) (*RateActivityResponse, error)
+	// BestDay scans a city's daily forecast and returns the best day(s) in
+	// a date window for outdoor plans or an activity.
+	BestDay(context.Context, *BestDayRequest) (*BestDayResponse, error)
+	// SearchLocations lists the places a name could mean, filtered by state
+	// and country, so clients can let the user pick one.
+	SearchLocations(context.Context, *SearchLocationsRequest) (*SearchLocationsResponse, error)
+	// ListCities returns the server's catalog of suggested cities, so
+	// clients don't each keep their own list.
+	ListCities(context.Context, *ListCitiesRequest) (*ListCitiesResponse, error)
+	// GeneratePackingList builds a structured packing list from the daily
+	// forecast at every destination over the trip dates.
+	GeneratePackingList(context.Context, *PackingListRequest) (*PackingListResponse, error)
+	// Digests are generated on the server every day at the subscriber's
+	// local delivery time and handed to the notification channels.
+	CreateDigest(context.Context, *CreateDigestRequest) (*DigestSubscription, error)
+	ListDigests(context.Context, *ListDigestsRequest) (*ListDigestsResponse, error)
+	DeleteDigest(context.Context, *DeleteDigestRequest) (*DeleteDigestResponse, error)
+	// Alerts are checked against the daily forecast on the server and
+	// handed to the notification channels when their condition is met.
+	CreateAlert(context.Context, *CreateAlertRequest) (*AlertSubscription, error)
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	DeleteAlert(context.Context, *DeleteAlertRequest) (*DeleteAlertResponse, error)
+	// SubscribeAlerts streams alerts as they fire, from the time of the
+	// call; alerts that fired before are not replayed. The stream stays
+	// open until the client cancels it.
+	SubscribeAlerts(*SubscribeAlertsRequest, grpc.ServerStreamingServer[AlertEvent]) error
+	// ExportAuditLog streams the audit log oldest first. Only callers named
+	// in AUDIT_EXPORTERS may read it; others get PERMISSION_DENIED.
+	ExportAuditLog(*ExportAuditLogRequest, grpc.ServerStreamingServer[AuditEntry]) error
 	mustEmbedUnimplementedAdvisorServiceServer()
 }
 
@@ -90,6 +444,66 @@ func (UnimplementedAdvisorServiceServer) GetAdvice(context.Context, *AdvisorRequ
 func (UnimplementedAdvisorServiceServer) StreamAdvice(*AdvisorRequest, grpc.ServerStreamingServer[StreamAdviceResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamAdvice not implemented")
 }
+func (UnimplementedAdvisorServiceServer) GetServerInfo(context.Context, *ServerInfoRequest) (*ServerInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerInfo not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ResendChunks(*ResendChunksRequest, grpc.ServerStreamingServer[StreamAdviceResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ResendChunks not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ListHistory(context.Context, *ListHistoryRequest) (*ListHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHistory not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ListAdviceHistory(context.Context, *ListAdviceHistoryRequest) (*ListAdviceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAdviceHistory not implemented")
+}
+func (UnimplementedAdvisorServiceServer) GetAdviceRecord(context.Context, *GetAdviceRecordRequest) (*AdviceRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAdviceRecord not implemented")
+}
+func (UnimplementedAdvisorServiceServer) CompareModels(context.Context, *CompareModelsRequest) (*CompareModelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareModels not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ChatStream(grpc.BidiStreamingServer[ChatRequest, ChatResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ChatStream not implemented")
+}
+func (UnimplementedAdvisorServiceServer) RateActivity(context.Context, *RateActivityRequest) (*RateActivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RateActivity not implemented")
+}
+func (UnimplementedAdvisorServiceServer) BestDay(context.Context, *BestDayRequest) (*BestDayResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BestDay not implemented")
+}
+func (UnimplementedAdvisorServiceServer) SearchLocations(context.Context, *SearchLocationsRequest) (*SearchLocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchLocations not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ListCities(context.Context, *ListCitiesRequest) (*ListCitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCities not implemented")
+}
+func (UnimplementedAdvisorServiceServer) GeneratePackingList(context.Context, *PackingListRequest) (*PackingListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePackingList not implemented")
+}
+func (UnimplementedAdvisorServiceServer) CreateDigest(context.Context, *CreateDigestRequest) (*DigestSubscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDigest not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ListDigests(context.Context, *ListDigestsRequest) (*ListDigestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDigests not implemented")
+}
+func (UnimplementedAdvisorServiceServer) DeleteDigest(context.Context, *DeleteDigestRequest) (*DeleteDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDigest not implemented")
+}
+func (UnimplementedAdvisorServiceServer) CreateAlert(context.Context, *CreateAlertRequest) (*AlertSubscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlert not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedAdvisorServiceServer) DeleteAlert(context.Context, *DeleteAlertRequest) (*DeleteAlertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlert not implemented")
+}
+func (UnimplementedAdvisorServiceServer) SubscribeAlerts(*SubscribeAlertsRequest, grpc.ServerStreamingServer[AlertEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAlerts not implemented")
+}
+func (UnimplementedAdvisorServiceServer) ExportAuditLog(*ExportAuditLogRequest, grpc.ServerStreamingServer[AuditEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportAuditLog not implemented")
+}
 func (UnimplementedAdvisorServiceServer) mustEmbedUnimplementedAdvisorServiceServer() {}
 func (UnimplementedAdvisorServiceServer) testEmbeddedByValue()                        {}
 
@@ -140,6 +554,334 @@ func _AdvisorService_StreamAdvice_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type AdvisorService_StreamAdviceServer = grpc.ServerStreamingServer[StreamAdviceResponse]
 
+func _AdvisorService_GetServerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).GetServerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_GetServerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).GetServerInfo(ctx, req.(*ServerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ResendChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResendChunksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdvisorServiceServer).ResendChunks(m, &grpc.GenericServerStream[ResendChunksRequest, StreamAdviceResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ResendChunksServer = grpc.ServerStreamingServer[StreamAdviceResponse]
+
+func _AdvisorService_ListHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).ListHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_ListHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).ListHistory(ctx, req.(*ListHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ListAdviceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAdviceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).ListAdviceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_ListAdviceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).ListAdviceHistory(ctx, req.(*ListAdviceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_GetAdviceRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAdviceRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).GetAdviceRecord(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_GetAdviceRecord_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).GetAdviceRecord(ctx, req.(*GetAdviceRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_CompareModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareModelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).CompareModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_CompareModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).CompareModels(ctx, req.(*CompareModelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ChatStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdvisorServiceServer).ChatStream(&grpc.GenericServerStream[ChatRequest, ChatResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ChatStreamServer = grpc.BidiStreamingServer[ChatRequest, ChatResponse]
+
+func _AdvisorService_RateActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).RateActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_RateActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).RateActivity(ctx, req.(*RateActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_BestDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BestDayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).BestDay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_BestDay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).BestDay(ctx, req.(*BestDayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_SearchLocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchLocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).SearchLocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_SearchLocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).SearchLocations(ctx, req.(*SearchLocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ListCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).ListCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_ListCities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).ListCities(ctx, req.(*ListCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_GeneratePackingList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackingListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).GeneratePackingList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_GeneratePackingList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).GeneratePackingList(ctx, req.(*PackingListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_CreateDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).CreateDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_CreateDigest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).CreateDigest(ctx, req.(*CreateDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ListDigests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDigestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).ListDigests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_ListDigests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).ListDigests(ctx, req.(*ListDigestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_DeleteDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).DeleteDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_DeleteDigest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).DeleteDigest(ctx, req.(*DeleteDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_CreateAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).CreateAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_CreateAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).CreateAlert(ctx, req.(*CreateAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_DeleteAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisorServiceServer).DeleteAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisorService_DeleteAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisorServiceServer).DeleteAlert(ctx, req.(*DeleteAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisorService_SubscribeAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAlertsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdvisorServiceServer).SubscribeAlerts(m, &grpc.GenericServerStream[SubscribeAlertsRequest, AlertEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_SubscribeAlertsServer = grpc.ServerStreamingServer[AlertEvent]
+
+func _AdvisorService_ExportAuditLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportAuditLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdvisorServiceServer).ExportAuditLog(m, &grpc.GenericServerStream[ExportAuditLogRequest, AuditEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdvisorService_ExportAuditLogServer = grpc.ServerStreamingServer[AuditEntry]
+
 // AdvisorService_ServiceDesc is the grpc.ServiceDesc for AdvisorService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +893,70 @@ var AdvisorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAdvice",
 			Handler:    _AdvisorService_GetAdvice_Handler,
 		},
+		{
+			MethodName: "GetServerInfo",
+			Handler:    _AdvisorService_GetServerInfo_Handler,
+		},
+		{
+			MethodName: "ListHistory",
+			Handler:    _AdvisorService_ListHistory_Handler,
+		},
+		{
+			MethodName: "ListAdviceHistory",
+			Handler:    _AdvisorService_ListAdviceHistory_Handler,
+		},
+		{
+			MethodName: "GetAdviceRecord",
+			Handler:    _AdvisorService_GetAdviceRecord_Handler,
+		},
+		{
+			MethodName: "CompareModels",
+			Handler:    _AdvisorService_CompareModels_Handler,
+		},
+		{
+			MethodName: "RateActivity",
+			Handler:    _AdvisorService_RateActivity_Handler,
+		},
+		{
+			MethodName: "BestDay",
+			Handler:    _AdvisorService_BestDay_Handler,
+		},
+		{
+			MethodName: "SearchLocations",
+			Handler:    _AdvisorService_SearchLocations_Handler,
+		},
+		{
+			MethodName: "ListCities",
+			Handler:    _AdvisorService_ListCities_Handler,
+		},
+		{
+			MethodName: "GeneratePackingList",
+			Handler:    _AdvisorService_GeneratePackingList_Handler,
+		},
+		{
+			MethodName: "CreateDigest",
+			Handler:    _AdvisorService_CreateDigest_Handler,
+		},
+		{
+			MethodName: "ListDigests",
+			Handler:    _AdvisorService_ListDigests_Handler,
+		},
+		{
+			MethodName: "DeleteDigest",
+			Handler:    _AdvisorService_DeleteDigest_Handler,
+		},
+		{
+			MethodName: "CreateAlert",
+			Handler:    _AdvisorService_CreateAlert_Handler,
+		},
+		{
+			MethodName: "ListAlerts",
+			Handler:    _AdvisorService_ListAlerts_Handler,
+		},
+		{
+			MethodName: "DeleteAlert",
+			Handler:    _AdvisorService_DeleteAlert_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -158,6 +964,27 @@ var AdvisorService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _AdvisorService_StreamAdvice_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ResendChunks",
+			Handler:       _AdvisorService_ResendChunks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ChatStream",
+			Handler:       _AdvisorService_ChatStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubscribeAlerts",
+			Handler:       _AdvisorService_SubscribeAlerts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportAuditLog",
+			Handler:       _AdvisorService_ExportAuditLog_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "shared/proto/advisor.proto",
 }
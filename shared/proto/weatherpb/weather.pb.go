@@ -21,10 +21,69 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type UnitSystem int32
+
+const (
+	UnitSystem_UNIT_SYSTEM_AUTO     UnitSystem = 0
+	UnitSystem_UNIT_SYSTEM_METRIC   UnitSystem = 1
+	UnitSystem_UNIT_SYSTEM_IMPERIAL UnitSystem = 2
+	UnitSystem_UNIT_SYSTEM_UK       UnitSystem = 3
+	UnitSystem_UNIT_SYSTEM_SI       UnitSystem = 4
+)
+
+// Enum value maps for UnitSystem.
+var (
+	UnitSystem_name = map[int32]string{
+		0: "UNIT_SYSTEM_AUTO",
+		1: "UNIT_SYSTEM_METRIC",
+		2: "UNIT_SYSTEM_IMPERIAL",
+		3: "UNIT_SYSTEM_UK",
+		4: "UNIT_SYSTEM_SI",
+	}
+	UnitSystem_value = map[string]int32{
+		"UNIT_SYSTEM_AUTO":     0,
+		"UNIT_SYSTEM_METRIC":   1,
+		"UNIT_SYSTEM_IMPERIAL": 2,
+		"UNIT_SYSTEM_UK":       3,
+		"UNIT_SYSTEM_SI":       4,
+	}
+)
+
+func (x UnitSystem) Enum() *UnitSystem {
+	p := new(UnitSystem)
+	*p = x
+	return p
+}
+
+func (x UnitSystem) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UnitSystem) Descriptor() protoreflect.EnumDescriptor {
+	return file_shared_proto_weather_proto_enumTypes[0].Descriptor()
+}
+
+func (UnitSystem) Type() protoreflect.EnumType {
+	return &file_shared_proto_weather_proto_enumTypes[0]
+}
+
+func (x UnitSystem) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UnitSystem.Descriptor instead.
+func (UnitSystem) EnumDescriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{0}
+}
+
 type WeatherRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Latitude  float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// ISO 3166-1 alpha-2 code of the location, used to pick local units when
+	// unit_system is AUTO.
+	CountryCode   string     `protobuf:"bytes,3,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	UnitSystem    UnitSystem `protobuf:"varint,4,opt,name=unit_system,json=unitSystem,proto3,enum=weather.UnitSystem" json:"unit_system,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,26 +132,204 @@ func (x *WeatherRequest) GetLongitude() float64 {
 	return 0
 }
 
-type WeatherResponse struct {
+func (x *WeatherRequest) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *WeatherRequest) GetUnitSystem() UnitSystem {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return UnitSystem_UNIT_SYSTEM_AUTO
+}
+
+type Units struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Temperature   float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	FeelsLike     float64                `protobuf:"fixed64,4,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
-	TempMin       float64                `protobuf:"fixed64,5,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
-	TempMax       float64                `protobuf:"fixed64,6,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
-	Pressure      int32                  `protobuf:"varint,7,opt,name=pressure,proto3" json:"pressure,omitempty"`
-	Humidity      int32                  `protobuf:"varint,8,opt,name=humidity,proto3" json:"humidity,omitempty"`
-	WindSpeed     float64                `protobuf:"fixed64,9,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
-	WindDeg       int32                  `protobuf:"varint,10,opt,name=wind_deg,json=windDeg,proto3" json:"wind_deg,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	System        UnitSystem             `protobuf:"varint,1,opt,name=system,proto3,enum=weather.UnitSystem" json:"system,omitempty"`
+	Temperature   string                 `protobuf:"bytes,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	WindSpeed     string                 `protobuf:"bytes,3,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	Pressure      string                 `protobuf:"bytes,4,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Units) Reset() {
+	*x = Units{}
+	mi := &file_shared_proto_weather_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Units) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Units) ProtoMessage() {}
+
+func (x *Units) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Units.ProtoReflect.Descriptor instead.
+func (*Units) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Units) GetSystem() UnitSystem {
+	if x != nil {
+		return x.System
+	}
+	return UnitSystem_UNIT_SYSTEM_AUTO
+}
+
+func (x *Units) GetTemperature() string {
+	if x != nil {
+		return x.Temperature
+	}
+	return ""
+}
+
+func (x *Units) GetWindSpeed() string {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return ""
+}
+
+func (x *Units) GetPressure() string {
+	if x != nil {
+		return x.Pressure
+	}
+	return ""
+}
+
+// Provider describes where data came from and how it must be credited.
+type Provider struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url     string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	License string                 `protobuf:"bytes,3,opt,name=license,proto3" json:"license,omitempty"`
+	// Text that must be shown wherever the data is displayed.
+	Attribution string `protobuf:"bytes,4,opt,name=attribution,proto3" json:"attribution,omitempty"`
+	// Forecast model that produced the data, e.g. "best_match".
+	Model string `protobuf:"bytes,5,opt,name=model,proto3" json:"model,omitempty"`
+	// Unix seconds of the model run; 0 when the provider doesn't report it.
+	ModelRunTime  int64 `protobuf:"varint,6,opt,name=model_run_time,json=modelRunTime,proto3" json:"model_run_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provider) Reset() {
+	*x = Provider{}
+	mi := &file_shared_proto_weather_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provider) ProtoMessage() {}
+
+func (x *Provider) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provider.ProtoReflect.Descriptor instead.
+func (*Provider) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Provider) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Provider) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Provider) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+func (x *Provider) GetAttribution() string {
+	if x != nil {
+		return x.Attribution
+	}
+	return ""
+}
+
+func (x *Provider) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Provider) GetModelRunTime() int64 {
+	if x != nil {
+		return x.ModelRunTime
+	}
+	return 0
+}
+
+type WeatherResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Location    string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Temperature float64                `protobuf:"fixed64,3,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	FeelsLike   float64                `protobuf:"fixed64,4,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	TempMin     float64                `protobuf:"fixed64,5,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax     float64                `protobuf:"fixed64,6,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	Pressure    int32                  `protobuf:"varint,7,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	Humidity    int32                  `protobuf:"varint,8,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	WindSpeed   float64                `protobuf:"fixed64,9,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDeg     int32                  `protobuf:"varint,10,opt,name=wind_deg,json=windDeg,proto3" json:"wind_deg,omitempty"`
+	Timestamp   int64                  `protobuf:"varint,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	WeatherCode int32                  `protobuf:"varint,12,opt,name=weather_code,json=weatherCode,proto3" json:"weather_code,omitempty"`
+	// Temperatures and wind_speed are expressed in these units. pressure is
+	// always hPa; pressure_value carries it in units.pressure.
+	Units         *Units    `protobuf:"bytes,13,opt,name=units,proto3" json:"units,omitempty"`
+	PressureValue float64   `protobuf:"fixed64,14,opt,name=pressure_value,json=pressureValue,proto3" json:"pressure_value,omitempty"`
+	Provider      *Provider `protobuf:"bytes,15,opt,name=provider,proto3" json:"provider,omitempty"`
+	UvIndex       float64   `protobuf:"fixed64,16,opt,name=uv_index,json=uvIndex,proto3" json:"uv_index,omitempty"`
+	IsDay         bool      `protobuf:"varint,17,opt,name=is_day,json=isDay,proto3" json:"is_day,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *WeatherResponse) Reset() {
 	*x = WeatherResponse{}
-	mi := &file_shared_proto_weather_proto_msgTypes[1]
+	mi := &file_shared_proto_weather_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -104,7 +341,7 @@ func (x *WeatherResponse) String() string {
 func (*WeatherResponse) ProtoMessage() {}
 
 func (x *WeatherResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_shared_proto_weather_proto_msgTypes[1]
+	mi := &file_shared_proto_weather_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -117,7 +354,7 @@ func (x *WeatherResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WeatherResponse.ProtoReflect.Descriptor instead.
 func (*WeatherResponse) Descriptor() ([]byte, []int) {
-	return file_shared_proto_weather_proto_rawDescGZIP(), []int{1}
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *WeatherResponse) GetLocation() string {
@@ -197,31 +434,945 @@ func (x *WeatherResponse) GetTimestamp() int64 {
 	return 0
 }
 
-var File_shared_proto_weather_proto protoreflect.FileDescriptor
+func (x *WeatherResponse) GetWeatherCode() int32 {
+	if x != nil {
+		return x.WeatherCode
+	}
+	return 0
+}
 
-const file_shared_proto_weather_proto_rawDesc = "" +
-	"\n" +
-	"\x1ashared/proto/weather.proto\x12\aweather\"J\n" +
-	"\x0eWeatherRequest\x12\x1a\n" +
-	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xd6\x02\n" +
-	"\x0fWeatherResponse\x12\x1a\n" +
-	"\blocation\x18\x01 \x01(\tR\blocation\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12 \n" +
-	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x1d\n" +
-	"\n" +
-	"feels_like\x18\x04 \x01(\x01R\tfeelsLike\x12\x19\n" +
-	"\btemp_min\x18\x05 \x01(\x01R\atempMin\x12\x19\n" +
-	"\btemp_max\x18\x06 \x01(\x01R\atempMax\x12\x1a\n" +
-	"\bpressure\x18\a \x01(\x05R\bpressure\x12\x1a\n" +
-	"\bhumidity\x18\b \x01(\x05R\bhumidity\x12\x1d\n" +
-	"\n" +
-	"wind_speed\x18\t \x01(\x01R\twindSpeed\x12\x19\n" +
-	"\bwind_deg\x18\n" +
-	" \x01(\x05R\awindDeg\x12\x1c\n" +
-	"\ttimestamp\x18\v \x01(\x03R\ttimestamp2X\n" +
+func (x *WeatherResponse) GetUnits() *Units {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *WeatherResponse) GetPressureValue() float64 {
+	if x != nil {
+		return x.PressureValue
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetProvider() *Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+func (x *WeatherResponse) GetUvIndex() float64 {
+	if x != nil {
+		return x.UvIndex
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetIsDay() bool {
+	if x != nil {
+		return x.IsDay
+	}
+	return false
+}
+
+// HourlyConditions is one forecast hour in metric units.
+type HourlyConditions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix seconds at the start of the hour.
+	Time int64 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// °C and km/h.
+	Temperature float64 `protobuf:"fixed64,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	WindSpeed   float64 `protobuf:"fixed64,3,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	// US EPA air quality index (0-500). Only in AirQualityForecast.
+	UsAqi int32 `protobuf:"varint,4,opt,name=us_aqi,json=usAqi,proto3" json:"us_aqi,omitempty"`
+	// µg/m³. Only in AirQualityForecast.
+	Pm2_5 float64 `protobuf:"fixed64,5,opt,name=pm2_5,json=pm25,proto3" json:"pm2_5,omitempty"`
+	// mm over the hour, and the chance of any (0-100). Only in
+	// HourlyForecast.
+	Precipitation            float64 `protobuf:"fixed64,6,opt,name=precipitation,proto3" json:"precipitation,omitempty"`
+	PrecipitationProbability int32   `protobuf:"varint,7,opt,name=precipitation_probability,json=precipitationProbability,proto3" json:"precipitation_probability,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *HourlyConditions) Reset() {
+	*x = HourlyConditions{}
+	mi := &file_shared_proto_weather_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HourlyConditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HourlyConditions) ProtoMessage() {}
+
+func (x *HourlyConditions) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HourlyConditions.ProtoReflect.Descriptor instead.
+func (*HourlyConditions) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HourlyConditions) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetWindSpeed() float64 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetUsAqi() int32 {
+	if x != nil {
+		return x.UsAqi
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetPm2_5() float64 {
+	if x != nil {
+		return x.Pm2_5
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetPrecipitation() float64 {
+	if x != nil {
+		return x.Precipitation
+	}
+	return 0
+}
+
+func (x *HourlyConditions) GetPrecipitationProbability() int32 {
+	if x != nil {
+		return x.PrecipitationProbability
+	}
+	return 0
+}
+
+type AirQualityForecast struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The next 24 hours, oldest first.
+	Hours []*HourlyConditions `protobuf:"bytes,1,rep,name=hours,proto3" json:"hours,omitempty"`
+	// Offset of the location's local time from UTC.
+	UtcOffsetSeconds int32       `protobuf:"varint,2,opt,name=utc_offset_seconds,json=utcOffsetSeconds,proto3" json:"utc_offset_seconds,omitempty"`
+	Providers        []*Provider `protobuf:"bytes,3,rep,name=providers,proto3" json:"providers,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AirQualityForecast) Reset() {
+	*x = AirQualityForecast{}
+	mi := &file_shared_proto_weather_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AirQualityForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AirQualityForecast) ProtoMessage() {}
+
+func (x *AirQualityForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AirQualityForecast.ProtoReflect.Descriptor instead.
+func (*AirQualityForecast) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AirQualityForecast) GetHours() []*HourlyConditions {
+	if x != nil {
+		return x.Hours
+	}
+	return nil
+}
+
+func (x *AirQualityForecast) GetUtcOffsetSeconds() int32 {
+	if x != nil {
+		return x.UtcOffsetSeconds
+	}
+	return 0
+}
+
+func (x *AirQualityForecast) GetProviders() []*Provider {
+	if x != nil {
+		return x.Providers
+	}
+	return nil
+}
+
+type DashboardLocation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Caller-chosen key echoed back in each snapshot, e.g. a city name.
+	Id            string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Latitude      float64 `protobuf:"fixed64,2,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64 `protobuf:"fixed64,3,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	CountryCode   string  `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DashboardLocation) Reset() {
+	*x = DashboardLocation{}
+	mi := &file_shared_proto_weather_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardLocation) ProtoMessage() {}
+
+func (x *DashboardLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardLocation.ProtoReflect.Descriptor instead.
+func (*DashboardLocation) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DashboardLocation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DashboardLocation) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *DashboardLocation) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *DashboardLocation) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+type DashboardRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Locations []*DashboardLocation   `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	// Seconds between snapshots. 0 uses the default (60); minimum 15.
+	IntervalSeconds int32      `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	UnitSystem      UnitSystem `protobuf:"varint,3,opt,name=unit_system,json=unitSystem,proto3,enum=weather.UnitSystem" json:"unit_system,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DashboardRequest) Reset() {
+	*x = DashboardRequest{}
+	mi := &file_shared_proto_weather_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardRequest) ProtoMessage() {}
+
+func (x *DashboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardRequest.ProtoReflect.Descriptor instead.
+func (*DashboardRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DashboardRequest) GetLocations() []*DashboardLocation {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *DashboardRequest) GetIntervalSeconds() int32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *DashboardRequest) GetUnitSystem() UnitSystem {
+	if x != nil {
+		return x.UnitSystem
+	}
+	return UnitSystem_UNIT_SYSTEM_AUTO
+}
+
+type LocationSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Latest successful reading. Kept from the previous snapshot when a
+	// refresh fails.
+	Weather *WeatherResponse `protobuf:"bytes,2,opt,name=weather,proto3" json:"weather,omitempty"`
+	// Unix seconds of the reading in weather; 0 if there has never been one.
+	UpdatedAt int64 `protobuf:"varint,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Set when the latest refresh failed.
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationSnapshot) Reset() {
+	*x = LocationSnapshot{}
+	mi := &file_shared_proto_weather_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationSnapshot) ProtoMessage() {}
+
+func (x *LocationSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationSnapshot.ProtoReflect.Descriptor instead.
+func (*LocationSnapshot) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LocationSnapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LocationSnapshot) GetWeather() *WeatherResponse {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *LocationSnapshot) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *LocationSnapshot) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DashboardSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*LocationSnapshot    `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	SentAt        int64                  `protobuf:"varint,2,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DashboardSnapshot) Reset() {
+	*x = DashboardSnapshot{}
+	mi := &file_shared_proto_weather_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardSnapshot) ProtoMessage() {}
+
+func (x *DashboardSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardSnapshot.ProtoReflect.Descriptor instead.
+func (*DashboardSnapshot) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DashboardSnapshot) GetLocations() []*LocationSnapshot {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *DashboardSnapshot) GetSentAt() int64 {
+	if x != nil {
+		return x.SentAt
+	}
+	return 0
+}
+
+type DailyForecastRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Latitude  float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Local dates (YYYY-MM-DD), inclusive. Empty start_date means today and
+	// empty end_date means seven days from the start. Open-Meteo forecasts
+	// at most 16 days ahead.
+	StartDate     string `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyForecastRequest) Reset() {
+	*x = DailyForecastRequest{}
+	mi := &file_shared_proto_weather_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyForecastRequest) ProtoMessage() {}
+
+func (x *DailyForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyForecastRequest.ProtoReflect.Descriptor instead.
+func (*DailyForecastRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DailyForecastRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *DailyForecastRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *DailyForecastRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *DailyForecastRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+type DailyConditions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Local date, YYYY-MM-DD.
+	Date        string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	WeatherCode int32  `protobuf:"varint,2,opt,name=weather_code,json=weatherCode,proto3" json:"weather_code,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// °C, km/h and mm.
+	TempMax                  float64 `protobuf:"fixed64,4,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	TempMin                  float64 `protobuf:"fixed64,5,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	PrecipitationProbability int32   `protobuf:"varint,6,opt,name=precipitation_probability,json=precipitationProbability,proto3" json:"precipitation_probability,omitempty"`
+	PrecipitationSum         float64 `protobuf:"fixed64,7,opt,name=precipitation_sum,json=precipitationSum,proto3" json:"precipitation_sum,omitempty"`
+	WindSpeedMax             float64 `protobuf:"fixed64,8,opt,name=wind_speed_max,json=windSpeedMax,proto3" json:"wind_speed_max,omitempty"`
+	UvIndexMax               float64 `protobuf:"fixed64,9,opt,name=uv_index_max,json=uvIndexMax,proto3" json:"uv_index_max,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *DailyConditions) Reset() {
+	*x = DailyConditions{}
+	mi := &file_shared_proto_weather_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyConditions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyConditions) ProtoMessage() {}
+
+func (x *DailyConditions) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyConditions.ProtoReflect.Descriptor instead.
+func (*DailyConditions) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DailyConditions) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailyConditions) GetWeatherCode() int32 {
+	if x != nil {
+		return x.WeatherCode
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *DailyConditions) GetTempMax() float64 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetTempMin() float64 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetPrecipitationProbability() int32 {
+	if x != nil {
+		return x.PrecipitationProbability
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetPrecipitationSum() float64 {
+	if x != nil {
+		return x.PrecipitationSum
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetWindSpeedMax() float64 {
+	if x != nil {
+		return x.WindSpeedMax
+	}
+	return 0
+}
+
+func (x *DailyConditions) GetUvIndexMax() float64 {
+	if x != nil {
+		return x.UvIndexMax
+	}
+	return 0
+}
+
+type DailyForecast struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Days             []*DailyConditions     `protobuf:"bytes,1,rep,name=days,proto3" json:"days,omitempty"`
+	UtcOffsetSeconds int32                  `protobuf:"varint,2,opt,name=utc_offset_seconds,json=utcOffsetSeconds,proto3" json:"utc_offset_seconds,omitempty"`
+	Provider         *Provider              `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DailyForecast) Reset() {
+	*x = DailyForecast{}
+	mi := &file_shared_proto_weather_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyForecast) ProtoMessage() {}
+
+func (x *DailyForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyForecast.ProtoReflect.Descriptor instead.
+func (*DailyForecast) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DailyForecast) GetDays() []*DailyConditions {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *DailyForecast) GetUtcOffsetSeconds() int32 {
+	if x != nil {
+		return x.UtcOffsetSeconds
+	}
+	return 0
+}
+
+func (x *DailyForecast) GetProvider() *Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+type HourlyForecastRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Latitude  float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Hours from the current hour. 0 means 48; at most 384 (16 days).
+	Hours         int32 `protobuf:"varint,3,opt,name=hours,proto3" json:"hours,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HourlyForecastRequest) Reset() {
+	*x = HourlyForecastRequest{}
+	mi := &file_shared_proto_weather_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HourlyForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HourlyForecastRequest) ProtoMessage() {}
+
+func (x *HourlyForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HourlyForecastRequest.ProtoReflect.Descriptor instead.
+func (*HourlyForecastRequest) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HourlyForecastRequest) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *HourlyForecastRequest) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *HourlyForecastRequest) GetHours() int32 {
+	if x != nil {
+		return x.Hours
+	}
+	return 0
+}
+
+type HourlyForecast struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Oldest first.
+	Hours            []*HourlyConditions `protobuf:"bytes,1,rep,name=hours,proto3" json:"hours,omitempty"`
+	UtcOffsetSeconds int32               `protobuf:"varint,2,opt,name=utc_offset_seconds,json=utcOffsetSeconds,proto3" json:"utc_offset_seconds,omitempty"`
+	Provider         *Provider           `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *HourlyForecast) Reset() {
+	*x = HourlyForecast{}
+	mi := &file_shared_proto_weather_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HourlyForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HourlyForecast) ProtoMessage() {}
+
+func (x *HourlyForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_shared_proto_weather_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HourlyForecast.ProtoReflect.Descriptor instead.
+func (*HourlyForecast) Descriptor() ([]byte, []int) {
+	return file_shared_proto_weather_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HourlyForecast) GetHours() []*HourlyConditions {
+	if x != nil {
+		return x.Hours
+	}
+	return nil
+}
+
+func (x *HourlyForecast) GetUtcOffsetSeconds() int32 {
+	if x != nil {
+		return x.UtcOffsetSeconds
+	}
+	return 0
+}
+
+func (x *HourlyForecast) GetProvider() *Provider {
+	if x != nil {
+		return x.Provider
+	}
+	return nil
+}
+
+var File_shared_proto_weather_proto protoreflect.FileDescriptor
+
+const file_shared_proto_weather_proto_rawDesc = "" +
+	"\n" +
+	"\x1ashared/proto/weather.proto\x12\aweather\"\xa3\x01\n" +
+	"\x0eWeatherRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12!\n" +
+	"\fcountry_code\x18\x03 \x01(\tR\vcountryCode\x124\n" +
+	"\vunit_system\x18\x04 \x01(\x0e2\x13.weather.UnitSystemR\n" +
+	"unitSystem\"\x91\x01\n" +
+	"\x05Units\x12+\n" +
+	"\x06system\x18\x01 \x01(\x0e2\x13.weather.UnitSystemR\x06system\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\tR\vtemperature\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x03 \x01(\tR\twindSpeed\x12\x1a\n" +
+	"\bpressure\x18\x04 \x01(\tR\bpressure\"\xa8\x01\n" +
+	"\bProvider\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x18\n" +
+	"\alicense\x18\x03 \x01(\tR\alicense\x12 \n" +
+	"\vattribution\x18\x04 \x01(\tR\vattribution\x12\x14\n" +
+	"\x05model\x18\x05 \x01(\tR\x05model\x12$\n" +
+	"\x0emodel_run_time\x18\x06 \x01(\x03R\fmodelRunTime\"\xa7\x04\n" +
+	"\x0fWeatherResponse\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12 \n" +
+	"\vtemperature\x18\x03 \x01(\x01R\vtemperature\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x04 \x01(\x01R\tfeelsLike\x12\x19\n" +
+	"\btemp_min\x18\x05 \x01(\x01R\atempMin\x12\x19\n" +
+	"\btemp_max\x18\x06 \x01(\x01R\atempMax\x12\x1a\n" +
+	"\bpressure\x18\a \x01(\x05R\bpressure\x12\x1a\n" +
+	"\bhumidity\x18\b \x01(\x05R\bhumidity\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\t \x01(\x01R\twindSpeed\x12\x19\n" +
+	"\bwind_deg\x18\n" +
+	" \x01(\x05R\awindDeg\x12\x1c\n" +
+	"\ttimestamp\x18\v \x01(\x03R\ttimestamp\x12!\n" +
+	"\fweather_code\x18\f \x01(\x05R\vweatherCode\x12$\n" +
+	"\x05units\x18\r \x01(\v2\x0e.weather.UnitsR\x05units\x12%\n" +
+	"\x0epressure_value\x18\x0e \x01(\x01R\rpressureValue\x12-\n" +
+	"\bprovider\x18\x0f \x01(\v2\x11.weather.ProviderR\bprovider\x12\x19\n" +
+	"\buv_index\x18\x10 \x01(\x01R\auvIndex\x12\x15\n" +
+	"\x06is_day\x18\x11 \x01(\bR\x05isDay\"\xf6\x01\n" +
+	"\x10HourlyConditions\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\x12\x1d\n" +
+	"\n" +
+	"wind_speed\x18\x03 \x01(\x01R\twindSpeed\x12\x15\n" +
+	"\x06us_aqi\x18\x04 \x01(\x05R\x05usAqi\x12\x13\n" +
+	"\x05pm2_5\x18\x05 \x01(\x01R\x04pm25\x12$\n" +
+	"\rprecipitation\x18\x06 \x01(\x01R\rprecipitation\x12;\n" +
+	"\x19precipitation_probability\x18\a \x01(\x05R\x18precipitationProbability\"\xa4\x01\n" +
+	"\x12AirQualityForecast\x12/\n" +
+	"\x05hours\x18\x01 \x03(\v2\x19.weather.HourlyConditionsR\x05hours\x12,\n" +
+	"\x12utc_offset_seconds\x18\x02 \x01(\x05R\x10utcOffsetSeconds\x12/\n" +
+	"\tproviders\x18\x03 \x03(\v2\x11.weather.ProviderR\tproviders\"\x80\x01\n" +
+	"\x11DashboardLocation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\blatitude\x18\x02 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x03 \x01(\x01R\tlongitude\x12!\n" +
+	"\fcountry_code\x18\x04 \x01(\tR\vcountryCode\"\xad\x01\n" +
+	"\x10DashboardRequest\x128\n" +
+	"\tlocations\x18\x01 \x03(\v2\x1a.weather.DashboardLocationR\tlocations\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\x05R\x0fintervalSeconds\x124\n" +
+	"\vunit_system\x18\x03 \x01(\x0e2\x13.weather.UnitSystemR\n" +
+	"unitSystem\"\x8b\x01\n" +
+	"\x10LocationSnapshot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x122\n" +
+	"\aweather\x18\x02 \x01(\v2\x18.weather.WeatherResponseR\aweather\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\x03R\tupdatedAt\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"e\n" +
+	"\x11DashboardSnapshot\x127\n" +
+	"\tlocations\x18\x01 \x03(\v2\x19.weather.LocationSnapshotR\tlocations\x12\x17\n" +
+	"\asent_at\x18\x02 \x01(\x03R\x06sentAt\"\x8a\x01\n" +
+	"\x14DailyForecastRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x04 \x01(\tR\aendDate\"\xd2\x02\n" +
+	"\x0fDailyConditions\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12!\n" +
+	"\fweather_code\x18\x02 \x01(\x05R\vweatherCode\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x19\n" +
+	"\btemp_max\x18\x04 \x01(\x01R\atempMax\x12\x19\n" +
+	"\btemp_min\x18\x05 \x01(\x01R\atempMin\x12;\n" +
+	"\x19precipitation_probability\x18\x06 \x01(\x05R\x18precipitationProbability\x12+\n" +
+	"\x11precipitation_sum\x18\a \x01(\x01R\x10precipitationSum\x12$\n" +
+	"\x0ewind_speed_max\x18\b \x01(\x01R\fwindSpeedMax\x12 \n" +
+	"\fuv_index_max\x18\t \x01(\x01R\n" +
+	"uvIndexMax\"\x9a\x01\n" +
+	"\rDailyForecast\x12,\n" +
+	"\x04days\x18\x01 \x03(\v2\x18.weather.DailyConditionsR\x04days\x12,\n" +
+	"\x12utc_offset_seconds\x18\x02 \x01(\x05R\x10utcOffsetSeconds\x12-\n" +
+	"\bprovider\x18\x03 \x01(\v2\x11.weather.ProviderR\bprovider\"g\n" +
+	"\x15HourlyForecastRequest\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x14\n" +
+	"\x05hours\x18\x03 \x01(\x05R\x05hours\"\x9e\x01\n" +
+	"\x0eHourlyForecast\x12/\n" +
+	"\x05hours\x18\x01 \x03(\v2\x19.weather.HourlyConditionsR\x05hours\x12,\n" +
+	"\x12utc_offset_seconds\x18\x02 \x01(\x05R\x10utcOffsetSeconds\x12-\n" +
+	"\bprovider\x18\x03 \x01(\v2\x11.weather.ProviderR\bprovider*|\n" +
+	"\n" +
+	"UnitSystem\x12\x14\n" +
+	"\x10UNIT_SYSTEM_AUTO\x10\x00\x12\x16\n" +
+	"\x12UNIT_SYSTEM_METRIC\x10\x01\x12\x18\n" +
+	"\x14UNIT_SYSTEM_IMPERIAL\x10\x02\x12\x12\n" +
+	"\x0eUNIT_SYSTEM_UK\x10\x03\x12\x12\n" +
+	"\x0eUNIT_SYSTEM_SI\x10\x042\x8c\x03\n" +
 	"\x0eWeatherService\x12F\n" +
-	"\x11GetCurrentWeather\x12\x17.weather.WeatherRequest\x1a\x18.weather.WeatherResponseB\x18Z\x16shared/proto/weatherpbb\x06proto3"
+	"\x11GetCurrentWeather\x12\x17.weather.WeatherRequest\x1a\x18.weather.WeatherResponse\x12M\n" +
+	"\x15GetAirQualityForecast\x12\x17.weather.WeatherRequest\x1a\x1b.weather.AirQualityForecast\x12I\n" +
+	"\x10GetDailyForecast\x12\x1d.weather.DailyForecastRequest\x1a\x16.weather.DailyForecast\x12L\n" +
+	"\x11GetHourlyForecast\x12\x1e.weather.HourlyForecastRequest\x1a\x17.weather.HourlyForecast\x12J\n" +
+	"\x0fStreamDashboard\x12\x19.weather.DashboardRequest\x1a\x1a.weather.DashboardSnapshot0\x01B\x18Z\x16shared/proto/weatherpbb\x06proto3"
 
 var (
 	file_shared_proto_weather_proto_rawDescOnce sync.Once
@@ -235,19 +1386,56 @@ func file_shared_proto_weather_proto_rawDescGZIP() []byte {
 	return file_shared_proto_weather_proto_rawDescData
 }
 
-var file_shared_proto_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_shared_proto_weather_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_shared_proto_weather_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_shared_proto_weather_proto_goTypes = []any{
-	(*WeatherRequest)(nil),  // 0: weather.WeatherRequest
-	(*WeatherResponse)(nil), // 1: weather.WeatherResponse
+	(UnitSystem)(0),               // 0: weather.UnitSystem
+	(*WeatherRequest)(nil),        // 1: weather.WeatherRequest
+	(*Units)(nil),                 // 2: weather.Units
+	(*Provider)(nil),              // 3: weather.Provider
+	(*WeatherResponse)(nil),       // 4: weather.WeatherResponse
+	(*HourlyConditions)(nil),      // 5: weather.HourlyConditions
+	(*AirQualityForecast)(nil),    // 6: weather.AirQualityForecast
+	(*DashboardLocation)(nil),     // 7: weather.DashboardLocation
+	(*DashboardRequest)(nil),      // 8: weather.DashboardRequest
+	(*LocationSnapshot)(nil),      // 9: weather.LocationSnapshot
+	(*DashboardSnapshot)(nil),     // 10: weather.DashboardSnapshot
+	(*DailyForecastRequest)(nil),  // 11: weather.DailyForecastRequest
+	(*DailyConditions)(nil),       // 12: weather.DailyConditions
+	(*DailyForecast)(nil),         // 13: weather.DailyForecast
+	(*HourlyForecastRequest)(nil), // 14: weather.HourlyForecastRequest
+	(*HourlyForecast)(nil),        // 15: weather.HourlyForecast
 }
 var file_shared_proto_weather_proto_depIdxs = []int32{
-	0, // 0: weather.WeatherService.GetCurrentWeather:input_type -> weather.WeatherRequest
-	1, // 1: weather.WeatherService.GetCurrentWeather:output_type -> weather.WeatherResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: weather.WeatherRequest.unit_system:type_name -> weather.UnitSystem
+	0,  // 1: weather.Units.system:type_name -> weather.UnitSystem
+	2,  // 2: weather.WeatherResponse.units:type_name -> weather.Units
+	3,  // 3: weather.WeatherResponse.provider:type_name -> weather.Provider
+	5,  // 4: weather.AirQualityForecast.hours:type_name -> weather.HourlyConditions
+	3,  // 5: weather.AirQualityForecast.providers:type_name -> weather.Provider
+	7,  // 6: weather.DashboardRequest.locations:type_name -> weather.DashboardLocation
+	0,  // 7: weather.DashboardRequest.unit_system:type_name -> weather.UnitSystem
+	4,  // 8: weather.LocationSnapshot.weather:type_name -> weather.WeatherResponse
+	9,  // 9: weather.DashboardSnapshot.locations:type_name -> weather.LocationSnapshot
+	12, // 10: weather.DailyForecast.days:type_name -> weather.DailyConditions
+	3,  // 11: weather.DailyForecast.provider:type_name -> weather.Provider
+	5,  // 12: weather.HourlyForecast.hours:type_name -> weather.HourlyConditions
+	3,  // 13: weather.HourlyForecast.provider:type_name -> weather.Provider
+	1,  // 14: weather.WeatherService.GetCurrentWeather:input_type -> weather.WeatherRequest
+	1,  // 15: weather.WeatherService.GetAirQualityForecast:input_type -> weather.WeatherRequest
+	11, // 16: weather.WeatherService.GetDailyForecast:input_type -> weather.DailyForecastRequest
+	14, // 17: weather.WeatherService.GetHourlyForecast:input_type -> weather.HourlyForecastRequest
+	8,  // 18: weather.WeatherService.StreamDashboard:input_type -> weather.DashboardRequest
+	4,  // 19: weather.WeatherService.GetCurrentWeather:output_type -> weather.WeatherResponse
+	6,  // 20: weather.WeatherService.GetAirQualityForecast:output_type -> weather.AirQualityForecast
+	13, // 21: weather.WeatherService.GetDailyForecast:output_type -> weather.DailyForecast
+	15, // 22: weather.WeatherService.GetHourlyForecast:output_type -> weather.HourlyForecast
+	10, // 23: weather.WeatherService.StreamDashboard:output_type -> weather.DashboardSnapshot
+	19, // [19:24] is the sub-list for method output_type
+	14, // [14:19] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_shared_proto_weather_proto_init() }
@@ -260,13 +1448,14 @@ func file_shared_proto_weather_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shared_proto_weather_proto_rawDesc), len(file_shared_proto_weather_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_shared_proto_weather_proto_goTypes,
 		DependencyIndexes: file_shared_proto_weather_proto_depIdxs,
+		EnumInfos:         file_shared_proto_weather_proto_enumTypes,
 		MessageInfos:      file_shared_proto_weather_proto_msgTypes,
 	}.Build()
 	File_shared_proto_weather_proto = out.File
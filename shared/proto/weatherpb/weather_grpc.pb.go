@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	WeatherService_GetCurrentWeather_FullMethodName = "/weather.WeatherService/GetCurrentWeather"
+	WeatherService_GetCurrentWeather_FullMethodName     = "/weather.WeatherService/GetCurrentWeather"
+	WeatherService_GetAirQualityForecast_FullMethodName = "/weather.WeatherService/GetAirQualityForecast"
+	WeatherService_GetDailyForecast_FullMethodName      = "/weather.WeatherService/GetDailyForecast"
+	WeatherService_GetHourlyForecast_FullMethodName     = "/weather.WeatherService/GetHourlyForecast"
+	WeatherService_StreamDashboard_FullMethodName       = "/weather.WeatherService/StreamDashboard"
 )
 
 // WeatherServiceClient is the client API for WeatherService service.
@@ -27,6 +31,15 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type WeatherServiceClient interface {
 	GetCurrentWeather(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (*WeatherResponse, error)
+	// Hourly air quality with temperature and wind, always metric.
+	GetAirQualityForecast(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (*AirQualityForecast, error)
+	// Daily highs, lows, rain, wind and UV for a date range, always metric.
+	GetDailyForecast(ctx context.Context, in *DailyForecastRequest, opts ...grpc.CallOption) (*DailyForecast, error)
+	// Hourly temperature, rain and wind, always metric.
+	GetHourlyForecast(ctx context.Context, in *HourlyForecastRequest, opts ...grpc.CallOption) (*HourlyForecast, error)
+	// Pushes a snapshot of every location on one stream, so dashboards don't
+	// need a stream per location.
+	StreamDashboard(ctx context.Context, in *DashboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DashboardSnapshot], error)
 }
 
 type weatherServiceClient struct {
@@ -47,11 +60,69 @@ func (c *weatherServiceClient) GetCurrentWeather(ctx context.Context, in *Weathe
 	return out, nil
 }
 
+func (c *weatherServiceClient) GetAirQualityForecast(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (*AirQualityForecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AirQualityForecast)
+	err := c.cc.Invoke(ctx, WeatherService_GetAirQualityForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetDailyForecast(ctx context.Context, in *DailyForecastRequest, opts ...grpc.CallOption) (*DailyForecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DailyForecast)
+	err := c.cc.Invoke(ctx, WeatherService_GetDailyForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetHourlyForecast(ctx context.Context, in *HourlyForecastRequest, opts ...grpc.CallOption) (*HourlyForecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HourlyForecast)
+	err := c.cc.Invoke(ctx, WeatherService_GetHourlyForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) StreamDashboard(ctx context.Context, in *DashboardRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DashboardSnapshot], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherService_ServiceDesc.Streams[0], WeatherService_StreamDashboard_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DashboardRequest, DashboardSnapshot]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_StreamDashboardClient = grpc.ServerStreamingClient[DashboardSnapshot]
+
 // WeatherServiceServer is the server API for WeatherService service.
 // All implementations must embed UnimplementedWeatherServiceServer
 // for forward compatibility.
 type WeatherServiceServer interface {
 	GetCurrentWeather(context.Context, *WeatherRequest) (*WeatherResponse, error)
+	// Hourly air quality with temperature and wind, always metric.
+	GetAirQualityForecast(context.Context, *WeatherRequest) (*AirQualityForecast, error)
+	// Daily highs, lows, rain, wind and UV for a date range, always metric.
+	GetDailyForecast(context.Context, *DailyForecastRequest) (*DailyForecast, error)
+	// Hourly temperature, rain and wind, always metric.
+	GetHourlyForecast(context.Context, *HourlyForecastRequest) (*HourlyForecast, error)
+	// Pushes a snapshot of every location on one stream, so dashboards don't
+	// need a stream per location.
+	StreamDashboard(*DashboardRequest, grpc.ServerStreamingServer[DashboardSnapshot]) error
 	mustEmbedUnimplementedWeatherServiceServer()
 }
 
@@ -65,6 +136,18 @@ type UnimplementedWeatherServiceServer struct{}
 func (UnimplementedWeatherServiceServer) GetCurrentWeather(context.Context, *WeatherRequest) (*WeatherResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentWeather not implemented")
 }
+func (UnimplementedWeatherServiceServer) GetAirQualityForecast(context.Context, *WeatherRequest) (*AirQualityForecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAirQualityForecast not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetDailyForecast(context.Context, *DailyForecastRequest) (*DailyForecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailyForecast not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetHourlyForecast(context.Context, *HourlyForecastRequest) (*HourlyForecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHourlyForecast not implemented")
+}
+func (UnimplementedWeatherServiceServer) StreamDashboard(*DashboardRequest, grpc.ServerStreamingServer[DashboardSnapshot]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDashboard not implemented")
+}
 func (UnimplementedWeatherServiceServer) mustEmbedUnimplementedWeatherServiceServer() {}
 func (UnimplementedWeatherServiceServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +187,71 @@ func _WeatherService_GetCurrentWeather_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WeatherService_GetAirQualityForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WeatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetAirQualityForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetAirQualityForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetAirQualityForecast(ctx, req.(*WeatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetDailyForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DailyForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetDailyForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetDailyForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetDailyForecast(ctx, req.(*DailyForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetHourlyForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HourlyForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetHourlyForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetHourlyForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetHourlyForecast(ctx, req.(*HourlyForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_StreamDashboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DashboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherServiceServer).StreamDashboard(m, &grpc.GenericServerStream[DashboardRequest, DashboardSnapshot]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_StreamDashboardServer = grpc.ServerStreamingServer[DashboardSnapshot]
+
 // WeatherService_ServiceDesc is the grpc.ServiceDesc for WeatherService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +263,25 @@ var WeatherService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCurrentWeather",
 			Handler:    _WeatherService_GetCurrentWeather_Handler,
 		},
+		{
+			MethodName: "GetAirQualityForecast",
+			Handler:    _WeatherService_GetAirQualityForecast_Handler,
+		},
+		{
+			MethodName: "GetDailyForecast",
+			Handler:    _WeatherService_GetDailyForecast_Handler,
+		},
+		{
+			MethodName: "GetHourlyForecast",
+			Handler:    _WeatherService_GetHourlyForecast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDashboard",
+			Handler:       _WeatherService_StreamDashboard_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "shared/proto/weather.proto",
 }